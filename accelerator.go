@@ -0,0 +1,63 @@
+package regression
+
+import (
+	"sync"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Accelerator solves the least squares problems of very large fits on a device such as a GPU, e.g. with
+// CUDA or OpenCL. Implementations are provided separately and registered with RegisterAccelerator,
+// typically from the init function of their package. Solve must not modify x or y.
+type Accelerator interface {
+	Solver
+	// Available reports whether the device can be used, e.g. whether a GPU is present.
+	Available() bool
+}
+
+// acceleration is the registered accelerator and the size of the fits it is used for.
+var acceleration struct {
+	sync.RWMutex
+	accel    Accelerator
+	minCells int
+}
+
+// RegisterAccelerator makes Run solve design matrices of at least minCells entries, rows times columns,
+// with a, unless the model has a solver set with SetSolver. When the accelerator is unavailable or fails,
+// QRSolver is used instead. A nil accelerator unregisters it.
+func RegisterAccelerator(a Accelerator, minCells int) {
+	acceleration.Lock()
+	defer acceleration.Unlock()
+	acceleration.accel, acceleration.minCells = a, minCells
+}
+
+// solverFor returns the solver for a design matrix of rows x cols: the one set with SetSolver,
+// the registered accelerator for large enough fits, or QRSolver.
+func (r *Regression) solverFor(rows, cols int) Solver {
+	if r.solve != nil {
+		return r.solve
+	}
+	acceleration.RLock()
+	a, minCells := acceleration.accel, acceleration.minCells
+	acceleration.RUnlock()
+	if a == nil || rows*cols < minCells || !a.Available() {
+		return QRSolver{}
+	}
+	return acceleratedSolver{accel: a, stats: &r.stats}
+}
+
+// acceleratedSolver solves with an accelerator, falling back to QRSolver when it fails.
+type acceleratedSolver struct {
+	accel Accelerator
+	stats *FitStats
+}
+
+// Solve satisfies the Solver interface.
+func (s acceleratedSolver) Solve(x, y *mat.Dense) ([]float64, *Diagnostics, error) {
+	c, diag, err := s.accel.Solve(x, y)
+	if err == nil {
+		s.stats.Accelerated = true
+		return c, diag, nil
+	}
+	return QRSolver{}.Solve(x, y)
+}
@@ -0,0 +1,71 @@
+package regression
+
+import (
+	"errors"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// fakeAccelerator solves with QRSolver, counting its calls.
+type fakeAccelerator struct {
+	available bool
+	fail      bool
+	calls     int
+}
+
+func (a *fakeAccelerator) Available() bool {
+	return a.available
+}
+
+func (a *fakeAccelerator) Solve(x, y *mat.Dense) ([]float64, *Diagnostics, error) {
+	a.calls++
+	if a.fail {
+		return nil, nil, errors.New("device error")
+	}
+	return QRSolver{}.Solve(x, y)
+}
+
+func TestRegisterAccelerator(t *testing.T) {
+	defer RegisterAccelerator(nil, 0)
+	want := carsRegression(t)
+	a := &fakeAccelerator{available: true}
+
+	// the cars data is 50 x 2
+	RegisterAccelerator(a, 101)
+	if r := carsRegression(t); a.calls != 0 || r.FitStats().Accelerated {
+		t.Errorf("Expected a small fit not to be accelerated, got %d calls", a.calls)
+	}
+
+	RegisterAccelerator(a, 100)
+	r := carsRegression(t)
+	if a.calls != 1 || !r.FitStats().Accelerated {
+		t.Errorf("Expected the fit to be accelerated, got %d calls", a.calls)
+	}
+	assertClose(t, "accelerated slope", r.Coeff(1), want.Coeff(1), 1e-12)
+
+	a.fail = true
+	r = carsRegression(t)
+	if a.calls != 2 || r.FitStats().Accelerated {
+		t.Errorf("Expected a failed accelerator to fall back, got %d calls", a.calls)
+	}
+	assertClose(t, "fallback slope", r.Coeff(1), want.Coeff(1), 1e-12)
+
+	a.fail, a.available = false, false
+	if carsRegression(t); a.calls != 2 {
+		t.Errorf("Expected an unavailable accelerator not to be called, got %d calls", a.calls)
+	}
+
+	a.available = true
+	r = new(Regression)
+	r.SetSolver(QRSolver{})
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if a.calls != 2 {
+		t.Errorf("Expected an explicit solver to take precedence, got %d calls", a.calls)
+	}
+}
@@ -0,0 +1,118 @@
+package regression
+
+import (
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidAnonymity signals invalid Anonymity options, e.g. a k below 2 or a non-positive bin width.
+var ErrInvalidAnonymity = errors.New("invalid anonymity options")
+
+// Anonymity configures ExportAnonymized.
+type Anonymity struct {
+	// K is the minimum number of rows sharing the values of the quasi-identifiers, at least 2.
+	K int
+	// QuasiIdentifiers are the base variables whose combination can identify a record, all of them by
+	// default. The group is a quasi-identifier too when the data has groups.
+	QuasiIdentifiers []int
+	// Coarsen maps base variables to a bin width: their values are rounded down to a multiple of it,
+	// e.g. ages to decades with 10, so fewer rows are rare.
+	Coarsen map[int]float64
+	// SuppressValues blanks the quasi-identifiers of rows in rare combinations, exported as NaN or
+	// null, rather than dropping the rows. The blanked rows are exported last, and stay dropped if they
+	// are themselves rare.
+	SuppressValues bool
+}
+
+// ExportAnonymized writes the training data as ExportData does, made k-anonymous so it can be
+// shared, e.g. for debugging: after coarsening, every combination of the quasi-identifiers occurs
+// in at least K rows, as rows in rarer combinations are suppressed. The feature crosses are computed
+// from the coarsened values, and the Predicted and Error columns are left out, as they are functions of
+// the exact values. The observed values are kept as they are. It returns the number of rows dropped.
+func (r *Regression) ExportAnonymized(w io.Writer, format ExportFormat, a Anonymity) (int, error) {
+	if !r.hasRun || len(r.coeff) == 0 {
+		return 0, ErrNotRun
+	}
+	if len(r.data) == 0 {
+		return 0, ErrNotEnoughData
+	}
+	if a.K < 2 {
+		return 0, ErrInvalidAnonymity
+	}
+	base := len(r.baseVariables(r.data[0]))
+	quasi := a.QuasiIdentifiers
+	if quasi == nil {
+		for i := 0; i < base; i++ {
+			quasi = append(quasi, i)
+		}
+	}
+	for _, i := range quasi {
+		if i < 0 || i >= base {
+			return 0, ErrDimensions
+		}
+	}
+	for i, width := range a.Coarsen {
+		if i < 0 || i >= base {
+			return 0, ErrDimensions
+		}
+		if !(width > 0) || math.IsInf(width, 0) {
+			return 0, ErrInvalidAnonymity
+		}
+	}
+
+	// coarsen the base variables and count the combinations of the quasi-identifiers
+	points := make([]*dataPoint, len(r.data))
+	keys := make([]string, len(r.data))
+	counts := make(map[string]int)
+	for j, d := range r.data {
+		vars := append([]float64(nil), r.baseVariables(d)...)
+		for i, width := range a.Coarsen {
+			vars[i] = math.Floor(vars[i]/width) * width
+		}
+		points[j] = &dataPoint{Observed: d.Observed, Variables: vars, Weight: d.Weight, Group: d.Group}
+		keys[j] = quasiKey(points[j], quasi)
+		counts[keys[j]]++
+	}
+
+	rare := func(p *dataPoint) {
+		for _, i := range quasi {
+			p.Variables[i] = math.NaN()
+		}
+		p.Group = ""
+	}
+	kept := points[:0]
+	var blanked []*dataPoint
+	for j, p := range points {
+		if counts[keys[j]] >= a.K {
+			kept = append(kept, p)
+		} else if a.SuppressValues {
+			rare(p)
+			blanked = append(blanked, p)
+		}
+	}
+	if len(blanked) >= a.K {
+		kept = append(kept, blanked...)
+	}
+	dropped := len(r.data) - len(kept)
+
+	for _, p := range kept {
+		row := r.designRow(p.Variables)[1:]
+		// designRow clips and imputes the base variables, which are exported as they are
+		copy(row, p.Variables)
+		p.Variables = row
+	}
+	return dropped, r.export(w, format, kept, false)
+}
+
+// quasiKey returns the values of the quasi-identifiers of a data point and its group as a string.
+func quasiKey(p *dataPoint, quasi []int) string {
+	parts := make([]string, 0, len(quasi)+1)
+	for _, i := range quasi {
+		parts = append(parts, strconv.FormatFloat(p.Variables[i], 'g', -1, 64))
+	}
+	parts = append(parts, strconv.Quote(p.Group))
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,122 @@
+package regression
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"math"
+	"strconv"
+	"testing"
+)
+
+// exportedRows exports r anonymized as CSV and returns the rows without the header.
+func exportedRows(t *testing.T, r *Regression, a Anonymity) ([][]string, int) {
+	var buf bytes.Buffer
+	dropped, err := r.ExportAnonymized(&buf, ExportCSV, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"dist", "speed", "Weight"}; len(records[0]) != len(want) || records[0][1] != "speed" {
+		t.Fatalf("Expected the columns %v, got %v", want, records[0])
+	}
+	return records[1:], dropped
+}
+
+func TestExportAnonymized(t *testing.T) {
+	r := carsRegression(t)
+
+	rows, dropped := exportedRows(t, r, Anonymity{K: 3})
+	counts := make(map[string]int)
+	for _, row := range rows {
+		counts[row[1]]++
+	}
+	for speed, n := range counts {
+		if n < 3 {
+			t.Errorf("Speed %s occurs only %d times", speed, n)
+		}
+	}
+	if dropped == 0 || len(rows)+dropped != len(carsSpeed) {
+		t.Errorf("Expected rare rows dropped, got %d rows and %d dropped", len(rows), dropped)
+	}
+
+	// coarsening keeps more rows
+	coarse, coarseDropped := exportedRows(t, r, Anonymity{K: 3, Coarsen: map[int]float64{0: 5}})
+	if coarseDropped >= dropped || len(coarse)+coarseDropped != len(carsSpeed) {
+		t.Errorf("Expected fewer rows dropped than %d, got %d", dropped, coarseDropped)
+	}
+	for _, row := range coarse {
+		speed, _ := strconv.ParseFloat(row[1], 64)
+		if math.Mod(speed, 5) != 0 {
+			t.Errorf("Expected the speed in bins of 5, got %v", row[1])
+		}
+	}
+
+	// or the rare values are blanked rather than the rows dropped
+	blanked, blankedDropped := exportedRows(t, r, Anonymity{K: 3, SuppressValues: true})
+	if blankedDropped != 0 || len(blanked) != len(carsSpeed) {
+		t.Errorf("Expected no rows dropped, got %d", blankedDropped)
+	}
+	for i, row := range blanked {
+		if (i >= len(rows)) != (row[1] == "NaN") {
+			t.Errorf("Row %d: expected the blanked rows last, got %v", i, row)
+		}
+	}
+}
+
+func TestExportAnonymizedCrosses(t *testing.T) {
+	r := new(Regression)
+	r.SetObserved("y")
+	r.SetVar(0, "x")
+	r.SetVar(1, "z")
+	r.AddCross(PowCross(0, 2))
+	for i := 0; i < 20; i++ {
+		x, z := float64(i), float64(i%4)
+		p := DataPoint(1+2*x+0.5*x*x+z, []float64{x, z})
+		p.Group = "g" + strconv.Itoa(i%2)
+		r.Train(p)
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	dropped, err := r.ExportAnonymized(&buf, ExportJSON, Anonymity{K: 2, QuasiIdentifiers: []int{0}, Coarsen: map[int]float64{0: 10}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &rows); err != nil {
+		t.Fatalf("%v in %s", err, buf.String())
+	}
+	if dropped != 0 || len(rows) != 20 {
+		t.Fatalf("Expected 20 rows, got %d", len(rows))
+	}
+	row := rows[13]
+	if row["x"] != 10.0 || row["(x)^2"] != 100.0 || row["z"] != 1.0 || row["Group"] != "g1" || row["y"] != r.data[13].Observed {
+		t.Errorf("Unexpected row %v", row)
+	}
+	if _, ok := row["Predicted"]; ok {
+		t.Errorf("Expected no values of the fit, got %v", row)
+	}
+	if r.data[13].Variables[0] != 13 {
+		t.Errorf("Expected the training data unchanged, got %v", r.data[13].Variables)
+	}
+}
+
+func TestExportAnonymizedErrors(t *testing.T) {
+	if _, err := new(Regression).ExportAnonymized(new(bytes.Buffer), ExportCSV, Anonymity{K: 2}); err != ErrNotRun {
+		t.Errorf("Expected ErrNotRun, got %v", err)
+	}
+	r := carsRegression(t)
+	for _, a := range []Anonymity{{K: 1}, {K: 2, Coarsen: map[int]float64{0: 0}}} {
+		if _, err := r.ExportAnonymized(new(bytes.Buffer), ExportCSV, a); err != ErrInvalidAnonymity {
+			t.Errorf("%+v: expected ErrInvalidAnonymity, got %v", a, err)
+		}
+	}
+	if _, err := r.ExportAnonymized(new(bytes.Buffer), ExportCSV, Anonymity{K: 2, QuasiIdentifiers: []int{1}}); err != ErrDimensions {
+		t.Errorf("Expected ErrDimensions, got %v", err)
+	}
+}
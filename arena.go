@@ -0,0 +1,68 @@
+package regression
+
+// defaultArenaBlock is the number of data points allocated at once by a DataPointArena.
+const defaultArenaBlock = 4096
+
+// DataPointArena allocates data points and copies of their variables from large contiguous blocks, which
+// cuts the number of allocations of bulk ingestion by orders of magnitude. The variables of a data point
+// have no spare capacity, so appending the feature crosses in Run never overwrites another data point.
+// A DataPointArena is not safe for concurrent use.
+type DataPointArena struct {
+	block  int
+	points []dataPoint
+	floats []float64
+}
+
+// NewDataPointArena creates an arena allocating blockSize data points at once, 4096 when zero or negative.
+func NewDataPointArena(blockSize int) *DataPointArena {
+	if blockSize <= 0 {
+		blockSize = defaultArenaBlock
+	}
+	return &DataPointArena{block: blockSize}
+}
+
+// DataPoint creates a data point like the DataPoint function, copying vars into the arena.
+func (a *DataPointArena) DataPoint(obs float64, vars []float64) *dataPoint {
+	return a.WeightedDataPoint(obs, vars, 1)
+}
+
+// WeightedDataPoint creates a weighted data point like the WeightedDataPoint function, copying vars
+// into the arena.
+func (a *DataPointArena) WeightedDataPoint(obs float64, vars []float64, weight float64) *dataPoint {
+	if len(a.points) == 0 {
+		a.points = make([]dataPoint, a.block)
+	}
+	d := &a.points[0]
+	a.points = a.points[1:]
+
+	if len(a.floats) < len(vars) {
+		n := a.block * len(vars)
+		if n < len(vars) {
+			n = len(vars)
+		}
+		a.floats = make([]float64, n)
+	}
+	v := a.floats[:len(vars):len(vars)]
+	a.floats = a.floats[len(vars):]
+	copy(v, vars)
+
+	*d = dataPoint{Observed: obs, Variables: v, Weight: weight}
+	return d
+}
+
+// NewDataPoints creates a data point per observed value in bulk, with two allocations. vars holds the
+// variables of the data points one after another, numVars per data point, and the data points refer to
+// it rather than to copies, so it must not be modified afterwards.
+func NewDataPoints(observed []float64, vars []float64, numVars int) (DataPoints, error) {
+	if numVars < 0 || len(vars) != len(observed)*numVars {
+		return nil, ErrDimensions
+	}
+	points := make([]dataPoint, len(observed))
+	ptrs := make(DataPoints, len(observed))
+	for i, obs := range observed {
+		lo, hi := i*numVars, (i+1)*numVars
+		points[i] = dataPoint{Observed: obs, Variables: vars[lo:hi:hi], Weight: 1}
+		ptrs[i] = &points[i]
+	}
+	return ptrs, nil
+}
@@ -0,0 +1,63 @@
+package regression
+
+import "testing"
+
+func TestDataPointArena(t *testing.T) {
+	a := NewDataPointArena(16)
+	want := new(Regression)
+	r := new(Regression)
+	for i := range carsSpeed {
+		vars := []float64{carsSpeed[i]}
+		r.Train(a.DataPoint(carsDist[i], vars))
+		want.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+		vars[0] = -1
+	}
+	// appending the crosses must not overwrite the variables of the following data points
+	r.AddCross(PowCross(0, 2))
+	want.AddCross(PowCross(0, 2))
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	want.Run()
+	for i := 0; i < 3; i++ {
+		assertClose(t, "coefficient", r.Coeff(i), want.Coeff(i), 1e-12)
+	}
+
+	d := a.WeightedDataPoint(1, []float64{2, 3}, 4)
+	if d.Observed != 1 || d.Weight != 4 || len(d.Variables) != 2 || cap(d.Variables) != 2 {
+		t.Errorf("Unexpected data point %+v", d)
+	}
+
+	allocs := testing.AllocsPerRun(10, func() {
+		a := NewDataPointArena(1000)
+		vars := []float64{1, 2}
+		for i := 0; i < 1000; i++ {
+			a.DataPoint(1, vars)
+		}
+	})
+	if allocs > 4 {
+		t.Errorf("Expected at most 4 allocations for 1000 data points, got %v", allocs)
+	}
+}
+
+func TestNewDataPoints(t *testing.T) {
+	vars := make([]float64, 0, len(carsSpeed))
+	vars = append(vars, carsSpeed...)
+	points, err := NewDataPoints(carsDist, vars, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := new(Regression)
+	r.Train(points...)
+	r.AddCross(PowCross(0, 2))
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if points[1].Variables[0] != carsSpeed[1] || len(points[0].Variables) != 2 {
+		t.Errorf("Expected the crosses to be appended without overwriting, got %v and %v", points[0].Variables, points[1].Variables)
+	}
+
+	if _, err := NewDataPoints(carsDist, vars[:3], 1); err != ErrDimensions {
+		t.Errorf("Expected ErrDimensions, got %v", err)
+	}
+}
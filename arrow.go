@@ -0,0 +1,344 @@
+package regression
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// ArrowOptions configures NewArrowWriter.
+type ArrowOptions struct {
+	// Contributions adds a column per variable and feature cross with its contribution to the prediction.
+	Contributions bool
+}
+
+// ArrowWriter writes scored batches as an Arrow IPC stream, the format read by pyarrow.ipc.open_stream,
+// Arrow Flight clients and most analytics engines. Every column is a non-nullable float64: the variables,
+// named as set with SetVar, then "predicted" and, optionally, a "contribution:<name>" column per
+// variable and feature cross. Each call to Write adds one record batch. An ArrowWriter is not safe
+// for concurrent use.
+type ArrowWriter struct {
+	r       *Regression
+	w       io.Writer
+	opts    ArrowOptions
+	columns []string
+	started bool
+	err     error
+}
+
+// NewArrowWriter returns a writer of scored batches to w. The schema is written with the first batch,
+// or by Close if there is none.
+func (r *Regression) NewArrowWriter(w io.Writer, opts ArrowOptions) (*ArrowWriter, error) {
+	if !r.hasRun || len(r.coeff) == 0 {
+		return nil, ErrNotRun
+	}
+	var columns []string
+	for i := 0; i < r.names.base; i++ {
+		columns = append(columns, r.GetVar(i))
+	}
+	columns = append(columns, "predicted")
+	if opts.Contributions {
+		for j := 0; j < len(r.coeff)-1; j++ {
+			columns = append(columns, "contribution:"+r.GetVar(j))
+		}
+	}
+	return &ArrowWriter{r: r, w: w, opts: opts, columns: columns}, nil
+}
+
+// Write scores the rows of batch and writes them as a record batch. Every row holds the base
+// variables of the model. After an error the writer writes nothing more and returns the error.
+func (a *ArrowWriter) Write(batch [][]float64) error {
+	if a.err != nil {
+		return a.err
+	}
+	base := a.r.names.base
+	cols := make([][]float64, len(a.columns))
+	for c := range cols {
+		cols[c] = make([]float64, len(batch))
+	}
+	for i, vars := range batch {
+		if len(vars) != base {
+			return ErrDimensions
+		}
+		p, err := a.r.Predict(vars)
+		if err != nil {
+			return err
+		}
+		for j, v := range vars {
+			cols[j][i] = v
+		}
+		cols[base][i] = p
+		if a.opts.Contributions {
+			contributions := a.r.contributions(vars)
+			if len(contributions) != len(cols)-base-1 {
+				return ErrDimensions
+			}
+			for j, c := range contributions {
+				cols[base+1+j][i] = c
+			}
+		}
+	}
+	if err := a.start(); err != nil {
+		return err
+	}
+	return a.fail(a.writeBatch(cols, len(batch)))
+}
+
+// Close writes the end of the stream. It does not close the underlying writer.
+func (a *ArrowWriter) Close() error {
+	if err := a.start(); err != nil {
+		return err
+	}
+	return a.fail(writeAll(a.w, []byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0}))
+}
+
+func (a *ArrowWriter) fail(err error) error {
+	if err != nil {
+		a.err = err
+	}
+	return err
+}
+
+// start writes the schema message ahead of the first batch.
+func (a *ArrowWriter) start() error {
+	if a.err != nil || a.started {
+		return a.err
+	}
+	a.started = true
+	fields := make(fbVector, len(a.columns))
+	for i, name := range a.columns {
+		fields[i] = fbTable{
+			fbRef(fbString(name)),
+			fbScalar(fbBool(false)),
+			fbScalar([]byte{arrowTypeFloatingPoint}),
+			fbRef(fbTable{fbScalar(fbInt16(arrowPrecisionDouble))}),
+			{},
+			fbRef(fbVector{}),
+		}
+	}
+	schema := fbTable{fbScalar(fbInt16(0)), fbRef(fields)}
+	return a.fail(a.writeMessage(arrowHeaderSchema, schema, nil))
+}
+
+// writeBatch writes a record batch message with the given columns of n values.
+func (a *ArrowWriter) writeBatch(cols [][]float64, n int) error {
+	nodes := make(fbStructs, 0, 16*len(cols))
+	buffers := make(fbStructs, 0, 32*len(cols))
+	body := make([]byte, 0, 8*n*len(cols))
+	for _, col := range cols {
+		nodes = append(nodes, fbInt64(int64(n))...)
+		nodes = append(nodes, fbInt64(0)...)
+		// the validity buffer is empty, as no value is null
+		buffers = append(buffers, fbInt64(int64(len(body)))...)
+		buffers = append(buffers, fbInt64(0)...)
+		buffers = append(buffers, fbInt64(int64(len(body)))...)
+		buffers = append(buffers, fbInt64(int64(8*n))...)
+		for _, v := range col {
+			body = append(body, fbInt64(int64(math.Float64bits(v)))...)
+		}
+	}
+	batch := fbTable{fbScalar(fbInt64(int64(n))), fbRef(nodes), fbRef(buffers)}
+	return a.writeMessage(arrowHeaderRecordBatch, batch, body)
+}
+
+// writeMessage writes an encapsulated IPC message: the continuation marker, the length of the
+// metadata, the Message flatbuffer padded to 8 bytes and the body.
+func (a *ArrowWriter) writeMessage(kind byte, header fbTable, body []byte) error {
+	message := fbTable{
+		fbScalar(fbInt16(arrowMetadataV5)),
+		fbScalar([]byte{kind}),
+		fbRef(header),
+		fbScalar(fbInt64(int64(len(body)))),
+	}
+	meta := fbFinish(message)
+	for len(meta)%8 != 0 {
+		meta = append(meta, 0)
+	}
+	prefix := make([]byte, 8)
+	binary.LittleEndian.PutUint32(prefix, 0xffffffff)
+	binary.LittleEndian.PutUint32(prefix[4:], uint32(len(meta)))
+	if err := writeAll(a.w, prefix); err != nil {
+		return err
+	}
+	if err := writeAll(a.w, meta); err != nil {
+		return err
+	}
+	return writeAll(a.w, body)
+}
+
+func writeAll(w io.Writer, b []byte) error {
+	_, err := w.Write(b)
+	return err
+}
+
+// Values of the Arrow IPC format, see Schema.fbs and Message.fbs of the Arrow specification.
+const (
+	arrowMetadataV5        = 4
+	arrowHeaderSchema      = 1
+	arrowHeaderRecordBatch = 3
+	arrowTypeFloatingPoint = 3
+	arrowPrecisionDouble   = 2
+)
+
+// The flatbuffers below are laid out front to back: a table is preceded by its vtable and followed
+// by the objects it refers to, so every offset points forward as the format requires.
+
+// fbObject is a flatbuffer table, string or vector. writeTo appends it and returns the position
+// offsets to it point at.
+type fbObject interface {
+	writeTo(b *fbBuilder) int
+}
+
+// fbField is a table field, either an inline little-endian scalar or a reference to an object.
+// The zero fbField is an absent field.
+type fbField struct {
+	scalar []byte
+	ref    fbObject
+}
+
+func fbScalar(b []byte) fbField { return fbField{scalar: b} }
+func fbRef(o fbObject) fbField  { return fbField{ref: o} }
+func (f fbField) present() bool { return f.scalar != nil || f.ref != nil }
+
+func (f fbField) size() int {
+	if f.ref != nil {
+		return 4
+	}
+	return len(f.scalar)
+}
+
+// fbTable is a table with its fields in the order of their ids.
+type fbTable []fbField
+
+// fbString is a string.
+type fbString string
+
+// fbVector is a vector of tables.
+type fbVector []fbTable
+
+// fbStructs is a vector of structs of 8 byte integers, given as their encoded bytes.
+type fbStructs []byte
+
+type fbBuilder struct {
+	buf []byte
+}
+
+// fbFinish returns the flatbuffer with root as its root table.
+func fbFinish(root fbTable) []byte {
+	b := &fbBuilder{buf: make([]byte, 4, 256)}
+	pos := root.writeTo(b)
+	binary.LittleEndian.PutUint32(b.buf, uint32(pos))
+	return b.buf
+}
+
+func (b *fbBuilder) pad(align int) {
+	for len(b.buf)%align != 0 {
+		b.buf = append(b.buf, 0)
+	}
+}
+
+func (b *fbBuilder) uint32(v int) {
+	b.buf = append(b.buf, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(b.buf[len(b.buf)-4:], uint32(v))
+}
+
+// patch sets the offset at pos to refer to target.
+func (b *fbBuilder) patch(pos, target int) {
+	binary.LittleEndian.PutUint32(b.buf[pos:], uint32(target-pos))
+}
+
+func (t fbTable) writeTo(b *fbBuilder) int {
+	// lay out the fields after the vtable offset, each aligned to its size
+	offsets := make([]int, len(t))
+	size := 4
+	for i, f := range t {
+		if !f.present() {
+			continue
+		}
+		for size%f.size() != 0 {
+			size++
+		}
+		offsets[i] = size
+		size += f.size()
+	}
+
+	// the vtable ends where the table starts, at a multiple of 8
+	vtable := 4 + 2*len(t)
+	b.pad(2)
+	for (len(b.buf)+vtable)%8 != 0 {
+		b.buf = append(b.buf, 0, 0)
+	}
+	start := len(b.buf)
+	b.buf = append(b.buf, make([]byte, vtable)...)
+	binary.LittleEndian.PutUint16(b.buf[start:], uint16(vtable))
+	binary.LittleEndian.PutUint16(b.buf[start+2:], uint16(size))
+	for i, off := range offsets {
+		binary.LittleEndian.PutUint16(b.buf[start+4+2*i:], uint16(off))
+	}
+
+	pos := len(b.buf)
+	b.buf = append(b.buf, make([]byte, size)...)
+	binary.LittleEndian.PutUint32(b.buf[pos:], uint32(pos-start))
+	for i, f := range t {
+		if f.scalar != nil {
+			copy(b.buf[pos+offsets[i]:], f.scalar)
+		}
+	}
+	for i, f := range t {
+		if f.ref != nil {
+			b.patch(pos+offsets[i], f.ref.writeTo(b))
+		}
+	}
+	return pos
+}
+
+func (s fbString) writeTo(b *fbBuilder) int {
+	b.pad(4)
+	pos := len(b.buf)
+	b.uint32(len(s))
+	b.buf = append(b.buf, s...)
+	b.buf = append(b.buf, 0)
+	return pos
+}
+
+func (v fbVector) writeTo(b *fbBuilder) int {
+	b.pad(4)
+	pos := len(b.buf)
+	b.uint32(len(v))
+	b.buf = append(b.buf, make([]byte, 4*len(v))...)
+	for i, t := range v {
+		b.patch(pos+4+4*i, t.writeTo(b))
+	}
+	return pos
+}
+
+func (s fbStructs) writeTo(b *fbBuilder) int {
+	// the structs hold 8 byte integers, so they start at a multiple of 8 after the length
+	b.pad(4)
+	if len(b.buf)%8 == 0 {
+		b.uint32(0)
+	}
+	pos := len(b.buf)
+	b.uint32(len(s) / 16)
+	b.buf = append(b.buf, s...)
+	return pos
+}
+
+func fbBool(v bool) []byte {
+	if v {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+func fbInt16(v int16) []byte {
+	b := make([]byte, 2)
+	binary.LittleEndian.PutUint16(b, uint16(v))
+	return b
+}
+
+func fbInt64(v int64) []byte {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, uint64(v))
+	return b
+}
@@ -0,0 +1,176 @@
+package regression
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+// arrowReader decodes the flatbuffers of an Arrow IPC stream, enough to check the output of ArrowWriter.
+type arrowReader struct {
+	t   *testing.T
+	buf []byte
+}
+
+func (a arrowReader) u32(pos int) int { return int(binary.LittleEndian.Uint32(a.buf[pos:])) }
+
+// field returns the position of field id of the table at pos, or -1 if it is absent.
+func (a arrowReader) field(pos, id int) int {
+	vtable := pos - int(int32(binary.LittleEndian.Uint32(a.buf[pos:])))
+	if 4+2*id >= int(binary.LittleEndian.Uint16(a.buf[vtable:])) {
+		return -1
+	}
+	off := int(binary.LittleEndian.Uint16(a.buf[vtable+4+2*id:]))
+	if off == 0 {
+		return -1
+	}
+	return pos + off
+}
+
+func (a arrowReader) ref(pos, id int) int {
+	f := a.field(pos, id)
+	if f < 0 {
+		a.t.Fatalf("missing field %d", id)
+	}
+	return f + a.u32(f)
+}
+
+func (a arrowReader) int64(pos int) int64 { return int64(binary.LittleEndian.Uint64(a.buf[pos:])) }
+
+type arrowMessage struct {
+	a      arrowReader
+	kind   byte
+	header int
+	body   []byte
+}
+
+func readArrowMessages(t *testing.T, stream []byte) []arrowMessage {
+	var messages []arrowMessage
+	for {
+		if binary.LittleEndian.Uint32(stream) != 0xffffffff {
+			t.Fatalf("Expected the continuation marker, got %x", stream[:4])
+		}
+		size := int(binary.LittleEndian.Uint32(stream[4:]))
+		if size == 0 {
+			if len(stream) != 8 {
+				t.Errorf("Expected the stream to end after the end marker, %d bytes left", len(stream)-8)
+			}
+			return messages
+		}
+		if size%8 != 0 {
+			t.Fatalf("Expected the metadata padded to 8 bytes, got %d", size)
+		}
+		a := arrowReader{t: t, buf: stream[8 : 8+size]}
+		root := a.u32(0)
+		if v := binary.LittleEndian.Uint16(a.buf[a.field(root, 0):]); v != arrowMetadataV5 {
+			t.Errorf("Expected metadata version V5, got %d", v)
+		}
+		m := arrowMessage{a: a, kind: a.buf[a.field(root, 1)], header: a.ref(root, 2)}
+		bodyLength := int(a.int64(a.field(root, 3)))
+		m.body = stream[8+size : 8+size+bodyLength]
+		messages = append(messages, m)
+		stream = stream[8+size+bodyLength:]
+	}
+}
+
+func TestArrowWriter(t *testing.T) {
+	r := carsRegression(t)
+	var buf bytes.Buffer
+	w, err := r.NewArrowWriter(&buf, ArrowOptions{Contributions: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	batches := [][][]float64{{{4}, {10}, {25}}, {{7.5}}}
+	for _, batch := range batches {
+		if err := w.Write(batch); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	messages := readArrowMessages(t, buf.Bytes())
+	if len(messages) != 3 || messages[0].kind != arrowHeaderSchema {
+		t.Fatalf("Expected a schema and two record batches, got %d messages", len(messages))
+	}
+	schema := messages[0]
+	a := schema.a
+	fields := a.ref(schema.header, 1)
+	want := []string{"speed", "predicted", "contribution:speed"}
+	if n := a.u32(fields); n != len(want) {
+		t.Fatalf("Expected %d fields, got %d", len(want), n)
+	}
+	for i, name := range want {
+		pos := fields + 4 + 4*i
+		field := pos + a.u32(pos)
+		s := a.ref(field, 0)
+		if got := string(a.buf[s+4 : s+4+a.u32(s)]); got != name {
+			t.Errorf("Field %d: expected %q, got %q", i, name, got)
+		}
+		if a.buf[a.field(field, 2)] != arrowTypeFloatingPoint {
+			t.Errorf("Field %d: expected a floating point type", i)
+		}
+		if p := binary.LittleEndian.Uint16(a.buf[a.field(a.ref(field, 3), 0):]); p != arrowPrecisionDouble {
+			t.Errorf("Field %d: expected double precision, got %d", i, p)
+		}
+		if children := a.ref(field, 5); a.u32(children) != 0 {
+			t.Errorf("Field %d: expected no children", i)
+		}
+	}
+
+	for k, m := range messages[1:] {
+		if m.kind != arrowHeaderRecordBatch {
+			t.Fatalf("Expected a record batch, got %d", m.kind)
+		}
+		a := m.a
+		batch := batches[k]
+		if n := a.int64(a.field(m.header, 0)); n != int64(len(batch)) {
+			t.Errorf("Expected %d rows, got %d", len(batch), n)
+		}
+		nodes, buffers := a.ref(m.header, 1), a.ref(m.header, 2)
+		if a.u32(nodes) != len(want) || a.u32(buffers) != 2*len(want) || (nodes+4)%8 != 0 || (buffers+4)%8 != 0 {
+			t.Fatalf("Unexpected nodes or buffers")
+		}
+		column := func(c int) []float64 {
+			offset, length := a.int64(buffers+4+16*(2*c+1)), a.int64(buffers+4+16*(2*c+1)+8)
+			if offset%8 != 0 || length != int64(8*len(batch)) {
+				t.Fatalf("Unexpected buffer at %d of %d bytes", offset, length)
+			}
+			values := make([]float64, len(batch))
+			for i := range values {
+				values[i] = math.Float64frombits(binary.LittleEndian.Uint64(m.body[int(offset)+8*i:]))
+			}
+			return values
+		}
+		speed, predicted, contribution := column(0), column(1), column(2)
+		for i, vars := range batch {
+			p, _ := r.Predict(vars)
+			if speed[i] != vars[0] || predicted[i] != p || contribution[i] != r.Coeff(1)*vars[0] {
+				t.Errorf("Row %d: got %v, %v and %v", i, speed[i], predicted[i], contribution[i])
+			}
+		}
+	}
+}
+
+func TestArrowWriterErrors(t *testing.T) {
+	if _, err := new(Regression).NewArrowWriter(new(bytes.Buffer), ArrowOptions{}); err != ErrNotRun {
+		t.Errorf("Expected ErrNotRun, got %v", err)
+	}
+	r := carsRegression(t)
+	var buf bytes.Buffer
+	w, err := r.NewArrowWriter(&buf, ArrowOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write([][]float64{{1, 2}}); err != ErrDimensions {
+		t.Errorf("Expected ErrDimensions, got %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if messages := readArrowMessages(t, buf.Bytes()); len(messages) != 1 {
+		t.Errorf("Expected the schema only, got %d messages", len(messages))
+	}
+}
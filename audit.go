@@ -0,0 +1,135 @@
+package regression
+
+import (
+	"encoding/json"
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// AuditRecord is a Predict call captured for the audit log.
+type AuditRecord struct {
+	Time time.Time `json:"time"`
+	// Model identifies the model version, see AuditOptions.
+	Model     string    `json:"model"`
+	Inputs    []float64 `json:"inputs"`
+	Predicted float64   `json:"predicted"`
+	// Error is the error returned by Predict, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// AuditSink receives the records of the audit log. Audit is called before Predict returns and
+// from every goroutine calling Predict, so it must be safe for concurrent use and should not block.
+type AuditSink interface {
+	Audit(AuditRecord)
+}
+
+// AuditFunc is an AuditSink calling a function.
+type AuditFunc func(AuditRecord)
+
+// Audit calls f(rec).
+func (f AuditFunc) Audit(rec AuditRecord) {
+	f(rec)
+}
+
+// AuditOptions configures the audit log set with SetAudit.
+type AuditOptions struct {
+	// SampleRate is the fraction of calls recorded, between 0 and 1. Zero records every call.
+	SampleRate float64
+	// Seed seeds the sampling, so the sampled calls can be reproduced.
+	Seed int64
+	// Version identifies the model in the records. By default it is the metadata value "version",
+	// see SetMetadata, or else the hash of the training data, see DataHash.
+	Version string
+	// Errors records failed calls regardless of the sample rate.
+	Errors bool
+}
+
+type auditor struct {
+	sink AuditSink
+	opts AuditOptions
+	mu   sync.Mutex
+	rng  *rand.Rand
+}
+
+// SetAudit records Predict calls, with their inputs, output, model version and time, to sink. Calls
+// of PredictPoint and the other methods predicting through Predict are recorded too. A nil sink stops
+// the audit log.
+func (r *Regression) SetAudit(sink AuditSink, opts AuditOptions) {
+	if sink == nil {
+		r.audit = nil
+		return
+	}
+	r.audit = &auditor{sink: sink, opts: opts, rng: rand.New(rand.NewSource(opts.Seed))}
+}
+
+func (a *auditor) record(r *Regression, vars []float64, p float64, err error) {
+	if !a.sampled(err) {
+		return
+	}
+	rec := AuditRecord{
+		Time:      time.Now(),
+		Model:     a.opts.Version,
+		Inputs:    append([]float64(nil), vars...),
+		Predicted: p,
+	}
+	if rec.Model == "" {
+		var ok bool
+		if rec.Model, ok = r.GetMetadata("version"); !ok {
+			rec.Model = r.DataHash()
+		}
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	a.sink.Audit(rec)
+}
+
+func (a *auditor) sampled(err error) bool {
+	if a.opts.SampleRate <= 0 || a.opts.SampleRate >= 1 || (err != nil && a.opts.Errors) {
+		return true
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.rng.Float64() < a.opts.SampleRate
+}
+
+// JSONAuditSink writes every record as a JSON line. It is safe for concurrent use.
+type JSONAuditSink struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	err error
+}
+
+// NewJSONAuditSink returns a sink writing JSON lines to w.
+func NewJSONAuditSink(w io.Writer) *JSONAuditSink {
+	return &JSONAuditSink{enc: json.NewEncoder(w)}
+}
+
+// Audit writes rec. Inputs and predictions that are not finite are written as null.
+func (s *JSONAuditSink) Audit(rec AuditRecord) {
+	out := auditJSON{AuditRecord: rec, Inputs: make([]*float64, len(rec.Inputs)), Predicted: finiteOrNil(rec.Predicted)}
+	for i, v := range rec.Inputs {
+		out.Inputs[i] = finiteOrNil(v)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return
+	}
+	s.err = s.enc.Encode(out)
+}
+
+// Err returns the first error writing a record; the records after it are dropped.
+func (s *JSONAuditSink) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+type auditJSON struct {
+	AuditRecord
+	Inputs    []*float64 `json:"inputs"`
+	Predicted *float64   `json:"predicted"`
+}
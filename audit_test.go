@@ -0,0 +1,100 @@
+package regression
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"math"
+	"sync"
+	"testing"
+)
+
+func TestAudit(t *testing.T) {
+	r := carsRegression(t)
+	r.SetMetadata("version", "v7")
+	var mu sync.Mutex
+	var records []AuditRecord
+	r.SetAudit(AuditFunc(func(rec AuditRecord) {
+		mu.Lock()
+		records = append(records, rec)
+		mu.Unlock()
+	}), AuditOptions{})
+
+	vars := []float64{10}
+	p, err := r.Predict(vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vars[0] = 99
+	if _, err := r.PredictPoint([]float64{12}); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+	rec := records[0]
+	if rec.Model != "v7" || rec.Inputs[0] != 10 || rec.Predicted != p || rec.Error != "" || rec.Time.IsZero() {
+		t.Errorf("Unexpected record %+v", rec)
+	}
+
+	r.DeleteMetadata("version")
+	r.Predict([]float64{1})
+	if records[2].Model != r.DataHash() {
+		t.Errorf("Expected the data hash as the model version, got %q", records[2].Model)
+	}
+
+	r.SetAudit(nil, AuditOptions{})
+	r.Predict([]float64{1})
+	if len(records) != 3 {
+		t.Errorf("Expected the audit log stopped, got %d records", len(records))
+	}
+}
+
+func TestAuditSampling(t *testing.T) {
+	r := carsRegression(t)
+	var n int
+	sink := AuditFunc(func(AuditRecord) { n++ })
+	r.SetAudit(sink, AuditOptions{SampleRate: 0.1, Seed: 3, Errors: true})
+	for i := 0; i < 2000; i++ {
+		r.Predict([]float64{float64(i % 25)})
+	}
+	if n < 150 || n > 250 {
+		t.Errorf("Expected about 200 sampled records, got %d", n)
+	}
+	n = 0
+	unfitted := new(Regression)
+	unfitted.SetAudit(sink, AuditOptions{SampleRate: 0.1, Errors: true})
+	for i := 0; i < 10; i++ {
+		unfitted.Predict([]float64{1})
+	}
+	if n != 10 {
+		t.Errorf("Expected every failed call recorded, got %d", n)
+	}
+}
+
+func TestJSONAuditSink(t *testing.T) {
+	r := carsRegression(t)
+	var buf bytes.Buffer
+	sink := NewJSONAuditSink(&buf)
+	r.SetAudit(sink, AuditOptions{Version: "pricing-2"})
+	r.Predict([]float64{10})
+	r.Predict([]float64{math.NaN()})
+	if err := sink.Err(); err != nil {
+		t.Fatal(err)
+	}
+	scanner := bufio.NewScanner(&buf)
+	var lines []map[string]interface{}
+	for scanner.Scan() {
+		var line map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) != 2 || lines[0]["model"] != "pricing-2" || lines[0]["predicted"] == nil {
+		t.Fatalf("Unexpected records %v", lines)
+	}
+	if inputs := lines[1]["inputs"].([]interface{}); inputs[0] != nil || lines[1]["predicted"] != nil {
+		t.Errorf("Expected nulls for NaN, got %v", lines[1])
+	}
+}
@@ -0,0 +1,208 @@
+package regression
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// AutoFitOptions configures AutoFit. The zero value is usable.
+type AutoFitOptions struct {
+	// Observed and Vars name the observed value and the variables of the data points.
+	Observed string
+	Vars     []string
+	// Folds is the number of cross-validation folds, 5 when zero.
+	Folds int
+	// MaxDegree is the highest power of the variables tried as feature crosses, 2 when zero.
+	// A degree of one only tries the variables themselves.
+	MaxDegree int
+	// Lambdas are the strengths of the ridge regularization tried besides the unregularized fit,
+	// 0.01, 0.1, 1, 10 and 100 when nil. Ridge fits normalize the variables with ZScore.
+	Lambdas []float64
+	// Seed seeds the assignment of the data points to folds.
+	Seed int64
+}
+
+// AutoFitCandidate is a model configuration evaluated by AutoFit.
+type AutoFitCandidate struct {
+	// Spec configures the model. Its variables are the Columns of the data points, in that order.
+	Spec    Spec
+	Columns []int
+	// CVRMSE is the root mean squared error of the predictions on the held out folds.
+	CVRMSE float64
+}
+
+// AutoFitResult is the outcome of AutoFit.
+type AutoFitResult struct {
+	// Model is the best candidate, fitted on all the data. It takes the variables in Best.Columns, see Select.
+	Model *Regression
+	Best  AutoFitCandidate
+	// Leaderboard holds every candidate that could be fitted, best first.
+	Leaderboard []AutoFitCandidate
+}
+
+// Select picks the variables used by the best model from the variables of a data point.
+func (a *AutoFitResult) Select(vars []float64) []float64 {
+	return selectColumns(vars, a.Best.Columns)
+}
+
+// AutoFit searches for a good baseline model of data: it tries polynomial feature crosses up to a degree,
+// ridge regularization strengths and, for the best of those, subsets of the variables found by backward
+// elimination, scoring every candidate by k-fold cross-validation. It returns the best candidate fitted
+// on all the data and the leaderboard of all candidates. The data points are not modified.
+func AutoFit(data DataPoints, opts AutoFitOptions) (*AutoFitResult, error) {
+	if len(data) < 3 {
+		return nil, ErrNotEnoughData
+	}
+	folds := opts.Folds
+	if folds <= 0 {
+		folds = 5
+	}
+	if folds > len(data) {
+		folds = len(data)
+	}
+	maxDegree := opts.MaxDegree
+	if maxDegree <= 0 {
+		maxDegree = 2
+	}
+	lambdas := opts.Lambdas
+	if lambdas == nil {
+		lambdas = []float64{0.01, 0.1, 1, 10, 100}
+	}
+	numVars := len(data[0].Variables)
+	for _, d := range data {
+		if len(d.Variables) != numVars {
+			return nil, ErrDimensions
+		}
+	}
+	fold := rand.New(rand.NewSource(opts.Seed)).Perm(len(data))
+	for i := range fold {
+		fold[i] %= folds
+	}
+
+	var leaderboard []AutoFitCandidate
+	evaluate := func(columns []int, degree int, lambda float64) (AutoFitCandidate, bool) {
+		c := AutoFitCandidate{Spec: autoSpec(opts, columns, degree, lambda), Columns: columns}
+		var sse, weights float64
+		for k := 0; k < folds; k++ {
+			var train, test DataPoints
+			for i, d := range data {
+				if fold[i] == k {
+					test = append(test, d)
+				} else {
+					train = append(train, d)
+				}
+			}
+			r, err := fitCandidate(c, train)
+			if err != nil {
+				return c, false
+			}
+			for _, d := range test {
+				p, err := r.Predict(selectColumns(d.Variables, columns))
+				if err != nil {
+					return c, false
+				}
+				sse += d.Weight * (p - d.Observed) * (p - d.Observed)
+				weights += d.Weight
+			}
+		}
+		c.CVRMSE = math.Sqrt(sse / weights)
+		if math.IsNaN(c.CVRMSE) {
+			return c, false
+		}
+		leaderboard = append(leaderboard, c)
+		return c, true
+	}
+
+	all := make([]int, numVars)
+	for i := range all {
+		all[i] = i
+	}
+	best := AutoFitCandidate{CVRMSE: math.Inf(1)}
+	var bestDegree int
+	var bestLambda float64
+	for degree := 1; degree <= maxDegree; degree++ {
+		for _, lambda := range append([]float64{0}, lambdas...) {
+			if c, ok := evaluate(all, degree, lambda); ok && c.CVRMSE < best.CVRMSE {
+				best, bestDegree, bestLambda = c, degree, lambda
+			}
+		}
+	}
+	if math.IsInf(best.CVRMSE, 1) {
+		return nil, ErrNotEnoughData
+	}
+
+	// backward elimination: drop the variable whose removal helps most, while any does
+	for len(best.Columns) > 1 {
+		improved := false
+		next := best
+		for drop := range best.Columns {
+			columns := append(append([]int(nil), best.Columns[:drop]...), best.Columns[drop+1:]...)
+			if c, ok := evaluate(columns, bestDegree, bestLambda); ok && c.CVRMSE < next.CVRMSE {
+				next, improved = c, true
+			}
+		}
+		if !improved {
+			break
+		}
+		best = next
+	}
+
+	model, err := fitCandidate(best, data)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(leaderboard, func(i, j int) bool { return leaderboard[i].CVRMSE < leaderboard[j].CVRMSE })
+	return &AutoFitResult{Model: model, Best: best, Leaderboard: leaderboard}, nil
+}
+
+// autoSpec describes a candidate of AutoFit.
+func autoSpec(opts AutoFitOptions, columns []int, degree int, lambda float64) Spec {
+	s := Spec{Observed: opts.Observed}
+	named := false
+	for _, c := range columns {
+		var name string
+		if c < len(opts.Vars) {
+			name = opts.Vars[c]
+			named = named || name != ""
+		}
+		s.Vars = append(s.Vars, name)
+	}
+	if !named {
+		s.Vars = nil
+	}
+	for d := 2; d <= degree; d++ {
+		for i := range columns {
+			s.Crosses = append(s.Crosses, CrossSpec{Type: "pow", Vars: []int{i}, Power: float64(d)})
+		}
+	}
+	if lambda > 0 {
+		s.Solver = "ridge"
+		s.Regularization = &RegularizationSpec{Lambda: lambda}
+		s.Normalization = "zscore"
+	}
+	return s
+}
+
+// fitCandidate fits a candidate of AutoFit on copies of the data points.
+func fitCandidate(c AutoFitCandidate, data DataPoints) (*Regression, error) {
+	r, err := c.Spec.Build()
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range data {
+		r.Train(WeightedDataPoint(d.Observed, selectColumns(d.Variables, c.Columns), d.Weight))
+	}
+	if err := r.Run(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func selectColumns(vars []float64, columns []int) []float64 {
+	selected := make([]float64, len(columns))
+	for i, c := range columns {
+		selected[i] = vars[c]
+	}
+	return selected
+}
@@ -0,0 +1,65 @@
+package regression
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestAutoFit(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	var data DataPoints
+	for i := 0; i < 120; i++ {
+		x, noise := 4*rng.Float64()-2, rng.NormFloat64()
+		data = append(data, DataPoint(1+2*x+3*x*x+0.3*rng.NormFloat64(), []float64{x, noise}))
+	}
+
+	result, err := AutoFit(data, AutoFitOptions{Observed: "y", Vars: []string{"x", "noise"}, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	best := result.Best
+	if len(best.Columns) != 1 || best.Columns[0] != 0 {
+		t.Errorf("Expected only x to be selected, got columns %v", best.Columns)
+	}
+	if len(best.Spec.Crosses) != 1 || best.Spec.Crosses[0].Power != 2 {
+		t.Errorf("Expected the square of x to be selected, got %+v", best.Spec.Crosses)
+	}
+	if best.CVRMSE > 0.4 {
+		t.Errorf("Expected a cross-validated RMSE close to the noise, got %v", best.CVRMSE)
+	}
+	// two degrees times six regularization strengths, then the eliminations
+	if len(result.Leaderboard) < 12 {
+		t.Fatalf("Expected at least 12 candidates, got %d", len(result.Leaderboard))
+	}
+	for i := 1; i < len(result.Leaderboard); i++ {
+		if result.Leaderboard[i].CVRMSE < result.Leaderboard[i-1].CVRMSE {
+			t.Errorf("Leaderboard not sorted at %d", i)
+		}
+	}
+	if result.Leaderboard[0].CVRMSE != best.CVRMSE {
+		t.Errorf("Expected the best candidate first, got %+v", result.Leaderboard[0])
+	}
+
+	got, err := result.Model.Predict(result.Select([]float64{1, 5}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "prediction", got, 6, 0.3)
+	if result.Model.GetVar(0) != "x" || result.Model.GetObserved() != "y" {
+		t.Errorf("Expected the model to keep the names, got %v", result.Model.Formula)
+	}
+	// the data points are left alone
+	if len(data[0].Variables) != 2 {
+		t.Errorf("Expected the data points unchanged, got %v", data[0].Variables)
+	}
+}
+
+func TestAutoFitErrors(t *testing.T) {
+	if _, err := AutoFit(DataPoints{DataPoint(1, []float64{1})}, AutoFitOptions{}); err != ErrNotEnoughData {
+		t.Errorf("Expected ErrNotEnoughData, got %v", err)
+	}
+	data := DataPoints{DataPoint(1, []float64{1}), DataPoint(2, []float64{2}), DataPoint(3, []float64{3, 4})}
+	if _, err := AutoFit(data, AutoFitOptions{}); err != ErrDimensions {
+		t.Errorf("Expected ErrDimensions, got %v", err)
+	}
+}
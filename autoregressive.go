@@ -0,0 +1,110 @@
+package regression
+
+import (
+	"math"
+	"strconv"
+)
+
+// forecastAlpha is the significance level of the bounds of PredictHorizon.
+const forecastAlpha = 0.05
+
+// Autoregressive is a time series model whose first Lags variables are the lagged observed values,
+// the most recent first, optionally followed by exogenous variables.
+type Autoregressive struct {
+	Model *Regression
+	Lags  int
+}
+
+// Forecast is the prediction for one step of a multi-step forecast, with the bounds of its 95%
+// prediction interval.
+type Forecast struct {
+	Step      int
+	Predicted float64
+	StdErr    float64
+	Lower     float64
+	Upper     float64
+}
+
+// NewAutoregressive wraps a fitted model whose first lags variables are the lagged observed values.
+// The model must not have feature crosses, whose effect on the lags isn't linear.
+func NewAutoregressive(r *Regression, lags int) (*Autoregressive, error) {
+	if !r.hasRun || len(r.coeff) == 0 {
+		return nil, ErrNotRun
+	}
+	if lags < 1 || lags > r.names.base || len(r.crosses) > 0 || r.split {
+		return nil, ErrDimensions
+	}
+	return &Autoregressive{Model: r, Lags: lags}, nil
+}
+
+// FitAutoregressive fits an autoregressive model of order lags to a series. When exog is not nil it holds
+// the exogenous variables at every point of the series, which follow the lags in the variables of the model.
+func FitAutoregressive(series []float64, lags int, exog [][]float64) (*Autoregressive, error) {
+	if lags < 1 {
+		return nil, ErrDimensions
+	}
+	if exog != nil && len(exog) != len(series) {
+		return nil, ErrDimensions
+	}
+	r := new(Regression)
+	for k := 0; k < lags; k++ {
+		r.SetVar(k, "lag"+strconv.Itoa(k+1))
+	}
+	for t := lags; t < len(series); t++ {
+		vars := make([]float64, lags, lags+len(series))
+		for k := range vars[:lags] {
+			vars[k] = series[t-k-1]
+		}
+		if exog != nil {
+			vars = append(vars, exog[t]...)
+		}
+		r.Train(DataPoint(series[t], vars))
+	}
+	if err := r.Run(); err != nil {
+		return nil, err
+	}
+	return &Autoregressive{Model: r, Lags: lags}, nil
+}
+
+// PredictHorizon forecasts the next steps from vars, the current lags followed by the exogenous
+// variables, by feeding every prediction back as the most recent lag. The exogenous variables are held
+// constant. The standard errors accumulate the residual noise of the earlier steps through the
+// autoregressive coefficients; the uncertainty of the coefficients themselves is not included.
+func (a *Autoregressive) PredictHorizon(vars []float64, steps int) ([]Forecast, error) {
+	if len(vars) != a.Model.names.base {
+		return nil, ErrDimensions
+	}
+	sigma := math.Sqrt(a.Model.sigma2)
+	critical := a.Model.CriticalT(forecastAlpha)
+
+	// psi[j] is the effect of the noise j steps back on the forecast
+	phi := make([]float64, a.Lags)
+	for k := range phi {
+		phi[k] = a.Model.Coeff(k + 1)
+	}
+	psi := make([]float64, steps)
+
+	current := append([]float64(nil), vars...)
+	forecasts := make([]Forecast, steps)
+	var variance float64
+	for h := 0; h < steps; h++ {
+		p, err := a.Model.Predict(current)
+		if err != nil {
+			return nil, err
+		}
+		psi[h] = 1
+		if h > 0 {
+			psi[h] = 0
+			for k := 1; k <= a.Lags && k <= h; k++ {
+				psi[h] += phi[k-1] * psi[h-k]
+			}
+		}
+		variance += psi[h] * psi[h]
+		se := sigma * math.Sqrt(variance)
+		forecasts[h] = Forecast{Step: h + 1, Predicted: p, StdErr: se, Lower: p - critical*se, Upper: p + critical*se}
+
+		copy(current[1:a.Lags], current[:a.Lags-1])
+		current[0] = p
+	}
+	return forecasts, nil
+}
@@ -0,0 +1,86 @@
+package regression
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestPredictHorizon(t *testing.T) {
+	// y[t] = 2 + 0.6 y[t-1] + 0.2 y[t-2] + e
+	rng := rand.New(rand.NewSource(2))
+	series := []float64{10, 10}
+	for t := 2; t < 3000; t++ {
+		series = append(series, 2+0.6*series[t-1]+0.2*series[t-2]+rng.NormFloat64())
+	}
+	ar, err := FitAutoregressive(series, 2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "phi1", ar.Model.Coeff(1), 0.6, 0.05)
+	assertClose(t, "phi2", ar.Model.Coeff(2), 0.2, 0.05)
+	if ar.Model.GetVar(1) != "lag2" {
+		t.Errorf("Unexpected name %q", ar.Model.GetVar(1))
+	}
+
+	forecasts, err := ar.PredictHorizon([]float64{12, 11}, 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, _ := ar.Model.Predict([]float64{12, 11})
+	assertClose(t, "first step", forecasts[0].Predicted, first, 1e-12)
+	second, _ := ar.Model.Predict([]float64{first, 12})
+	assertClose(t, "second step", forecasts[1].Predicted, second, 1e-12)
+	sigma := math.Sqrt(ar.Model.sigma2)
+	assertClose(t, "first step error", forecasts[0].StdErr, sigma, 1e-12)
+	assertClose(t, "second step error", forecasts[1].StdErr, sigma*math.Sqrt(1+ar.Model.Coeff(1)*ar.Model.Coeff(1)), 1e-12)
+	for h := 1; h < len(forecasts); h++ {
+		if forecasts[h].StdErr < forecasts[h-1].StdErr || forecasts[h].Step != h+1 {
+			t.Errorf("Expected growing uncertainty at step %d, got %+v", h+1, forecasts[h])
+		}
+	}
+	// far out the forecast reverts to the mean 2 / (1 - 0.8) = 10 and the error to the
+	// unconditional standard deviation of the series
+	last := forecasts[len(forecasts)-1]
+	assertClose(t, "long run mean", last.Predicted, 10, 0.5)
+	var mean, sq float64
+	for _, y := range series {
+		mean += y
+		sq += y * y
+	}
+	mean /= float64(len(series))
+	assertClose(t, "long run error", last.StdErr, math.Sqrt(sq/float64(len(series))-mean*mean), 0.2)
+	if !(last.Lower < last.Predicted && last.Predicted < last.Upper) {
+		t.Errorf("Unexpected bounds %+v", last)
+	}
+}
+
+func TestPredictHorizonExogenous(t *testing.T) {
+	series := make([]float64, 50)
+	exog := make([][]float64, 50)
+	for t := range series {
+		exog[t] = []float64{float64(t % 2)}
+		if t > 0 {
+			series[t] = 0.5*series[t-1] + 3*exog[t][0] + 0.01*float64(t%3)
+		}
+	}
+	ar, err := FitAutoregressive(series, 1, exog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forecasts, err := ar.PredictHorizon([]float64{4, 1}, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "exogenous forecast", forecasts[2].Predicted, 0.125*4+3*(1+0.5+0.25), 0.05)
+
+	if _, err := ar.PredictHorizon([]float64{4}, 3); err != ErrDimensions {
+		t.Errorf("Expected ErrDimensions, got %v", err)
+	}
+	if _, err := NewAutoregressive(carsRegression(t), 2); err != ErrDimensions {
+		t.Errorf("Expected ErrDimensions, got %v", err)
+	}
+	if _, err := FitAutoregressive(series, 1, exog[:3]); err != ErrDimensions {
+		t.Errorf("Expected ErrDimensions, got %v", err)
+	}
+}
@@ -0,0 +1,262 @@
+package regression
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// ErrInvalidPrior signals a prior with a negative precision, shape or rate.
+var ErrInvalidPrior = errors.New("prior precision, shape and rate must not be negative")
+
+// Prior is a conjugate normal-inverse-gamma prior on the coefficients and the residual variance:
+// sigma^2 ~ InvGamma(Shape, Rate) and, given sigma^2, the coefficients are independent normals with
+// the given Mean and variances sigma^2/Precision. The zero value is the flat, improper prior, for which
+// the posterior mean equals the least squares estimate.
+type Prior struct {
+	// Mean is the prior mean of the coefficients, the offset first, followed by the variables and
+	// feature crosses. It defaults to zero.
+	Mean []float64
+	// Precision holds the prior precision of every coefficient relative to the residual variance.
+	// It defaults to zero, a flat prior. A precision of lambda on all but the offset gives the ridge estimate.
+	Precision []float64
+	Shape     float64
+	Rate      float64
+}
+
+// posterior is the normal-inverse-gamma posterior of a model fitted with a prior.
+type posterior struct {
+	mean  []float64
+	cov   *mat.Dense // covariance of the coefficients given sigma^2 = 1
+	chol  [][]float64
+	shape float64
+	rate  float64
+}
+
+// SetPrior makes Run fit a Bayesian linear regression with a conjugate prior. The coefficients are the
+// posterior means, and the standard errors and residual variance are posterior expectations. Use
+// CredibleInterval, SampleCoeffs and SamplePredict to work with the posterior distribution, which is
+// not saved with the model.
+// Normalization, custom solvers, instruments, sign constraints, censoring and per-segment models are not
+// supported together with a prior.
+func (r *Regression) SetPrior(p Prior) error {
+	for _, v := range p.Precision {
+		if v < 0 {
+			return ErrInvalidPrior
+		}
+	}
+	if p.Shape < 0 || p.Rate < 0 {
+		return ErrInvalidPrior
+	}
+	r.prior = &p
+	return nil
+}
+
+// solve computes the posterior for the weighted design matrix x and observed values y of n observations.
+func (p *Prior) solve(x, y *mat.Dense, n int) ([]float64, *Diagnostics, *posterior, error) {
+	_, cols := x.Dims()
+	if (p.Mean != nil && len(p.Mean) != cols) || (p.Precision != nil && len(p.Precision) != cols) {
+		return nil, nil, nil, ErrDimensions
+	}
+	mean := make([]float64, cols)
+	copy(mean, p.Mean)
+	precision := make([]float64, cols)
+	copy(precision, p.Precision)
+
+	// Lambda_n = X'X + Lambda_0, mu_n = Lambda_n^-1 (X'y + Lambda_0 mu_0)
+	xtx := new(mat.Dense)
+	xtx.Mul(x.T(), x)
+	xty := new(mat.Dense)
+	xty.Mul(x.T(), y)
+	a := mat.NewDense(cols, cols, nil)
+	a.Copy(xtx)
+	b := make([]float64, cols)
+	var prior float64
+	for j := 0; j < cols; j++ {
+		a.Set(j, j, a.At(j, j)+precision[j])
+		b[j] = xty.At(j, 0) + precision[j]*mean[j]
+		prior += precision[j] * mean[j] * mean[j]
+	}
+	cov := new(mat.Dense)
+	if err := cov.Inverse(a); err != nil {
+		return nil, nil, nil, err
+	}
+	coeffs := make([]float64, cols)
+	var fitted float64
+	for i := range coeffs {
+		for j := range b {
+			coeffs[i] += cov.At(i, j) * b[j]
+		}
+		fitted += coeffs[i] * b[i]
+	}
+	var yty float64
+	rows, _ := y.Dims()
+	for i := 0; i < rows; i++ {
+		yty += y.At(i, 0) * y.At(i, 0)
+	}
+
+	post := &posterior{
+		mean:  coeffs,
+		cov:   cov,
+		shape: p.Shape + float64(n)/2,
+		rate:  p.Rate + (yty+prior-fitted)/2,
+	}
+	chol, ok := cholesky(cov)
+	if !ok || !(post.rate > 0) {
+		return nil, nil, nil, ErrSingular
+	}
+	post.chol = chol
+	return coeffs, &Diagnostics{Unscaled: cov}, post, nil
+}
+
+// bayesInference replaces the least squares estimates of calcInference by posterior expectations.
+// Given the data, the coefficients follow a multivariate t distribution with 2*shape degrees of freedom.
+func (r *Regression) bayesInference(post *posterior) {
+	r.posterior = post
+	r.sigma2 = math.NaN()
+	r.covariance = nil
+	if post.shape > 1 {
+		r.sigma2 = post.rate / (post.shape - 1)
+		r.covariance = new(mat.Dense)
+		r.covariance.Scale(r.sigma2, post.cov)
+	}
+}
+
+// CredibleInterval returns the central credible interval with probability 1-alpha of coefficient i
+// of a model fitted with a prior.
+func (r *Regression) CredibleInterval(i int, alpha float64) (lo, hi float64, err error) {
+	if r.posterior == nil {
+		return 0, 0, ErrNotRun
+	}
+	if i < 0 || i >= len(r.posterior.mean) {
+		return 0, 0, ErrDimensions
+	}
+	post := r.posterior
+	scale := math.Sqrt(post.rate / post.shape * post.cov.At(i, i))
+	t := r.distributions().StudentsT(2 * post.shape).Quantile(1 - alpha/2)
+	return post.mean[i] - t*scale, post.mean[i] + t*scale, nil
+}
+
+// SampleCoeffs draws n samples of the coefficients from the posterior of a model fitted with a prior,
+// using the model's source of randomness.
+func (r *Regression) SampleCoeffs(n int) ([][]float64, error) {
+	if r.posterior == nil {
+		return nil, ErrNotRun
+	}
+	samples := make([][]float64, n)
+	for s := range samples {
+		samples[s], _ = r.posterior.sample(r)
+	}
+	return samples, nil
+}
+
+// SamplePredict draws n samples from the posterior predictive distribution of the observed value for vars,
+// which accounts for both the uncertainty of the coefficients and the residual noise, e.g. as input to a
+// Monte Carlo simulation. Feature crosses are applied to vars as in Predict.
+func (r *Regression) SamplePredict(vars []float64, n int) ([]float64, error) {
+	if r.posterior == nil {
+		return nil, ErrNotRun
+	}
+	row := r.designRow(vars)
+	if len(row) != len(r.posterior.mean) {
+		return nil, ErrDimensions
+	}
+	samples := make([]float64, n)
+	for s := range samples {
+		coeffs, sigma2 := r.posterior.sample(r)
+		samples[s] = dot(coeffs, row) + math.Sqrt(sigma2)*r.random().NormFloat64()
+	}
+	return samples, nil
+}
+
+// sample draws the residual variance and then the coefficients given the variance.
+func (p *posterior) sample(r *Regression) ([]float64, float64) {
+	rng := r.random()
+	sigma2 := p.rate / gammaSample(rng.NormFloat64, rng.Float64, p.shape)
+	z := make([]float64, len(p.mean))
+	for i := range z {
+		z[i] = rng.NormFloat64()
+	}
+	coeffs := make([]float64, len(p.mean))
+	sigma := math.Sqrt(sigma2)
+	for i := range coeffs {
+		coeffs[i] = p.mean[i]
+		for k := 0; k <= i; k++ {
+			coeffs[i] += sigma * p.chol[i][k] * z[k]
+		}
+	}
+	return coeffs, sigma2
+}
+
+// gammaSample draws from the gamma distribution with the given shape and a rate of one using the method
+// of Marsaglia and Tsang.
+func gammaSample(norm, uniform func() float64, shape float64) float64 {
+	if shape < 1 {
+		// boost the shape and scale the sample down by U^(1/shape)
+		return gammaSample(norm, uniform, shape+1) * math.Pow(uniform(), 1/shape)
+	}
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := norm()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := uniform()
+		if math.Log(u) < x*x/2+d-d*v+d*math.Log(v) {
+			return d * v
+		}
+	}
+}
+
+// cholesky returns the lower triangular Cholesky factor of a symmetric positive definite matrix.
+func cholesky(a *mat.Dense) ([][]float64, bool) {
+	n, _ := a.Dims()
+	l := make([][]float64, n)
+	for i := range l {
+		l[i] = make([]float64, n)
+		for j := 0; j <= i; j++ {
+			v := a.At(i, j)
+			for k := 0; k < j; k++ {
+				v -= l[i][k] * l[j][k]
+			}
+			if i == j {
+				if v <= 0 {
+					return nil, false
+				}
+				l[i][i] = math.Sqrt(v)
+			} else {
+				l[i][j] = v / l[j][j]
+			}
+		}
+	}
+	return l, true
+}
+
+// ShrinkToward sets a prior centred on the coefficients of prev, a model fitted before on older data,
+// so retraining moves the coefficients smoothly rather than jumping with every new batch of data.
+// The prior precision of each coefficient is strength times the precision prev estimated it with:
+// a strength of 1 weighs the previous estimates about as much as the data they were fitted on
+// (ignoring their correlations), 0.1 a tenth of that. Coefficients prev left out of its fit are not shrunk.
+// The new model must have the same variables and feature crosses as prev.
+func (r *Regression) ShrinkToward(prev *Regression, strength float64) error {
+	if !prev.hasRun || len(prev.coeff) == 0 {
+		return ErrNotRun
+	}
+	if prev.covariance == nil || !(prev.sigma2 > 0) {
+		return ErrNoStatistics
+	}
+	if !(strength >= 0) {
+		return ErrInvalidPrior
+	}
+	p := Prior{Mean: prev.coeffs(), Precision: make([]float64, len(prev.coeff))}
+	for j := range p.Precision {
+		if v := prev.covariance.At(j, j); v > 0 {
+			p.Precision[j] = strength * prev.sigma2 / v
+		}
+	}
+	return r.SetPrior(p)
+}
@@ -0,0 +1,167 @@
+package regression
+
+import (
+	"math"
+	"testing"
+)
+
+func carsBayes(t *testing.T, p Prior) *Regression {
+	r := new(Regression)
+	if err := r.SetPrior(p); err != nil {
+		t.Fatal(err)
+	}
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestSetPrior(t *testing.T) {
+	ols := carsRegression(t)
+
+	// with a flat prior the posterior mean is the least squares estimate
+	flat := carsBayes(t, Prior{})
+	for i := 0; i < 2; i++ {
+		assertClose(t, "flat prior", flat.Coeff(i), ols.Coeff(i), 1e-9)
+	}
+	lo, hi, err := flat.CredibleInterval(1, 0.05)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !(lo < flat.Coeff(1) && flat.Coeff(1) < hi) {
+		t.Errorf("Expected the credible interval to contain the coefficient, got [%v, %v]", lo, hi)
+	}
+	// with 2*shape = n degrees of freedom the interval is close to the confidence interval
+	assertClose(t, "interval width", hi-lo, 2*ols.CriticalT(0.05)*ols.StdErr(1), 0.1)
+
+	// a precision on the slope only is ridge regression
+	ridge := new(Regression)
+	ridge.SetSolver(RidgeSolver{Lambda: 500})
+	for i := range carsSpeed {
+		ridge.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	if err := ridge.Run(); err != nil {
+		t.Fatal(err)
+	}
+	shrunk := carsBayes(t, Prior{Precision: []float64{0, 500}})
+	for i := 0; i < 2; i++ {
+		assertClose(t, "ridge prior", shrunk.Coeff(i), ridge.Coeff(i), 1e-9)
+	}
+
+	// a strong prior dominates the data
+	strong := carsBayes(t, Prior{Mean: []float64{0, 1}, Precision: []float64{1e9, 1e9}, Shape: 2, Rate: 1})
+	assertClose(t, "strong prior", strong.Coeff(1), 1, 1e-4)
+}
+
+func TestSamplePredict(t *testing.T) {
+	r := carsBayes(t, Prior{})
+	r.SetSeed(3)
+
+	coeffs, err := r.SampleCoeffs(20000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mean, sq float64
+	for _, c := range coeffs {
+		mean += c[1]
+		sq += c[1] * c[1]
+	}
+	mean /= float64(len(coeffs))
+	assertClose(t, "sampled slope", mean, r.Coeff(1), 0.02)
+	assertClose(t, "sampled slope sd", math.Sqrt(sq/float64(len(coeffs))-mean*mean), r.StdErr(1), 0.02)
+
+	samples, err := r.SamplePredict([]float64{15}, 20000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := r.Predict([]float64{15})
+	se, _ := r.PredictSE([]float64{15})
+	mean, sq = 0, 0
+	for _, s := range samples {
+		mean += s
+		sq += s * s
+	}
+	mean /= float64(len(samples))
+	sd := math.Sqrt(sq/float64(len(samples)) - mean*mean)
+	assertClose(t, "predictive mean", mean, want, 0.5)
+	assertClose(t, "predictive sd", sd, math.Sqrt(r.sigma2+se*se), 0.5)
+}
+
+func TestSetPriorErrors(t *testing.T) {
+	r := new(Regression)
+	if err := r.SetPrior(Prior{Precision: []float64{-1}}); err != ErrInvalidPrior {
+		t.Errorf("Expected ErrInvalidPrior, got %v", err)
+	}
+	if err := r.SetPrior(Prior{Shape: -1}); err != ErrInvalidPrior {
+		t.Errorf("Expected ErrInvalidPrior, got %v", err)
+	}
+	if _, err := r.SamplePredict([]float64{1}, 1); err != ErrNotRun {
+		t.Errorf("Expected ErrNotRun, got %v", err)
+	}
+	if _, _, err := carsRegression(t).CredibleInterval(1, 0.05); err != ErrNotRun {
+		t.Errorf("Expected ErrNotRun without a prior, got %v", err)
+	}
+
+	if err := r.SetPrior(Prior{Mean: []float64{1, 2, 3}}); err != nil {
+		t.Fatal(err)
+	}
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	if err := r.Run(); err != ErrDimensions {
+		t.Errorf("Expected ErrDimensions, got %v", err)
+	}
+
+	r = new(Regression)
+	if err := r.SetPrior(Prior{}); err != nil {
+		t.Fatal(err)
+	}
+	r.SetNormalization(ZScore)
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	if err := r.Run(); err != ErrUnsupported {
+		t.Errorf("Expected ErrUnsupported, got %v", err)
+	}
+}
+
+func TestShrinkToward(t *testing.T) {
+	prev := carsRegression(t)
+
+	// refit on the second half of the data only, with and without shrinkage
+	fit := func(strength float64) *Regression {
+		r := new(Regression)
+		if strength > 0 {
+			if err := r.ShrinkToward(prev, strength); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for i := len(carsSpeed) / 2; i < len(carsSpeed); i++ {
+			r.Train(DataPoint(carsDist[i]+20, []float64{carsSpeed[i]}))
+		}
+		if err := r.Run(); err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+	free, light, heavy := fit(0), fit(0.1), fit(1000)
+	for i := 0; i < 2; i++ {
+		jump := math.Abs(free.Coeff(i) - prev.Coeff(i))
+		if d := math.Abs(light.Coeff(i) - prev.Coeff(i)); !(d < jump) {
+			t.Errorf("Expected coefficient %d to move less than %v with shrinkage, got %v", i, jump, d)
+		}
+		if d := math.Abs(heavy.Coeff(i) - prev.Coeff(i)); !(d < 0.1*jump) {
+			t.Errorf("Expected coefficient %d to stay close to the previous model, moved %v of %v", i, d, jump)
+		}
+	}
+
+	if err := new(Regression).ShrinkToward(new(Regression), 1); err != ErrNotRun {
+		t.Errorf("Expected ErrNotRun, got %v", err)
+	}
+	if err := new(Regression).ShrinkToward(prev, -1); err != ErrInvalidPrior {
+		t.Errorf("Expected ErrInvalidPrior, got %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrInvalidBench signals benchmark parameters that are not positive.
+var ErrInvalidBench = errors.New("number of predictions and parallelism must be positive")
+
+// BenchResult is the prediction throughput and latency of a model measured by Bench.
+type BenchResult struct {
+	Predictions int
+	Parallelism int
+	// Duration is the wall time of the benchmark.
+	Duration time.Duration
+	// Throughput is the number of predictions per second.
+	Throughput float64
+	P50        time.Duration
+	P90        time.Duration
+	P99        time.Duration
+	Max        time.Duration
+	latencies  []time.Duration
+}
+
+// Percentile returns the latency below which fraction p of the predictions completed, for p between 0 and 1.
+func (b *BenchResult) Percentile(p float64) time.Duration {
+	if len(b.latencies) == 0 {
+		return 0
+	}
+	i := int(math.Ceil(p*float64(len(b.latencies)))) - 1
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(b.latencies) {
+		i = len(b.latencies) - 1
+	}
+	return b.latencies[i]
+}
+
+// Histogram counts the latencies in buckets bounded above by bounds, which must be sorted. counts[i] is
+// the number of predictions with a latency up to bounds[i] and above the previous bound, and the last
+// count holds the latencies above the last bound, so len(counts) is len(bounds)+1.
+func (b *BenchResult) Histogram(bounds []time.Duration) []int {
+	counts := make([]int, len(bounds)+1)
+	for _, l := range b.latencies {
+		counts[sort.Search(len(bounds), func(i int) bool { return l <= bounds[i] })]++
+	}
+	return counts
+}
+
+// Bench measures the prediction throughput and latency of the fitted model, making n predictions from
+// parallelism goroutines, e.g. to plan the capacity of a scoring service. The predictions cycle through
+// the variables of the training data, or use zeros if the model holds none. The latency of each prediction
+// includes applying feature crosses and choosing the segment, as in Predict.
+func (r *Regression) Bench(n, parallelism int) (*BenchResult, error) {
+	if n <= 0 || parallelism <= 0 {
+		return nil, ErrInvalidBench
+	}
+	if !r.hasRun || len(r.coeff) == 0 {
+		return nil, ErrNotRun
+	}
+	inputs := make([][]float64, 0, len(r.data))
+	for _, d := range r.data {
+		// the training data has the feature crosses appended to the base variables
+		inputs = append(inputs, d.Variables[:r.names.base])
+	}
+	if len(inputs) == 0 {
+		inputs = append(inputs, make([]float64, r.names.base))
+	}
+	if _, err := r.Predict(inputs[0]); err != nil {
+		return nil, err
+	}
+
+	latencies := make([]time.Duration, n)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for w := 0; w < parallelism; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < n; i += parallelism {
+				t := time.Now()
+				r.Predict(inputs[i%len(inputs)])
+				latencies[i] = time.Since(t)
+			}
+		}(w)
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	b := &BenchResult{
+		Predictions: n,
+		Parallelism: parallelism,
+		Duration:    elapsed,
+		Throughput:  float64(n) / elapsed.Seconds(),
+		latencies:   latencies,
+	}
+	b.P50, b.P90, b.P99 = b.Percentile(0.5), b.Percentile(0.9), b.Percentile(0.99)
+	b.Max = latencies[n-1]
+	return b, nil
+}
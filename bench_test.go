@@ -0,0 +1,35 @@
+package regression
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBench(t *testing.T) {
+	r := carsRegression(t)
+	b, err := r.Bench(1000, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Predictions != 1000 || b.Parallelism != 4 || b.Throughput <= 0 {
+		t.Errorf("Unexpected result %+v", b)
+	}
+	if !(b.P50 <= b.P90 && b.P90 <= b.P99 && b.P99 <= b.Max) {
+		t.Errorf("Expected ordered percentiles, got %v %v %v %v", b.P50, b.P90, b.P99, b.Max)
+	}
+	if b.Percentile(1) != b.Max || b.Percentile(0) > b.P50 {
+		t.Errorf("Unexpected percentiles %v and %v", b.Percentile(0), b.Percentile(1))
+	}
+
+	counts := b.Histogram([]time.Duration{b.P50, b.Max})
+	if len(counts) != 3 || counts[0] < 500 || counts[0]+counts[1] != 1000 || counts[2] != 0 {
+		t.Errorf("Unexpected histogram %v", counts)
+	}
+
+	if _, err := r.Bench(0, 1); err != ErrInvalidBench {
+		t.Errorf("Expected ErrInvalidBench, got %v", err)
+	}
+	if _, err := new(Regression).Bench(1, 1); err != ErrNotRun {
+		t.Errorf("Expected ErrNotRun, got %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package regression
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Blended is a composite predictor averaging the predictions of several models, e.g. models fitted
+// per data source combined into one scorer. It can be saved and loaded with encoding/json.
+type Blended struct {
+	Models []*Regression `json:"models"`
+	// Weights of the models, summing to one.
+	Weights []float64 `json:"weights"`
+}
+
+// Blend combines fitted models into a weighted average of their predictions. The weights are
+// normalized to sum to one; nil weights the models equally. All models take the same variables.
+func Blend(models []*Regression, weights []float64) (*Blended, error) {
+	if len(models) == 0 {
+		return nil, ErrNotEnoughData
+	}
+	if weights == nil {
+		weights = make([]float64, len(models))
+		for i := range weights {
+			weights[i] = 1
+		}
+	}
+	if len(weights) != len(models) {
+		return nil, ErrDimensions
+	}
+	for _, m := range models {
+		if !m.hasRun || len(m.coeff) == 0 {
+			return nil, ErrNotRun
+		}
+	}
+	if !allFinite(weights) {
+		return nil, ErrNonFinite
+	}
+	var sum float64
+	for _, w := range weights {
+		sum += w
+	}
+	if sum == 0 {
+		return nil, ErrInvalidWeight
+	}
+	b := &Blended{Models: append([]*Regression(nil), models...), Weights: make([]float64, len(weights))}
+	for i, w := range weights {
+		b.Weights[i] = w / sum
+	}
+	return b, nil
+}
+
+// FitBlend combines fitted models like Blend, with weights fitted by least squares on holdout data
+// that none of the models was trained on, constrained to sum to one. Weights can be negative when
+// models are strongly correlated.
+func FitBlend(models []*Regression, holdout []*dataPoint) (*Blended, error) {
+	if len(models) == 0 {
+		return nil, ErrNotEnoughData
+	}
+	if len(models) == 1 {
+		return Blend(models, nil)
+	}
+	if len(holdout) < len(models) {
+		return nil, ErrNotEnoughData
+	}
+	predictions := make([][]float64, len(holdout))
+	for i, d := range holdout {
+		predictions[i] = make([]float64, len(models))
+		for k, m := range models {
+			p, err := m.Predict(d.Variables)
+			if err != nil {
+				return nil, err
+			}
+			predictions[i][k] = p
+		}
+	}
+
+	// with the last weight 1 minus the others, regress y - p_last on p_k - p_last
+	last := len(models) - 1
+	x := mat.NewDense(len(holdout), last, nil)
+	y := mat.NewDense(len(holdout), 1, nil)
+	for i, d := range holdout {
+		w := math.Sqrt(d.Weight)
+		y.Set(i, 0, w*(d.Observed-predictions[i][last]))
+		for k := 0; k < last; k++ {
+			x.Set(i, k, w*(predictions[i][k]-predictions[i][last]))
+		}
+	}
+	c, _, err := QRSolver{}.Solve(x, y)
+	if err != nil {
+		return nil, err
+	}
+	weights := append(c, 1)
+	for _, w := range c {
+		weights[last] -= w
+	}
+	return Blend(models, weights)
+}
+
+// Predict returns the weighted average of the predictions of the models for vars.
+func (b *Blended) Predict(vars []float64) (float64, error) {
+	var p float64
+	for i, m := range b.Models {
+		v, err := m.Predict(vars)
+		if err != nil {
+			return 0, err
+		}
+		p += b.Weights[i] * v
+	}
+	return p, nil
+}
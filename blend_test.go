@@ -0,0 +1,96 @@
+package regression
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+)
+
+func TestBlend(t *testing.T) {
+	a, b := new(Regression), new(Regression)
+	for i := 0; i < 10; i++ {
+		x := float64(i)
+		a.Train(DataPoint(2*x, []float64{x}))
+		b.Train(DataPoint(4*x+1, []float64{x}))
+	}
+	for _, r := range []*Regression{a, b} {
+		if err := r.Run(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	blended, err := Blend([]*Regression{a, b}, []float64{3, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := blended.Predict([]float64{2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "weighted average", got, 0.75*4+0.25*9, 1e-9)
+
+	equal, err := Blend([]*Regression{a, b}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ = equal.Predict([]float64{2})
+	assertClose(t, "equal weights", got, 6.5, 1e-9)
+
+	data, err := json.Marshal(blended)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded Blended
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	got, err = loaded.Predict([]float64{2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "loaded blend", got, 0.75*4+0.25*9, 1e-9)
+
+	if _, err := Blend([]*Regression{a, b}, []float64{1}); err != ErrDimensions {
+		t.Errorf("Expected ErrDimensions, got %v", err)
+	}
+	if _, err := Blend([]*Regression{a, b}, []float64{1, -1}); err != ErrInvalidWeight {
+		t.Errorf("Expected ErrInvalidWeight, got %v", err)
+	}
+	if _, err := Blend([]*Regression{a, new(Regression)}, nil); err != ErrNotRun {
+		t.Errorf("Expected ErrNotRun, got %v", err)
+	}
+}
+
+func TestFitBlend(t *testing.T) {
+	// two models that miss the truth 3x in opposite directions blend best at 1:2
+	a, b := new(Regression), new(Regression)
+	for i := 0; i < 10; i++ {
+		x := float64(i)
+		a.Train(DataPoint(5*x, []float64{x}))
+		b.Train(DataPoint(2*x, []float64{x}))
+	}
+	for _, r := range []*Regression{a, b} {
+		if err := r.Run(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rng := rand.New(rand.NewSource(1))
+	var holdout []*dataPoint
+	for i := 0; i < 50; i++ {
+		x := 10 * rng.Float64()
+		holdout = append(holdout, DataPoint(3*x, []float64{x}))
+	}
+
+	blended, err := FitBlend([]*Regression{a, b}, holdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "first weight", blended.Weights[0], 1.0/3, 1e-9)
+	assertClose(t, "second weight", blended.Weights[1], 2.0/3, 1e-9)
+	got, _ := blended.Predict([]float64{7})
+	assertClose(t, "blended prediction", got, 21, 1e-9)
+
+	if _, err := FitBlend([]*Regression{a, b}, holdout[:1]); err != ErrNotEnoughData {
+		t.Errorf("Expected ErrNotEnoughData, got %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package regression
+
+import (
+	"errors"
+	"sort"
+)
+
+// ErrInvalidBins signals that a report was requested with fewer than one bin.
+var ErrInvalidBins = errors.New("number of bins must be positive")
+
+// CalibrationBin summarizes the data points in one quantile bin of the predictions.
+type CalibrationBin struct {
+	Count         int
+	MinPredicted  float64
+	MaxPredicted  float64
+	MeanPredicted float64
+	MeanObserved  float64
+}
+
+// CalibrationReport compares predicted and observed values. Slope and Intercept come from regressing
+// the observed values on the predictions; a well calibrated model has a slope of 1 and an intercept of 0.
+type CalibrationReport struct {
+	Bins      []CalibrationBin
+	Slope     float64
+	Intercept float64
+}
+
+// Calibration buckets the training data into quantile bins of the predictions and reports the mean
+// observed and predicted value per bin. An unregularized least squares fit is perfectly calibrated on
+// its own training data, so use CalibrationFor to evaluate it on a holdout set.
+func (r *Regression) Calibration(bins int) (*CalibrationReport, error) {
+	if !r.hasRun {
+		return nil, ErrNotRun
+	}
+	return calibrate(r.data, bins)
+}
+
+// CalibrationFor predicts the given data points and reports their calibration, see Calibration.
+// The Predicted and Error fields of the data points are updated.
+func (r *Regression) CalibrationFor(d []*dataPoint, bins int) (*CalibrationReport, error) {
+	if !r.hasRun {
+		return nil, ErrNotRun
+	}
+	for _, p := range d {
+		var err error
+		if p.Predicted, err = r.Predict(p.Variables); err != nil {
+			return nil, err
+		}
+		p.Error = p.Predicted - p.Observed
+	}
+	return calibrate(d, bins)
+}
+
+func calibrate(d []*dataPoint, bins int) (*CalibrationReport, error) {
+	if bins < 1 {
+		return nil, ErrInvalidBins
+	}
+	if len(d) == 0 {
+		return nil, ErrNotEnoughData
+	}
+	if bins > len(d) {
+		bins = len(d)
+	}
+	points := make([]*dataPoint, len(d))
+	copy(points, d)
+	sort.SliceStable(points, func(i, j int) bool { return points[i].Predicted < points[j].Predicted })
+
+	report := &CalibrationReport{Bins: make([]CalibrationBin, bins)}
+	for b := range report.Bins {
+		group := points[b*len(points)/bins : (b+1)*len(points)/bins]
+		var weights, predicted, observed float64
+		for _, p := range group {
+			weights += p.Weight
+			predicted += p.Weight * p.Predicted
+			observed += p.Weight * p.Observed
+		}
+		report.Bins[b] = CalibrationBin{
+			Count:         len(group),
+			MinPredicted:  group[0].Predicted,
+			MaxPredicted:  group[len(group)-1].Predicted,
+			MeanPredicted: predicted / weights,
+			MeanObserved:  observed / weights,
+		}
+	}
+
+	var weights, mp, mo float64
+	for _, p := range points {
+		weights += p.Weight
+		mp += p.Weight * p.Predicted
+		mo += p.Weight * p.Observed
+	}
+	mp /= weights
+	mo /= weights
+	var spp, spo float64
+	for _, p := range points {
+		spp += p.Weight * (p.Predicted - mp) * (p.Predicted - mp)
+		spo += p.Weight * (p.Predicted - mp) * (p.Observed - mo)
+	}
+	report.Slope = spo / spp
+	report.Intercept = mo - report.Slope*mp
+	return report, nil
+}
@@ -0,0 +1,56 @@
+package regression
+
+import "testing"
+
+func TestCalibration(t *testing.T) {
+	r := carsRegression(t)
+	report, err := r.Calibration(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Bins) != 5 {
+		t.Fatalf("Expected 5 bins, got %d", len(report.Bins))
+	}
+	total := 0
+	for i, b := range report.Bins {
+		total += b.Count
+		if b.MinPredicted > b.MeanPredicted || b.MeanPredicted > b.MaxPredicted {
+			t.Errorf("Bin %d: expected the mean within [%v, %v], got %v", i, b.MinPredicted, b.MaxPredicted, b.MeanPredicted)
+		}
+		if i > 0 && b.MinPredicted < report.Bins[i-1].MaxPredicted {
+			t.Errorf("Bin %d overlaps the previous bin", i)
+		}
+	}
+	if total != len(carsSpeed) {
+		t.Errorf("Expected %d points in the bins, got %d", len(carsSpeed), total)
+	}
+	// least squares is calibrated on its training data
+	assertClose(t, "slope", report.Slope, 1, 1e-9)
+	assertClose(t, "intercept", report.Intercept, 0, 1e-9)
+}
+
+func TestCalibrationFor(t *testing.T) {
+	r := carsRegression(t)
+	// a holdout where the observed values are twice the predictions
+	var holdout []*dataPoint
+	for _, x := range []float64{5, 10, 15, 20, 25} {
+		p, _ := r.Predict([]float64{x})
+		holdout = append(holdout, DataPoint(2*p+1, []float64{x}))
+	}
+	report, err := r.CalibrationFor(holdout, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Bins) != 5 {
+		t.Errorf("Expected the bins to be limited to the number of points, got %d", len(report.Bins))
+	}
+	assertClose(t, "slope", report.Slope, 2, 1e-9)
+	assertClose(t, "intercept", report.Intercept, 1, 1e-9)
+
+	if _, err := r.Calibration(0); err != ErrInvalidBins {
+		t.Errorf("Expected ErrInvalidBins, got %v", err)
+	}
+	if _, err := new(Regression).Calibration(5); err != ErrNotRun {
+		t.Errorf("Expected ErrNotRun, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Canary decides whether a newly loaded model may replace the model being served, by scoring it on a
+// retained validation set and sanity check cases. The zero Canary accepts every model.
+type Canary struct {
+	// Validation is a data set held out of training, with the base variables only. The candidate is
+	// scored on it, and so is the current model if there is one.
+	Validation []*dataPoint
+	// MaxRMSEIncrease is the relative increase of the validation RMSE over the current model allowed,
+	// e.g. 0.05 for 5%. Zero allows no increase.
+	MaxRMSEIncrease float64
+	// MaxRMSE bounds the validation RMSE of the candidate; zero doesn't bound it.
+	MaxRMSE float64
+	// Cases are checked with SanityCheck and must all pass.
+	Cases []SanityCase
+}
+
+// CanaryReport is the result of a canary evaluation.
+type CanaryReport struct {
+	// Current and Candidate are the validation metrics of the models. Current is zero when
+	// there is no current model or no validation set.
+	Current, Candidate FitMetrics
+	// Sanity is the sanity check of the candidate, nil without cases.
+	Sanity *SanityReport
+	// Reasons explains why the candidate was rejected; it is empty when it was accepted.
+	Reasons []string
+}
+
+// Accepted reports whether the candidate passed the canary.
+func (c *CanaryReport) Accepted() bool {
+	return len(c.Reasons) == 0
+}
+
+// CanaryError is returned when a canary rejects a model, with the report explaining why.
+type CanaryError struct {
+	Report *CanaryReport
+}
+
+func (e *CanaryError) Error() string {
+	return "model rejected by canary: " + strings.Join(e.Report.Reasons, "; ")
+}
+
+// Evaluate scores candidate against current, which may be nil for the first model. It returns a
+// *CanaryError when the candidate is rejected.
+func (c Canary) Evaluate(current, candidate *Regression) (*CanaryReport, error) {
+	report := new(CanaryReport)
+	reject := func(format string, args ...interface{}) {
+		report.Reasons = append(report.Reasons, fmt.Sprintf(format, args...))
+	}
+
+	if len(c.Validation) > 0 {
+		m, err := validationMetrics(candidate, c.Validation)
+		report.Candidate = m
+		switch {
+		case err != nil:
+			reject("scoring the validation set: %v", err)
+		case math.IsNaN(m.RMSE) || math.IsInf(m.RMSE, 0):
+			reject("validation RMSE is %v", m.RMSE)
+		case c.MaxRMSE > 0 && m.RMSE > c.MaxRMSE:
+			reject("validation RMSE %v is above %v", m.RMSE, c.MaxRMSE)
+		}
+		if current != nil && err == nil {
+			if report.Current, err = validationMetrics(current, c.Validation); err == nil {
+				limit := report.Current.RMSE * (1 + c.MaxRMSEIncrease)
+				if m.RMSE > limit {
+					reject("validation RMSE %v is above %v, the current %v plus %v%%",
+						m.RMSE, limit, report.Current.RMSE, 100*c.MaxRMSEIncrease)
+				}
+			}
+		}
+	}
+	if len(c.Cases) > 0 {
+		var err error
+		if report.Sanity, err = candidate.SanityCheck(c.Cases); err != nil {
+			reject("%d of %d sanity cases failed", len(report.Sanity.Failures), report.Sanity.Checked)
+		}
+	}
+	if !report.Accepted() {
+		return report, &CanaryError{Report: report}
+	}
+	return report, nil
+}
+
+// validationMetrics scores r on data.
+func validationMetrics(r *Regression, data []*dataPoint) (FitMetrics, error) {
+	observed := make([]float64, len(data))
+	predicted := make([]float64, len(data))
+	weights := make([]float64, len(data))
+	for i, d := range data {
+		p, err := r.Predict(d.Variables)
+		if err != nil {
+			return FitMetrics{}, err
+		}
+		observed[i], predicted[i], weights[i] = d.Observed, p, d.Weight
+	}
+	return metrics(observed, predicted, weights), nil
+}
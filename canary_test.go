@@ -0,0 +1,71 @@
+package regression
+
+import (
+	"strings"
+	"testing"
+)
+
+func lineModel(t *testing.T, slope float64) *Regression {
+	r := new(Regression)
+	for x := 0.0; x < 10; x++ {
+		r.Train(DataPoint(1+slope*x+0.1*float64(int(x)%3), []float64{x}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestCanary(t *testing.T) {
+	var validation []*dataPoint
+	for x := 0.5; x < 10; x++ {
+		validation = append(validation, DataPoint(1+2*x, []float64{x}))
+	}
+	current := lineModel(t, 2)
+	c := Canary{Validation: validation, MaxRMSEIncrease: 0.5}
+
+	report, err := c.Evaluate(current, lineModel(t, 2))
+	if err != nil || !report.Accepted() {
+		t.Fatalf("Expected an equal model accepted, got %v", err)
+	}
+	if report.Current.RMSE == 0 || report.Candidate.RMSE != report.Current.RMSE {
+		t.Errorf("Expected equal validation metrics, got %+v and %+v", report.Current, report.Candidate)
+	}
+
+	report, err = c.Evaluate(current, lineModel(t, 2.5))
+	if _, ok := err.(*CanaryError); !ok || report.Accepted() || !strings.Contains(err.Error(), "validation RMSE") {
+		t.Errorf("Expected a worse model rejected, got %v", err)
+	}
+
+	// without a current model only the absolute bound and the cases apply
+	if _, err := c.Evaluate(nil, lineModel(t, 2.5)); err != nil {
+		t.Errorf("Expected the first model accepted, got %v", err)
+	}
+	c = Canary{Cases: []SanityCase{{In: []float64{4}, Min: 8, Max: 10}}}
+	report, err = c.Evaluate(current, lineModel(t, 3))
+	if err == nil || report.Sanity == nil || len(report.Sanity.Failures) != 1 {
+		t.Errorf("Expected the sanity case failed, got %v", err)
+	}
+	if _, err := (Canary{}).Evaluate(current, lineModel(t, 3)); err != nil {
+		t.Errorf("Expected the zero Canary to accept, got %v", err)
+	}
+}
+
+func TestStoredModelCanary(t *testing.T) {
+	flaky := &flakyStorage{model: lineModel(t, 2)}
+	m, err := NewStoredModel(flaky, "line")
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := m.Model()
+	m.SetCanary(Canary{Cases: []SanityCase{{In: []float64{4}, Min: 8, Max: 10}}})
+	flaky.Save("line", lineModel(t, 3))
+	if err := m.Refresh(); err == nil || m.Model() != first {
+		t.Errorf("Expected the new model rejected, got %v", err)
+	}
+	second := lineModel(t, 2)
+	flaky.Save("line", second)
+	if err := m.Refresh(); err != nil || m.Model() != second {
+		t.Errorf("Expected the new model swapped in, got %v", err)
+	}
+}
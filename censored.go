@@ -0,0 +1,284 @@
+package regression
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	// ErrInvalidCensoring signals censoring points that don't leave a range of uncensored values.
+	ErrInvalidCensoring = errors.New("lower censoring point must be below the upper one")
+	// ErrNotConverged signals that an iterative estimation didn't converge, e.g. because the data
+	// leave the maximum likelihood estimate undefined.
+	ErrNotConverged = errors.New("estimation did not converge")
+)
+
+const (
+	tobitIterations = 100
+	tobitTolerance  = 1e-10
+)
+
+// censoring holds the censoring points of a Tobit model. A side without censoring is infinite.
+type censoring struct {
+	lower, upper float64
+}
+
+// SetCensoring makes Run fit a censored (Tobit) regression: observed values at or below lower are taken
+// to be censored from below and values at or above upper censored from above, e.g. spending capped at a
+// budget. Use math.Inf(-1) or math.Inf(1) to leave a side uncensored. The coefficients and the residual
+// variance of the uncensored (latent) values are estimated by maximum likelihood, so unlike least squares
+// they aren't biased by the clipping. Predict returns the latent value, PredictCensored the expected
+// observed value. Custom solvers, instruments, sign constraints, fixed effects and per-segment models
+// are not supported.
+func (r *Regression) SetCensoring(lower, upper float64) error {
+	if !(lower < upper) {
+		return ErrInvalidCensoring
+	}
+	r.censor = &censoring{lower: lower, upper: upper}
+	return nil
+}
+
+// Censoring returns the censoring points set with SetCensoring, and false when the model is not censored.
+func (r *Regression) Censoring() (lower, upper float64, ok bool) {
+	if r.censor == nil {
+		return math.Inf(-1), math.Inf(1), false
+	}
+	return r.censor.lower, r.censor.upper, true
+}
+
+// PredictCensored returns the expected observed value for vars of a censored model, that is the latent
+// value predicted by Predict accounting for the probability of it being clipped at the censoring points.
+func (r *Regression) PredictCensored(vars []float64) (float64, error) {
+	if r.censor == nil {
+		return 0, ErrUnsupported
+	}
+	mu, err := r.Predict(vars)
+	if err != nil {
+		return 0, err
+	}
+	return r.censor.expected(mu, math.Sqrt(r.sigma2)), nil
+}
+
+// expected returns the mean of a normal variable with mean mu and standard deviation sigma clipped
+// to the censoring points.
+func (c *censoring) expected(mu, sigma float64) float64 {
+	a, b := (c.lower-mu)/sigma, (c.upper-mu)/sigma
+	e := mu*(normCDF(b)-normCDF(a)) + sigma*(normPDF(a)-normPDF(b))
+	if !math.IsInf(c.lower, -1) {
+		e += c.lower * normCDF(a)
+	}
+	if !math.IsInf(c.upper, 1) {
+		e += c.upper * (1 - normCDF(b))
+	}
+	return e
+}
+
+// fit estimates the coefficients by maximum likelihood with Newton's method, starting from the least
+// squares solution. It works in Olsen's parameterization gamma = beta/sigma, theta = 1/sigma, in which the
+// log-likelihood is concave. Columns aliased in the least squares fit are left out. The Unscaled matrix
+// of the returned diagnostics holds the covariance of the coefficients, from the observed information,
+// rather than (X'X)^-1; the residual standard deviation of the latent values is returned separately.
+func (c *censoring) fit(x, y *mat.Dense, weights []float64) ([]float64, *Diagnostics, float64, error) {
+	rows, cols := x.Dims()
+	wx := mat.NewDense(rows, cols, nil)
+	wx.Copy(x)
+	wy := mat.NewDense(rows, 1, nil)
+	wy.Copy(y)
+	for i, w := range weights {
+		w = math.Sqrt(w)
+		wy.Set(i, 0, w*wy.At(i, 0))
+		for j := 0; j < cols; j++ {
+			wx.Set(i, j, w*wx.At(i, j))
+		}
+	}
+	start, diag, err := QRSolver{}.Solve(wx, wy)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	var active []int
+	for j := 0; j < cols; j++ {
+		if !containsInt(diag.Aliased, j) {
+			active = append(active, j)
+		}
+	}
+	k := len(active)
+
+	var sse, sw float64
+	for i, w := range weights {
+		e := y.At(i, 0)
+		for j := 0; j < cols; j++ {
+			e -= x.At(i, j) * start[j]
+		}
+		sse += w * e * e
+		sw += w
+	}
+	sigma := math.Sqrt(sse / sw)
+	if !(sigma > 0) {
+		sigma = 1
+	}
+	p := make([]float64, k+1)
+	for a, j := range active {
+		p[a] = start[j] / sigma
+	}
+	p[k] = 1 / sigma
+
+	ll, grad, hess := c.logLikelihood(x, y, weights, active, p)
+	converged := false
+	for iter := 0; iter < tobitIterations && !converged; iter++ {
+		// Newton step: H d = -grad
+		step := new(mat.Dense)
+		if err := step.Solve(hess, mat.NewDense(k+1, 1, grad)); err != nil {
+			return nil, nil, 0, ErrNotConverged
+		}
+		next := make([]float64, k+1)
+		improved := false
+		for t := 1.0; t > 1e-10; t /= 2 {
+			for a := range next {
+				next[a] = p[a] - t*step.At(a, 0)
+			}
+			if next[k] <= 0 {
+				continue
+			}
+			nll, ngrad, nhess := c.logLikelihood(x, y, weights, active, next)
+			if nll >= ll-tobitTolerance*math.Abs(ll) {
+				var change, size float64
+				for a := range next {
+					change = math.Max(change, math.Abs(next[a]-p[a]))
+					size = math.Max(size, math.Abs(next[a]))
+				}
+				converged = change <= tobitTolerance*(1+size)
+				p, ll, grad, hess = next, nll, ngrad, nhess
+				improved = true
+				break
+			}
+		}
+		if !improved {
+			break
+		}
+	}
+	if !converged || math.IsNaN(ll) {
+		return nil, nil, 0, ErrNotConverged
+	}
+
+	// the covariance of the parameters is the inverse of the observed information -H;
+	// that of beta = gamma/theta follows from the delta method
+	info := mat.NewDense(k+1, k+1, nil)
+	info.Scale(-1, hess)
+	cov := new(mat.Dense)
+	if err := cov.Inverse(info); err != nil {
+		return nil, nil, 0, err
+	}
+	theta := p[k]
+	jac := mat.NewDense(k, k+1, nil)
+	for a := 0; a < k; a++ {
+		jac.Set(a, a, 1/theta)
+		jac.Set(a, k, -p[a]/(theta*theta))
+	}
+	tmp := new(mat.Dense)
+	tmp.Mul(jac, cov)
+	covBeta := new(mat.Dense)
+	covBeta.Mul(tmp, jac.T())
+
+	coeffs := make([]float64, cols)
+	covariance := mat.NewDense(cols, cols, nil)
+	for a, j := range active {
+		coeffs[j] = p[a] / theta
+		for b, m := range active {
+			covariance.Set(j, m, covBeta.At(a, b))
+		}
+	}
+	return coeffs, &Diagnostics{Aliased: diag.Aliased, Unscaled: covariance}, 1 / theta, nil
+}
+
+// logLikelihood returns the weighted log-likelihood of the Tobit model in Olsen's parameterization
+// with its gradient and Hessian in the parameters p, the gamma of the active columns followed by theta.
+func (c *censoring) logLikelihood(x, y *mat.Dense, weights []float64, active []int, p []float64) (float64, []float64, *mat.Dense) {
+	k := len(active)
+	theta := p[k]
+	var ll float64
+	grad := make([]float64, k+1)
+	hess := mat.NewDense(k+1, k+1, nil)
+	v := make([]float64, k+1)
+	for i, w := range weights {
+		if w == 0 {
+			continue
+		}
+		obs := y.At(i, 0)
+		var xg float64
+		for a, j := range active {
+			xg += x.At(i, j) * p[a]
+		}
+
+		// every case depends on the parameters through a single linear index v'p
+		var d1, d2 float64
+		switch {
+		case obs <= c.lower:
+			// log P(latent <= lower) = log Phi(theta*lower - x'gamma)
+			for a, j := range active {
+				v[a] = -x.At(i, j)
+			}
+			v[k] = c.lower
+			idx := theta*c.lower - xg
+			lp, lambda := logNormCDF(idx)
+			ll += w * lp
+			d1, d2 = lambda, -lambda*(idx+lambda)
+		case obs >= c.upper:
+			// log P(latent >= upper) = log Phi(x'gamma - theta*upper)
+			for a, j := range active {
+				v[a] = x.At(i, j)
+			}
+			v[k] = -c.upper
+			idx := xg - theta*c.upper
+			lp, lambda := logNormCDF(idx)
+			ll += w * lp
+			d1, d2 = lambda, -lambda*(idx+lambda)
+		default:
+			// log theta + log phi(theta*y - x'gamma)
+			for a, j := range active {
+				v[a] = -x.At(i, j)
+			}
+			v[k] = obs
+			z := theta*obs - xg
+			ll += w * (math.Log(theta) - z*z/2 - 0.5*math.Log(2*math.Pi))
+			d1, d2 = -z, -1
+			grad[k] += w / theta
+			hess.Set(k, k, hess.At(k, k)-w/(theta*theta))
+		}
+		for a := range v {
+			grad[a] += w * d1 * v[a]
+			for b := range v {
+				hess.Set(a, b, hess.At(a, b)+w*d2*v[a]*v[b])
+			}
+		}
+	}
+	return ll, grad, hess
+}
+
+// censoredInference replaces the least squares estimates of calcInference by those of the Tobit fit.
+func (r *Regression) censoredInference(diag *Diagnostics, sigma float64) {
+	r.sigma2 = sigma * sigma
+	r.covariance = diag.Unscaled
+}
+
+func normPDF(a float64) float64 {
+	return math.Exp(-a*a/2) / math.Sqrt(2*math.Pi)
+}
+
+func normCDF(a float64) float64 {
+	return 0.5 * math.Erfc(-a/math.Sqrt2)
+}
+
+// logNormCDF returns log Phi(a) of the standard normal distribution together with the inverse Mills
+// ratio phi(a)/Phi(a), using an asymptotic expansion far in the lower tail where Phi(a) underflows.
+func logNormCDF(a float64) (float64, float64) {
+	if a > -30 {
+		p := normCDF(a)
+		return math.Log(p), normPDF(a) / p
+	}
+	// Phi(a)/phi(a) ~ -1/a * (1 - 1/a^2 + 3/a^4)
+	a2 := a * a
+	ratio := -1 / a * (1 - 1/a2 + 3/(a2*a2))
+	return -a2/2 - 0.5*math.Log(2*math.Pi) + math.Log(ratio), 1 / ratio
+}
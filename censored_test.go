@@ -0,0 +1,133 @@
+package regression
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSetCensoring(t *testing.T) {
+	// spending of 1 + 2x with unit noise, capped at a budget of 6
+	rng := rand.New(rand.NewSource(7))
+	tobit, ols := new(Regression), new(Regression)
+	if err := tobit.SetCensoring(math.Inf(-1), 6); err != nil {
+		t.Fatal(err)
+	}
+	capped := 0
+	for i := 0; i < 1000; i++ {
+		x := 5 * rng.Float64()
+		y := 1 + 2*x + rng.NormFloat64()
+		if y > 6 {
+			y = 6
+			capped++
+		}
+		tobit.Train(DataPoint(y, []float64{x}))
+		ols.Train(DataPoint(y, []float64{x}))
+	}
+	if capped < 200 {
+		t.Fatalf("Expected a good share of capped values, got %d", capped)
+	}
+	if err := tobit.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if err := ols.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	assertClose(t, "offset", tobit.Coeff(0), 1, 0.15)
+	assertClose(t, "slope", tobit.Coeff(1), 2, 0.1)
+	assertClose(t, "sigma", math.Sqrt(tobit.sigma2), 1, 0.1)
+	if ols.Coeff(1) > 1.8 {
+		t.Errorf("Expected least squares to be biased by the cap, got slope %v", ols.Coeff(1))
+	}
+	if se := tobit.StdErr(1); !(se > 0 && se < 0.1) {
+		t.Errorf("Unexpected standard error %v", se)
+	}
+
+	latent, err := tobit.Predict([]float64{4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	observed, err := tobit.PredictCensored([]float64{4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !(observed < 6 && observed < latent) {
+		t.Errorf("Expected the expected observed value below the cap and the latent value, got %v and %v", observed, latent)
+	}
+	low, err := tobit.PredictCensored([]float64{0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "uncensored region", low, tobit.Coeff(0), 1e-6)
+
+	var buf bytes.Buffer
+	if err := tobit.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lower, upper, ok := loaded.Censoring()
+	if !ok || !math.IsInf(lower, -1) || upper != 6 {
+		t.Errorf("Expected the censoring to be restored, got %v, %v, %v", lower, upper, ok)
+	}
+	if got, err := loaded.PredictCensored([]float64{4}); err != nil || got != observed {
+		t.Errorf("Expected %v from the loaded model, got %v, %v", observed, got, err)
+	}
+}
+
+func TestSetCensoringWeights(t *testing.T) {
+	// a weight of two is the same as training a data point twice
+	rng := rand.New(rand.NewSource(3))
+	weighted, repeated := new(Regression), new(Regression)
+	for _, r := range []*Regression{weighted, repeated} {
+		if err := r.SetCensoring(0, 10); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 100; i++ {
+		x := 10 * rng.Float64()
+		y := math.Max(0, math.Min(10, 3*x-5+2*rng.NormFloat64()))
+		weighted.Train(WeightedDataPoint(y, []float64{x}, 2))
+		repeated.Train(DataPoint(y, []float64{x}), DataPoint(y, []float64{x}))
+	}
+	if err := weighted.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if err := repeated.Run(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		assertClose(t, "coefficient", weighted.Coeff(i), repeated.Coeff(i), 1e-8)
+	}
+}
+
+func TestSetCensoringErrors(t *testing.T) {
+	r := new(Regression)
+	if err := r.SetCensoring(1, 1); err != ErrInvalidCensoring {
+		t.Errorf("Expected ErrInvalidCensoring, got %v", err)
+	}
+	if err := r.SetCensoring(math.NaN(), 1); err != ErrInvalidCensoring {
+		t.Errorf("Expected ErrInvalidCensoring, got %v", err)
+	}
+	if _, _, ok := r.Censoring(); ok {
+		t.Error("Expected no censoring")
+	}
+	if _, err := r.PredictCensored([]float64{1}); err != ErrUnsupported {
+		t.Errorf("Expected ErrUnsupported, got %v", err)
+	}
+
+	if err := r.SetCensoring(0, 10); err != nil {
+		t.Fatal(err)
+	}
+	r.SetSolver(RidgeSolver{Lambda: 1})
+	for i := 0; i < 10; i++ {
+		r.Train(DataPoint(float64(i), []float64{float64(i % 3)}))
+	}
+	if err := r.Run(); err != ErrUnsupported {
+		t.Errorf("Expected ErrUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+//go:build cgo
+// +build cgo
+
+// Command libregression builds a C shared library that scores models saved with Regression.Save,
+// so services written in other languages can use them without re-implementing the feature crosses:
+//
+//	go build -buildmode=c-shared -o libregression.so ./cmd/libregression
+//
+// The build also writes libregression.h. A model is loaded from its JSON once and referred to by a handle.
+// Failing calls describe the error in a buffer supplied by the caller, so calls from several threads
+// don't share any error state:
+//
+//	char err[256];
+//	int model = regression_load(json, err, sizeof err);
+//	double prediction;
+//	if (model == 0 || regression_predict(model, vars, nvars, &prediction, err, sizeof err) != 0) {
+//		/* err describes the failure */
+//	}
+//	regression_free(model);
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"github.com/sajari/regression"
+	"github.com/sajari/regression/predict"
+)
+
+var (
+	mu     sync.Mutex
+	models = make(map[C.int]*predict.Model)
+	next   C.int
+)
+
+// setError copies the message of err to the caller's buffer of n bytes, truncating it to fit with
+// its NUL terminator. A nil buffer is left alone.
+func setError(buf *C.char, n C.int, err error) {
+	if buf == nil || n <= 0 {
+		return
+	}
+	dst := unsafe.Slice((*byte)(unsafe.Pointer(buf)), int(n))
+	dst[copy(dst[:len(dst)-1], err.Error())] = 0
+}
+
+// doubles views a C array of n doubles as a Go slice without copying.
+func doubles(p *C.double, n C.int) []float64 {
+	if p == nil || n <= 0 {
+		return nil
+	}
+	return unsafe.Slice((*float64)(unsafe.Pointer(p)), int(n))
+}
+
+// regression_predict_coeffs returns coeffs[0] + sum(coeffs[i+1] * vars[i]) for a model without feature
+// crosses; ncoeffs must be nvars + 1. Otherwise it returns NaN and writes the error to err.
+//
+//export regression_predict_coeffs
+func regression_predict_coeffs(coeffs *C.double, ncoeffs C.int, vars *C.double, nvars C.int, err *C.char, errlen C.int) C.double {
+	c, v := doubles(coeffs, ncoeffs), doubles(vars, nvars)
+	if len(c) != len(v)+1 {
+		setError(err, errlen, regression.ErrDimensions)
+		return C.double(math.NaN())
+	}
+	p := c[0]
+	for i, x := range v {
+		p += c[i+1] * x
+	}
+	return C.double(p)
+}
+
+// regression_load loads a model from the NUL terminated JSON written by Regression.Save and returns
+// its handle, or 0 on failure with the error written to err.
+//
+//export regression_load
+func regression_load(json *C.char, err *C.char, errlen C.int) (model C.int) {
+	// a panic must not take down the host process
+	defer func() {
+		if p := recover(); p != nil {
+			setError(err, errlen, fmt.Errorf("loading the model panicked: %v", p))
+			model = 0
+		}
+	}()
+	r, e := regression.Load(strings.NewReader(C.GoString(json)))
+	var m *predict.Model
+	if e == nil {
+		m, e = r.Predictor()
+	}
+	if e != nil {
+		setError(err, errlen, e)
+		return 0
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	next++
+	models[next] = m
+	return next
+}
+
+// regression_predict writes the prediction of a loaded model for the nvars variables to out, applying the
+// feature crosses and segments of the model. It returns 0 on success and -1 on failure with the error
+// written to err.
+//
+//export regression_predict
+func regression_predict(model C.int, vars *C.double, nvars C.int, out *C.double, err *C.char, errlen C.int) (status C.int) {
+	defer func() {
+		if p := recover(); p != nil {
+			setError(err, errlen, fmt.Errorf("prediction panicked: %v", p))
+			status = -1
+		}
+	}()
+	mu.Lock()
+	m, ok := models[model]
+	mu.Unlock()
+	if !ok {
+		setError(err, errlen, regression.ErrUnknownModel)
+		return -1
+	}
+	p, e := m.Predict(doubles(vars, nvars))
+	if e != nil {
+		setError(err, errlen, e)
+		return -1
+	}
+	*out = C.double(p)
+	return 0
+}
+
+// regression_free releases a loaded model.
+//
+//export regression_free
+func regression_free(model C.int) {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(models, model)
+}
+
+func main() {}
@@ -0,0 +1,151 @@
+package regression
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// CollinearDrop reports a variable that was dropped from the fit by AutoDropCollinear.
+type CollinearDrop struct {
+	Name string
+	// VIF is the variance inflation factor of the variable when it was dropped, +Inf for a variable that
+	// is an exact linear combination of the others, e.g. a duplicate.
+	VIF float64
+}
+
+// AutoDropCollinear makes Run remove multicollinearity, e.g. when upstream adds a duplicate feature: while
+// the largest variance inflation factor of the variables, including the feature crosses, exceeds
+// vifThreshold, that variable is dropped and the factors are computed again, before the fit. Dropped
+// variables get a zero coefficient, are reported as aliased and are listed by CollinearDrops, so the
+// model keeps taking the same variables. A threshold of 10 is common; zero disables the remediation.
+// It isn't supported with censoring, priors, Update, RunStream, RunAggregate or RunMultilevel, which
+// return ErrUnsupported.
+func (r *Regression) AutoDropCollinear(vifThreshold float64) {
+	r.vifThreshold = vifThreshold
+}
+
+// CollinearDrops returns the variables dropped by the last fit because of AutoDropCollinear, in the order
+// they were dropped.
+func (r *Regression) CollinearDrops() []CollinearDrop {
+	drops := make([]CollinearDrop, len(r.collinearDrops))
+	for k, d := range r.collinearDrops {
+		drops[k] = CollinearDrop{Name: r.GetVar(d.col - 1), VIF: d.vif}
+	}
+	return drops
+}
+
+// VIF returns the variance inflation factors of the variables, including the feature crosses, on the
+// weighted training data: 1/(1-R²) of the regression of each variable on the others, which is +Inf for
+// exact linear combinations. The variables dropped by the fit, see CollinearDrops and SignAdjustments,
+// are left out and get NaN, as do variables that are zero throughout.
+func (r *Regression) VIF() ([]float64, error) {
+	if !r.hasRun || len(r.coeff) == 0 {
+		return nil, ErrNotRun
+	}
+	if len(r.data) == 0 {
+		return nil, ErrNotEnoughData
+	}
+	var dropped []int
+	for _, d := range r.collinearDrops {
+		dropped = append(dropped, d.col)
+	}
+	for _, d := range r.signDrops {
+		dropped = append(dropped, d.col)
+	}
+	a := newNormalEquations(len(r.coeff))
+	row := make([]float64, len(r.coeff))
+	for _, d := range r.data {
+		row[0] = 1
+		copy(row[1:], d.Variables)
+		for _, col := range dropped {
+			row[col] = 0
+		}
+		a.add(row, 0, d.Weight)
+	}
+	return a.vifs()[1:], nil
+}
+
+// vifs returns the variance inflation factors of the columns of the design matrix, NaN for the offset
+// and columns that are zero throughout. The factor of column j is the residual sum of squares of its
+// regression on the offset over that on all other columns, the latter being 1/(X'X)^-1_jj.
+func (a *normalEquations) vifs() []float64 {
+	_, unscaled := a.solve()
+	v := make([]float64, len(a.xty))
+	v[0] = math.NaN()
+	for j := 1; j < len(v); j++ {
+		switch {
+		case a.xtx[j][j] == 0:
+			v[j] = math.NaN()
+		case unscaled.At(j, j) == 0:
+			// aliased
+			v[j] = math.Inf(1)
+		default:
+			ss := a.xtx[j][j]
+			if a.xtx[0][0] > 0 {
+				ss -= a.xtx[0][j] * a.xtx[0][j] / a.xtx[0][0]
+			}
+			v[j] = ss * unscaled.At(j, j)
+		}
+	}
+	return v
+}
+
+// collinearDrop is a column dropped by collinearSolver and its variance inflation factor.
+type collinearDrop struct {
+	col int
+	vif float64
+}
+
+// collinearSolver wraps a solver to drop the columns with the largest variance inflation factors before
+// solving. Columns are columns of the design matrix, so variable i is column i+1.
+type collinearSolver struct {
+	inner     Solver
+	threshold float64
+	dropped   *[]collinearDrop
+}
+
+func (r *Regression) collinearSolver(s Solver) Solver {
+	r.collinearDrops = nil
+	if r.vifThreshold <= 0 {
+		return s
+	}
+	return collinearSolver{inner: s, threshold: r.vifThreshold, dropped: &r.collinearDrops}
+}
+
+// Solve satisfies the Solver interface.
+func (s collinearSolver) Solve(x, y *mat.Dense) ([]float64, *Diagnostics, error) {
+	rows, cols := x.Dims()
+	dropped := mat.NewDense(rows, cols, nil)
+	dropped.Copy(x)
+	x = dropped
+	for {
+		a := newNormalEquations(cols)
+		for i := 0; i < rows; i++ {
+			a.add(x.RawRowView(i), 0, 1)
+		}
+		worst, vif := -1, s.threshold
+		for col, v := range a.vifs() {
+			if v > vif {
+				worst, vif = col, v
+			}
+		}
+		if worst < 0 {
+			break
+		}
+		*s.dropped = append(*s.dropped, collinearDrop{col: worst, vif: vif})
+		for i := 0; i < rows; i++ {
+			x.Set(i, worst, 0)
+		}
+	}
+
+	c, diag, err := s.inner.Solve(x, y)
+	if err != nil {
+		return nil, nil, err
+	}
+	aliased := make([]int, len(*s.dropped))
+	for k, d := range *s.dropped {
+		aliased[k] = d.col
+	}
+	return c, markAliased(diag, aliased), nil
+}
@@ -0,0 +1,151 @@
+package regression
+
+import (
+	"math"
+	"testing"
+)
+
+// collinearRegression trains y = 1 + 2x + 3z on data where w duplicates x and v is almost z.
+func collinearRegression() *Regression {
+	r := new(Regression)
+	r.SetObserved("y")
+	r.SetVar(0, "x")
+	r.SetVar(1, "z")
+	r.SetVar(2, "w")
+	r.SetVar(3, "v")
+	for i := 0; i < 40; i++ {
+		x, z := float64(i%9), float64(i%7)
+		v := z + 0.01*float64(i%3-1)
+		r.Train(DataPoint(1+2*x+3*z+0.1*float64(i%5), []float64{x, z, x, v}))
+	}
+	return r
+}
+
+func TestAutoDropCollinear(t *testing.T) {
+	r := collinearRegression()
+	r.AutoDropCollinear(10)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	drops := r.CollinearDrops()
+	if len(drops) != 2 || drops[0].Name != "w" || !math.IsInf(drops[0].VIF, 1) ||
+		drops[1].Name != "v" || drops[1].VIF <= 10 || math.IsInf(drops[1].VIF, 0) {
+		t.Fatalf("Expected w and then v dropped, got %+v", drops)
+	}
+	if r.Coeff(3) != 0 || r.Coeff(4) != 0 || !containsInt(r.aliased, 3) || !containsInt(r.aliased, 4) {
+		t.Errorf("Expected zero coefficients for the dropped variables, got %v and aliased %v", r.coeffs(), r.aliased)
+	}
+	assertClose(t, "x", r.Coeff(1), 2, 0.05)
+	assertClose(t, "z", r.Coeff(2), 3, 0.05)
+	// the model keeps taking every variable
+	if _, err := r.Predict([]float64{1, 2, 1, 2}); err != nil {
+		t.Error(err)
+	}
+
+	vif, err := r.VIF()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vif) != 4 || vif[0] > 10 || vif[1] > 10 || !math.IsNaN(vif[2]) || !math.IsNaN(vif[3]) {
+		t.Errorf("Expected small factors for x and z, got %v", vif)
+	}
+
+	// without the remediation nothing is dropped
+	r = collinearRegression()
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.CollinearDrops()) != 0 {
+		t.Errorf("Expected no drops, got %+v", r.CollinearDrops())
+	}
+	if vif, _ := r.VIF(); !math.IsInf(vif[2], 1) || vif[3] < 10 {
+		t.Errorf("Expected w aliased and v inflated, got %v", vif)
+	}
+}
+
+func TestVIF(t *testing.T) {
+	// with two variables the factor is 1/(1-r²) for their correlation r
+	r := new(Regression)
+	var xs, zs []float64
+	for i := 0; i < 30; i++ {
+		x, z := float64(i), float64(i%4)+0.3*float64(i)
+		xs, zs = append(xs, x), append(zs, z)
+		r.Train(DataPoint(x+z, []float64{x, z}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	vif, err := r.VIF()
+	if err != nil {
+		t.Fatal(err)
+	}
+	corr := correlation(xs, zs)
+	want := 1 / (1 - corr*corr)
+	assertClose(t, "VIF x", vif[0], want, 1e-9)
+	assertClose(t, "VIF z", vif[1], want, 1e-9)
+
+	if _, err := new(Regression).VIF(); err != ErrNotRun {
+		t.Errorf("Expected ErrNotRun, got %v", err)
+	}
+}
+
+func TestAutoDropCollinearSigns(t *testing.T) {
+	r := collinearRegression()
+	r.AutoDropCollinear(10)
+	r.RequireSign(1, Negative)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.CollinearDrops()) != 2 || len(r.SignAdjustments()) != 1 || r.SignAdjustments()[0].Name != "z" {
+		t.Errorf("Expected two collinear drops and z dropped for its sign, got %+v and %+v",
+			r.CollinearDrops(), r.SignAdjustments())
+	}
+}
+
+func correlation(a, b []float64) float64 {
+	var ma, mb float64
+	for i := range a {
+		ma += a[i] / float64(len(a))
+		mb += b[i] / float64(len(b))
+	}
+	var sab, saa, sbb float64
+	for i := range a {
+		sab += (a[i] - ma) * (b[i] - mb)
+		saa += (a[i] - ma) * (a[i] - ma)
+		sbb += (b[i] - mb) * (b[i] - mb)
+	}
+	return sab / math.Sqrt(saa*sbb)
+}
+
+func TestAutoDropCollinearUnsupported(t *testing.T) {
+	r := collinearRegression()
+	r.AutoDropCollinear(10)
+	r.SetCensoring(0, 100)
+	if err := r.Run(); err != ErrUnsupported {
+		t.Errorf("Expected ErrUnsupported with censoring, got %v", err)
+	}
+
+	r = new(Regression)
+	r.AutoDropCollinear(10)
+	points := []*dataPoint{DataPoint(1, []float64{1}), DataPoint(2, []float64{2}), DataPoint(4, []float64{3})}
+	next := func() (*dataPoint, bool) {
+		if len(points) == 0 {
+			return nil, false
+		}
+		p := points[0]
+		points = points[1:]
+		return p, true
+	}
+	if err := r.RunStream(next); err != ErrUnsupported {
+		t.Errorf("Expected ErrUnsupported streaming, got %v", err)
+	}
+
+	r = collinearRegression()
+	r.AutoDropCollinear(10)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Update(DataPoint(1, []float64{1, 2, 1, 2})); err != ErrUnsupported {
+		t.Errorf("Expected ErrUnsupported updating, got %v", err)
+	}
+}
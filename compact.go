@@ -0,0 +1,94 @@
+//go:build go1.18
+// +build go1.18
+
+package regression
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrNotCompactable signals that a model uses features that a Compact model cannot evaluate,
+// such as custom feature crosses, per-segment models, winsorized variables or missing indicators.
+var ErrNotCompactable = errors.New("model cannot be compacted")
+
+// Float is the set of floating point types a Compact model can be evaluated with.
+type Float interface {
+	~float32 | ~float64
+}
+
+// Compact is a fitted model reduced to its coefficients and feature crosses and evaluated natively in T,
+// so a Compact[float32] halves the memory of the coefficients and inputs and avoids converting them.
+type Compact[T Float] struct {
+	coeffs  []T
+	crosses []CrossSpec
+}
+
+// NewCompact creates a Compact model from a fitted regression.
+func NewCompact[T Float](r *Regression) (*Compact[T], error) {
+	if !r.hasRun || len(r.coeff) == 0 {
+		return nil, ErrNotRun
+	}
+	if len(r.segments) > 0 || r.clips != nil || len(r.missing) > 0 {
+		return nil, ErrNotCompactable
+	}
+	c := &Compact[T]{coeffs: make([]T, len(r.coeff))}
+	for i := range c.coeffs {
+		c.coeffs[i] = T(r.coeff[i])
+	}
+	for _, cross := range r.crosses {
+		spec, err := specOf(cross)
+		if err != nil || (spec.Type != "pow" && spec.Type != "multiplier") {
+			return nil, ErrNotCompactable
+		}
+		c.crosses = append(c.crosses, spec)
+	}
+	return c, nil
+}
+
+// Predict returns the prediction for vars, applying the feature crosses of the model. It returns
+// ErrDimensions unless vars holds a value for every variable of the model.
+func (c *Compact[T]) Predict(vars []T) (T, error) {
+	if len(vars) != len(c.coeffs)-1-len(c.crosses) {
+		return 0, ErrDimensions
+	}
+	p := c.coeffs[0]
+	for j, v := range vars {
+		p += c.coeffs[j+1] * v
+	}
+	j := len(vars) + 1
+	for _, s := range c.crosses {
+		p += c.coeffs[j] * crossValue(s, vars)
+		j++
+	}
+	return p, nil
+}
+
+// PredictBatch predicts every row, appending the predictions to dst. It stops at the first row that
+// Predict rejects, returning the predictions of the preceding rows with the error.
+func (c *Compact[T]) PredictBatch(rows [][]T, dst []T) ([]T, error) {
+	for _, vars := range rows {
+		p, err := c.Predict(vars)
+		if err != nil {
+			return dst, err
+		}
+		dst = append(dst, p)
+	}
+	return dst, nil
+}
+
+// crossValue evaluates a pow or multiplier cross on the base variables.
+func crossValue[T Float](s CrossSpec, vars []T) T {
+	if s.Type == "pow" {
+		v := vars[s.Vars[0]]
+		if s.Power == 2 {
+			return v * v
+		}
+		return T(math.Pow(float64(v), s.Power))
+	}
+	var out T = 1
+	for _, i := range s.Vars {
+		out *= vars[i]
+	}
+	return out
+}
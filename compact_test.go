@@ -0,0 +1,67 @@
+//go:build go1.18
+// +build go1.18
+
+package regression
+
+import "testing"
+
+func TestCompact(t *testing.T) {
+	r := new(Regression)
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i], float64(i % 4)}))
+	}
+	r.AddCross(PowCross(0, 2))
+	r.AddCross(MultiplierCross(0, 1))
+	r.AddCross(PowCross(1, 0.5))
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	c64, err := NewCompact[float64](r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c32, err := NewCompact[float32](r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, vars := range [][]float64{{21, 1}, {4, 3}, {12.5, 0}} {
+		want, _ := r.Predict(vars)
+		p, err := c64.Predict(vars)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertClose(t, "float64", p, want, 1e-9)
+		got, err := c32.Predict([]float32{float32(vars[0]), float32(vars[1])})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertClose(t, "float32", float64(got), want, 1e-3)
+	}
+
+	batch, err := c32.PredictBatch([][]float32{{21, 1}, {4, 3}}, nil)
+	if first, _ := c32.Predict([]float32{21, 1}); err != nil || len(batch) != 2 || batch[0] != first {
+		t.Errorf("Unexpected batch predictions %v, %v", batch, err)
+	}
+
+	for _, vars := range [][]float32{nil, {21}, {21, 1, 0}} {
+		if _, err := c32.Predict(vars); err != ErrDimensions {
+			t.Errorf("Expected ErrDimensions for %v, got %v", vars, err)
+		}
+	}
+	batch, err = c32.PredictBatch([][]float32{{21, 1}, {4}}, nil)
+	if err != ErrDimensions || len(batch) != 1 {
+		t.Errorf("Expected ErrDimensions after one prediction, got %v, %v", batch, err)
+	}
+}
+
+func TestCompactErrors(t *testing.T) {
+	if _, err := NewCompact[float32](new(Regression)); err != ErrNotRun {
+		t.Errorf("Expected ErrNotRun, got %v", err)
+	}
+	r := carsRegression(t)
+	r.crosses = append(r.crosses, customCross{})
+	if _, err := NewCompact[float32](r); err != ErrNotCompactable {
+		t.Errorf("Expected ErrNotCompactable, got %v", err)
+	}
+}
@@ -0,0 +1,200 @@
+package regression
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// packedData is training data compressed by CompressData. Every float column is stored as the XOR of each
+// value with the previous one, which is zero or has long runs of zero bits for repeated and slowly changing
+// values, with the zero bytes at either end dropped.
+type packedData struct {
+	points int
+	vars   int
+	// columns are the observed values, weights, predicted values and the base variables. The errors
+	// are the differences of the predicted and observed values.
+	columns [][]byte
+	// last holds the bits of the last value of every column, which the next value is XORed with.
+	last []uint64
+	// groups are the distinct groups, and group the index of the group of every point as uvarints.
+	groups []string
+	group  []byte
+	// flags has the treated and post bits of every point.
+	flags []byte
+}
+
+// packedFixed is the number of columns ahead of the variables.
+const packedFixed = 3
+
+// CompressData compresses the training data in memory, e.g. when it is kept only for occasional refits.
+// Typical data with repeated, integer or slowly changing values shrinks to a fraction of its size. The data
+// points are decompressed on demand by Run, RunIfChanged, Reset and Update. Other methods reading the
+// training data, such as residual and influence diagnostics, see only the data points trained after
+// compression until DecompressData is called. Compressing again adds the new data points.
+// Data verified with VerifyOnPredict can't be compressed and all data points need the same number of
+// variables.
+func (r *Regression) CompressData() error {
+	if r.dataGuard == VerifyOnPredict {
+		return ErrUnsupported
+	}
+	// keep the data points as trained rather than as clipped by winsorization
+	r.unwinsorize()
+	if len(r.data) == 0 {
+		return nil
+	}
+	p := r.packed
+	if p == nil {
+		vars := len(r.baseVariables(r.data[0]))
+		p = &packedData{vars: vars, columns: make([][]byte, packedFixed+vars), last: make([]uint64, packedFixed+vars)}
+	}
+	for _, d := range r.data {
+		if len(r.baseVariables(d)) != p.vars {
+			return ErrDimensions
+		}
+	}
+
+	groups := make(map[string]int, len(p.groups))
+	for i, g := range p.groups {
+		groups[g] = i
+	}
+	var buf [binary.MaxVarintLen64]byte
+	for _, d := range r.data {
+		for j, v := range packedValues(d, r.baseVariables(d)) {
+			b := math.Float64bits(v)
+			p.columns[j] = appendXOR(p.columns[j], b^p.last[j])
+			p.last[j] = b
+		}
+		g, ok := groups[d.Group]
+		if !ok {
+			g = len(p.groups)
+			groups[d.Group] = g
+			p.groups = append(p.groups, d.Group)
+		}
+		p.group = append(p.group, buf[:binary.PutUvarint(buf[:], uint64(g))]...)
+		if p.points%4 == 0 {
+			p.flags = append(p.flags, 0)
+		}
+		var f byte
+		if d.Treated {
+			f |= 1
+		}
+		if d.Post {
+			f |= 2
+		}
+		p.flags[len(p.flags)-1] |= f << uint(2*(p.points%4))
+		p.points++
+	}
+	r.packed = p
+	r.data = nil
+	return nil
+}
+
+// DecompressData restores the training data compressed by CompressData, ahead of any data points trained
+// since. For fitted models the feature crosses are applied again.
+func (r *Regression) DecompressData() {
+	p := r.packed
+	if p == nil {
+		return
+	}
+	r.packed = nil
+	points := p.unpackAll()
+	if r.hasRun {
+		r.crossPoints(points)
+	}
+	r.data = append(points, r.data...)
+}
+
+// CompressedSize returns the number of bytes of the compressed training data, zero if there is none.
+func (r *Regression) CompressedSize() int {
+	p := r.packed
+	if p == nil {
+		return 0
+	}
+	n := len(p.group) + len(p.flags)
+	for _, c := range p.columns {
+		n += len(c)
+	}
+	for _, g := range p.groups {
+		n += len(g)
+	}
+	return n
+}
+
+// packedValues returns the float columns of a data point in the order of packedData.columns.
+func packedValues(d *dataPoint, vars []float64) []float64 {
+	return append([]float64{d.Observed, d.Weight, d.Predicted}, vars...)
+}
+
+// appendXOR appends x as a header byte, encoding the numbers of leading and trailing zero bytes, followed
+// by the bytes in between. Zero is the single byte 0.
+func appendXOR(dst []byte, x uint64) []byte {
+	if x == 0 {
+		return append(dst, 0)
+	}
+	lead, trail := 0, 0
+	for x>>uint(56-8*lead) == 0 {
+		lead++
+	}
+	for x>>uint(8*trail)&0xff == 0 {
+		trail++
+	}
+	dst = append(dst, byte(1+lead*8+trail))
+	for i := 7 - lead; i >= trail; i-- {
+		dst = append(dst, byte(x>>uint(8*i)))
+	}
+	return dst
+}
+
+// readXOR decodes a value appended by appendXOR from src, returning it and the number of bytes read.
+func readXOR(src []byte) (uint64, int) {
+	h := int(src[0])
+	if h == 0 {
+		return 0, 1
+	}
+	lead, trail := (h-1)/8, (h-1)%8
+	var x uint64
+	n := 1
+	for i := 7 - lead; i >= trail; i-- {
+		x |= uint64(src[n]) << uint(8*i)
+		n++
+	}
+	return x, n
+}
+
+// unpackAll decodes all data points.
+func (p *packedData) unpackAll() []*dataPoint {
+	points := make([]*dataPoint, p.points)
+	vars := make([]float64, p.points*p.vars)
+	for i := range points {
+		points[i] = &dataPoint{Variables: vars[i*p.vars : (i+1)*p.vars : (i+1)*p.vars]}
+	}
+	for j, column := range p.columns {
+		var prev uint64
+		for i, d := range points {
+			x, n := readXOR(column)
+			column = column[n:]
+			prev ^= x
+			v := math.Float64frombits(prev)
+			switch j {
+			case 0:
+				d.Observed = v
+			case 1:
+				d.Weight = v
+			case 2:
+				d.Predicted = v
+				d.Error = d.Predicted - d.Observed
+			default:
+				vars[i*p.vars+j-packedFixed] = v
+			}
+		}
+	}
+	group := p.group
+	for i, d := range points {
+		g, n := binary.Uvarint(group)
+		group = group[n:]
+		d.Group = p.groups[g]
+		f := p.flags[i/4] >> uint(2*(i%4))
+		d.Treated, d.Post = f&1 != 0, f&2 != 0
+	}
+	return points
+}
@@ -0,0 +1,122 @@
+package regression
+
+import (
+	"math"
+	"testing"
+)
+
+func TestXOREncoding(t *testing.T) {
+	values := []uint64{0, 1, 0xff, 0x100, 1 << 63, math.MaxUint64, 0x00ff00ff00ff0000, math.Float64bits(12.5)}
+	var buf []byte
+	for _, v := range values {
+		buf = appendXOR(buf, v)
+	}
+	for _, want := range values {
+		got, n := readXOR(buf)
+		if got != want {
+			t.Errorf("Expected %x, got %x", want, got)
+		}
+		buf = buf[n:]
+	}
+	if len(buf) != 0 {
+		t.Errorf("Expected all bytes read, %d left", len(buf))
+	}
+}
+
+func TestCompressData(t *testing.T) {
+	r := new(Regression)
+	r.AddCross(PowCross(0, 2))
+	r.SetMissingIndicator(1)
+	var points []*dataPoint
+	for i := 0; i < 1000; i++ {
+		x, z := float64(i%40), float64(i%7)
+		if i%11 == 0 {
+			z = math.NaN()
+		}
+		p := GroupedDataPoint(3+0.5*x+0.01*x*x+float64(i%3), []float64{x, z}, []string{"a", "b", "c"}[i%3])
+		p.Treated, p.Post = i%2 == 0, i%5 == 0
+		points = append(points, p)
+	}
+	r.Train(points...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	want := make([]dataPoint, len(r.data))
+	for i, d := range r.data {
+		want[i] = *d
+		want[i].Variables = append([]float64(nil), d.Variables...)
+	}
+	coeffs := r.coeffs()
+
+	if err := r.CompressData(); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.data) != 0 {
+		t.Fatalf("Expected the data points released, %d left", len(r.data))
+	}
+	// the observed, weight, predicted and error values and the two variables
+	raw := len(want) * 8 * (4 + 2)
+	if size := r.CompressedSize(); size == 0 || size > raw/2 {
+		t.Errorf("Expected the data compressed to under half of %d bytes, got %d", raw, size)
+	}
+
+	r.DecompressData()
+	if len(r.data) != len(want) {
+		t.Fatalf("Expected %d data points, got %d", len(want), len(r.data))
+	}
+	for i, d := range r.data {
+		w := want[i]
+		if d.Observed != w.Observed || d.Weight != w.Weight || d.Predicted != w.Predicted || d.Error != w.Error ||
+			d.Group != w.Group || d.Treated != w.Treated || d.Post != w.Post || d.crossed != w.crossed {
+			t.Fatalf("Point %d: got %+v, want %+v", i, *d, w)
+		}
+		for j, v := range w.Variables {
+			if got := d.Variables[j]; got != v && !(math.IsNaN(got) && math.IsNaN(v)) {
+				t.Fatalf("Point %d variable %d: got %v, want %v", i, j, got, v)
+			}
+		}
+	}
+
+	// refits decompress on demand
+	if err := r.CompressData(); err != nil {
+		t.Fatal(err)
+	}
+	r.Reset()
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	for i, c := range r.coeffs() {
+		assertClose(t, "coefficient", c, coeffs[i], 1e-9)
+	}
+
+	// with the data points trained since after the compressed ones
+	if err := r.CompressData(); err != nil {
+		t.Fatal(err)
+	}
+	r.Train(DataPoint(10, []float64{4, 1}))
+	if changed, err := r.RunIfChanged(); !changed || err != nil {
+		t.Fatalf("Expected a refit, got %v, %v", changed, err)
+	}
+	if len(r.data) != len(want)+1 || r.data[len(want)].Observed != 10 {
+		t.Fatalf("Expected the new data point last, got %d data points", len(r.data))
+	}
+}
+
+func TestCompressDataUpdate(t *testing.T) {
+	r := carsRegression(t)
+	if err := r.CompressData(); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Update(DataPoint(50, []float64{20})); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.data) != len(carsSpeed) {
+		t.Errorf("Expected the data decompressed, got %d data points", len(r.data))
+	}
+
+	g := carsRegression(t)
+	g.SetDataGuard(VerifyOnPredict)
+	if err := g.CompressData(); err != ErrUnsupported {
+		t.Errorf("Expected ErrUnsupported, got %v", err)
+	}
+}
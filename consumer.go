@@ -0,0 +1,181 @@
+package regression
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// ErrNoObserved signals a training record without an observed value.
+var ErrNoObserved = errors.New("record has no observed value")
+
+// MessageSource is a stream of encoded training records, such as a Kafka topic or a NATS subscription.
+// Message queue clients are adapted with MessageSourceFunc, e.g. for github.com/segmentio/kafka-go:
+//
+//	src := regression.MessageSourceFunc(func(ctx context.Context) ([]byte, error) {
+//		m, err := reader.ReadMessage(ctx)
+//		return m.Value, err
+//	})
+//
+// or for github.com/nats-io/nats.go:
+//
+//	src := regression.MessageSourceFunc(func(ctx context.Context) ([]byte, error) {
+//		m, err := sub.NextMsgWithContext(ctx)
+//		if err != nil {
+//			return nil, err
+//		}
+//		return m.Data, nil
+//	})
+type MessageSource interface {
+	// Next blocks until the next message is available. It returns io.EOF at the end of the stream.
+	Next(ctx context.Context) ([]byte, error)
+}
+
+// MessageSourceFunc adapts a function to a MessageSource.
+type MessageSourceFunc func(ctx context.Context) ([]byte, error)
+
+// Next calls f.
+func (f MessageSourceFunc) Next(ctx context.Context) ([]byte, error) {
+	return f(ctx)
+}
+
+// ConsumeOptions configures Consume and ConsumeMessages.
+type ConsumeOptions struct {
+	// BatchSize is the number of data points passed to each Update, 1 when zero.
+	BatchSize int
+	// FlushInterval, when set, updates the model with a partial batch after waiting this long for it to fill.
+	FlushInterval time.Duration
+	// Lock, when set, is held during every Update, so the model can be guarded with the same lock
+	// while it serves predictions.
+	Lock sync.Locker
+	// OnError receives the records that ConsumeMessages can't decode, which are counted by IngestReport.
+	// If nil, they end consumption.
+	OnError func(msg []byte, err error)
+}
+
+// Consume trains a fitted model continuously with the data points received from points, passing them to
+// Update in batches. It returns nil once points is closed and the last batch is applied, the context's
+// error once it is done, or the first error of Update.
+func (r *Regression) Consume(ctx context.Context, points <-chan *dataPoint, opts ConsumeOptions) error {
+	size := opts.BatchSize
+	if size <= 0 {
+		size = 1
+	}
+	var flush <-chan time.Time
+	if opts.FlushInterval > 0 {
+		ticker := time.NewTicker(opts.FlushInterval)
+		defer ticker.Stop()
+		flush = ticker.C
+	}
+
+	batch := make([]*dataPoint, 0, size)
+	update := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if opts.Lock != nil {
+			opts.Lock.Lock()
+			defer opts.Lock.Unlock()
+		}
+		err := r.Update(batch...)
+		batch = batch[:0]
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-flush:
+			if err := update(); err != nil {
+				return err
+			}
+		case d, ok := <-points:
+			if !ok {
+				return update()
+			}
+			batch = append(batch, d)
+			if len(batch) < size {
+				continue
+			}
+			if err := update(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ConsumeMessages trains a fitted model continuously with the records read from src, decoded with
+// DecodeRecord, as Consume does. It returns nil at the end of the stream.
+func (r *Regression) ConsumeMessages(ctx context.Context, src MessageSource, opts ConsumeOptions) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// create the ingest stats before the reader and Update share them
+	r.ingestStats()
+
+	points := make(chan *dataPoint)
+	read := make(chan error, 1)
+	go func() {
+		defer close(points)
+		for {
+			msg, err := src.Next(ctx)
+			if err == io.EOF {
+				read <- nil
+				return
+			}
+			if err != nil {
+				read <- err
+				return
+			}
+			d, err := r.DecodeRecord(msg)
+			if err != nil {
+				if opts.OnError == nil {
+					read <- err
+					return
+				}
+				r.ingestRecord(msg, err)
+				opts.OnError(msg, err)
+				continue
+			}
+			select {
+			case points <- d:
+			case <-ctx.Done():
+				read <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	err := r.Consume(ctx, points, opts)
+	cancel()
+	if readErr := <-read; err == nil {
+		err = readErr
+	}
+	return err
+}
+
+// DecodeRecord decodes a JSON training record into a data point. The record holds its variables as
+// the records of ScoreJSONL do, the observed value in an "observed" field and an optional "weight".
+func (r *Regression) DecodeRecord(b []byte) (*dataPoint, error) {
+	var rec struct {
+		Observed *float64 `json:"observed"`
+		Weight   *float64 `json:"weight"`
+	}
+	if err := json.Unmarshal(b, &rec); err != nil {
+		return nil, err
+	}
+	if rec.Observed == nil {
+		return nil, ErrNoObserved
+	}
+	vars, _, err := r.parseRecord(b)
+	if err != nil {
+		return nil, err
+	}
+	if rec.Weight != nil {
+		return WeightedDataPoint(*rec.Observed, vars, *rec.Weight), nil
+	}
+	return DataPoint(*rec.Observed, vars), nil
+}
@@ -0,0 +1,101 @@
+package regression
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+// consumerRegression fits a model on the first 40 cars.
+func consumerRegression(t *testing.T) *Regression {
+	r := new(Regression)
+	r.SetObserved("dist")
+	r.SetVar(0, "speed")
+	r.Train(carsPoints(false)[:40]...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestConsume(t *testing.T) {
+	r := consumerRegression(t)
+	points := make(chan *dataPoint)
+	go func() {
+		for _, d := range carsPoints(false)[40:] {
+			points <- d
+		}
+		close(points)
+	}()
+	var mu sync.Mutex
+	if err := r.Consume(context.Background(), points, ConsumeOptions{BatchSize: 3, Lock: &mu}); err != nil {
+		t.Fatal(err)
+	}
+	want := carsRegression(t)
+	for i := 0; i < 2; i++ {
+		assertClose(t, "coefficient", r.Coeff(i), want.Coeff(i), 1e-6)
+	}
+
+	before := r.Coeff(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	points = make(chan *dataPoint)
+	done := make(chan error)
+	go func() {
+		done <- r.Consume(ctx, points, ConsumeOptions{BatchSize: 10, FlushInterval: time.Millisecond})
+	}()
+	points <- DataPoint(100, []float64{30})
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if r.Coeff(1) == before {
+		t.Error("Expected the partial batch to be flushed")
+	}
+}
+
+func TestConsumeMessages(t *testing.T) {
+	var msgs []string
+	for i, d := range carsPoints(false)[40:] {
+		if i%2 == 0 {
+			msgs = append(msgs, fmt.Sprintf(`{"observed": %g, "vars": [%g]}`, d.Observed, d.Variables[0]))
+		} else {
+			msgs = append(msgs, fmt.Sprintf(`{"dist": 0, "observed": %g, "speed": %g, "weight": 1}`, d.Observed, d.Variables[0]))
+		}
+	}
+	msgs = append(msgs, `{"vars": [1]}`, `not json`)
+	src := func() MessageSource {
+		i := 0
+		return MessageSourceFunc(func(ctx context.Context) ([]byte, error) {
+			if i == len(msgs) {
+				return nil, io.EOF
+			}
+			i++
+			return []byte(msgs[i-1]), nil
+		})
+	}
+
+	r := consumerRegression(t)
+	var failed int
+	err := r.ConsumeMessages(context.Background(), src(), ConsumeOptions{
+		BatchSize: 4,
+		OnError:   func(msg []byte, err error) { failed++ },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if failed != 2 {
+		t.Errorf("Expected 2 records to fail decoding, got %d", failed)
+	}
+	want := carsRegression(t)
+	for i := 0; i < 2; i++ {
+		assertClose(t, "coefficient", r.Coeff(i), want.Coeff(i), 1e-6)
+	}
+
+	if err := consumerRegression(t).ConsumeMessages(context.Background(), src(), ConsumeOptions{}); err != ErrNoObserved {
+		t.Errorf("Expected ErrNoObserved, got %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package regression
+
+import "math"
+
+// ContributionStat summarizes the contribution, coefficient times value, of one feature to the
+// predictions of a batch.
+type ContributionStat struct {
+	Name    string
+	Mean    float64
+	StdDev  float64
+	Min     float64
+	Max     float64
+	MeanAbs float64
+}
+
+// contributions returns the contribution of every variable and feature cross to the prediction for vars,
+// excluding the offset. Per-segment models use the coefficients of the matching segment.
+func (r *Regression) contributions(vars []float64) []float64 {
+	m := r
+	if s := r.segmentFor(vars); s != nil {
+		m = s
+	}
+	row := m.designRow(vars)
+	c := make([]float64, len(m.coeff)-1)
+	for j := range c {
+		c[j] = m.coeff[j+1] * row[j+1]
+	}
+	return c
+}
+
+// ContributionStats aggregates the per-feature contributions to the predictions for a batch of inputs,
+// showing which variables and feature crosses drive the predictions.
+func (r *Regression) ContributionStats(inputs [][]float64) ([]ContributionStat, error) {
+	if !r.hasRun || len(r.coeff) == 0 {
+		return nil, ErrNotRun
+	}
+	if len(inputs) == 0 {
+		return nil, ErrNotEnoughData
+	}
+
+	stats := make([]ContributionStat, len(r.coeff)-1)
+	for j := range stats {
+		stats[j] = ContributionStat{Name: r.GetVar(j), Min: math.Inf(1), Max: math.Inf(-1)}
+	}
+	sq := make([]float64, len(stats))
+	for _, vars := range inputs {
+		for j, c := range r.contributions(vars) {
+			s := &stats[j]
+			s.Mean += c
+			s.MeanAbs += math.Abs(c)
+			s.Min = math.Min(s.Min, c)
+			s.Max = math.Max(s.Max, c)
+			sq[j] += c * c
+		}
+	}
+
+	n := float64(len(inputs))
+	for j := range stats {
+		s := &stats[j]
+		s.Mean /= n
+		s.MeanAbs /= n
+		s.StdDev = math.Sqrt(math.Max(sq[j]/n-s.Mean*s.Mean, 0))
+	}
+	return stats, nil
+}
@@ -0,0 +1,36 @@
+package regression
+
+import "testing"
+
+func TestContributionStats(t *testing.T) {
+	r := new(Regression)
+	r.SetVar(0, "speed")
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	r.AddCross(PowCross(0, 2))
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := r.ContributionStats([][]float64{{10}, {20}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 2 || stats[0].Name != "speed" || stats[1].Name != "(speed)^2" {
+		t.Fatalf("Unexpected stats %+v", stats)
+	}
+	c1, c2 := r.Coeff(1), r.Coeff(2)
+	assertClose(t, "mean", stats[0].Mean, 15*c1, 1e-9)
+	assertClose(t, "stddev", stats[0].StdDev, 5*c1, 1e-9)
+	assertClose(t, "min", stats[1].Min, 100*c2, 1e-9)
+	assertClose(t, "max", stats[1].Max, 400*c2, 1e-9)
+	assertClose(t, "mean abs", stats[1].MeanAbs, 250*c2, 1e-9)
+
+	if _, err := r.ContributionStats(nil); err != ErrNotEnoughData {
+		t.Errorf("Expected ErrNotEnoughData, got %v", err)
+	}
+	if _, err := new(Regression).ContributionStats([][]float64{{1}}); err != ErrNotRun {
+		t.Errorf("Expected ErrNotRun, got %v", err)
+	}
+}
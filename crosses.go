@@ -1,8 +1,10 @@
 package regression
 
 import (
+	"fmt"
 	"math"
-	"strconv"
+
+	"github.com/sajari/regression/internal/crossname"
 )
 
 type featureCross interface {
@@ -14,45 +16,90 @@ type functionalCross struct {
 	functionName string
 	boundVars    []int
 	crossFn      func([]float64) []float64
+	spec         CrossSpec
+}
+
+// CrossSpec describes one of the package's feature crosses so it can be serialized and rebuilt.
+// Type is "pow" for PowCross, "multiplier" for MultiplierCross, "time" for TimeFeaturesCross
+// or "fourier" for FourierCross.
+type CrossSpec struct {
+	Type      string   `json:"type" yaml:"type"`
+	Vars      []int    `json:"vars" yaml:"vars"`
+	Power     float64  `json:"power,omitempty" yaml:"power,omitempty"`
+	Features  []string `json:"features,omitempty" yaml:"features,omitempty"`
+	Period    float64  `json:"period,omitempty" yaml:"period,omitempty"`
+	Harmonics int      `json:"harmonics,omitempty" yaml:"harmonics,omitempty"`
+}
+
+func (s CrossSpec) build() (featureCross, error) {
+	switch s.Type {
+	case "pow":
+		if len(s.Vars) != 1 {
+			return nil, fmt.Errorf("pow cross expects 1 variable, got %d", len(s.Vars))
+		}
+		return PowCross(s.Vars[0], s.Power), nil
+	case "multiplier":
+		return MultiplierCross(s.Vars...), nil
+	case "time":
+		if len(s.Vars) != 1 {
+			return nil, fmt.Errorf("time cross expects 1 variable, got %d", len(s.Vars))
+		}
+		features := make([]TimeFeature, len(s.Features))
+		for i, f := range s.Features {
+			features[i] = TimeFeature(f)
+			if !features[i].known() {
+				return nil, fmt.Errorf("unknown time feature %q", f)
+			}
+		}
+		return TimeFeaturesCross(s.Vars[0], features...), nil
+	case "fourier":
+		if len(s.Vars) != 1 {
+			return nil, fmt.Errorf("fourier cross expects 1 variable, got %d", len(s.Vars))
+		}
+		if !(s.Period > 0) || s.Harmonics < 1 {
+			return nil, fmt.Errorf("fourier cross expects a positive period and harmonics, got %v and %d", s.Period, s.Harmonics)
+		}
+		return FourierCross(s.Vars[0], s.Period, s.Harmonics), nil
+	}
+	return nil, fmt.Errorf("unknown cross type %q", s.Type)
+}
+
+func specOf(cross featureCross) (CrossSpec, error) {
+	if c, ok := cross.(*functionalCross); ok && c.spec.Type != "" {
+		return c.spec, nil
+	}
+	if c, ok := cross.(interface{ spec() CrossSpec }); ok {
+		return c.spec(), nil
+	}
+	return CrossSpec{}, ErrCrossNotSerializable
 }
 
 func (c *functionalCross) Calculate(input []float64) []float64 {
 	return c.crossFn(input)
 }
 
+// ExtendNames names the single column of the cross after its first variable.
 func (c *functionalCross) ExtendNames(input map[int]string, initialSize int) int {
-	for i, varIndex := range c.boundVars {
-		if input[varIndex] != "" {
-			input[initialSize+i] = "(" + input[varIndex] + ")" + c.functionName
-		}
-	}
-	return len(c.boundVars)
+	return crossname.Extend(input, initialSize, c.boundVars, c.functionName)
 }
 
 // Feature cross based on computing the power of an input.
 func PowCross(i int, power float64) featureCross {
 	return &functionalCross{
-		functionName: "^" + strconv.FormatFloat(power, 'f', -1, 64),
+		functionName: crossname.Pow(power),
 		boundVars:    []int{i},
 		crossFn: func(vars []float64) []float64 {
 
 			return []float64{math.Pow(vars[i], power)}
 		},
+		spec: CrossSpec{Type: "pow", Vars: []int{i}, Power: power},
 	}
 }
 
 // Feature cross based on the multiplication of multiple inputs.
 func MultiplierCross(vars ...int) featureCross {
-	name := ""
-	for i, v := range vars {
-		name += strconv.Itoa(v)
-		if i < (len(vars) - 1) {
-			name += "*"
-		}
-	}
-
 	return &functionalCross{
-		functionName: name,
+		functionName: crossname.Multiplier(vars),
 		boundVars:    vars,
 		crossFn: func(input []float64) []float64 {
 			var output float64 = 1
@@ -61,5 +108,6 @@ func MultiplierCross(vars ...int) featureCross {
 			}
 			return []float64{output}
 		},
+		spec: CrossSpec{Type: "multiplier", Vars: vars},
 	}
 }
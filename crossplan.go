@@ -0,0 +1,108 @@
+package regression
+
+import (
+	"math"
+	"runtime"
+	"sync"
+)
+
+// parallelCrossCells is the number of row × cross evaluations above which applyCrosses
+// splits the rows into chunks and crosses them concurrently.
+const parallelCrossCells = 1 << 15
+
+const (
+	opCustom = iota
+	opPow
+	opMultiplier
+)
+
+// crossOp is one compiled feature cross. The package's power and multiplier crosses are
+// evaluated inline; any other cross goes through its Calculate method.
+type crossOp struct {
+	kind  int
+	vars  []int
+	power float64
+	cross featureCross
+}
+
+// crossPlan is the compiled form of a model's feature crosses, applied in registration order.
+type crossPlan []crossOp
+
+func compileCrosses(crosses []featureCross) crossPlan {
+	plan := make(crossPlan, len(crosses))
+	for i, cross := range crosses {
+		plan[i] = crossOp{kind: opCustom, cross: cross}
+		c, ok := cross.(*functionalCross)
+		if !ok {
+			continue
+		}
+		switch c.spec.Type {
+		case "pow":
+			plan[i] = crossOp{kind: opPow, vars: c.spec.Vars, power: c.spec.Power}
+		case "multiplier":
+			plan[i] = crossOp{kind: opMultiplier, vars: c.spec.Vars}
+		}
+	}
+	return plan
+}
+
+// apply returns vars extended with every cross's outputs. Later crosses see the outputs of
+// earlier ones, exactly as repeated Calculate calls would. width is the expected number of
+// cross outputs; when it is right the result is allocated once.
+func (p crossPlan) apply(vars []float64, width int) []float64 {
+	row := make([]float64, len(vars), len(vars)+width)
+	copy(row, vars)
+	for _, op := range p {
+		switch op.kind {
+		case opPow:
+			row = append(row, math.Pow(row[op.vars[0]], op.power))
+		case opMultiplier:
+			var output float64 = 1
+			for _, v := range op.vars {
+				output *= row[v]
+			}
+			row = append(row, output)
+		default:
+			row = append(row, op.cross.Calculate(row)...)
+		}
+	}
+	return row
+}
+
+// applyAll crosses every point. The first point fixes the output width, the remaining
+// points are split into chunks across GOMAXPROCS goroutines when there is enough work.
+func (p crossPlan) applyAll(data []*dataPoint) {
+	if len(data) == 0 || len(p) == 0 {
+		return
+	}
+	base := len(data[0].Variables)
+	first := p.apply(data[0].Variables, len(p))
+	data[0].Variables = first[:len(first):len(first)]
+	width := len(first) - base
+	data[0].crossed = width
+	rest := data[1:]
+
+	workers := runtime.GOMAXPROCS(0)
+	if len(rest)*len(p) < parallelCrossCells || workers < 2 {
+		p.applyRange(rest, width)
+		return
+	}
+	chunk := (len(rest) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for lo := 0; lo < len(rest); lo += chunk {
+		hi := minInt(lo+chunk, len(rest))
+		wg.Add(1)
+		go func(points []*dataPoint) {
+			defer wg.Done()
+			p.applyRange(points, width)
+		}(rest[lo:hi])
+	}
+	wg.Wait()
+}
+
+func (p crossPlan) applyRange(points []*dataPoint, width int) {
+	for _, point := range points {
+		point.Variables = p.apply(point.Variables, width)
+		point.crossed = width
+	}
+}
@@ -0,0 +1,85 @@
+package regression
+
+import (
+	"math"
+	"runtime"
+	"testing"
+)
+
+type doubleCross struct{ i int }
+
+func (c doubleCross) Calculate(vars []float64) []float64 {
+	return []float64{2 * vars[c.i], -vars[c.i]}
+}
+
+func (c doubleCross) ExtendNames(names map[int]string, initialSize int) int {
+	names[initialSize] = "2*" + names[c.i]
+	names[initialSize+1] = "-" + names[c.i]
+	return 2
+}
+
+func crossPlanPoints(n int) []*dataPoint {
+	points := make([]*dataPoint, n)
+	for i := range points {
+		x := float64(i)
+		points[i] = DataPoint(x*0.5+1, []float64{x, math.Sin(x), x / 7})
+	}
+	return points
+}
+
+func TestCrossPlanMatchesCalculate(t *testing.T) {
+	crosses := []featureCross{
+		PowCross(0, 2),
+		MultiplierCross(1, 2),
+		doubleCross{3},
+		MultiplierCross(0, 5),
+		TimeFeaturesCross(0, HourOfDay),
+	}
+	old := runtime.GOMAXPROCS(4)
+	defer runtime.GOMAXPROCS(old)
+
+	want := crossPlanPoints(parallelCrossCells/len(crosses) + 100)
+	for _, point := range want {
+		for _, cross := range crosses {
+			point.Variables = append(point.Variables, cross.Calculate(point.Variables)...)
+		}
+	}
+	got := crossPlanPoints(len(want))
+	compileCrosses(crosses).applyAll(got)
+
+	for i := range want {
+		if len(got[i].Variables) != len(want[i].Variables) {
+			t.Fatalf("row %d: got %d variables, want %d", i, len(got[i].Variables), len(want[i].Variables))
+		}
+		for j, v := range want[i].Variables {
+			if got[i].Variables[j] != v {
+				t.Fatalf("row %d variable %d: got %v, want %v", i, j, got[i].Variables[j], v)
+			}
+		}
+		if cap(got[i].Variables) != len(got[i].Variables) {
+			t.Errorf("row %d: capacity %d, want %d", i, cap(got[i].Variables), len(got[i].Variables))
+		}
+	}
+}
+
+func TestCrossPlanLeavesInputUntouched(t *testing.T) {
+	backing := []float64{1, 2, 3, 4}
+	points := []*dataPoint{DataPoint(0, backing[:2]), DataPoint(0, backing[2:4])}
+	compileCrosses([]featureCross{MultiplierCross(0, 1)}).applyAll(points)
+	if backing[2] != 3 || backing[3] != 4 {
+		t.Errorf("crossing wrote into the caller's slice: %v", backing)
+	}
+	if points[0].Variables[2] != 2 || points[1].Variables[2] != 12 {
+		t.Errorf("got %v and %v", points[0].Variables, points[1].Variables)
+	}
+}
+
+func BenchmarkApplyCrosses(b *testing.B) {
+	crosses := []featureCross{PowCross(0, 2), PowCross(1, 3), MultiplierCross(0, 1), MultiplierCross(1, 2), MultiplierCross(0, 1, 2)}
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		points := crossPlanPoints(100000)
+		b.StartTimer()
+		compileCrosses(crosses).applyAll(points)
+	}
+}
@@ -0,0 +1,87 @@
+package regression
+
+import "errors"
+
+// ErrCrossIndex signals a feature cross index outside the registered crosses.
+var ErrCrossIndex = errors.New("feature cross index out of range")
+
+// crossEntry is a registered feature cross and whether it takes part in fitting and prediction.
+type crossEntry struct {
+	cross   featureCross
+	enabled bool
+}
+
+// registry returns every registered feature cross in the order of AddCross, recording them on
+// first use. Until a cross is disabled or removed all registered crosses are in r.crosses.
+func (r *Regression) registry() []crossEntry {
+	if r.crossSet == nil {
+		r.crossSet = make([]crossEntry, len(r.crosses))
+		for i, cross := range r.crosses {
+			r.crossSet[i] = crossEntry{cross: cross, enabled: true}
+		}
+	}
+	return r.crossSet
+}
+
+// NumCrosses returns the number of registered feature crosses, enabled or not.
+func (r *Regression) NumCrosses() int {
+	if r.crossSet == nil {
+		return len(r.crosses)
+	}
+	return len(r.crossSet)
+}
+
+// CrossEnabled reports whether the i-th registered feature cross is enabled.
+func (r *Regression) CrossEnabled(i int) bool {
+	if i < 0 || i >= r.NumCrosses() {
+		return false
+	}
+	return r.crossSet == nil || r.crossSet[i].enabled
+}
+
+// SetCrossEnabled enables or disables the i-th registered feature cross, counting from zero in the
+// order of AddCross. Disabled crosses are excluded from fitting and prediction but keep their index,
+// so they can be enabled again. Changing a fitted model discards the fit as Reset does; call Run or
+// RunIfChanged to refit on the training data; until then Predict returns ErrNotRun. Disabled crosses
+// are not saved.
+func (r *Regression) SetCrossEnabled(i int, enabled bool) error {
+	set := r.registry()
+	if i < 0 || i >= len(set) {
+		return ErrCrossIndex
+	}
+	if set[i].enabled == enabled {
+		return nil
+	}
+	set[i].enabled = enabled
+	r.syncCrosses()
+	return nil
+}
+
+// RemoveCross removes the i-th registered feature cross, counting from zero in the order of AddCross.
+// The crosses registered after it move down one index. Changing a fitted model discards the fit as
+// Reset does.
+func (r *Regression) RemoveCross(i int) error {
+	set := r.registry()
+	if i < 0 || i >= len(set) {
+		return ErrCrossIndex
+	}
+	r.crossSet = append(set[:i], set[i+1:]...)
+	r.syncCrosses()
+	return nil
+}
+
+// syncCrosses rebuilds the enabled crosses from the registry. The coefficients and the cross columns
+// of the training data are discarded, as they were computed with the previous crosses.
+func (r *Regression) syncCrosses() {
+	if r.hasRun {
+		r.Reset()
+		r.coeff = make(map[int]float64)
+	}
+	r.uncross()
+	r.crosses = nil
+	for _, e := range r.crossSet {
+		if e.enabled {
+			r.crosses = append(r.crosses, e.cross)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package regression
+
+import "testing"
+
+func TestSetCrossEnabled(t *testing.T) {
+	r := new(Regression)
+	r.AddCross(PowCross(0, 2))
+	r.AddCross(PowCross(0, 3))
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.coeff) != 4 {
+		t.Fatalf("Expected 4 coefficients, got %d", len(r.coeff))
+	}
+
+	if err := r.SetCrossEnabled(1, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Predict([]float64{10}); err != ErrNotRun {
+		t.Errorf("Expected ErrNotRun after disabling a cross, got %v", err)
+	}
+	if r.CrossEnabled(1) || !r.CrossEnabled(0) || r.NumCrosses() != 2 {
+		t.Errorf("Expected only the first cross enabled")
+	}
+	if changed, err := r.RunIfChanged(); !changed || err != nil {
+		t.Fatalf("Expected a refit, got %v, %v", changed, err)
+	}
+	if len(r.coeff) != 3 || len(r.data[0].Variables) != 2 {
+		t.Fatalf("Expected the disabled cross excluded, got %v and %v", r.coeffs(), r.data[0].Variables)
+	}
+
+	// the fit matches a model built with the enabled cross only
+	want := new(Regression)
+	want.AddCross(PowCross(0, 2))
+	for i := range carsSpeed {
+		want.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	if err := want.Run(); err != nil {
+		t.Fatal(err)
+	}
+	for i := range want.coeff {
+		assertClose(t, "coefficient", r.Coeff(i), want.Coeff(i), 1e-9)
+	}
+	got, err := r.Predict([]float64{10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantPrediction, _ := want.Predict([]float64{10})
+	assertClose(t, "prediction", got, wantPrediction, 1e-9)
+
+	// enabling it again restores the original fit
+	if err := r.SetCrossEnabled(1, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.coeff) != 4 {
+		t.Errorf("Expected 4 coefficients, got %d", len(r.coeff))
+	}
+
+	if err := r.SetCrossEnabled(2, true); err != ErrCrossIndex {
+		t.Errorf("Expected ErrCrossIndex, got %v", err)
+	}
+}
+
+func TestRemoveCross(t *testing.T) {
+	r := new(Regression)
+	r.SetVar(0, "speed")
+	r.AddCross(PowCross(0, 2))
+	r.AddCross(PowCross(0, 3))
+	if err := r.SetCrossEnabled(0, false); err != nil {
+		t.Fatal(err)
+	}
+	r.AddCross(PowCross(0, 4))
+	if err := r.RemoveCross(1); err != nil {
+		t.Fatal(err)
+	}
+	if r.NumCrosses() != 2 || r.CrossEnabled(0) || !r.CrossEnabled(1) {
+		t.Fatalf("Expected the disabled square and the enabled fourth power, got %v", r.crossSet)
+	}
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if names := r.coeffNames(); len(names) != 3 || names[2] != "(speed)^4" {
+		t.Errorf("Expected the fourth power only, got %v", names)
+	}
+	if err := r.RemoveCross(-1); err != ErrCrossIndex {
+		t.Errorf("Expected ErrCrossIndex, got %v", err)
+	}
+}
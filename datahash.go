@@ -0,0 +1,164 @@
+package regression
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"math/bits"
+)
+
+// HashOrder selects whether the data hash depends on the order of the data points.
+type HashOrder int
+
+const (
+	// OrderedHash hashes the data points in the order they were trained.
+	OrderedHash HashOrder = iota
+	// UnorderedHash hashes the data points as a multiset, so shuffled data gives the same hash.
+	UnorderedHash
+)
+
+// SetHashOrder sets whether the data hash computed by Run depends on the order of the data points.
+func (r *Regression) SetHashOrder(o HashOrder) {
+	r.hashOrder = o
+}
+
+// DataHash returns the hash, in hex, of the training data the model was fitted with: the observed
+// values, weights, groups and variables before feature crosses are applied. Data points added by Update
+// are included. Compare it with HashData to verify which dataset produced a model. The hash identifies
+// datasets; it isn't meant to withstand deliberately crafted collisions.
+func (r *Regression) DataHash() string {
+	if r.hasher != nil {
+		return r.hasher.String()
+	}
+	return r.dataHash
+}
+
+// HashData computes the hash of data points as DataHash does. It must be called before the points are
+// used for training, which appends the feature crosses to their variables.
+func HashData(points []*dataPoint, order HashOrder) string {
+	h := newDataHasher(order)
+	for _, d := range points {
+		h.add(d)
+	}
+	return h.String()
+}
+
+// dataHasher hashes data points incrementally. The data is mixed into 64-bit lanes, which is an order of
+// magnitude faster than hashing it with SHA-256, and String hashes the lanes with SHA-256. In unordered
+// mode the data points are mixed separately and their digests are added lane by lane, which doesn't
+// depend on their order.
+type dataHasher struct {
+	order  HashOrder
+	stream laneMixer
+	sum    [4]uint64
+	n      uint64
+}
+
+func newDataHasher(order HashOrder) *dataHasher {
+	return &dataHasher{order: order, stream: newLaneMixer()}
+}
+
+func (h *dataHasher) add(d *dataPoint) {
+	h.n++
+	if h.order == OrderedHash {
+		h.stream.point(d)
+		return
+	}
+	m := newLaneMixer()
+	m.point(d)
+	for i, v := range m.digest() {
+		h.sum[i] += v
+	}
+}
+
+func (h *dataHasher) String() string {
+	final := sha256.New()
+	lanes := h.sum
+	if h.order == OrderedHash {
+		final.Write([]byte("ordered"))
+		lanes = h.stream.digest()
+	} else {
+		final.Write([]byte("unordered"))
+	}
+	var b []byte
+	for _, v := range lanes {
+		b = appendUint64(b, v)
+	}
+	final.Write(appendUint64(b, h.n))
+	return hex.EncodeToString(final.Sum(nil))
+}
+
+// The primes of xxHash64, whose round and avalanche laneMixer uses.
+const (
+	prime1 uint64 = 11400714785074694791
+	prime2 uint64 = 14029467366897019727
+	prime3 uint64 = 1609587929392839161
+	prime4 uint64 = 9650029242287828579
+	prime5 uint64 = 2870177450012600261
+)
+
+// laneMixer mixes a stream of 64-bit words into four lanes, each word into the next lane in turn.
+type laneMixer struct {
+	lanes [4]uint64
+	words uint64
+}
+
+func newLaneMixer() laneMixer {
+	return laneMixer{lanes: [4]uint64{prime1, prime2, prime3, prime4}}
+}
+
+func (m *laneMixer) word(w uint64) {
+	l := &m.lanes[m.words&3]
+	*l = bits.RotateLeft64(*l+w*prime2, 31) * prime1
+	m.words++
+}
+
+func (m *laneMixer) point(d *dataPoint) {
+	m.word(math.Float64bits(d.Observed))
+	m.word(math.Float64bits(d.Weight))
+	m.word(uint64(len(d.Group)))
+	for i := 0; i < len(d.Group); i += 8 {
+		var w uint64
+		for k := i; k < len(d.Group) && k < i+8; k++ {
+			w |= uint64(d.Group[k]) << (8 * uint(k-i))
+		}
+		m.word(w)
+	}
+	m.word(uint64(len(d.Variables)))
+	for _, v := range d.Variables {
+		m.word(math.Float64bits(v))
+	}
+}
+
+// digest combines the lanes, so every word of the stream affects every lane of the digest.
+func (m *laneMixer) digest() [4]uint64 {
+	h := m.words * prime5
+	for _, l := range m.lanes {
+		h = (h^bits.RotateLeft64(l*prime2, 31)*prime1)*prime1 + prime4
+	}
+	var d [4]uint64
+	for i, l := range m.lanes {
+		d[i] = avalanche(h + bits.RotateLeft64(l, 7*i+1)*prime3)
+	}
+	return d
+}
+
+// avalanche is the final mix of xxHash64.
+func avalanche(h uint64) uint64 {
+	h ^= h >> 33
+	h *= prime2
+	h ^= h >> 29
+	h *= prime3
+	return h ^ h>>32
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendFloat64(b []byte, v float64) []byte {
+	return appendUint64(b, math.Float64bits(v))
+}
@@ -0,0 +1,96 @@
+package regression
+
+import (
+	"bytes"
+	"testing"
+)
+
+func carsPoints(reverse bool) []*dataPoint {
+	points := make([]*dataPoint, len(carsSpeed))
+	for i := range carsSpeed {
+		j := i
+		if reverse {
+			j = len(carsSpeed) - 1 - i
+		}
+		points[i] = DataPoint(carsDist[j], []float64{carsSpeed[j]})
+	}
+	return points
+}
+
+func TestDataHash(t *testing.T) {
+	for _, order := range []HashOrder{OrderedHash, UnorderedHash} {
+		r := new(Regression)
+		r.SetHashOrder(order)
+		r.Train(carsPoints(false)...)
+		r.AddCross(PowCross(0, 2))
+		if err := r.Run(); err != nil {
+			t.Fatal(err)
+		}
+
+		want := HashData(carsPoints(false), order)
+		if got := r.DataHash(); got != want || len(got) != 64 {
+			t.Errorf("order %d: DataHash() = %q, want %q", order, got, want)
+		}
+		reversed := HashData(carsPoints(true), order)
+		if order == OrderedHash && reversed == want {
+			t.Error("ordered hash didn't change when the data was reversed")
+		}
+		if order == UnorderedHash && reversed != want {
+			t.Error("unordered hash changed when the data was reversed")
+		}
+
+		var buf bytes.Buffer
+		if err := r.Save(&buf); err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := Load(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := loaded.DataHash(); got != want {
+			t.Errorf("loaded DataHash() = %q, want %q", got, want)
+		}
+	}
+
+	if HashData(carsPoints(false), OrderedHash) == HashData(carsPoints(false), UnorderedHash) {
+		t.Error("ordered and unordered hashes should differ")
+	}
+	changed := carsPoints(false)
+	changed[10].Observed++
+	if HashData(changed, UnorderedHash) == HashData(carsPoints(false), UnorderedHash) {
+		t.Error("hash didn't change when an observation changed")
+	}
+}
+
+func TestDataHashStreamAndUpdate(t *testing.T) {
+	points := carsPoints(false)
+	i := 0
+	r := new(Regression)
+	r.SetHashOrder(UnorderedHash)
+	err := r.RunStream(func() (*dataPoint, bool) {
+		if i == 40 {
+			return nil, false
+		}
+		i++
+		return points[i-1], true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := r.DataHash(), HashData(points[:40], UnorderedHash); got != want {
+		t.Errorf("RunStream DataHash() = %q, want %q", got, want)
+	}
+
+	r = new(Regression)
+	r.SetHashOrder(UnorderedHash)
+	r.Train(carsPoints(false)[:40]...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Update(points[40:]...); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := r.DataHash(), HashData(carsPoints(true), UnorderedHash); got != want {
+		t.Errorf("DataHash() after Update = %q, want %q", got, want)
+	}
+}
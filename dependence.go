@@ -0,0 +1,40 @@
+package regression
+
+// PartialDependence returns the average prediction over the training data as variable varIndex
+// sweeps over grid, with the other variables held at their observed values. Feature crosses and
+// per-segment models are applied as in Predict, so the result shows the effect of a variable even
+// when its coefficients are hard to read directly. Weighted data points are weighted in the average.
+// The training data is required, so models fitted with RunStream or loaded with Load are not supported.
+func (r *Regression) PartialDependence(varIndex int, grid []float64) ([]float64, error) {
+	if !r.hasRun || len(r.coeff) == 0 {
+		return nil, ErrNotRun
+	}
+	if len(r.data) == 0 {
+		return nil, ErrNotEnoughData
+	}
+	if varIndex < 0 || varIndex >= r.names.base {
+		return nil, ErrDimensions
+	}
+
+	var weights float64
+	for _, d := range r.data {
+		weights += d.Weight
+	}
+	dependence := make([]float64, len(grid))
+	vars := make([]float64, r.names.base)
+	for g, v := range grid {
+		var sum float64
+		for _, d := range r.data {
+			// the training data has the feature crosses appended to the base variables
+			copy(vars, d.Variables[:r.names.base])
+			vars[varIndex] = v
+			p, err := r.Predict(vars)
+			if err != nil {
+				return nil, err
+			}
+			sum += d.Weight * p
+		}
+		dependence[g] = sum / weights
+	}
+	return dependence, nil
+}
@@ -0,0 +1,41 @@
+package regression
+
+import "testing"
+
+func TestPartialDependence(t *testing.T) {
+	r := new(Regression)
+	r.SetObserved("dist")
+	r.SetVar(0, "speed")
+	r.SetVar(1, "load")
+	for i := range carsSpeed {
+		load := float64(i % 3)
+		r.Train(DataPoint(carsDist[i]+2*load, []float64{carsSpeed[i], load}))
+	}
+	r.AddCross(PowCross(0, 2))
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	var meanLoad float64
+	for i := range carsSpeed {
+		meanLoad += float64(i % 3)
+	}
+	meanLoad /= float64(len(carsSpeed))
+
+	grid := []float64{5, 15, 25}
+	pd, err := r.PartialDependence(0, grid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, x := range grid {
+		want := r.Coeff(0) + r.Coeff(1)*x + r.Coeff(2)*meanLoad + r.Coeff(3)*x*x
+		assertClose(t, "partial dependence", pd[i], want, 1e-9)
+	}
+
+	if _, err := r.PartialDependence(2, grid); err != ErrDimensions {
+		t.Errorf("Expected ErrDimensions for a feature cross, got %v", err)
+	}
+	if _, err := new(Regression).PartialDependence(0, grid); err != ErrNotRun {
+		t.Errorf("Expected ErrNotRun, got %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package regression
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// ErrIncompleteDesign signals that a difference-in-differences design lacks treated or control data points
+// before or after the treatment.
+var ErrIncompleteDesign = errors.New("treated and control groups need data points before and after treatment")
+
+// DiDReport is the difference-in-differences estimate of a treatment effect.
+type DiDReport struct {
+	// Estimate is the coefficient of the interaction of treatment group and period.
+	Estimate float64
+	// StdErr is clustered by the groups of the data points, or heteroskedasticity robust (HC1) when
+	// the data points have no groups.
+	StdErr float64
+	TStat  float64
+	// PValue is two-sided, from a t distribution with the number of clusters minus one degrees of freedom,
+	// or the residual degrees of freedom without clusters.
+	PValue float64
+	// Clusters is the number of clusters, zero when the data points have no groups.
+	Clusters int
+}
+
+// DiDDataPoint creates a *datapoint for a difference-in-differences design, marking whether it belongs to the
+// treatment group and whether it was observed after the treatment. Observations of the same entity should
+// share the cluster, which is used as the group of the data point.
+func DiDDataPoint(obs float64, vars []float64, treated, post bool, cluster string) *dataPoint {
+	return &dataPoint{Observed: obs, Variables: vars, Weight: 1, Group: cluster, Treated: treated, Post: post}
+}
+
+// RunDiD fits a difference-in-differences model: the treatment group indicator, the period indicator and
+// their interaction are appended to the variables of every data point, as variables named "treated", "post"
+// and "treated:post", and the regression is run. The coefficient of the interaction is the estimated
+// treatment effect, reported with standard errors robust to correlation within clusters. The model is
+// fitted to copies of the data points, so the data points trained are left as they are.
+func (r *Regression) RunDiD() (*DiDReport, error) {
+	if !r.initialised {
+		return nil, ErrNotEnoughData
+	}
+	if r.hasRun {
+		return nil, ErrRegressionRun
+	}
+	var cells [4]int
+	for _, d := range r.data {
+		cells[boolIndex(d.Treated)*2+boolIndex(d.Post)]++
+	}
+	for _, n := range cells {
+		if n == 0 {
+			return nil, ErrIncompleteDesign
+		}
+	}
+
+	k := len(r.data[0].Variables)
+	trained := r.data
+	r.data = make([]*dataPoint, len(trained))
+	for i, d := range trained {
+		t, p := float64(boolIndex(d.Treated)), float64(boolIndex(d.Post))
+		c := *d
+		c.Variables = make([]float64, len(d.Variables), len(d.Variables)+3)
+		copy(c.Variables, d.Variables)
+		c.Variables = append(c.Variables, t, p, t*p)
+		r.data[i] = &c
+	}
+	r.SetVar(k, "treated")
+	r.SetVar(k+1, "post")
+	r.SetVar(k+2, "treated:post")
+	if err := r.Run(); err != nil {
+		r.data = trained
+		return nil, err
+	}
+
+	cov, clusters, err := r.clusteredCovariance()
+	if err != nil {
+		return nil, err
+	}
+	report := &DiDReport{
+		Estimate: r.Coeff(k + 3),
+		StdErr:   math.Sqrt(cov.At(k+3, k+3)),
+		Clusters: clusters,
+	}
+	report.TStat = report.Estimate / report.StdErr
+	df := r.dfResidual
+	if clusters > 0 {
+		df = clusters - 1
+	}
+	report.PValue = math.NaN()
+	if df > 0 {
+		d := r.distributions().StudentsT(float64(df))
+		report.PValue = 2 * d.CDF(-math.Abs(report.TStat))
+	}
+	return report, nil
+}
+
+// clusteredCovariance returns the sandwich covariance of the coefficients clustered by the groups of the
+// data points with the small sample correction of CR1, or HC1 when no data point has a group.
+func (r *Regression) clusteredCovariance() (*mat.Dense, int, error) {
+	if r.covariance == nil || r.dfResidual <= 0 {
+		return nil, 0, ErrNotRun
+	}
+	// the bread (X'WX)^-1
+	bread := new(mat.Dense)
+	bread.Scale(1/r.sigma2, r.covariance)
+
+	// the scores X'Wu summed within clusters, every ungrouped data point is a cluster of its own
+	n := len(r.coeff)
+	groups := make(map[string][]float64)
+	var scores [][]float64
+	for _, d := range r.data {
+		s := groups[d.Group]
+		if s == nil {
+			s = make([]float64, n)
+			scores = append(scores, s)
+			if d.Group != "" {
+				groups[d.Group] = s
+			}
+		}
+		row := append([]float64{1}, d.Variables...)
+		u := d.Weight * (d.Observed - d.Predicted)
+		for j := range s {
+			s[j] += row[j] * u
+		}
+	}
+	meat := mat.NewDense(n, n, nil)
+	for _, s := range scores {
+		for i := range s {
+			for j := range s {
+				meat.Set(i, j, meat.At(i, j)+s[i]*s[j])
+			}
+		}
+	}
+	tmp := new(mat.Dense)
+	tmp.Mul(bread, meat)
+	cov := new(mat.Dense)
+	cov.Mul(tmp, bread)
+
+	observations := float64(r.observations)
+	correction := observations / float64(r.dfResidual)
+	clusters := 0
+	if len(groups) > 0 {
+		clusters = len(scores)
+		g := float64(clusters)
+		correction = g / (g - 1) * (observations - 1) / float64(r.dfResidual)
+	}
+	cov.Scale(correction, cov)
+	return cov, clusters, nil
+}
+
+func boolIndex(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
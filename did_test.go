@@ -0,0 +1,108 @@
+package regression
+
+import (
+	"math"
+	"testing"
+)
+
+// didData is a 2x2 design of four cells with four observations each.
+var didData = [4][]float64{
+	{10, 12, 11, 13}, // control, before
+	{14, 15, 13, 16}, // control, after
+	{20, 22, 21, 19}, // treated, before
+	{30, 33, 29, 31}, // treated, after
+}
+
+func TestRunDiD(t *testing.T) {
+	r := new(Regression)
+	var points []*dataPoint
+	for cell, ys := range didData {
+		for _, y := range ys {
+			points = append(points, DiDDataPoint(y, nil, cell >= 2, cell%2 == 1, ""))
+		}
+	}
+	r.Train(points...)
+	report, err := r.RunDiD()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range points {
+		if len(p.Variables) != 0 {
+			t.Fatalf("Expected the trained data points left as they are, got %v", p.Variables)
+		}
+	}
+
+	// the saturated model reproduces the cell means, and HC0 is the sum of the cell variances over their sizes
+	var means [4]float64
+	var hc0, n float64
+	for c, ys := range didData {
+		for _, y := range ys {
+			means[c] += y / float64(len(ys))
+		}
+		var ss float64
+		for _, y := range ys {
+			ss += (y - means[c]) * (y - means[c])
+		}
+		size := float64(len(ys))
+		hc0 += ss / size / size
+		n += size
+	}
+	assertClose(t, "estimate", report.Estimate, (means[3]-means[2])-(means[1]-means[0]), 1e-9)
+	assertClose(t, "stderr", report.StdErr, math.Sqrt(hc0*n/(n-4)), 1e-9)
+	assertClose(t, "t", report.TStat, report.Estimate/report.StdErr, 1e-12)
+	if report.Clusters != 0 || report.PValue >= 0.001 {
+		t.Errorf("Unexpected report %+v", report)
+	}
+	if r.GetVar(2) != "treated:post" {
+		t.Errorf("Expected the interaction to be named, got %q", r.GetVar(2))
+	}
+}
+
+func TestRunDiDClustered(t *testing.T) {
+	singletons := new(Regression)
+	clustered := new(Regression)
+	i := 0
+	for cell, ys := range didData {
+		for j, y := range ys {
+			singletons.Train(DiDDataPoint(y, []float64{float64(j)}, cell >= 2, cell%2 == 1, string(rune('a'+i))))
+			// the same entity before and after treatment
+			clustered.Train(DiDDataPoint(y, []float64{float64(j)}, cell >= 2, cell%2 == 1, string(rune('a'+cell/2*4+j))))
+			i++
+		}
+	}
+	hc1, err := singletons.RunDiD()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cr1, err := clustered.RunDiD()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hc1.Clusters != 16 || cr1.Clusters != 8 {
+		t.Errorf("Expected 16 and 8 clusters, got %d and %d", hc1.Clusters, cr1.Clusters)
+	}
+	// with a cluster per data point CR1 is HC1 up to the factor (n-1)/n * g/(g-1) = 1
+	unclustered := new(Regression)
+	for cell, ys := range didData {
+		for j, y := range ys {
+			unclustered.Train(DiDDataPoint(y, []float64{float64(j)}, cell >= 2, cell%2 == 1, ""))
+		}
+	}
+	want, _ := unclustered.RunDiD()
+	assertClose(t, "singleton clusters", hc1.StdErr, want.StdErr, 1e-9)
+	assertClose(t, "estimate", cr1.Estimate, want.Estimate, 1e-9)
+	if cr1.StdErr == hc1.StdErr {
+		t.Error("Expected clustering to change the standard error")
+	}
+}
+
+func TestRunDiDIncomplete(t *testing.T) {
+	r := new(Regression)
+	for _, y := range didData[0] {
+		r.Train(DiDDataPoint(y, nil, false, false, ""))
+		r.Train(DiDDataPoint(y, nil, true, false, ""))
+	}
+	if _, err := r.RunDiD(); err != ErrIncompleteDesign {
+		t.Errorf("Expected ErrIncompleteDesign, got %v", err)
+	}
+}
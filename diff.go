@@ -0,0 +1,113 @@
+package regression
+
+import "math"
+
+// offsetName is the name used for the offset in reports.
+const offsetName = "(offset)"
+
+// CoeffDelta is the change of a coefficient between two models.
+type CoeffDelta struct {
+	Name string
+	Old  float64
+	New  float64
+	// Delta is New - Old.
+	Delta float64
+	// RelativeDelta is Delta relative to the magnitude of Old, ±Inf when only Old is zero and 0 when
+	// both are.
+	RelativeDelta float64
+}
+
+// DiffReport describes the changes between two versions of a model.
+type DiffReport struct {
+	// Coefficients holds the deltas of the coefficients present in both models, matched by name.
+	Coefficients []CoeffDelta
+	// Added and Removed list the variables only present in the new or the old model.
+	Added   []string
+	Removed []string
+	R2Old   float64
+	R2New   float64
+	R2Delta float64
+	// ObservationsOld and ObservationsNew are the number of data points the models were fitted with.
+	ObservationsOld int
+	ObservationsNew int
+}
+
+// MaxRelativeDelta returns the largest relative change of any coefficient, which is useful to
+// gate deploying a retrained model. A NaN delta, e.g. of a coefficient that isn't finite, counts as
+// infinite, so such a gate fails closed.
+func (d *DiffReport) MaxRelativeDelta() float64 {
+	var max float64
+	for _, c := range d.Coefficients {
+		if math.IsNaN(c.RelativeDelta) {
+			return math.Inf(1)
+		}
+		max = math.Max(max, math.Abs(c.RelativeDelta))
+	}
+	return max
+}
+
+// coeffNames returns the names of the offset, variables and feature crosses, in coefficient order.
+func (r *Regression) coeffNames() []string {
+	names := make([]string, len(r.coeff))
+	for i := range names {
+		if i == 0 {
+			names[i] = offsetName
+		} else {
+			names[i] = r.GetVar(i - 1)
+		}
+	}
+	return names
+}
+
+// relativeDelta returns the change from o to n relative to the magnitude of o.
+func relativeDelta(o, n float64) float64 {
+	if o == 0 {
+		if n == 0 {
+			return 0
+		}
+		return math.Copysign(math.Inf(1), n)
+	}
+	return (n - o) / math.Abs(o)
+}
+
+// Diff compares a model with a newer version, e.g. before deploying a retrained model.
+func Diff(before, after *Regression) (*DiffReport, error) {
+	if !before.hasRun || !after.hasRun {
+		return nil, ErrNotRun
+	}
+	report := &DiffReport{
+		R2Old:           before.R2,
+		R2New:           after.R2,
+		R2Delta:         after.R2 - before.R2,
+		ObservationsOld: before.observations,
+		ObservationsNew: after.observations,
+	}
+
+	old := make(map[string]float64, len(before.coeff))
+	for i, name := range before.coeffNames() {
+		old[name] = before.coeff[i]
+	}
+	seen := make(map[string]bool, len(after.coeff))
+	for i, name := range after.coeffNames() {
+		seen[name] = true
+		o, ok := old[name]
+		if !ok {
+			report.Added = append(report.Added, name)
+			continue
+		}
+		n := after.coeff[i]
+		report.Coefficients = append(report.Coefficients, CoeffDelta{
+			Name:          name,
+			Old:           o,
+			New:           n,
+			Delta:         n - o,
+			RelativeDelta: relativeDelta(o, n),
+		})
+	}
+	for _, name := range before.coeffNames() {
+		if !seen[name] {
+			report.Removed = append(report.Removed, name)
+		}
+	}
+	return report, nil
+}
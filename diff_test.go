@@ -0,0 +1,63 @@
+package regression
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	before := carsRegression(t)
+
+	after := new(Regression)
+	after.SetObserved("dist")
+	after.SetVar(0, "speed")
+	for i := range carsSpeed[:40] {
+		after.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	after.AddCross(PowCross(0, 2))
+	if err := after.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := Diff(before, after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Coefficients) != 2 || d.Coefficients[0].Name != "(offset)" || d.Coefficients[1].Name != "speed" {
+		t.Fatalf("Unexpected coefficient deltas %+v", d.Coefficients)
+	}
+	c := d.Coefficients[1]
+	assertClose(t, "delta", c.Delta, after.Coeff(1)-before.Coeff(1), 1e-12)
+	assertClose(t, "relative delta", c.RelativeDelta, c.Delta/before.Coeff(1), 1e-12)
+	if len(d.Added) != 1 || d.Added[0] != "(speed)^2" || len(d.Removed) != 0 {
+		t.Errorf("Expected (speed)^2 to be added, got %v and %v", d.Added, d.Removed)
+	}
+	assertClose(t, "R2 delta", d.R2Delta, after.R2-before.R2, 1e-12)
+	if d.ObservationsOld != 50 || d.ObservationsNew != 40 {
+		t.Errorf("Expected 50 and 40 observations, got %d and %d", d.ObservationsOld, d.ObservationsNew)
+	}
+	if d.MaxRelativeDelta() <= 0 {
+		t.Error("Expected a positive maximum relative delta")
+	}
+
+	reverse, _ := Diff(after, before)
+	if len(reverse.Removed) != 1 || len(reverse.Added) != 0 {
+		t.Errorf("Expected (speed)^2 to be removed, got %v and %v", reverse.Added, reverse.Removed)
+	}
+	if _, err := Diff(new(Regression), after); err != ErrNotRun {
+		t.Errorf("Expected ErrNotRun, got %v", err)
+	}
+}
+
+func TestRelativeDeltaZero(t *testing.T) {
+	if v := relativeDelta(0, 0); v != 0 {
+		t.Errorf("Expected 0 for an unchanged zero coefficient, got %v", v)
+	}
+	if v := relativeDelta(0, -2); !math.IsInf(v, -1) {
+		t.Errorf("Expected -Inf for a coefficient leaving zero, got %v", v)
+	}
+	d := &DiffReport{Coefficients: []CoeffDelta{{RelativeDelta: 0.1}, {RelativeDelta: math.NaN()}}}
+	if v := d.MaxRelativeDelta(); !math.IsInf(v, 1) {
+		t.Errorf("Expected a NaN delta to count as infinite, got %v", v)
+	}
+}
@@ -0,0 +1,91 @@
+package regression
+
+import "math"
+
+// Equal reports whether two fitted models are semantically the same: they have the same schema and
+// feature crosses, and make the same predictions with exactly the same coefficients. Training data,
+// metrics and metadata are not compared.
+func (r *Regression) Equal(other *Regression) bool {
+	return r.ApproxEqual(other, 0)
+}
+
+// ApproxEqual reports whether two fitted models are the same as Equal does, with their coefficients
+// within tol of each other, relative to their magnitude when it is above one.
+func (r *Regression) ApproxEqual(other *Regression, tol float64) bool {
+	if r == other {
+		return true
+	}
+	if r == nil || other == nil || r.hasRun != other.hasRun || len(r.coeff) != len(other.coeff) {
+		return false
+	}
+	if !r.sameSchema(other) || r.crossesSignature() != other.crossesSignature() {
+		return false
+	}
+	for i := range r.coeff {
+		if !approx(r.coeff[i], other.coeff[i], tol) {
+			return false
+		}
+	}
+	if len(r.clips) != len(other.clips) {
+		return false
+	}
+	for i, c := range r.clips {
+		o, ok := other.clips[i]
+		if !ok || !approx(c[0], o[0], tol) || !approx(c[1], o[1], tol) {
+			return false
+		}
+	}
+	if len(r.fixedEffects) != len(other.fixedEffects) {
+		return false
+	}
+	for g, e := range r.fixedEffects {
+		o, ok := other.fixedEffects[g]
+		if !ok || !approx(e, o, tol) {
+			return false
+		}
+	}
+	if len(r.groupEffects) != len(other.groupEffects) {
+		return false
+	}
+	for g, e := range r.groupEffects {
+		o, ok := other.groupEffects[g]
+		if !ok || len(e) != len(o) {
+			return false
+		}
+		for k := range e {
+			if !approx(e[k], o[k], tol) {
+				return false
+			}
+		}
+	}
+	if r.split != other.split || (r.split && r.splitVar != other.splitVar) || len(r.segments) != len(other.segments) {
+		return false
+	}
+	for v, s := range r.segments {
+		if !s.ApproxEqual(other.segments[v], tol) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameSchema reports whether two models have the same observed value and variables, with the same types.
+func (r *Regression) sameSchema(other *Regression) bool {
+	a, b := r.Schema(), other.Schema()
+	if a.Observed != b.Observed || len(a.Vars) != len(b.Vars) {
+		return false
+	}
+	for i := range a.Vars {
+		if a.Vars[i] != b.Vars[i] || a.Types[i] != b.Types[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func approx(a, b, tol float64) bool {
+	if a == b {
+		return true
+	}
+	return math.Abs(a-b) <= tol*math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+}
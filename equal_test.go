@@ -0,0 +1,57 @@
+package regression
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEqual(t *testing.T) {
+	r := carsRegression(t)
+	if !r.Equal(carsRegression(t)) {
+		t.Error("Expected models fitted on the same data to be equal")
+	}
+
+	var buf bytes.Buffer
+	if err := r.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Equal(loaded) || !loaded.Equal(r) {
+		t.Error("Expected a loaded model to equal the saved one")
+	}
+
+	nudged := carsRegression(t)
+	nudged.coeff[1] += 1e-9
+	if r.Equal(nudged) {
+		t.Error("Expected different coefficients not to be equal")
+	}
+	if !r.ApproxEqual(nudged, 1e-6) {
+		t.Error("Expected nearby coefficients to be approximately equal")
+	}
+	nudged.coeff[1] += 1e-3
+	if r.ApproxEqual(nudged, 1e-6) {
+		t.Error("Expected distant coefficients not to be approximately equal")
+	}
+
+	renamed := carsRegression(t)
+	renamed.SetVar(0, "velocity")
+	if r.ApproxEqual(renamed, 1) {
+		t.Error("Expected a different schema not to be equal")
+	}
+	crossed := carsRegression(t)
+	crossed.AddCross(PowCross(0, 2))
+	if r.ApproxEqual(crossed, 1) {
+		t.Error("Expected different crosses not to be equal")
+	}
+	clipped := carsRegression(t)
+	clipped.clips = map[int][2]float64{0: {5, 20}}
+	if r.Equal(clipped) {
+		t.Error("Expected different clips not to be equal")
+	}
+	if r.Equal(new(Regression)) || r.Equal(nil) {
+		t.Error("Expected an unfitted model not to be equal")
+	}
+}
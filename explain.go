@@ -0,0 +1,109 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// explainAlpha is the significance level used by ExplainModel.
+const explainAlpha = 0.05
+
+// ExplainModel describes the fitted model in plain English for stakeholder-facing reports, e.g.
+// "Each additional USD of price is associated with a change of -0.42 orders in demand, holding the other
+// variables constant (statistically significant, p = 0.0012)." Variables are described by their names and
+// units, see SetVarUnit. Effects are significant when their p-value is below 0.05. Numbers are rendered
+// with the number format, see SetNumberFormat, or with two or three significant digits by default.
+func (r *Regression) ExplainModel() (string, error) {
+	if !r.hasRun || len(r.coeff) == 0 {
+		return "", ErrNotRun
+	}
+	observed := r.names.obs
+	if observed == "" {
+		observed = "the observed value"
+	}
+	var lines []string
+	if r.observations > 0 {
+		lines = append(lines, fmt.Sprintf("The model explains %s%% of the variance in %s (R² = %s), based on %d observations.",
+			r.formatNumber(100*r.R2, 1), observed, r.explainNumber(r.R2, 3), r.observations))
+	}
+
+	aliased := make(map[int]bool, len(r.aliased))
+	for _, i := range r.aliased {
+		aliased[i] = true
+	}
+	holding := ""
+	if len(r.coeff) > 2 {
+		holding = ", holding the other variables constant"
+	}
+	for i := 1; i < len(r.coeff); i++ {
+		name := r.GetVar(i - 1)
+		if aliased[i] {
+			lines = append(lines, fmt.Sprintf("%s was left out of the fit, as it adds no information beyond the other variables.", name))
+			continue
+		}
+		var line string
+		if i-1 < r.names.base {
+			unit := r.GetVarUnit(i - 1)
+			if unit == "" {
+				unit = "unit"
+			}
+			line = fmt.Sprintf("Each additional %s of %s is associated with %s%s", unit, name, r.explainChange(r.coeff[i]), holding)
+		} else {
+			line = fmt.Sprintf("The term %s adds %s per unit", name, r.explainChange(r.coeff[i]))
+		}
+		lines = append(lines, line+r.explainSignificance(i)+".")
+	}
+	lines = append(lines, fmt.Sprintf("With all variables at zero, the model predicts %s.", r.explainValue(r.coeff[0])))
+	return strings.Join(lines, "\n"), nil
+}
+
+// explainChange describes a change of the observed value.
+func (r *Regression) explainChange(v float64) string {
+	observed := r.names.obs
+	if observed == "" {
+		observed = "the observed value"
+	}
+	change := r.explainNumber(v, 3)
+	if v >= 0 {
+		change = "+" + change
+	}
+	if r.names.obsUnit != "" {
+		return fmt.Sprintf("a change of %s %s in %s", change, r.names.obsUnit, observed)
+	}
+	return fmt.Sprintf("a change of %s in %s", change, observed)
+}
+
+// explainValue describes a value of the observed value.
+func (r *Regression) explainValue(v float64) string {
+	value := r.explainNumber(v, 3)
+	if r.names.obsUnit != "" {
+		value += " " + r.names.obsUnit
+	}
+	if r.names.obs != "" {
+		return r.names.obs + " of " + value
+	}
+	return value
+}
+
+// explainSignificance describes the p-value of coefficient i, if known.
+func (r *Regression) explainSignificance(i int) string {
+	p := r.PValue(i)
+	if math.IsNaN(p) {
+		return ""
+	}
+	if p < explainAlpha {
+		return " (statistically significant, p = " + r.explainNumber(p, 2) + ")"
+	}
+	return " (not statistically significant, p = " + r.explainNumber(p, 2) + ")"
+}
+
+// explainNumber renders v with the number format, or with the given number of significant digits when
+// there is none.
+func (r *Regression) explainNumber(v float64, digits int) string {
+	if r.numberFormat == nil {
+		return strconv.FormatFloat(v, 'g', digits, 64)
+	}
+	return r.formatNumber(v, digits)
+}
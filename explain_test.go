@@ -0,0 +1,119 @@
+package regression
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestExplainModel(t *testing.T) {
+	r := carsRegression(t)
+	text, err := r.ExplainModel()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"The model explains 65.1% of the variance in dist (R² = 0.651), based on 50 observations.",
+		"Each additional unit of speed is associated with a change of +3.93 in dist (statistically significant, p = 1.5e-12).",
+		"With all variables at zero, the model predicts dist of -17.6.",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in\n%s", want, text)
+		}
+	}
+
+	r.SetVarUnit(0, "mph")
+	r.SetObservedUnit("ft")
+	var buf bytes.Buffer
+	if err := r.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text, _ = loaded.ExplainModel()
+	want := "Each additional mph of speed is associated with a change of +3.93 ft in dist (statistically significant"
+	if !strings.Contains(text, want) {
+		t.Errorf("Expected %q in\n%s", want, text)
+	}
+
+	if _, err := new(Regression).ExplainModel(); err != ErrNotRun {
+		t.Errorf("Expected ErrNotRun, got %v", err)
+	}
+}
+
+func TestExplainModelCrosses(t *testing.T) {
+	r := new(Regression)
+	r.SetObserved("dist")
+	r.SetVar(0, "speed")
+	r.SetVar(1, "speed again")
+	r.AddCross(PowCross(0, 2))
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i], carsSpeed[i]}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	text, _ := r.ExplainModel()
+	for _, want := range []string{
+		"holding the other variables constant (not statistically significant",
+		"speed again was left out of the fit",
+		"The term (speed)^2 adds a change of +0.1 in dist per unit",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in\n%s", want, text)
+		}
+	}
+}
+
+func TestExplainModelNumberFormat(t *testing.T) {
+	r := carsRegression(t)
+	f, _ := LocaleFormat("de", 2)
+	f.Scientific = 1e6
+	if err := r.SetNumberFormat(f); err != nil {
+		t.Fatal(err)
+	}
+	text, _ := r.ExplainModel()
+	for _, want := range []string{
+		"The model explains 65,11% of the variance in dist (R² = 0,65)",
+		"a change of +3,93 in dist (statistically significant, p = 1,49e-12)",
+		"the model predicts dist of -17,58.",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in\n%s", want, text)
+		}
+	}
+}
+
+func TestVarUnits(t *testing.T) {
+	r := new(Regression)
+	r.SetObserved("dist")
+	r.SetObservedUnit("ft")
+	r.SetVar(0, "speed")
+	r.SetVarUnit(0, "mph")
+	r.AddCross(PowCross(0, 2))
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(r.Formula, "Predicted [ft] = 2.4701 + speed [mph]*0.9133 + (speed)^2*") {
+		t.Errorf("Unexpected formula %q", r.Formula)
+	}
+	if r.GetVarUnit(0) != "mph" || r.GetVarUnit(1) != "" || r.GetObservedUnit() != "ft" {
+		t.Errorf("Unexpected units %q, %q and %q", r.GetVarUnit(0), r.GetVarUnit(1), r.GetObservedUnit())
+	}
+	if !strings.HasPrefix(r.String(), "dist [ft]|\tspeed [mph]|\t(speed)^2\n") {
+		t.Errorf("Unexpected summary header in %q", r.String())
+	}
+
+	card, err := r.ModelCard()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card.ObservedUnit != "ft" || len(card.Units) != 2 || card.Units[0] != "mph" || card.Units[1] != "" {
+		t.Errorf("Unexpected units in the model card: %q and %q", card.ObservedUnit, card.Units)
+	}
+}
@@ -0,0 +1,176 @@
+package regression
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// ExportFormat selects the file format written by ExportData.
+type ExportFormat int
+
+const (
+	// ExportCSV writes a header row of column names followed by a row per data point.
+	ExportCSV ExportFormat = iota
+	// ExportJSON writes an array with an object per data point, keyed by column name in column order.
+	// Values that are not finite are written as null.
+	ExportJSON
+)
+
+// ExportData writes the training data of a fitted model to w, for analysis outside Go, e.g. with
+// pandas.read_csv or in a spreadsheet. The columns are the observed value, the variables including
+// the feature crosses, the weight, the group if any data point has one, and the Predicted and Error
+// values of the fit. Columns are named as in the formula; an unnamed observed value is "Observed".
+// Use ExportAnonymized for data shared more widely.
+func (r *Regression) ExportData(w io.Writer, format ExportFormat) error {
+	if !r.hasRun || len(r.coeff) == 0 {
+		return ErrNotRun
+	}
+	if len(r.data) == 0 {
+		// loaded models hold no training data
+		return ErrNotEnoughData
+	}
+	return r.export(w, format, r.data, true)
+}
+
+// export writes data in format, with the Predicted and Error columns if fit is set.
+func (r *Regression) export(w io.Writer, format ExportFormat, data []*dataPoint, fit bool) error {
+	columns := r.exportColumns()
+	if !fit {
+		columns = columns[:len(columns)-2]
+	}
+	switch format {
+	case ExportCSV:
+		return r.exportCSV(w, columns, data, fit)
+	case ExportJSON:
+		return r.exportJSON(w, columns, data, fit)
+	}
+	return fmt.Errorf("unknown export format %d", format)
+}
+
+// exportColumns returns the column names of ExportData.
+func (r *Regression) exportColumns() []string {
+	obs := r.GetObserved()
+	if obs == "" {
+		obs = "Observed"
+	}
+	columns := []string{obs}
+	for i := range r.data[0].Variables {
+		columns = append(columns, r.GetVar(i))
+	}
+	columns = append(columns, "Weight")
+	if r.hasGroups() {
+		columns = append(columns, "Group")
+	}
+	return append(columns, "Predicted", "Error")
+}
+
+func (r *Regression) hasGroups() bool {
+	for _, d := range r.data {
+		if d.Group != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// exportValues returns the numeric values of a data point in column order, without the group. The
+// tail holds the values of the fit if fit is set.
+func exportValues(d *dataPoint, fit bool) (head, tail []float64) {
+	head = append([]float64{d.Observed}, d.Variables...)
+	head = append(head, d.Weight)
+	if !fit {
+		return head, nil
+	}
+	return head, []float64{d.Predicted, d.Error}
+}
+
+func (r *Regression) exportCSV(w io.Writer, columns []string, data []*dataPoint, fit bool) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+	groups := r.hasGroups()
+	record := make([]string, 0, len(columns))
+	for _, d := range data {
+		head, tail := exportValues(d, fit)
+		record = record[:0]
+		for _, v := range head {
+			record = append(record, strconv.FormatFloat(v, 'g', -1, 64))
+		}
+		if groups {
+			record = append(record, d.Group)
+		}
+		for _, v := range tail {
+			record = append(record, strconv.FormatFloat(v, 'g', -1, 64))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func (r *Regression) exportJSON(w io.Writer, columns []string, data []*dataPoint, fit bool) error {
+	keys := make([][]byte, len(columns))
+	for i, c := range columns {
+		b, err := json.Marshal(c)
+		if err != nil {
+			return err
+		}
+		keys[i] = append(b, ':')
+	}
+	groups := r.hasGroups()
+	bw := bufio.NewWriter(w)
+	bw.WriteByte('[')
+	var buf []byte
+	for i, d := range data {
+		head, tail := exportValues(d, fit)
+		buf = buf[:0]
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, "\n{"...)
+		col := 0
+		field := func(value []byte) {
+			if col > 0 {
+				buf = append(buf, ',')
+			}
+			buf = append(buf, keys[col]...)
+			buf = append(buf, value...)
+			col++
+		}
+		for _, v := range head {
+			field(jsonNumber(v))
+		}
+		if groups {
+			b, err := json.Marshal(d.Group)
+			if err != nil {
+				return err
+			}
+			field(b)
+		}
+		for _, v := range tail {
+			field(jsonNumber(v))
+		}
+		buf = append(buf, '}')
+		if _, err := bw.Write(buf); err != nil {
+			return err
+		}
+	}
+	bw.WriteString("\n]\n")
+	return bw.Flush()
+}
+
+// jsonNumber formats v as a JSON number, or null when it is not finite.
+func jsonNumber(v float64) []byte {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return []byte("null")
+	}
+	return strconv.AppendFloat(nil, v, 'g', -1, 64)
+}
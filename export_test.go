@@ -0,0 +1,78 @@
+package regression
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+	"testing"
+)
+
+func TestExportDataCSV(t *testing.T) {
+	r := carsRegression(t)
+	var buf bytes.Buffer
+	if err := r.ExportData(&buf, ExportCSV); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"dist", "speed", "Weight", "Predicted", "Error"}
+	if len(records) != len(carsSpeed)+1 || len(records[0]) != len(want) {
+		t.Fatalf("Expected a header and %d rows of %d columns, got %v", len(carsSpeed), len(want), records[0])
+	}
+	for i, name := range want {
+		if records[0][i] != name {
+			t.Errorf("Expected column %d to be %q, got %q", i, name, records[0][i])
+		}
+	}
+	for i, record := range records[1:] {
+		predicted, _ := strconv.ParseFloat(record[3], 64)
+		if record[1] != strconv.FormatFloat(carsSpeed[i], 'g', -1, 64) || predicted != r.data[i].Predicted {
+			t.Errorf("Row %d: got %v", i, record)
+		}
+	}
+}
+
+func TestExportDataJSON(t *testing.T) {
+	r := new(Regression)
+	r.SetObserved("y")
+	r.SetVar(0, "x")
+	r.AddCross(PowCross(0, 2))
+	for i := 0; i < 20; i++ {
+		x := float64(i)
+		p := DataPoint(1+2*x+0.5*x*x+float64(i%3), []float64{x})
+		p.Group = "g" + strconv.Itoa(i%2)
+		r.Train(p)
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := r.ExportData(&buf, ExportJSON); err != nil {
+		t.Fatal(err)
+	}
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &rows); err != nil {
+		t.Fatalf("%v in %s", err, buf.String())
+	}
+	if len(rows) != 20 {
+		t.Fatalf("Expected 20 rows, got %d", len(rows))
+	}
+	row := rows[3]
+	if row["y"] != r.data[3].Observed || row["(x)^2"] != 9.0 || row["Group"] != "g1" ||
+		row["Predicted"] != r.data[3].Predicted || row["Error"] != r.data[3].Error {
+		t.Errorf("Unexpected row %v", row)
+	}
+	if keys := bytes.Index(buf.Bytes(), []byte(`"y":`)); keys > bytes.Index(buf.Bytes(), []byte(`"Predicted":`)) {
+		t.Errorf("Expected the columns in order, got %s", buf.String())
+	}
+}
+
+func TestExportDataNotRun(t *testing.T) {
+	r := new(Regression)
+	if err := r.ExportData(new(bytes.Buffer), ExportCSV); err != ErrNotRun {
+		t.Errorf("Expected ErrNotRun, got %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package regression
+
+import (
+	"errors"
+	"expvar"
+	"math"
+	"sync"
+	"time"
+)
+
+// ErrExpvarExists signals that an expvar variable with the name is already published.
+var ErrExpvarExists = errors.New("expvar variable already published")
+
+// ExpvarStatus is the state of a model published with PublishExpvar. R2 and RMSE are omitted when
+// they aren't finite, which JSON can't represent.
+type ExpvarStatus struct {
+	Run          bool      `json:"run"`
+	TrainedAt    time.Time `json:"trained_at,omitempty"`
+	DataPoints   int       `json:"data_points"`
+	Observations int       `json:"observations"`
+	Observed     string    `json:"observed"`
+	Variables    []string  `json:"variables,omitempty"`
+	Coefficients []float64 `json:"coefficients,omitempty"`
+	R2           *float64  `json:"r2,omitempty"`
+	RMSE         *float64  `json:"rmse,omitempty"`
+}
+
+// PublishExpvar publishes the status of a model as the expvar variable name, served as JSON on
+// /debug/vars by the default HTTP mux once expvar is imported. The status is read on every request,
+// so it reflects updates of the model; use PublishExpvarLocked when the model is updated concurrently.
+func PublishExpvar(name string, r *Regression) error {
+	return PublishExpvarLocked(name, r, nil)
+}
+
+// PublishExpvarLocked publishes the status of a model like PublishExpvar, holding lock while reading it.
+// It should be the lock guarding the updates of the model, such as the Lock of ConsumeOptions.
+func PublishExpvarLocked(name string, r *Regression, lock sync.Locker) error {
+	if expvar.Get(name) != nil {
+		return ErrExpvarExists
+	}
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		if lock != nil {
+			lock.Lock()
+			defer lock.Unlock()
+		}
+		return r.ExpvarStatus()
+	}))
+	return nil
+}
+
+// ExpvarStatus returns the status of the model as published by PublishExpvar.
+func (r *Regression) ExpvarStatus() ExpvarStatus {
+	s := ExpvarStatus{
+		Run:          r.hasRun && len(r.coeff) > 0,
+		TrainedAt:    r.trainedAt,
+		DataPoints:   len(r.data),
+		Observations: r.observations,
+		Observed:     r.GetObserved(),
+	}
+	if !s.Run {
+		return s
+	}
+	names := r.coeffNames()
+	s.Variables = names[1:]
+	s.Coefficients = make([]float64, len(r.coeff))
+	for i := range s.Coefficients {
+		s.Coefficients[i] = r.coeff[i]
+	}
+	s.R2 = finiteOrNil(r.R2)
+	s.RMSE = finiteOrNil(r.RMSE())
+	return s
+}
+
+func finiteOrNil(v float64) *float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil
+	}
+	return &v
+}
@@ -0,0 +1,47 @@
+package regression
+
+import (
+	"encoding/json"
+	"expvar"
+	"sync"
+	"testing"
+)
+
+func TestPublishExpvar(t *testing.T) {
+	r := new(Regression)
+	r.SetObserved("dist")
+	if err := PublishExpvar("regression_test_cars", r); err != nil {
+		t.Fatal(err)
+	}
+	var status ExpvarStatus
+	if err := json.Unmarshal([]byte(expvar.Get("regression_test_cars").String()), &status); err != nil {
+		t.Fatal(err)
+	}
+	if status.Run || status.Coefficients != nil || status.R2 != nil {
+		t.Errorf("Unexpected status before Run %+v", status)
+	}
+
+	for i, speed := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{speed}))
+	}
+	r.SetVar(0, "speed")
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(expvar.Get("regression_test_cars").String()), &status); err != nil {
+		t.Fatal(err)
+	}
+	if !status.Run || status.DataPoints != len(carsSpeed) || status.Observations != len(carsSpeed) ||
+		status.Observed != "dist" || len(status.Variables) != 1 || status.Variables[0] != "speed" {
+		t.Errorf("Unexpected status %+v", status)
+	}
+	assertClose(t, "slope", status.Coefficients[1], r.Coeff(1), 1e-9)
+	assertClose(t, "r2", *status.R2, r.R2, 1e-9)
+	if status.TrainedAt.IsZero() {
+		t.Error("Expected the training time")
+	}
+
+	if err := PublishExpvarLocked("regression_test_cars", r, new(sync.Mutex)); err != ErrExpvarExists {
+		t.Errorf("Expected ErrExpvarExists, got %v", err)
+	}
+}
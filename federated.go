@@ -0,0 +1,241 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ErrNoUpdates signals that Aggregate had no valid client update to combine.
+var ErrNoUpdates = errors.New("no valid client updates")
+
+// ClientUpdate is the contribution of a participant to federated training: the sufficient statistics
+// of its local data, from which the least squares fit of the pooled data follows exactly, so the data
+// itself never leaves the client. The gradient of the squared loss at any coefficients c follows from
+// them as X'X c - X'y. It is serialized as JSON for transport.
+type ClientUpdate struct {
+	// Client identifies the participant.
+	Client string `json:"client"`
+	// Vars is the number of base variables, without the feature crosses.
+	Vars int `json:"vars"`
+	// Rows is the number of data points with a non-zero weight and Weight their total weight.
+	Rows   int     `json:"rows"`
+	Weight float64 `json:"weight"`
+	// XTX is the upper triangle of the weighted X'X of the design matrix, with the intercept first;
+	// the entries below the diagonal are ignored. XTY is X'y, YTY y'y and SumY the sum of y.
+	XTX  [][]float64 `json:"xtx"`
+	XTY  []float64   `json:"xty"`
+	YTY  float64     `json:"yty"`
+	SumY float64     `json:"sum_y"`
+}
+
+// LocalUpdate computes the update of a client from its data points, with the feature crosses of the
+// model applied as in Run. Clients set up the model as the server does, e.g. from the same Spec.
+// Models with missing indicators are not supported, as the imputed values are fitted on the pooled data.
+func (r *Regression) LocalUpdate(client string, d []*dataPoint) (*ClientUpdate, error) {
+	if len(r.missing) > 0 {
+		return nil, ErrUnsupported
+	}
+	if len(d) == 0 {
+		return nil, ErrNotEnoughData
+	}
+	var a *normalEquations
+	for _, p := range d {
+		if len(p.Variables) != len(d[0].Variables) {
+			return nil, ErrDimensions
+		}
+		row := r.designRow(p.Variables)
+		if a == nil {
+			a = newNormalEquations(len(row))
+		}
+		a.add(row, r.clipObserved(p.Observed), p.Weight)
+	}
+	return &ClientUpdate{
+		Client: client,
+		Vars:   len(d[0].Variables),
+		Rows:   a.n,
+		Weight: a.sumW,
+		XTX:    a.xtx,
+		XTY:    a.xty,
+		YTY:    a.yty,
+		SumY:   a.sumY,
+	}, nil
+}
+
+// AggregateOptions configures Aggregate.
+type AggregateOptions struct {
+	// Weights scales the statistics of clients, e.g. by their trust; clients without a weight get 1
+	// and a weight of zero excludes a client.
+	Weights map[string]float64
+	// MaxWeight clips the total weight of the data points of every client, scaling down the statistics
+	// of larger clients, so no single participant dominates the fit. Zero doesn't clip.
+	MaxWeight float64
+}
+
+// Aggregation is the result of Aggregate.
+type Aggregation struct {
+	// Update holds the combined statistics, with the client ids joined by commas as its Client.
+	Update *ClientUpdate
+	// Accepted are the clients combined, and Clipped those of them scaled down by MaxWeight.
+	Accepted []string
+	Clipped  []string
+	// Rejected explains why the updates of the other clients were dropped.
+	Rejected map[string]string
+}
+
+// Aggregate combines the updates of clients on the server. As the clients aren't trusted, updates that
+// are malformed, inconsistent with the first update or not finite are rejected rather than failing the
+// aggregation; the statistics of the rest are weighted and clipped as set by opts and summed. Fit the
+// result with RunAggregate.
+func Aggregate(updates []*ClientUpdate, opts AggregateOptions) (*Aggregation, error) {
+	agg := &Aggregation{Rejected: make(map[string]string)}
+	var total *normalEquations
+	vars := 0
+	for _, u := range updates {
+		if u == nil {
+			continue
+		}
+		w, ok := opts.Weights[u.Client]
+		if !ok {
+			w = 1
+		}
+		if reason := u.invalid(); reason != "" {
+			agg.Rejected[u.Client] = reason
+			continue
+		}
+		if _, dup := agg.Rejected[u.Client]; dup || containsString(agg.Accepted, u.Client) {
+			agg.Rejected[u.Client] = "duplicate client"
+			continue
+		}
+		if total != nil && (len(u.XTY) != len(total.xty) || u.Vars != vars) {
+			agg.Rejected[u.Client] = "dimensions differ from the other clients"
+			continue
+		}
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			agg.Rejected[u.Client] = "invalid client weight"
+			continue
+		}
+		if w == 0 {
+			agg.Rejected[u.Client] = "excluded by its client weight"
+			continue
+		}
+		if opts.MaxWeight > 0 && w*u.Weight > opts.MaxWeight {
+			w = opts.MaxWeight / u.Weight
+			agg.Clipped = append(agg.Clipped, u.Client)
+		}
+
+		if total == nil {
+			total = newNormalEquations(len(u.XTY))
+			vars = u.Vars
+		}
+		for i := range total.xty {
+			total.xty[i] += w * u.XTY[i]
+			for j := i; j < len(total.xty); j++ {
+				total.xtx[i][j] += w * u.XTX[i][j]
+			}
+		}
+		total.yty += w * u.YTY
+		total.sumY += w * u.SumY
+		total.sumW += w * u.Weight
+		total.n += u.Rows
+		agg.Accepted = append(agg.Accepted, u.Client)
+	}
+	if total == nil {
+		return agg, ErrNoUpdates
+	}
+	sort.Strings(agg.Accepted)
+	sort.Strings(agg.Clipped)
+	agg.Update = &ClientUpdate{
+		Client: strings.Join(agg.Accepted, ","),
+		Vars:   vars,
+		Rows:   total.n,
+		Weight: total.sumW,
+		XTX:    total.xtx,
+		XTY:    total.xty,
+		YTY:    total.yty,
+		SumY:   total.sumY,
+	}
+	return agg, nil
+}
+
+// invalid returns why the update can't be aggregated, or an empty string.
+func (u *ClientUpdate) invalid() string {
+	cols := len(u.XTY)
+	if cols == 0 || u.Vars < 0 || u.Vars >= cols || len(u.XTX) != cols {
+		return "malformed statistics"
+	}
+	if u.Rows <= 0 || !(u.Weight > 0) || math.IsInf(u.Weight, 0) {
+		return "no data points"
+	}
+	finite := func(v float64) bool { return !math.IsNaN(v) && !math.IsInf(v, 0) }
+	if !finite(u.YTY) || !finite(u.SumY) || u.YTY < 0 {
+		return "invalid statistics"
+	}
+	for i, row := range u.XTX {
+		if len(row) != cols {
+			return "malformed statistics"
+		}
+		if !finite(u.XTY[i]) || row[i] < 0 {
+			return "invalid statistics"
+		}
+		for j := i; j < cols; j++ {
+			if !finite(row[j]) {
+				return "invalid statistics"
+			}
+		}
+	}
+	// the intercept column is all ones, so its square sums to the weight
+	if math.Abs(u.XTX[0][0]-u.Weight) > 1e-9*u.Weight {
+		return "inconsistent statistics"
+	}
+	return ""
+}
+
+// RunAggregate fits the regression to the statistics combined by Aggregate, or to a single client
+// update, as RunStream does to its data points. The statistics are kept, so the model can be trained
+// further with Update. The models supported are those of RunStream, without missing indicators.
+func (r *Regression) RunAggregate(u *ClientUpdate) error {
+	if r.hasRun {
+		return ErrRegressionRun
+	}
+	if r.solve != nil || r.split || r.instruments != nil || r.signs != nil || r.censor != nil || r.prior != nil ||
+		r.winsorVars != nil || r.winsorObs != nil || len(r.missing) > 0 || r.vifThreshold > 0 {
+		return ErrUnsupported
+	}
+	if u.invalid() != "" || len(r.designRow(make([]float64, u.Vars))) != len(u.XTY) {
+		return ErrDimensions
+	}
+	if u.Rows < 3 {
+		return ErrNotEnoughData
+	}
+	if u.Rows < len(u.XTY) {
+		return ErrTooManyVars
+	}
+
+	a := newNormalEquations(len(u.XTY))
+	for i := range a.xtx {
+		copy(a.xtx[i][i:], u.XTX[i][i:])
+	}
+	copy(a.xty, u.XTY)
+	a.n, a.sumW, a.yty, a.sumY = u.Rows, u.Weight, u.YTY, u.SumY
+
+	r.initialised = true
+	r.hasRun = true
+	r.extendNames(u.Vars)
+	c, unscaled := a.solve()
+	r.setCoeffs(c)
+	r.calcStreamMetrics(a, c, unscaled)
+	r.onlineState().stats = a
+	r.newPoints = 0
+	return nil
+}
+
+func containsString(ids []string, id string) bool {
+	for _, s := range ids {
+		if s == id {
+			return true
+		}
+	}
+	return false
+}
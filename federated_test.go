@@ -0,0 +1,118 @@
+package regression
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+// carsClients splits the cars data among three clients and returns their updates.
+func carsClients(t *testing.T) []*ClientUpdate {
+	var updates []*ClientUpdate
+	for c, client := range []string{"a", "b", "c"} {
+		var points []*dataPoint
+		for i := c; i < len(carsSpeed); i += 3 {
+			points = append(points, DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+		}
+		u, err := new(Regression).LocalUpdate(client, points)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the updates cross the network as JSON
+		b, err := json.Marshal(u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u = new(ClientUpdate)
+		if err := json.Unmarshal(b, u); err != nil {
+			t.Fatal(err)
+		}
+		updates = append(updates, u)
+	}
+	return updates
+}
+
+func TestAggregate(t *testing.T) {
+	agg, err := Aggregate(carsClients(t), AggregateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(agg.Accepted) != 3 || len(agg.Rejected) != 0 || agg.Update.Client != "a,b,c" {
+		t.Errorf("Expected all clients accepted, got %+v", agg)
+	}
+	r := new(Regression)
+	r.SetVar(0, "speed")
+	if err := r.RunAggregate(agg.Update); err != nil {
+		t.Fatal(err)
+	}
+
+	// the pooled fit is exact
+	want := carsRegression(t)
+	for i, c := range want.coeffs() {
+		assertClose(t, "coefficient", r.Coeff(i), c, 1e-9)
+	}
+	assertClose(t, "R2", r.R2, want.R2, 1e-9)
+	if r.GetVar(0) != "speed" {
+		t.Errorf("Expected the variable names, got %q", r.GetVar(0))
+	}
+	if err := r.Update(DataPoint(50, []float64{20})); err != nil {
+		t.Error(err)
+	}
+	if err := r.RunAggregate(agg.Update); err != ErrRegressionRun {
+		t.Errorf("Expected ErrRegressionRun, got %v", err)
+	}
+}
+
+func TestAggregateUntrusted(t *testing.T) {
+	updates := carsClients(t)
+	nan := *updates[0]
+	nan.Client, nan.YTY = "nan", math.NaN()
+	forged := *updates[0]
+	forged.Client, forged.Weight = "forged", 1
+	short := *updates[0]
+	short.Client, short.XTY = "short", short.XTY[:1]
+	big := *updates[1]
+	big.Client, big.Weight = "big", big.Weight*100
+	big.XTX = [][]float64{{big.XTX[0][0] * 100, big.XTX[0][1] * 100}, {0, big.XTX[1][1] * 100}}
+	big.XTY = []float64{big.XTY[0] * 100, big.XTY[1] * 100}
+	big.YTY, big.SumY = big.YTY*100, big.SumY*100
+	updates = append(updates, &nan, &forged, &short, updates[0], &big)
+
+	agg, err := Aggregate(updates, AggregateOptions{
+		Weights:   map[string]float64{"c": 0},
+		MaxWeight: 20,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(agg.Accepted) != 3 || agg.Accepted[0] != "a" || agg.Accepted[1] != "b" || agg.Accepted[2] != "big" {
+		t.Errorf("Expected a, b and big accepted, got %v", agg.Accepted)
+	}
+	for client, reason := range map[string]string{
+		"nan":    "invalid statistics",
+		"forged": "inconsistent statistics",
+		"short":  "malformed statistics",
+		"a":      "duplicate client",
+		"c":      "excluded by its client weight",
+	} {
+		if agg.Rejected[client] != reason {
+			t.Errorf("%s: expected %q, got %q", client, reason, agg.Rejected[client])
+		}
+	}
+	if len(agg.Clipped) != 1 || agg.Clipped[0] != "big" {
+		t.Errorf("Expected big clipped, got %v", agg.Clipped)
+	}
+	assertClose(t, "weight", agg.Update.Weight, updates[0].Weight+updates[1].Weight+20, 1e-9)
+
+	if _, err := Aggregate([]*ClientUpdate{&nan}, AggregateOptions{}); err != ErrNoUpdates {
+		t.Errorf("Expected ErrNoUpdates, got %v", err)
+	}
+	if err := new(Regression).RunAggregate(&forged); err != ErrDimensions {
+		t.Errorf("Expected ErrDimensions, got %v", err)
+	}
+	split := new(Regression)
+	split.SplitByVar(0)
+	if err := split.RunAggregate(agg.Update); err != ErrUnsupported {
+		t.Errorf("Expected ErrUnsupported for a per-segment model, got %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package regression
+
+import (
+	"runtime"
+	"time"
+)
+
+// FitStats describes the cost of the last fit of the model, e.g. to track training cost as data grows.
+type FitStats struct {
+	// Total is the wall time of the fit, and the other durations that of its stages: applying the feature
+	// crosses, building the design matrix, solving it, computing the fit metrics and inference, and fitting
+	// the per-segment models.
+	Total    time.Duration
+	Crosses  time.Duration
+	Design   time.Duration
+	Solve    time.Duration
+	Metrics  time.Duration
+	Segments time.Duration
+	// Rows and Cols are the dimensions of the design matrix and MatrixBytes its size.
+	Rows        int
+	Cols        int
+	MatrixBytes int
+	// Allocs and AllocBytes are the number and size of the heap allocations while fitting. They are measured
+	// for the whole process, so they include the allocations of concurrent goroutines.
+	Allocs     uint64
+	AllocBytes uint64
+	// Accelerated is set when the problem was solved by the accelerator registered with RegisterAccelerator.
+	Accelerated bool
+}
+
+// FitStats returns the cost of the last fit by Run, RunFixedEffects or RunDiD.
+func (r *Regression) FitStats() FitStats {
+	return r.stats
+}
+
+// stageTimer times the stages of a fit into FitStats.
+type stageTimer struct {
+	stats       *FitStats
+	start, last time.Time
+	mem         runtime.MemStats
+}
+
+// startTimer starts timing a fit, keeping the time spent on the feature crosses, which are applied before.
+func (r *Regression) startTimer() *stageTimer {
+	r.stats = FitStats{Crosses: r.stats.Crosses}
+	t := &stageTimer{stats: &r.stats}
+	runtime.ReadMemStats(&t.mem)
+	t.start = time.Now()
+	t.last = t.start
+	return t
+}
+
+// lap returns the time since the last lap.
+func (t *stageTimer) lap() time.Duration {
+	now := time.Now()
+	d := now.Sub(t.last)
+	t.last = now
+	return d
+}
+
+// stop records the total time and the allocations of the fit.
+func (t *stageTimer) stop() {
+	t.stats.Total = time.Since(t.start) + t.stats.Crosses
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	t.stats.Allocs = mem.Mallocs - t.mem.Mallocs
+	t.stats.AllocBytes = mem.TotalAlloc - t.mem.TotalAlloc
+}
@@ -0,0 +1,28 @@
+package regression
+
+import "testing"
+
+func TestFitStats(t *testing.T) {
+	r := new(Regression)
+	r.AddCross(PowCross(0, 2))
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	s := r.FitStats()
+	if s.Rows != len(carsSpeed) || s.Cols != 3 || s.MatrixBytes != 8*3*len(carsSpeed) {
+		t.Errorf("Unexpected matrix size in %+v", s)
+	}
+	if s.Total <= 0 || s.Total < s.Crosses+s.Design+s.Solve+s.Metrics+s.Segments {
+		t.Errorf("Expected the stages to add up to at most the total, got %+v", s)
+	}
+	if s.Allocs == 0 || s.AllocBytes == 0 {
+		t.Errorf("Expected allocations to be counted, got %+v", s)
+	}
+	if new(Regression).FitStats() != (FitStats{}) {
+		t.Error("Expected no stats before a fit")
+	}
+}
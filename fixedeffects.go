@@ -0,0 +1,149 @@
+package regression
+
+import "errors"
+
+// ErrUnknownGroup signals that a group has no fixed effect in the model.
+var ErrUnknownGroup = errors.New("unknown group")
+
+// RunFixedEffects fits the regression with a fixed effect per group of the data points, see
+// GroupedDataPoint, absorbing group level confounders. The observed value and the variables, including
+// the feature crosses, are demeaned within each group before fitting and the grand means are added back,
+// so the offset is the average fixed effect. The degrees of freedom account for the absorbed group means
+// and R2 is the within-group R2. The model is fitted to demeaned copies of the data points, so the data
+// points trained are left as they are, with the residuals of the fit. Per-segment models are not supported.
+func (r *Regression) RunFixedEffects() error {
+	if !r.initialised {
+		return ErrNotEnoughData
+	}
+	if r.hasRun {
+		return ErrRegressionRun
+	}
+	if r.split || r.censor != nil || r.prior != nil {
+		return ErrUnsupported
+	}
+
+	r.DecompressData()
+	r.uncross()
+	numOfBaseVars := len(r.data[0].Variables)
+	if err := r.checkSplit(numOfBaseVars); err != nil {
+		return err
+	}
+	r.hashData()
+	if err := r.winsorize(); err != nil {
+		return err
+	}
+	if err := r.applyCrosses(); err != nil {
+		return err
+	}
+	r.hasRun = true
+
+	means, grand := r.groupMeans()
+	trained := r.data
+	r.data = make([]*dataPoint, len(trained))
+	for i, d := range trained {
+		g, ok := means[d.Group]
+		if !ok {
+			g = grand
+		}
+		p := &dataPoint{
+			Observed:  d.Observed - g[0] + grand[0],
+			Variables: make([]float64, len(d.Variables)),
+			Weight:    d.Weight,
+			Group:     d.Group,
+		}
+		for j, v := range d.Variables {
+			p.Variables[j] = v - g[j+1] + grand[j+1]
+		}
+		r.data[i] = p
+	}
+	r.absorbed = len(means) - 1
+	err := r.fit(numOfBaseVars)
+	transformed := r.data
+	r.data = trained
+	if err != nil {
+		return err
+	}
+	// the within-group residuals are the residuals of the fixed effects
+	for i, d := range trained {
+		d.Error = transformed[i].Error
+		d.Predicted = d.Observed + d.Error
+	}
+
+	r.fixedEffects = make(map[string]float64, len(means))
+	for group, g := range means {
+		effect := g[0]
+		for j := 1; j < len(r.coeff); j++ {
+			effect -= r.coeff[j] * g[j]
+		}
+		r.fixedEffects[group] = effect
+	}
+	return nil
+}
+
+// groupMeans returns the weighted means of the observed value followed by the variables within every
+// group with a positive weight, and over all data points.
+func (r *Regression) groupMeans() (map[string][]float64, []float64) {
+	n := len(r.data[0].Variables) + 1
+	sums := make(map[string][]float64)
+	weights := make(map[string]float64)
+	grand := make([]float64, n)
+	var total float64
+	for _, d := range r.data {
+		s, ok := sums[d.Group]
+		if !ok {
+			s = make([]float64, n)
+			sums[d.Group] = s
+		}
+		weights[d.Group] += d.Weight
+		total += d.Weight
+		s[0] += d.Weight * d.Observed
+		grand[0] += d.Weight * d.Observed
+		for j, v := range d.Variables {
+			s[j+1] += d.Weight * v
+			grand[j+1] += d.Weight * v
+		}
+	}
+	for group, s := range sums {
+		w := weights[group]
+		if w == 0 {
+			// a group without weight doesn't affect the fit, and has no fixed effect
+			delete(sums, group)
+			continue
+		}
+		for j := range s {
+			s[j] /= w
+		}
+	}
+	for j := range grand {
+		grand[j] /= total
+	}
+	return sums, grand
+}
+
+// FixedEffect returns the fixed effect of a group fitted by RunFixedEffects, which takes the place of the offset.
+func (r *Regression) FixedEffect(group string) (float64, error) {
+	if r.fixedEffects == nil {
+		return 0, ErrNotRun
+	}
+	effect, ok := r.fixedEffects[group]
+	if !ok {
+		return 0, ErrUnknownGroup
+	}
+	return effect, nil
+}
+
+// PredictGroup predicts the observed value for vars in a group, using the group's fixed effect as the offset.
+// For a model fitted by RunMultilevel the group's random effects are added, and groups not seen in training
+// get the global prediction.
+func (r *Regression) PredictGroup(vars []float64, group string) (float64, error) {
+	if r.groupEffects != nil {
+		return r.predictMultilevel(vars, group)
+	}
+	effect, err := r.FixedEffect(group)
+	if err != nil {
+		return 0, err
+	}
+	row := r.designRow(vars)
+	row[0] = 0
+	return effect + r.predictRow(row), nil
+}
@@ -0,0 +1,83 @@
+package regression
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+func TestRunFixedEffects(t *testing.T) {
+	// the group effect is correlated with x, biasing a pooled fit
+	rnd := rand.New(rand.NewSource(3))
+	groups := []string{"a", "b", "c", "d"}
+	effects := []float64{0, 5, 10, 20}
+	fe := new(Regression)
+	lsdv := new(Regression)
+	for g, group := range groups {
+		for i := 0; i < 15; i++ {
+			x := float64(g)*2 + rnd.NormFloat64()
+			y := effects[g] + 1.5*x + rnd.NormFloat64()
+			fe.Train(GroupedDataPoint(y, []float64{x}, group))
+			// least squares with dummy variables estimates the same model
+			dummies := make([]float64, len(groups)-1)
+			if g > 0 {
+				dummies[g-1] = 1
+			}
+			lsdv.Train(DataPoint(y, append([]float64{x}, dummies...)))
+		}
+	}
+	if err := fe.RunFixedEffects(); err != nil {
+		t.Fatal(err)
+	}
+	if err := lsdv.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	assertClose(t, "slope", fe.Coeff(1), lsdv.Coeff(1), 1e-9)
+	assertClose(t, "slope stderr", fe.StdErr(1), lsdv.StdErr(1), 1e-9)
+	if _, residual := fe.DegreesOfFreedom(); residual != 60-1-4 {
+		t.Errorf("Expected 55 residual degrees of freedom, got %d", residual)
+	}
+	for g, group := range groups {
+		want := lsdv.Coeff(0)
+		if g > 0 {
+			want += lsdv.Coeff(g + 1)
+		}
+		got, err := fe.FixedEffect(group)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertClose(t, "fixed effect "+group, got, want, 1e-9)
+		p, _ := fe.PredictGroup([]float64{3}, group)
+		assertClose(t, "prediction "+group, p, want+3*lsdv.Coeff(1), 1e-9)
+	}
+	// the data points trained are left as they are, with the residuals of the fixed effects
+	for i, d := range fe.data {
+		assertClose(t, "observed", d.Observed, lsdv.data[i].Observed, 0)
+		assertClose(t, "variable", d.Variables[0], lsdv.data[i].Variables[0], 0)
+		p, _ := fe.PredictGroup(d.Variables, d.Group)
+		assertClose(t, "predicted", d.Predicted, p, 1e-9)
+	}
+	if fe.R2 <= 0 || fe.R2 >= lsdv.R2 {
+		t.Errorf("Expected the within R2 (%v) to be below the R2 with dummies (%v)", fe.R2, lsdv.R2)
+	}
+	if _, err := fe.FixedEffect("e"); err != ErrUnknownGroup {
+		t.Errorf("Expected ErrUnknownGroup, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := fe.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := fe.PredictGroup([]float64{3}, "c")
+	got, _ := loaded.PredictGroup([]float64{3}, "c")
+	assertClose(t, "loaded prediction", got, want, 1e-12)
+
+	if err := fe.Update(DataPoint(1, []float64{1})); err != ErrUnsupported {
+		t.Errorf("Expected ErrUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package regression
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidFormat signals that a NumberFormat has a negative precision or a scientific threshold
+// that isn't above one, or that a locale is not supported.
+var ErrInvalidFormat = errors.New("invalid number format")
+
+// NumberFormat controls how numbers are rendered by the formula, String and the residuals.
+type NumberFormat struct {
+	// Precision is the number of digits after the decimal point.
+	Precision int
+	// Scientific is the magnitude from which numbers are rendered in scientific notation, as are nonzero
+	// numbers below its inverse. Zero disables scientific notation.
+	Scientific float64
+	// Thousands separates the groups of thousands of the integer part in fixed notation.
+	Thousands string
+	// Decimal is the decimal separator, a point when empty.
+	Decimal string
+}
+
+// localeSeparators are the thousands and decimal separators of the supported locales.
+var localeSeparators = map[string][2]string{
+	"en":    {",", "."},
+	"de":    {".", ","},
+	"fr":    {"\u202f", ","},
+	"it":    {".", ","},
+	"es":    {".", ","},
+	"nl":    {".", ","},
+	"pt":    {".", ","},
+	"pl":    {"\u00a0", ","},
+	"sv":    {"\u00a0", ","},
+	"da":    {".", ","},
+	"fi":    {"\u00a0", ","},
+	"de-CH": {"\u2019", "."},
+}
+
+// LocaleFormat returns the number format of a locale, given as a language such as "de" or a language
+// and region such as "de-CH", with the given precision. A region without its own separators uses those
+// of its language.
+func LocaleFormat(locale string, precision int) (NumberFormat, error) {
+	locale = strings.Replace(locale, "_", "-", -1)
+	sep, ok := localeSeparators[locale]
+	if !ok {
+		if i := strings.IndexByte(locale, '-'); i >= 0 {
+			sep, ok = localeSeparators[strings.ToLower(locale[:i])]
+		} else {
+			sep, ok = localeSeparators[strings.ToLower(locale)]
+		}
+	}
+	if !ok || precision < 0 {
+		return NumberFormat{}, ErrInvalidFormat
+	}
+	return NumberFormat{Precision: precision, Thousands: sep[0], Decimal: sep[1]}, nil
+}
+
+// SetNumberFormat sets the format of the numbers in the formula, String and the residuals, replacing
+// their fixed precisions. The formula of a model that has been run is rendered again.
+func (r *Regression) SetNumberFormat(f NumberFormat) error {
+	if f.Precision < 0 || f.Scientific < 0 || (f.Scientific > 0 && f.Scientific <= 1) {
+		return ErrInvalidFormat
+	}
+	r.numberFormat = &f
+	if len(r.coeff) > 0 {
+		r.Formula = r.formula()
+	}
+	return nil
+}
+
+// formatNumber renders v with the number format, or with the given precision when there is none.
+// A negative precision renders v with %v.
+func (r *Regression) formatNumber(v float64, precision int) string {
+	if r.numberFormat == nil {
+		if precision < 0 {
+			return fmt.Sprintf("%v", v)
+		}
+		return strconv.FormatFloat(v, 'f', precision, 64)
+	}
+	return r.numberFormat.format(v)
+}
+
+func (f *NumberFormat) format(v float64) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	a := math.Abs(v)
+	if f.Scientific > 0 && a != 0 && (a >= f.Scientific || a < 1/f.Scientific) {
+		return f.withDecimal(strconv.FormatFloat(v, 'e', f.Precision, 64))
+	}
+	s := strconv.FormatFloat(v, 'f', f.Precision, 64)
+	if f.Thousands == "" {
+		return f.withDecimal(s)
+	}
+
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	integer, fraction := s, ""
+	if dot := strings.IndexByte(s, '.'); dot >= 0 {
+		integer, fraction = s[:dot], s[dot:]
+	}
+	grouped := integer[:(len(integer)-1)%3+1]
+	for i := len(grouped); i < len(integer); i += 3 {
+		grouped += f.Thousands + integer[i:i+3]
+	}
+	return sign + grouped + f.withDecimal(fraction)
+}
+
+// withDecimal replaces the decimal point of s with the decimal separator.
+func (f *NumberFormat) withDecimal(s string) string {
+	if f.Decimal == "" {
+		return s
+	}
+	return strings.Replace(s, ".", f.Decimal, 1)
+}
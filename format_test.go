@@ -0,0 +1,82 @@
+package regression
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestNumberFormat(t *testing.T) {
+	for _, c := range []struct {
+		format NumberFormat
+		v      float64
+		want   string
+	}{
+		{NumberFormat{Precision: 2}, 3.14159, "3.14"},
+		{NumberFormat{Precision: 3, Scientific: 1e4}, 2.5e-9, "2.500e-09"},
+		{NumberFormat{Precision: 3, Scientific: 1e4}, 1.5e6, "1.500e+06"},
+		{NumberFormat{Precision: 3, Scientific: 1e4}, 0, "0.000"},
+		{NumberFormat{Precision: 1, Thousands: ","}, -1234567.25, "-1,234,567.2"},
+		{NumberFormat{Precision: 0, Thousands: " "}, 123456, "123 456"},
+		{NumberFormat{Precision: 0, Thousands: ","}, 999, "999"},
+		{NumberFormat{Precision: 2}, math.Inf(-1), "-Inf"},
+		{NumberFormat{Precision: 2, Thousands: ".", Decimal: ","}, 1234.5, "1.234,50"},
+		{NumberFormat{Precision: 2, Scientific: 10, Decimal: ","}, 1234.5, "1,23e+03"},
+	} {
+		if got := c.format.format(c.v); got != c.want {
+			t.Errorf("Expected %q for %v with %+v, got %q", c.want, c.v, c.format, got)
+		}
+	}
+}
+
+func TestSetNumberFormat(t *testing.T) {
+	r := carsRegression(t)
+	if !strings.HasPrefix(r.Formula, "Predicted = -17.5791 + speed*3.9324") {
+		t.Fatalf("Unexpected default formula %q", r.Formula)
+	}
+	if err := r.SetNumberFormat(NumberFormat{Precision: 2, Scientific: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if r.Formula != "Predicted = -1.76e+01 + speed*3.93" {
+		t.Errorf("Unexpected formula %q", r.Formula)
+	}
+	if res := r.calcResiduals(); !strings.Contains(res, "1.00e+01|\t-1.85|\t1.18e+01") {
+		t.Errorf("Unexpected residuals %q", res)
+	}
+	if got := r.calcR2(); got != "R2 = 0.65" {
+		t.Errorf("Unexpected R2 %q", got)
+	}
+
+	for _, f := range []NumberFormat{{Precision: -1}, {Scientific: 1}, {Scientific: -5}} {
+		if err := r.SetNumberFormat(f); err != ErrInvalidFormat {
+			t.Errorf("Expected ErrInvalidFormat for %+v, got %v", f, err)
+		}
+	}
+}
+
+func TestLocaleFormat(t *testing.T) {
+	for _, c := range []struct {
+		locale string
+		want   string
+	}{
+		{"en", "-1,234,567.89"},
+		{"de", "-1.234.567,89"},
+		{"de_DE", "-1.234.567,89"},
+		{"de-CH", "-1\u2019234\u2019567.89"},
+		{"FR", "-1\u202f234\u202f567,89"},
+	} {
+		f, err := LocaleFormat(c.locale, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := f.format(-1234567.891); got != c.want {
+			t.Errorf("Expected %q for %s, got %q", c.want, c.locale, got)
+		}
+	}
+	if _, err := LocaleFormat("xx", 2); err != ErrInvalidFormat {
+		t.Errorf("Expected ErrInvalidFormat, got %v", err)
+	}
+	if _, err := LocaleFormat("de", -1); err != ErrInvalidFormat {
+		t.Errorf("Expected ErrInvalidFormat, got %v", err)
+	}
+}
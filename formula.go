@@ -0,0 +1,287 @@
+package regression
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// SetFormula configures the observed value, the variables and the feature crosses from an R style
+// model formula such as "y ~ x1 + x2 + x1:x2 + poly(x3, 2)". Any previously added crosses are replaced.
+//
+// Supported terms are variables, interactions (x1:x2), crossings (x1*x2, which expands to
+// x1 + x2 + x1:x2), raw polynomials (poly(x, 3) is x + x^2 + x^3) and powers (I(x^0.5)).
+// Names containing spaces or operators can be quoted with backticks.
+//
+// Every variable named in the formula is a variable of the data points. Variables already named with
+// SetVar keep their index, new names take the next free index in order of appearance.
+// The offset is always fitted, so "- 1" and "0 +" are not supported.
+func (r *Regression) SetFormula(formula string) error {
+	if r.hasRun {
+		return ErrRegressionRun
+	}
+	p := &formulaParser{input: formula}
+	if err := p.tokenize(); err != nil {
+		return err
+	}
+	obs, terms, err := p.parse()
+	if err != nil {
+		return err
+	}
+
+	indices := make(map[string]int, len(r.names.vars))
+	next := 0
+	for i, name := range r.names.vars {
+		indices[name] = i
+		if i >= next {
+			next = i + 1
+		}
+	}
+	index := func(name string) int {
+		i, ok := indices[name]
+		if !ok {
+			i = next
+			next++
+			indices[name] = i
+			r.SetVar(i, name)
+		}
+		return i
+	}
+
+	r.SetObserved(obs)
+	r.crosses, r.crossSet = nil, nil
+	seen := make(map[string]bool)
+	for _, t := range terms {
+		vars := make([]int, len(t.vars))
+		for i, name := range t.vars {
+			vars[i] = index(name)
+		}
+		key := fmt.Sprint(vars, t.power)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		switch {
+		case t.power != 0:
+			r.AddCross(PowCross(vars[0], t.power))
+		case len(vars) > 1:
+			r.AddCross(MultiplierCross(vars...))
+		}
+	}
+	return nil
+}
+
+// formulaTerm is a variable (one var), an interaction (several vars) or a power of a variable.
+type formulaTerm struct {
+	vars  []string
+	power float64
+}
+
+type formulaParser struct {
+	input  string
+	tokens []string
+	pos    int
+}
+
+func (p *formulaParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid formula %q: %s", p.input, fmt.Sprintf(format, args...))
+}
+
+func (p *formulaParser) tokenize() error {
+	runes := []rune(p.input)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case strings.ContainsRune("~+:*(),^-", c):
+			p.tokens = append(p.tokens, string(c))
+			i++
+		case c == '`':
+			end := i + 1
+			for end < len(runes) && runes[end] != '`' {
+				end++
+			}
+			if end == len(runes) {
+				return p.errorf("unterminated quoted name")
+			}
+			// quoted names are marked with a leading backtick so they are never mistaken for functions
+			p.tokens = append(p.tokens, "`"+string(runes[i+1:end]))
+			i = end + 1
+		case unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' || c == '.':
+			end := i
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || runes[end] == '_' || runes[end] == '.') {
+				end++
+			}
+			p.tokens = append(p.tokens, string(runes[i:end]))
+			i = end
+		default:
+			return p.errorf("unexpected character %q", c)
+		}
+	}
+	return nil
+}
+
+func (p *formulaParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *formulaParser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *formulaParser) expect(t string) error {
+	if got := p.next(); got != t {
+		return p.errorf("expected %q, got %q", t, got)
+	}
+	return nil
+}
+
+func (p *formulaParser) name() (string, error) {
+	t := p.next()
+	if strings.HasPrefix(t, "`") {
+		return t[1:], nil
+	}
+	if t == "" || !(unicode.IsLetter([]rune(t)[0]) || t[0] == '_' || t[0] == '.') {
+		return "", p.errorf("expected a variable name, got %q", t)
+	}
+	return t, nil
+}
+
+func (p *formulaParser) number() (float64, error) {
+	t := p.next()
+	v, err := strconv.ParseFloat(t, 64)
+	if err != nil {
+		return 0, p.errorf("expected a number, got %q", t)
+	}
+	return v, nil
+}
+
+func (p *formulaParser) parse() (string, []formulaTerm, error) {
+	obs, err := p.name()
+	if err != nil {
+		return "", nil, err
+	}
+	if err := p.expect("~"); err != nil {
+		return "", nil, err
+	}
+
+	var terms []formulaTerm
+	for {
+		if t := p.peek(); t == "0" || t == "1" || t == "-" {
+			return "", nil, p.errorf("the offset is always fitted and cannot be added or removed")
+		}
+		ts, err := p.term()
+		if err != nil {
+			return "", nil, err
+		}
+		terms = append(terms, ts...)
+		if p.peek() == "" {
+			return obs, terms, nil
+		}
+		if err := p.expect("+"); err != nil {
+			return "", nil, err
+		}
+	}
+}
+
+// term parses a term and expands crossings into the main effects and interactions.
+func (p *formulaParser) term() ([]formulaTerm, error) {
+	switch p.peek() {
+	case "poly":
+		p.next()
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+		degree, err := p.number()
+		if err != nil {
+			return nil, err
+		}
+		if degree < 1 || degree != float64(int(degree)) {
+			return nil, p.errorf("poly degree must be a positive integer")
+		}
+		terms := []formulaTerm{{vars: []string{name}}}
+		for d := 2; d <= int(degree); d++ {
+			terms = append(terms, formulaTerm{vars: []string{name}, power: float64(d)})
+		}
+		return terms, p.expect(")")
+	case "I":
+		p.next()
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("^"); err != nil {
+			return nil, err
+		}
+		power, err := p.number()
+		if err != nil {
+			return nil, err
+		}
+		terms := []formulaTerm{{vars: []string{name}}}
+		if power != 1 {
+			terms = append(terms, formulaTerm{vars: []string{name}, power: power})
+		}
+		return terms, p.expect(")")
+	}
+
+	// groups of ':' interactions joined by '*'
+	groups := [][]string{nil}
+	for {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		last := len(groups) - 1
+		groups[last] = append(groups[last], name)
+		switch p.peek() {
+		case ":":
+			p.next()
+			continue
+		case "*":
+			p.next()
+			groups = append(groups, nil)
+			continue
+		}
+		break
+	}
+
+	// every variable is a main effect, and each non-empty combination of groups an interaction
+	var terms []formulaTerm
+	for _, g := range groups {
+		for _, name := range g {
+			terms = append(terms, formulaTerm{vars: []string{name}})
+		}
+	}
+	var combos []formulaTerm
+	for mask := 1; mask < 1<<uint(len(groups)); mask++ {
+		var vars []string
+		for i, g := range groups {
+			if mask&(1<<uint(i)) != 0 {
+				vars = append(vars, g...)
+			}
+		}
+		if len(vars) > 1 {
+			combos = append(combos, formulaTerm{vars: vars})
+		}
+	}
+	sort.SliceStable(combos, func(i, j int) bool { return len(combos[i].vars) < len(combos[j].vars) })
+	return append(terms, combos...), nil
+}
@@ -0,0 +1,110 @@
+package regression
+
+import "testing"
+
+func TestSetFormula(t *testing.T) {
+	r := new(Regression)
+	r.SetVar(1, "x2")
+	if err := r.SetFormula("y ~ x1 + x2 + x1:x2 + poly(x3, 3) + I(x1^0.5)"); err != nil {
+		t.Fatal(err)
+	}
+	if r.GetObserved() != "y" {
+		t.Errorf("Expected observed y, got %q", r.GetObserved())
+	}
+	// x2 keeps its index, x1 and x3 take the next free ones
+	if r.GetVar(1) != "x2" || r.GetVar(2) != "x1" || r.GetVar(3) != "x3" {
+		t.Errorf("Unexpected variable names %v", r.names.vars)
+	}
+	if len(r.crosses) != 4 {
+		t.Fatalf("Expected 4 crosses, got %d", len(r.crosses))
+	}
+
+	vars := []float64{0, 3, 4, 2}
+	row := r.designRow(vars)
+	want := []float64{1, 0, 3, 4, 2, 12, 4, 8, 2}
+	for i := range want {
+		assertClose(t, "design row", row[i], want[i], 1e-12)
+	}
+}
+
+func TestSetFormulaCrossing(t *testing.T) {
+	r := new(Regression)
+	if err := r.SetFormula("`Murders per annum` ~ a*b*`c d`"); err != nil {
+		t.Fatal(err)
+	}
+	if r.GetObserved() != "Murders per annum" || r.GetVar(2) != "c d" {
+		t.Errorf("Expected quoted names, got %q and %v", r.GetObserved(), r.names.vars)
+	}
+	// a:b, a:c, b:c and a:b:c
+	row := r.designRow([]float64{2, 3, 5})
+	want := []float64{1, 2, 3, 5, 6, 10, 15, 30}
+	if len(row) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, row)
+	}
+	for i := range want {
+		assertClose(t, "design row", row[i], want[i], 1e-12)
+	}
+}
+
+func TestSetFormulaFit(t *testing.T) {
+	r := new(Regression)
+	if err := r.SetFormula("dist ~ poly(speed, 2)"); err != nil {
+		t.Fatal(err)
+	}
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	// lm(dist ~ speed + I(speed^2), data = cars)
+	assertClose(t, "intercept", r.Coeff(0), 2.47014, 1e-4)
+	assertClose(t, "speed", r.Coeff(1), 0.91329, 1e-4)
+	assertClose(t, "speed^2", r.Coeff(2), 0.09996, 1e-4)
+	if r.GetVar(1) != "(speed)^2" {
+		t.Errorf("Expected the cross to be named, got %q", r.GetVar(1))
+	}
+}
+
+func TestSetFormulaNamesAfterInteraction(t *testing.T) {
+	r := new(Regression)
+	if err := r.SetFormula("y ~ a*b + I(a^2)"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		a, b := float64(i%7), float64(i%3)
+		r.Train(DataPoint(1+a-b+0.5*a*b+0.2*a*a+0.01*float64(i%4), []float64{a, b}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	// the interaction is a single column, so the power follows it directly
+	for i, want := range []string{"a", "b", "(a)0*1", "(a)^2"} {
+		if got := r.GetVar(i); got != want {
+			t.Errorf("Expected variable %d to be %q, got %q", i, want, got)
+		}
+	}
+	if n := len(r.coeffs()); n != 5 {
+		t.Errorf("Expected 5 coefficients, got %d", n)
+	}
+}
+
+func TestSetFormulaErrors(t *testing.T) {
+	for _, f := range []string{
+		"",
+		"y",
+		"y ~",
+		"y ~ x +",
+		"y ~ x - 1",
+		"y ~ 0 + x",
+		"y ~ poly(x)",
+		"y ~ poly(x, 1.5)",
+		"y ~ I(x)",
+		"y ~ x $ z",
+		"y ~ `x",
+	} {
+		if err := new(Regression).SetFormula(f); err == nil {
+			t.Errorf("Expected an error for %q", f)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package regression
+
+import "time"
+
+// StaleFeature describes a variable whose value is older than its freshness bound.
+type StaleFeature struct {
+	Var    int
+	Name   string
+	Age    time.Duration
+	MaxAge time.Duration
+}
+
+// SetFreshness sets the maximum age of variable i at prediction time, see PredictWithTimestamps.
+// A maxAge of zero removes the bound.
+func (r *Regression) SetFreshness(i int, maxAge time.Duration) {
+	if maxAge <= 0 {
+		delete(r.freshness, i)
+		return
+	}
+	if r.freshness == nil {
+		r.freshness = make(map[int]time.Duration)
+	}
+	r.freshness[i] = maxAge
+}
+
+// OnStaleFeature sets a callback which receives every stale variable found by PredictWithTimestamps,
+// e.g. to log a warning or count a metric. It is called before the prediction returns, so it should not block.
+func (r *Regression) OnStaleFeature(f func(StaleFeature)) {
+	r.onStaleFeature = f
+}
+
+// PredictWithTimestamps predicts like Predict and checks when the variables were observed against the
+// bounds set with SetFreshness. observedAt holds the time of every variable, with the zero time for
+// variables whose time is unknown. The prediction is made regardless; the stale variables are returned
+// and passed to the OnStaleFeature callback.
+func (r *Regression) PredictWithTimestamps(vars []float64, observedAt []time.Time) (float64, []StaleFeature, error) {
+	if len(observedAt) != len(vars) {
+		return 0, nil, ErrDimensions
+	}
+	p, err := r.Predict(vars)
+	if err != nil {
+		return 0, nil, err
+	}
+	now := time.Now()
+	var stale []StaleFeature
+	for i, at := range observedAt {
+		maxAge, ok := r.freshness[i]
+		if !ok || at.IsZero() {
+			continue
+		}
+		if age := now.Sub(at); age > maxAge {
+			s := StaleFeature{Var: i, Name: r.GetVar(i), Age: age, MaxAge: maxAge}
+			stale = append(stale, s)
+			if r.onStaleFeature != nil {
+				r.onStaleFeature(s)
+			}
+		}
+	}
+	return p, stale, nil
+}
@@ -0,0 +1,46 @@
+package regression
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPredictWithTimestamps(t *testing.T) {
+	r := new(Regression)
+	r.SetVar(0, "x")
+	r.SetVar(1, "z")
+	for i := 0; i < 10; i++ {
+		x, z := float64(i), float64(i*i%7)
+		r.Train(DataPoint(1+2*x+3*z, []float64{x, z}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	r.SetFreshness(0, time.Minute)
+	r.SetFreshness(1, time.Hour)
+	var warned []StaleFeature
+	r.OnStaleFeature(func(s StaleFeature) { warned = append(warned, s) })
+
+	now := time.Now()
+	p, stale, err := r.PredictWithTimestamps([]float64{1, 2}, []time.Time{now.Add(-5 * time.Minute), now.Add(-5 * time.Minute)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := r.Predict([]float64{1, 2})
+	assertClose(t, "prediction", p, want, 1e-12)
+	if len(stale) != 1 || stale[0].Var != 0 || stale[0].Name != "x" || stale[0].MaxAge != time.Minute || stale[0].Age < 5*time.Minute {
+		t.Errorf("Unexpected stale features %+v", stale)
+	}
+	if len(warned) != 1 || warned[0] != stale[0] {
+		t.Errorf("Expected the callback to receive the stale feature, got %+v", warned)
+	}
+
+	// unknown times and unbounded variables are not checked
+	r.SetFreshness(0, 0)
+	if _, stale, _ := r.PredictWithTimestamps([]float64{1, 2}, []time.Time{now.Add(-time.Hour * 24), {}}); len(stale) != 0 {
+		t.Errorf("Expected no stale features, got %+v", stale)
+	}
+	if _, _, err := r.PredictWithTimestamps([]float64{1, 2}, nil); err != ErrDimensions {
+		t.Errorf("Expected ErrDimensions, got %v", err)
+	}
+}
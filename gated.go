@@ -0,0 +1,138 @@
+package regression
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"sort"
+)
+
+// ErrInvalidThresholds signals routing thresholds that are not finite and sorted.
+var ErrInvalidThresholds = errors.New("thresholds must be finite and sorted")
+
+// GatedModel is a mixture of experts: it routes every prediction to one of several fitted models,
+// either by thresholds on a gate variable or by a learned logistic gate. It suits data with regimes
+// that one linear model doesn't fit, e.g. weekday and weekend traffic.
+type GatedModel struct {
+	Experts []*Regression `json:"experts"`
+	// GateVar and Thresholds route by threshold: values of the gate variable below Thresholds[0] go
+	// to the first expert, those from Thresholds[k-1] up to Thresholds[k] to expert k, and so on.
+	GateVar    int       `json:"gate_var"`
+	Thresholds []float64 `json:"thresholds,omitempty"`
+	// Gate holds the offset and coefficients of a learned logistic gate between two experts:
+	// the second expert is used where the modelled probability is at least one half.
+	Gate []float64 `json:"gate,omitempty"`
+}
+
+// NewThresholdGate routes by thresholds on variable gateVar to len(thresholds)+1 fitted experts.
+func NewThresholdGate(gateVar int, thresholds []float64, experts ...*Regression) (*GatedModel, error) {
+	if gateVar < 0 {
+		return nil, ErrDimensions
+	}
+	if len(experts) != len(thresholds)+1 {
+		return nil, ErrDimensions
+	}
+	if !sort.Float64sAreSorted(thresholds) || !allFinite(thresholds) {
+		return nil, ErrInvalidThresholds
+	}
+	if err := checkExperts(experts); err != nil {
+		return nil, err
+	}
+	return &GatedModel{Experts: experts, GateVar: gateVar, Thresholds: append([]float64(nil), thresholds...)}, nil
+}
+
+// FitLogisticGate learns a logistic gate between two fitted experts: every data point is labelled by
+// the expert predicting it more closely, and a logistic regression of the labels on the variables
+// decides the routing. The data points are typically those the experts were trained on.
+func FitLogisticGate(first, second *Regression, data []*dataPoint) (*GatedModel, error) {
+	experts := []*Regression{first, second}
+	if err := checkExperts(experts); err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, ErrNotEnoughData
+	}
+	labels := make([]*dataPoint, len(data))
+	seconds := 0
+	for i, d := range data {
+		a, err := first.Predict(d.Variables)
+		if err != nil {
+			return nil, err
+		}
+		b, err := second.Predict(d.Variables)
+		if err != nil {
+			return nil, err
+		}
+		var label float64
+		if math.Abs(b-d.Observed) < math.Abs(a-d.Observed) {
+			label = 1
+			seconds++
+		}
+		labels[i] = WeightedDataPoint(label, d.Variables, d.Weight)
+	}
+	if seconds == 0 || seconds == len(data) {
+		// one expert is always better, nothing to route
+		return nil, ErrNotEnoughData
+	}
+	gate, err := fitLogistic(labels)
+	if err != nil {
+		return nil, err
+	}
+	return &GatedModel{Experts: experts, Gate: gate}, nil
+}
+
+func checkExperts(experts []*Regression) error {
+	if len(experts) == 0 {
+		return ErrNotEnoughData
+	}
+	for _, e := range experts {
+		if e == nil || !e.hasRun || len(e.coeff) == 0 {
+			return ErrNotRun
+		}
+	}
+	return nil
+}
+
+// Route returns the index of the expert used for vars.
+func (g *GatedModel) Route(vars []float64) (int, error) {
+	if g.Gate != nil {
+		if len(vars) != len(g.Gate)-1 {
+			return 0, ErrDimensions
+		}
+		if sigmoid(g.Gate[0]+dot(g.Gate[1:], vars)) >= 0.5 {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	if g.GateVar >= len(vars) {
+		return 0, ErrDimensions
+	}
+	return sort.Search(len(g.Thresholds), func(k int) bool { return vars[g.GateVar] < g.Thresholds[k] }), nil
+}
+
+// Predict predicts the observed value for vars with the expert it is routed to.
+func (g *GatedModel) Predict(vars []float64) (float64, error) {
+	k, err := g.Route(vars)
+	if err != nil {
+		return 0, err
+	}
+	if k >= len(g.Experts) {
+		return 0, ErrDimensions
+	}
+	return g.Experts[k].Predict(vars)
+}
+
+// Save writes the gate and all experts to w as JSON.
+func (g *GatedModel) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(g)
+}
+
+// LoadGatedModel reads a model previously written with GatedModel.Save.
+func LoadGatedModel(rd io.Reader) (*GatedModel, error) {
+	g := new(GatedModel)
+	if err := json.NewDecoder(rd).Decode(g); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
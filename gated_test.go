@@ -0,0 +1,98 @@
+package regression
+
+import (
+	"bytes"
+	"testing"
+)
+
+func gatedExperts(t *testing.T) (*Regression, *Regression, []*dataPoint) {
+	// y = x below 5 and y = 20 - 2x from 5 on
+	low, high := new(Regression), new(Regression)
+	var data []*dataPoint
+	for i := 0; i < 10; i++ {
+		x := float64(i) + 0.5
+		if x < 5 {
+			low.Train(DataPoint(x, []float64{x}))
+			data = append(data, DataPoint(x, []float64{x}))
+		} else {
+			high.Train(DataPoint(20-2*x, []float64{x}))
+			data = append(data, DataPoint(20-2*x, []float64{x}))
+		}
+	}
+	for _, r := range []*Regression{low, high} {
+		if err := r.Run(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return low, high, data
+}
+
+func TestThresholdGate(t *testing.T) {
+	low, high, _ := gatedExperts(t)
+	g, err := NewThresholdGate(0, []float64{5}, low, high)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct{ x, want float64 }{{1, 1}, {4.9, 4.9}, {5, 10}, {8, 4}} {
+		got, err := g.Predict([]float64{c.x})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertClose(t, "threshold routing", got, c.want, 1e-9)
+	}
+
+	var buf bytes.Buffer
+	if err := g.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadGatedModel(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := loaded.Predict([]float64{8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "loaded routing", got, 4, 1e-9)
+
+	if _, err := NewThresholdGate(0, []float64{5, 1}, low, high, low); err != ErrInvalidThresholds {
+		t.Errorf("Expected ErrInvalidThresholds, got %v", err)
+	}
+	if _, err := NewThresholdGate(0, nil, low, high); err != ErrDimensions {
+		t.Errorf("Expected ErrDimensions, got %v", err)
+	}
+	if _, err := NewThresholdGate(0, []float64{5}, low, new(Regression)); err != ErrNotRun {
+		t.Errorf("Expected ErrNotRun, got %v", err)
+	}
+	if _, err := g.Predict(nil); err != ErrDimensions {
+		t.Errorf("Expected ErrDimensions, got %v", err)
+	}
+}
+
+func TestFitLogisticGate(t *testing.T) {
+	low, high, data := gatedExperts(t)
+	// overlapping points keep the labels from being perfectly separated
+	data = append(data, DataPoint(8, []float64{6}), DataPoint(6.5, []float64{6.5}))
+	g, err := FitLogisticGate(low, high, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		x    float64
+		want int
+	}{{1, 0}, {4, 0}, {9, 1}} {
+		got, err := g.Route([]float64{c.x})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.want {
+			t.Errorf("Expected %v to be routed to expert %d, got %d", c.x, c.want, got)
+		}
+	}
+	got, _ := g.Predict([]float64{9})
+	assertClose(t, "gated prediction", got, 2, 1e-9)
+
+	if _, err := FitLogisticGate(low, low, data); err != ErrNotEnoughData {
+		t.Errorf("Expected ErrNotEnoughData, got %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package regression
+
+import "errors"
+
+// ErrDataMutated signals that the training data was modified after the model was run.
+var ErrDataMutated = errors.New("training data was modified after Run")
+
+// DataGuard selects how the model protects its training data from modification by the caller,
+// which shares the data points passed to Train.
+type DataGuard int
+
+const (
+	// NoGuard shares the data points with the caller without any check.
+	NoGuard DataGuard = iota
+	// CopyOnTrain makes Train store deep copies of the data points.
+	CopyOnTrain
+	// VerifyOnPredict makes Run record a checksum of the training data, which Predict verifies.
+	// Verification hashes all the training data on every prediction.
+	VerifyOnPredict
+)
+
+// SetDataGuard sets how the training data is protected. It applies to the data points trained afterwards.
+func (r *Regression) SetDataGuard(g DataGuard) {
+	r.dataGuard = g
+}
+
+// copyPoint returns a deep copy of the training fields of d.
+func copyPoint(d *dataPoint) *dataPoint {
+	c := *d
+	c.Variables = append([]float64(nil), d.Variables...)
+	return &c
+}
+
+// sealData records the checksum of the training data as fitted.
+func (r *Regression) sealData() {
+	if r.dataGuard != VerifyOnPredict {
+		return
+	}
+	r.sealed = HashData(r.data, OrderedHash)
+}
+
+// checkData returns ErrDataMutated when the training data differs from the data recorded by sealData.
+func (r *Regression) checkData() error {
+	if r.sealed == "" || HashData(r.data, OrderedHash) == r.sealed {
+		return nil
+	}
+	return ErrDataMutated
+}
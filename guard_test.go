@@ -0,0 +1,71 @@
+package regression
+
+import "testing"
+
+func guardedPoints() []*dataPoint {
+	var points []*dataPoint
+	for i, speed := range carsSpeed {
+		points = append(points, DataPoint(carsDist[i], []float64{speed}))
+	}
+	return points
+}
+
+func TestCopyOnTrain(t *testing.T) {
+	points := guardedPoints()
+	r := new(Regression)
+	r.SetDataGuard(CopyOnTrain)
+	r.Train(points...)
+	points[0].Variables[0] = 1000
+	points[1].Observed = -1000
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	want := carsRegression(t)
+	assertClose(t, "slope", r.Coeff(1), want.Coeff(1), 1e-9)
+
+	// the copies are not affected by the crosses either
+	points = guardedPoints()
+	r = new(Regression)
+	r.SetDataGuard(CopyOnTrain)
+	r.AddCross(PowCross(0, 2))
+	r.Train(points...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if len(points[0].Variables) != 1 {
+		t.Errorf("Expected the caller's points to be unchanged, got %v", points[0].Variables)
+	}
+}
+
+func TestVerifyOnPredict(t *testing.T) {
+	points := guardedPoints()
+	r := new(Regression)
+	r.SetDataGuard(VerifyOnPredict)
+	r.Train(points...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Predict([]float64{10}); err != nil {
+		t.Fatal(err)
+	}
+	points[3].Observed++
+	if _, err := r.Predict([]float64{10}); err != ErrDataMutated {
+		t.Errorf("Expected ErrDataMutated, got %v", err)
+	}
+	points[3].Observed--
+	if _, err := r.Predict([]float64{10}); err != nil {
+		t.Errorf("Expected the restored data to pass, got %v", err)
+	}
+
+	// without a guard mutations aren't checked
+	points = guardedPoints()
+	r = new(Regression)
+	r.Train(points...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	points[3].Observed++
+	if _, err := r.Predict([]float64{10}); err != nil {
+		t.Errorf("Expected no check, got %v", err)
+	}
+}
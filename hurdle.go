@@ -0,0 +1,189 @@
+package regression
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrNegativeObserved signals an observed value below zero for a model of non-negative values.
+var ErrNegativeObserved = errors.New("observed value is negative")
+
+const (
+	logisticIterations = 100
+	logisticTolerance  = 1e-10
+)
+
+// Hurdle is a two-part model for non-negative observed values with excess zeros, e.g. auctions that
+// often get no bid: a logistic regression models the probability of a positive value, and a linear
+// regression, or a log-linear one, models the positive values. Predict multiplies the two parts.
+type Hurdle struct {
+	// Positive is the model of the positive values. It may be configured, e.g. with SetVar,
+	// AddCross or SetSolver, before Run; it is trained by Run.
+	Positive *Regression
+
+	logLinear bool
+	data      []*dataPoint
+	zero      []float64
+	smearing  float64
+	hasRun    bool
+}
+
+// NewHurdle creates a hurdle model. With logLinear the positive values are modeled on the log scale,
+// and their predicted mean is corrected with Duan's smearing estimate.
+func NewHurdle(logLinear bool) *Hurdle {
+	return &Hurdle{Positive: new(Regression), logLinear: logLinear}
+}
+
+// Train adds data points to the training data. Their observed values must not be negative.
+func (h *Hurdle) Train(d ...*dataPoint) {
+	h.data = append(h.data, d...)
+}
+
+// Run fits the logistic model of zero versus positive values on the variables of all data points and
+// the model of the positive values on the data points with a positive observed value. Feature crosses
+// of the Positive model only apply to the positive values.
+func (h *Hurdle) Run() error {
+	if h.hasRun {
+		return ErrRegressionRun
+	}
+	if len(h.data) == 0 {
+		return ErrNotEnoughData
+	}
+	numVars := len(h.data[0].Variables)
+	positives := 0
+	for _, d := range h.data {
+		if len(d.Variables) != numVars {
+			return ErrDimensions
+		}
+		if d.Observed < 0 {
+			return ErrNegativeObserved
+		}
+		if d.Observed > 0 {
+			positives++
+		}
+	}
+	// both parts need data
+	if positives == 0 || positives == len(h.data) {
+		return ErrNotEnoughData
+	}
+
+	zero, err := fitLogistic(h.data)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range h.data {
+		if d.Observed > 0 {
+			obs := d.Observed
+			if h.logLinear {
+				obs = math.Log(obs)
+			}
+			// Run appends the feature crosses to the variables
+			h.Positive.Train(WeightedDataPoint(obs, append([]float64(nil), d.Variables...), d.Weight))
+		}
+	}
+	if err := h.Positive.Run(); err != nil {
+		return err
+	}
+	h.smearing = 1
+	if h.logLinear {
+		var sum, weights float64
+		for _, d := range h.Positive.data {
+			sum += d.Weight * math.Exp(-d.Error)
+			weights += d.Weight
+		}
+		h.smearing = sum / weights
+	}
+	h.zero = zero
+	h.hasRun = true
+	return nil
+}
+
+// ZeroCoeff returns coefficient i of the logistic model of a positive value, on the log-odds scale;
+// coefficient 0 is the offset.
+func (h *Hurdle) ZeroCoeff(i int) float64 {
+	if i < 0 || i >= len(h.zero) {
+		return 0
+	}
+	return h.zero[i]
+}
+
+// ProbPositive returns the probability of a positive value for vars.
+func (h *Hurdle) ProbPositive(vars []float64) (float64, error) {
+	if !h.hasRun {
+		return 0, ErrNotRun
+	}
+	if len(vars) != len(h.zero)-1 {
+		return 0, ErrDimensions
+	}
+	return sigmoid(h.zero[0] + dot(h.zero[1:], vars)), nil
+}
+
+// PredictPositive returns the expected value for vars given that it is positive.
+func (h *Hurdle) PredictPositive(vars []float64) (float64, error) {
+	if !h.hasRun {
+		return 0, ErrNotRun
+	}
+	p, err := h.Positive.Predict(vars)
+	if err != nil {
+		return 0, err
+	}
+	if h.logLinear {
+		return math.Exp(p) * h.smearing, nil
+	}
+	return p, nil
+}
+
+// Predict returns the expected value for vars: the probability of a positive value times the
+// expected positive value.
+func (h *Hurdle) Predict(vars []float64) (float64, error) {
+	prob, err := h.ProbPositive(vars)
+	if err != nil {
+		return 0, err
+	}
+	positive, err := h.PredictPositive(vars)
+	if err != nil {
+		return 0, err
+	}
+	return prob * positive, nil
+}
+
+// fitLogistic fits a logistic regression of a positive observed value on the variables of the data
+// points by iteratively reweighted least squares, solving the normal equations in each step. It returns the offset followed by the coefficients.
+func fitLogistic(data []*dataPoint) ([]float64, error) {
+	cols := len(data[0].Variables) + 1
+	coeffs := make([]float64, cols)
+	row := make([]float64, cols)
+	row[0] = 1
+	for iter := 0; iter < logisticIterations; iter++ {
+		a := newNormalEquations(cols)
+		for _, d := range data {
+			copy(row[1:], d.Variables)
+			eta := dot(coeffs, row)
+			p := sigmoid(eta)
+			var y float64
+			if d.Observed > 0 {
+				y = 1
+			}
+			// the working response and weight of the Newton step
+			v := math.Max(p*(1-p), 1e-12)
+			a.add(row, eta+(y-p)/v, d.Weight*v)
+		}
+		next, _ := a.solve()
+		var change, size float64
+		for j := range next {
+			change = math.Max(change, math.Abs(next[j]-coeffs[j]))
+			size = math.Max(size, math.Abs(next[j]))
+		}
+		coeffs = next
+		if change <= logisticTolerance*(1+size) {
+			return coeffs, nil
+		}
+	}
+	// typically the zeros and positive values are perfectly separated
+	return nil, ErrNotConverged
+}
+
+func sigmoid(x float64) float64 {
+	return 1 / (1 + math.Exp(-x))
+}
@@ -0,0 +1,100 @@
+package regression
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestHurdle(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	h := NewHurdle(false)
+	h.Positive.SetObserved("bid")
+	for i := 0; i < 2000; i++ {
+		x := 4*rng.Float64() - 2
+		var y float64
+		if rng.Float64() < sigmoid(-1+1.5*x) {
+			y = 10 + 3*x + 0.5*rng.NormFloat64()
+		}
+		h.Train(DataPoint(y, []float64{x}))
+	}
+	if err := h.Run(); err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "zero offset", h.ZeroCoeff(0), -1, 0.2)
+	assertClose(t, "zero slope", h.ZeroCoeff(1), 1.5, 0.2)
+	assertClose(t, "positive offset", h.Positive.Coeff(0), 10, 0.1)
+	assertClose(t, "positive slope", h.Positive.Coeff(1), 3, 0.1)
+	if h.Positive.GetObserved() != "bid" {
+		t.Errorf("Expected the configuration of the positive model to be kept")
+	}
+
+	prob, err := h.ProbPositive([]float64{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	positive, err := h.PredictPositive([]float64{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := h.Predict([]float64{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "probability", prob, sigmoid(0.5), 0.05)
+	assertClose(t, "prediction", got, prob*positive, 1e-12)
+	if err := h.Run(); err != ErrRegressionRun {
+		t.Errorf("Expected ErrRegressionRun, got %v", err)
+	}
+}
+
+func TestHurdleLogLinear(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	h := NewHurdle(true)
+	for i := 0; i < 3000; i++ {
+		x := 2 * rng.Float64()
+		var y float64
+		if rng.Float64() < 0.6 {
+			y = math.Exp(1 + 0.5*x + 0.4*rng.NormFloat64())
+		}
+		h.Train(DataPoint(y, []float64{x}))
+	}
+	if err := h.Run(); err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "log slope", h.Positive.Coeff(1), 0.5, 0.05)
+	// the mean of a log-normal value is exp(mu + sigma^2/2)
+	got, err := h.PredictPositive([]float64{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "positive mean", got, math.Exp(1.5+0.08), 0.15)
+	assertClose(t, "slope of the constant probability", h.ZeroCoeff(1), 0, 0.2)
+}
+
+func TestHurdleErrors(t *testing.T) {
+	h := NewHurdle(false)
+	if _, err := h.Predict([]float64{1}); err != ErrNotRun {
+		t.Errorf("Expected ErrNotRun, got %v", err)
+	}
+	if err := h.Run(); err != ErrNotEnoughData {
+		t.Errorf("Expected ErrNotEnoughData, got %v", err)
+	}
+	h.Train(DataPoint(1, []float64{1}), DataPoint(2, []float64{2}))
+	if err := h.Run(); err != ErrNotEnoughData {
+		t.Errorf("Expected ErrNotEnoughData without zeros, got %v", err)
+	}
+	h.Train(DataPoint(-1, []float64{3}))
+	if err := h.Run(); err != ErrNegativeObserved {
+		t.Errorf("Expected ErrNegativeObserved, got %v", err)
+	}
+
+	// zeros below and positive values above 2 are perfectly separated
+	h = NewHurdle(false)
+	for i := 0; i < 5; i++ {
+		h.Train(DataPoint(0, []float64{float64(i)}), DataPoint(1+float64(i), []float64{float64(i + 5)}))
+	}
+	if err := h.Run(); err != ErrNotConverged {
+		t.Errorf("Expected ErrNotConverged, got %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Imputation selects how PredictPartial fills in the unknown variables.
+type Imputation int
+
+const (
+	// MeanImputation fills in the weighted means of the variables in the training data.
+	MeanImputation Imputation = iota
+	// ConditionalImputation fills in the means of the unknown variables conditional on the known ones,
+	// as implied by the means and covariance of the variables in the training data.
+	ConditionalImputation
+)
+
+func (m Imputation) String() string {
+	switch m {
+	case MeanImputation:
+		return "mean"
+	case ConditionalImputation:
+		return "conditional"
+	}
+	return fmt.Sprintf("Imputation(%d)", int(m))
+}
+
+// parseImputation is the inverse of Imputation.String.
+func parseImputation(s string) (Imputation, error) {
+	switch strings.ToLower(s) {
+	case "", "mean":
+		return MeanImputation, nil
+	case "conditional":
+		return ConditionalImputation, nil
+	}
+	return 0, fmt.Errorf("unknown imputation %q", s)
+}
+
+// PartialPrediction is the result of PredictPartial.
+type PartialPrediction struct {
+	Predicted float64
+	// Vars are the variables the prediction was made with, known and imputed.
+	Vars []float64
+	// Imputed flags the variables that were filled in.
+	Imputed []bool
+}
+
+// moments are the weighted means and covariance of the base variables in the training data.
+type moments struct {
+	mean []float64
+	cov  [][]float64
+}
+
+// SetImputation sets how PredictPartial fills in unknown variables, MeanImputation by default.
+func (r *Regression) SetImputation(m Imputation) {
+	r.imputation = m
+}
+
+// PredictPartial predicts the observed value from the variables that are known, keyed by index, filling in
+// the others as set with SetImputation, e.g. for real-time scoring when some variables arrive late. It needs
+// the training data or a model saved with it, which records the moments of the variables.
+func (r *Regression) PredictPartial(known map[int]float64) (*PartialPrediction, error) {
+	if !r.hasRun || len(r.coeff) == 0 {
+		return nil, ErrNotRun
+	}
+	m, err := r.inputMoments()
+	if err != nil {
+		return nil, err
+	}
+	p := &PartialPrediction{Vars: make([]float64, r.names.base), Imputed: make([]bool, r.names.base)}
+	var unknown, given []int
+	for i := range p.Vars {
+		v, ok := known[i]
+		if !ok {
+			unknown = append(unknown, i)
+			p.Vars[i], p.Imputed[i] = m.mean[i], true
+			continue
+		}
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, ErrNonFinite
+		}
+		given = append(given, i)
+		p.Vars[i] = v
+	}
+	if len(given) != len(known) {
+		return nil, ErrDimensions
+	}
+
+	if r.imputation == ConditionalImputation && len(unknown) > 0 && len(given) > 0 {
+		// E[u | k] = mean_u + cov_uk cov_kk^-1 (k - mean_k)
+		kk := mat.NewDense(len(given), len(given), nil)
+		dev := mat.NewDense(len(given), 1, nil)
+		for a, i := range given {
+			dev.Set(a, 0, p.Vars[i]-m.mean[i])
+			for b, j := range given {
+				kk.Set(a, b, m.cov[i][j])
+			}
+		}
+		w := new(mat.Dense)
+		if err := w.Solve(kk, dev); err != nil {
+			return nil, ErrSingular
+		}
+		for _, u := range unknown {
+			for a, i := range given {
+				p.Vars[u] += m.cov[u][i] * w.At(a, 0)
+			}
+		}
+	}
+
+	if p.Predicted, err = r.Predict(p.Vars); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// inputMoments returns the moments of the base variables, computing them from the training data on first use.
+func (r *Regression) inputMoments() (*moments, error) {
+	if r.moments != nil {
+		return r.moments, nil
+	}
+	if len(r.data) == 0 {
+		return nil, ErrNoStatistics
+	}
+	n := r.names.base
+	m := &moments{mean: make([]float64, n), cov: make([][]float64, n)}
+	var weights float64
+	for _, d := range r.data {
+		weights += d.Weight
+		for j := range m.mean {
+			m.mean[j] += d.Weight * d.Variables[j]
+		}
+	}
+	if weights == 0 {
+		return nil, ErrNotEnoughData
+	}
+	for j := range m.mean {
+		m.mean[j] /= weights
+		m.cov[j] = make([]float64, n)
+	}
+	for _, d := range r.data {
+		for i := 0; i < n; i++ {
+			di := d.Variables[i] - m.mean[i]
+			for j := 0; j <= i; j++ {
+				m.cov[i][j] += d.Weight * di * (d.Variables[j] - m.mean[j])
+			}
+		}
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j <= i; j++ {
+			m.cov[i][j] /= weights
+			m.cov[j][i] = m.cov[i][j]
+		}
+	}
+	r.moments = m
+	return m, nil
+}
@@ -0,0 +1,97 @@
+package regression
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+func imputeRegression(t *testing.T) *Regression {
+	rng := rand.New(rand.NewSource(4))
+	r := new(Regression)
+	for i := 0; i < 500; i++ {
+		x1 := rng.NormFloat64()*3 + 5
+		x2 := 2*x1 + rng.NormFloat64()
+		x3 := rng.NormFloat64()
+		r.Train(DataPoint(1+x1+x2+x3+0.1*rng.NormFloat64(), []float64{x1, x2, x3}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestPredictPartial(t *testing.T) {
+	r := imputeRegression(t)
+	m, err := r.inputMoments()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := r.PredictPartial(map[int]float64{0: 10, 2: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Imputed[0] || !p.Imputed[1] || p.Imputed[2] {
+		t.Errorf("Unexpected imputed flags %v", p.Imputed)
+	}
+	if p.Vars[0] != 10 || p.Vars[1] != m.mean[1] || p.Vars[2] != 1 {
+		t.Errorf("Expected the mean to be imputed, got %v", p.Vars)
+	}
+	want, _ := r.Predict(p.Vars)
+	assertClose(t, "prediction", p.Predicted, want, 1e-12)
+
+	r.SetImputation(ConditionalImputation)
+	p, err = r.PredictPartial(map[int]float64{0: 10, 2: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// x2 is about twice x1 and unrelated to x3
+	assertClose(t, "conditional mean", p.Vars[1], 20, 0.3)
+	assertClose(t, "conditional prediction", p.Predicted, 1+10+20+1, 0.5)
+
+	p, err = r.PredictPartial(map[int]float64{0: 1, 1: 2, 2: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, imputed := range p.Imputed {
+		if imputed {
+			t.Errorf("Expected known variable %d not to be imputed", i)
+		}
+	}
+
+	if _, err := r.PredictPartial(map[int]float64{3: 1}); err != ErrDimensions {
+		t.Errorf("Expected ErrDimensions, got %v", err)
+	}
+	if _, err := new(Regression).PredictPartial(nil); err != ErrNotRun {
+		t.Errorf("Expected ErrNotRun, got %v", err)
+	}
+}
+
+func TestPredictPartialLoaded(t *testing.T) {
+	r := imputeRegression(t)
+	r.SetImputation(ConditionalImputation)
+	var buf bytes.Buffer
+	if err := r.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := r.PredictPartial(map[int]float64{1: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := loaded.PredictPartial(map[int]float64{1: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "loaded prediction", got.Predicted, want.Predicted, 1e-9)
+
+	// a model without the moments can't fill in variables
+	loaded.moments = nil
+	if _, err := loaded.PredictPartial(map[int]float64{1: 4}); err != ErrNoStatistics {
+		t.Errorf("Expected ErrNoStatistics, got %v", err)
+	}
+}
@@ -0,0 +1,262 @@
+// Package infer evaluates models saved with github.com/sajari/regression's Save or MarshalJSON. It depends
+// only on the standard library, not on a matrix library, so scoring services can load and evaluate models
+// without pulling in the dependencies of training.
+//
+// Models with the "pow" and "multiplier" feature crosses, winsorization cut points and per-segment models
+// are supported. Group effects, the uncertainty of predictions and other feature crosses are not.
+package infer
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/sajari/regression/internal/crossname"
+)
+
+var (
+	// ErrDimensions signals that the number of variables doesn't match the model.
+	ErrDimensions = errors.New("infer: wrong number of variables")
+	// ErrInvalid signals that a saved model is malformed.
+	ErrInvalid = errors.New("infer: invalid model")
+	// ErrUnsupportedCross signals a feature cross that this package can't evaluate.
+	ErrUnsupportedCross = errors.New("infer: unsupported feature cross")
+)
+
+// offsetName is the name of the offset in explanations, as in the regression package.
+const offsetName = "(offset)"
+
+// Model is a fitted model loaded for inference.
+type Model struct {
+	// Observed is the name of the observed value.
+	Observed string
+	// Names are the names of the coefficients: the offset, the variables and the feature crosses.
+	Names        []string
+	Coefficients []float64
+
+	base     int
+	crosses  []cross
+	clips    map[int][2]float64
+	missing  []int
+	fills    []float64
+	splitVar int
+	segments map[float64]*Model
+}
+
+// Contribution is the part of a prediction due to one coefficient.
+type Contribution struct {
+	Name string
+	// Value is the value of the variable or feature cross, 1 for the offset.
+	Value       float64
+	Coefficient float64
+	// Contribution is Coefficient * Value; the contributions sum to the prediction.
+	Contribution float64
+}
+
+type cross struct {
+	kind  string
+	vars  []int
+	power float64
+}
+
+// saved is the part of the serialized model used for inference.
+type saved struct {
+	Observed     string                      `json:"observed"`
+	Vars         map[int]string              `json:"vars"`
+	BaseVars     int                         `json:"base_vars"`
+	Coefficients []float64                   `json:"coefficients"`
+	Crosses      []savedCross                `json:"crosses"`
+	Clips        map[int][2]float64          `json:"clips"`
+	SplitVar     *int                        `json:"split_var"`
+	Segments     map[string]*json.RawMessage `json:"segments"`
+	Missing      map[int]float64             `json:"missing_indicators"`
+}
+
+type savedCross struct {
+	Type  string  `json:"type"`
+	Vars  []int   `json:"vars"`
+	Power float64 `json:"power"`
+}
+
+// Load reads a model saved with Regression.Save.
+func Load(rd io.Reader) (*Model, error) {
+	var s saved
+	if err := json.NewDecoder(rd).Decode(&s); err != nil {
+		return nil, err
+	}
+	return s.model()
+}
+
+func (s *saved) model() (*Model, error) {
+	if len(s.Coefficients) == 0 || s.BaseVars < 0 {
+		return nil, ErrInvalid
+	}
+	m := &Model{Observed: s.Observed, Coefficients: s.Coefficients, base: s.BaseVars, clips: s.Clips, splitVar: -1}
+
+	// name the crosses as the regression package does
+	names := make(map[int]string, len(s.Vars))
+	for i, name := range s.Vars {
+		if i < s.BaseVars {
+			names[i] = name
+		}
+	}
+	columns := s.BaseVars
+	for _, sc := range s.Crosses {
+		c := cross{kind: sc.Type, vars: sc.Vars, power: sc.Power}
+		var fn string
+		switch sc.Type {
+		case "pow":
+			if len(sc.Vars) != 1 {
+				return nil, ErrInvalid
+			}
+			fn = crossname.Pow(sc.Power)
+		case "multiplier":
+			fn = crossname.Multiplier(sc.Vars)
+		default:
+			return nil, ErrUnsupportedCross
+		}
+		for _, v := range sc.Vars {
+			if v < 0 || v >= columns {
+				return nil, ErrInvalid
+			}
+		}
+		columns += crossname.Extend(names, columns, sc.Vars, fn)
+		m.crosses = append(m.crosses, c)
+	}
+	for v := range s.Missing {
+		if v < 0 || v >= s.BaseVars {
+			return nil, ErrInvalid
+		}
+		m.missing = append(m.missing, v)
+	}
+	sort.Ints(m.missing)
+	for _, v := range m.missing {
+		if names[v] != "" {
+			names[columns] = "(" + names[v] + ")missing"
+		}
+		m.fills = append(m.fills, s.Missing[v])
+		columns++
+	}
+	if len(s.Coefficients) != columns+1 {
+		return nil, ErrInvalid
+	}
+	m.Names = make([]string, len(s.Coefficients))
+	m.Names[0] = offsetName
+	for i := 1; i < len(m.Names); i++ {
+		if m.Names[i] = names[i-1]; m.Names[i] == "" {
+			m.Names[i] = "X" + strconv.Itoa(i-1)
+		}
+	}
+
+	if s.SplitVar != nil {
+		if *s.SplitVar < 0 || *s.SplitVar >= s.BaseVars {
+			return nil, ErrInvalid
+		}
+		m.splitVar = *s.SplitVar
+		m.segments = make(map[float64]*Model, len(s.Segments))
+		for k, raw := range s.Segments {
+			v, err := strconv.ParseFloat(k, 64)
+			if err != nil || raw == nil {
+				return nil, ErrInvalid
+			}
+			var seg saved
+			if err := json.Unmarshal(*raw, &seg); err != nil {
+				return nil, err
+			}
+			if m.segments[v], err = seg.model(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return m, nil
+}
+
+// Vars returns the number of variables the model expects.
+func (m *Model) Vars() int {
+	return m.base
+}
+
+// Predict predicts the observed value for vars, as Regression.Predict does.
+func (m *Model) Predict(vars []float64) (float64, error) {
+	if len(vars) != m.base {
+		return 0, ErrDimensions
+	}
+	if s := m.segmentFor(vars); s != nil {
+		return s.Predict(vars)
+	}
+	var p float64
+	for j, v := range m.row(vars) {
+		p += m.Coefficients[j] * v
+	}
+	return p, nil
+}
+
+// Explain returns the contributions of the offset, variables and feature crosses to the prediction for vars,
+// in coefficient order. The contributions of a per-segment model are those of the segment's model.
+func (m *Model) Explain(vars []float64) ([]Contribution, error) {
+	if len(vars) != m.base {
+		return nil, ErrDimensions
+	}
+	if s := m.segmentFor(vars); s != nil {
+		return s.Explain(vars)
+	}
+	row := m.row(vars)
+	contributions := make([]Contribution, len(row))
+	for j, v := range row {
+		contributions[j] = Contribution{Name: m.Names[j], Value: v, Coefficient: m.Coefficients[j], Contribution: m.Coefficients[j] * v}
+	}
+	return contributions, nil
+}
+
+// Segments returns the values of the split variable with a model of their own, sorted.
+func (m *Model) Segments() []float64 {
+	values := make([]float64, 0, len(m.segments))
+	for v := range m.segments {
+		values = append(values, v)
+	}
+	sort.Float64s(values)
+	return values
+}
+
+func (m *Model) segmentFor(vars []float64) *Model {
+	if len(m.segments) == 0 {
+		return nil
+	}
+	return m.segments[vars[m.splitVar]]
+}
+
+// row builds the design row: the offset, the clipped variables and the feature crosses.
+func (m *Model) row(vars []float64) []float64 {
+	row := make([]float64, 1, len(m.Coefficients))
+	row[0] = 1
+	row = append(row, vars...)
+	for i, c := range m.clips {
+		if i < len(vars) {
+			row[i+1] = math.Max(c[0], math.Min(c[1], row[i+1]))
+		}
+	}
+	// missing values are imputed ahead of the crosses and indicated after them
+	indicators := make([]float64, len(m.missing))
+	for j, v := range m.missing {
+		if math.IsNaN(row[v+1]) {
+			row[v+1] = m.fills[j]
+			indicators[j] = 1
+		}
+	}
+	for _, c := range m.crosses {
+		in := row[1:]
+		if c.kind == "pow" {
+			row = append(row, math.Pow(in[c.vars[0]], c.power))
+			continue
+		}
+		product := 1.0
+		for _, v := range c.vars {
+			product *= in[v]
+		}
+		row = append(row, product)
+	}
+	return append(row, indicators...)
+}
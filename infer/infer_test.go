@@ -0,0 +1,148 @@
+package infer
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/sajari/regression"
+)
+
+func fit(t *testing.T, setup func(r *regression.Regression)) *regression.Regression {
+	r := new(regression.Regression)
+	r.SetObserved("y")
+	r.SetVar(0, "a")
+	r.SetVar(1, "b")
+	setup(r)
+	for i := 0; i < 40; i++ {
+		a, b := float64(i%9), float64(i%4)
+		r.Train(regression.DataPoint(1+2*a-b+0.5*a*b+0.1*a*a+0.01*float64(i%5), []float64{a, b}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func load(t *testing.T, r *regression.Regression) *Model {
+	var buf bytes.Buffer
+	if err := r.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	m, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestPredict(t *testing.T) {
+	for name, setup := range map[string]func(r *regression.Regression){
+		"plain": func(r *regression.Regression) {},
+		"crosses": func(r *regression.Regression) {
+			r.AddCross(regression.MultiplierCross(0, 1))
+			r.AddCross(regression.PowCross(0, 2))
+		},
+		"winsorized": func(r *regression.Regression) {
+			r.WinsorizeVar(0, 0.1, 0.9)
+		},
+		"segments": func(r *regression.Regression) {
+			r.SplitByVar(1)
+		},
+	} {
+		r := fit(t, setup)
+		m := load(t, r)
+		if m.Vars() != 2 || m.Observed != "y" {
+			t.Errorf("%s: unexpected model %+v", name, m)
+		}
+		for _, vars := range [][]float64{{0, 0}, {3, 1}, {8, 3}, {20, 2}} {
+			want, err := r.Predict(vars)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := m.Predict(vars)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(got-want) > 1e-9 {
+				t.Errorf("%s: expected %v for %v, got %v", name, want, vars, got)
+			}
+
+			contributions, err := m.Explain(vars)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var sum float64
+			for _, c := range contributions {
+				sum += c.Contribution
+			}
+			if math.Abs(sum-want) > 1e-9 {
+				t.Errorf("%s: expected the contributions to sum to %v, got %v", name, want, sum)
+			}
+		}
+	}
+}
+
+func TestExplainNames(t *testing.T) {
+	r := fit(t, func(r *regression.Regression) {
+		r.AddCross(regression.PowCross(0, 2))
+	})
+	m := load(t, r)
+	want := []string{"(offset)", r.GetVar(0), r.GetVar(1), r.GetVar(2)}
+	contributions, err := m.Explain([]float64{2, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for j, c := range contributions {
+		if c.Name != want[j] || m.Names[j] != want[j] {
+			t.Errorf("Expected name %q, got %q", want[j], c.Name)
+		}
+		if c.Coefficient != r.Coeff(j) {
+			t.Errorf("Expected coefficient %v, got %v", r.Coeff(j), c.Coefficient)
+		}
+	}
+	if contributions[3].Value != 4 {
+		t.Errorf("Expected the squared value 4, got %v", contributions[3].Value)
+	}
+}
+
+func TestExplainNamesAfterInteraction(t *testing.T) {
+	r := fit(t, func(r *regression.Regression) {
+		r.AddCross(regression.MultiplierCross(0, 1))
+		r.AddCross(regression.PowCross(0, 2))
+	})
+	m := load(t, r)
+	want := []string{"(offset)", "a", "b", "(a)0*1", "(a)^2"}
+	if len(m.Names) != len(want) {
+		t.Fatalf("Expected names %v, got %v", want, m.Names)
+	}
+	for j := range want {
+		if m.Names[j] != want[j] {
+			t.Errorf("Expected name %q, got %q", want[j], m.Names[j])
+		}
+		if j > 0 && m.Names[j] != r.GetVar(j-1) {
+			t.Errorf("Expected the name of the regression package %q, got %q", r.GetVar(j-1), m.Names[j])
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	m := load(t, fit(t, func(r *regression.Regression) {}))
+	if _, err := m.Predict([]float64{1}); err != ErrDimensions {
+		t.Errorf("Expected ErrDimensions, got %v", err)
+	}
+	for _, c := range []struct {
+		json string
+		err  error
+	}{
+		{`{"coefficients": []}`, ErrInvalid},
+		{`{"base_vars": 1, "coefficients": [1]}`, ErrInvalid},
+		{`{"base_vars": 1, "coefficients": [1, 2, 3], "crosses": [{"type": "fourier", "vars": [0]}]}`, ErrUnsupportedCross},
+		{`{"base_vars": 1, "coefficients": [1, 2, 3], "crosses": [{"type": "pow", "vars": [4]}]}`, ErrInvalid},
+	} {
+		if _, err := Load(strings.NewReader(c.json)); err != c.err {
+			t.Errorf("Expected %v for %s, got %v", c.err, c.json, err)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package regression
+
+import (
+	"math"
+	"time"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/mathext"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Distribution is a continuous univariate distribution used to turn test
+// statistics into p-values and critical values.
+type Distribution interface {
+	CDF(x float64) float64
+	Quantile(p float64) float64
+}
+
+// DistributionProvider builds the reference distributions for the given degrees of freedom.
+// The default provider is backed by gonum's distuv package.
+type DistributionProvider interface {
+	StudentsT(df float64) Distribution
+	F(d1, d2 float64) Distribution
+}
+
+type gonumDistributions struct{}
+
+func (gonumDistributions) StudentsT(df float64) Distribution {
+	return distuv.StudentsT{Mu: 0, Sigma: 1, Nu: df}
+}
+
+func (gonumDistributions) F(d1, d2 float64) Distribution {
+	return fDistribution{distuv.F{D1: d1, D2: d2}}
+}
+
+// fDistribution adds a quantile function to distuv.F, which only provides the CDF, and an upper
+// tail that doesn't lose the precision of small p-values to 1 - CDF(x).
+type fDistribution struct {
+	distuv.F
+}
+
+func (f fDistribution) Survival(x float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	if math.IsInf(x, 1) {
+		return 0
+	}
+	return mathext.RegIncBeta(f.D2/2, f.D1/2, f.D2/(f.D2+f.D1*x))
+}
+
+func (f fDistribution) Quantile(p float64) float64 {
+	if p <= 0 {
+		return 0
+	}
+	if p >= 1 {
+		return math.Inf(1)
+	}
+	lo, hi := 0.0, 1.0
+	for f.CDF(hi) < p {
+		lo, hi = hi, hi*2
+	}
+	for i := 0; i < 200 && hi-lo > 1e-12*hi; i++ {
+		mid := (lo + hi) / 2
+		if f.CDF(mid) < p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// SetDistributions overrides the provider used to compute p-values and critical values.
+func (r *Regression) SetDistributions(p DistributionProvider) {
+	r.dist = p
+}
+
+func (r *Regression) distributions() DistributionProvider {
+	if r.dist == nil {
+		return gonumDistributions{}
+	}
+	return r.dist
+}
+
+// DegreesOfFreedom returns the model and residual degrees of freedom of the fit.
+func (r *Regression) DegreesOfFreedom() (model, residual int) {
+	if len(r.coeff) == 0 {
+		return 0, 0
+	}
+	return len(r.coeff) - 1, r.dfResidual
+}
+
+// StdErr returns the standard error of coefficient i.
+func (r *Regression) StdErr(i int) float64 {
+	if r.covariance == nil {
+		return math.NaN()
+	}
+	n, _ := r.covariance.Dims()
+	if i < 0 || i >= n {
+		return math.NaN()
+	}
+	return math.Sqrt(r.covariance.At(i, i))
+}
+
+// TStat returns the t statistic of coefficient i under the null hypothesis that it is zero.
+func (r *Regression) TStat(i int) float64 {
+	return r.Coeff(i) / r.StdErr(i)
+}
+
+// PValue returns the two-sided p-value of the t test for coefficient i.
+func (r *Regression) PValue(i int) float64 {
+	t := r.TStat(i)
+	if math.IsNaN(t) || r.dfResidual <= 0 {
+		return math.NaN()
+	}
+	d := r.distributions().StudentsT(float64(r.dfResidual))
+	return 2 * d.CDF(-math.Abs(t))
+}
+
+// CriticalT returns the two-sided critical t value at significance level alpha,
+// using the residual degrees of freedom of the fit.
+func (r *Regression) CriticalT(alpha float64) float64 {
+	if r.dfResidual <= 0 {
+		return math.NaN()
+	}
+	d := r.distributions().StudentsT(float64(r.dfResidual))
+	return d.Quantile(1 - alpha/2)
+}
+
+// FStat returns the F statistic testing that all coefficients except the offset are zero.
+func (r *Regression) FStat() float64 {
+	model, residual := r.DegreesOfFreedom()
+	if model <= 0 || residual <= 0 {
+		return math.NaN()
+	}
+	return (r.R2 / float64(model)) / ((1 - r.R2) / float64(residual))
+}
+
+// FPValue returns the p-value of the overall F test.
+func (r *Regression) FPValue() float64 {
+	f := r.FStat()
+	if math.IsNaN(f) {
+		return math.NaN()
+	}
+	model, residual := r.DegreesOfFreedom()
+	d := r.distributions().F(float64(model), float64(residual))
+	if s, ok := d.(survival); ok {
+		return s.Survival(f)
+	}
+	return 1 - d.CDF(f)
+}
+
+// survival is implemented by distributions that compute the upper tail probability directly,
+// which is more precise than 1 - CDF(x) for small p-values.
+type survival interface {
+	Survival(x float64) float64
+}
+
+// CriticalF returns the critical F value at significance level alpha for the overall F test.
+func (r *Regression) CriticalF(alpha float64) float64 {
+	model, residual := r.DegreesOfFreedom()
+	if model <= 0 || residual <= 0 {
+		return math.NaN()
+	}
+	d := r.distributions().F(float64(model), float64(residual))
+	return d.Quantile(1 - alpha)
+}
+
+// calcInference estimates the residual variance and scales the unscaled covariance
+// matrix (X'X)^-1 reported by the solver into the covariance matrix of the coefficients.
+func (r *Regression) calcInference(diag *Diagnostics) {
+	var sse, weights float64
+	observations := 0
+	for _, d := range r.data {
+		sse += d.Weight * d.Error * d.Error
+		weights += d.Weight
+		// as in R, observations with zero weight don't count towards the degrees of freedom
+		if d.Weight != 0 {
+			observations++
+		}
+	}
+	r.rmse = math.Sqrt(sse / weights)
+	// aliased variables don't use up a degree of freedom
+	rank := len(r.coeff)
+	if diag != nil {
+		rank -= len(diag.Aliased)
+	}
+	r.observations = observations
+	r.trainedAt = time.Now()
+	r.newPoints = 0
+	// as are the group means absorbed by RunFixedEffects
+	r.dfResidual = observations - rank - r.absorbed
+	r.sigma2 = math.NaN()
+	if r.dfResidual > 0 {
+		r.sigma2 = sse / float64(r.dfResidual)
+	}
+	r.covariance = nil
+	if diag != nil && diag.Unscaled != nil {
+		r.covariance = new(mat.Dense)
+		r.covariance.Scale(r.sigma2, diag.Unscaled)
+	}
+}
+
+// Observations returns the number of data points the model was fitted with, not counting points with zero weight.
+func (r *Regression) Observations() int {
+	return r.observations
+}
+
+// RMSE returns the root mean squared error of the fit on the training data, weighted when the
+// data points carry weights.
+func (r *Regression) RMSE() float64 {
+	if !r.hasRun {
+		return math.NaN()
+	}
+	return r.rmse
+}
+
+// PredictSE returns the standard error of the predicted mean for vars, derived from the
+// coefficient covariance matrix. Feature crosses are applied to vars as in Predict.
+func (r *Regression) PredictSE(vars []float64) (float64, error) {
+	if !r.initialised {
+		return 0, ErrNotEnoughData
+	}
+	if s := r.segmentFor(vars); s != nil {
+		return s.PredictSE(vars)
+	}
+	if r.covariance == nil {
+		return 0, ErrNotRun
+	}
+	row := r.designRow(vars)
+	n, _ := r.covariance.Dims()
+	var variance float64
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			variance += row[i] * r.covariance.At(i, j) * row[j]
+		}
+	}
+	return math.Sqrt(variance), nil
+}
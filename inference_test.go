@@ -0,0 +1,105 @@
+package regression
+
+import (
+	"math"
+	"testing"
+)
+
+var (
+	carsSpeed = []float64{4, 4, 7, 7, 8, 9, 10, 10, 10, 11, 11, 12, 12, 12, 12, 13, 13, 13, 13, 14, 14, 14, 14, 15, 15, 15, 16, 16, 17, 17, 17, 18, 18, 18, 18, 19, 19, 19, 20, 20, 20, 20, 20, 22, 23, 24, 24, 24, 24, 25}
+	carsDist  = []float64{2, 10, 4, 22, 16, 10, 18, 26, 34, 17, 28, 14, 20, 24, 28, 26, 34, 34, 46, 26, 36, 60, 80, 20, 26, 54, 32, 40, 32, 40, 50, 42, 56, 76, 84, 36, 46, 68, 32, 48, 52, 56, 64, 66, 54, 70, 92, 93, 120, 85}
+)
+
+// carsRegression fits R's `lm(dist ~ speed, data = cars)`.
+func carsRegression(t *testing.T) *Regression {
+	r := new(Regression)
+	r.SetObserved("dist")
+	r.SetVar(0, "speed")
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func assertClose(t *testing.T, name string, got, want, tol float64) {
+	if math.Abs(got-want) > tol {
+		t.Errorf("%s: expected %v, got %v", name, want, got)
+	}
+}
+
+func TestInference(t *testing.T) {
+	r := carsRegression(t)
+
+	// Reference values from R's summary(lm(dist ~ speed, data = cars))
+	assertClose(t, "intercept", r.Coeff(0), -17.5791, 1e-4)
+	assertClose(t, "speed", r.Coeff(1), 3.9324, 1e-4)
+	assertClose(t, "intercept stderr", r.StdErr(0), 6.7584, 1e-4)
+	assertClose(t, "speed stderr", r.StdErr(1), 0.4155, 1e-4)
+	assertClose(t, "intercept t", r.TStat(0), -2.601, 1e-3)
+	assertClose(t, "speed t", r.TStat(1), 9.464, 1e-3)
+	assertClose(t, "intercept p", r.PValue(0), 0.0123, 1e-4)
+	assertClose(t, "speed p", r.PValue(1), 1.49e-12, 1e-14)
+	assertClose(t, "F", r.FStat(), 89.57, 1e-2)
+	assertClose(t, "F p", r.FPValue(), 1.490e-12, 1e-14)
+
+	model, residual := r.DegreesOfFreedom()
+	if model != 1 || residual != 48 {
+		t.Errorf("Expected 1 and 48 degrees of freedom, got %d and %d", model, residual)
+	}
+
+	// qt(0.975, 48) and qf(0.95, 1, 48)
+	assertClose(t, "critical t", r.CriticalT(0.05), 2.010635, 1e-5)
+	assertClose(t, "critical F", r.CriticalF(0.05), 4.042652, 1e-5)
+}
+
+type fixedDistribution float64
+
+func (d fixedDistribution) CDF(x float64) float64      { return float64(d) }
+func (d fixedDistribution) Quantile(p float64) float64 { return float64(d) }
+
+type fixedProvider struct{}
+
+func (fixedProvider) StudentsT(df float64) Distribution { return fixedDistribution(0.25) }
+func (fixedProvider) F(d1, d2 float64) Distribution     { return fixedDistribution(0.25) }
+
+func TestSetDistributions(t *testing.T) {
+	r := carsRegression(t)
+	r.SetDistributions(fixedProvider{})
+	if r.PValue(1) != 0.5 {
+		t.Errorf("Expected the injected distribution to be used, got p = %v", r.PValue(1))
+	}
+	if r.FPValue() != 0.75 {
+		t.Errorf("Expected the injected distribution to be used, got p = %v", r.FPValue())
+	}
+}
+
+func TestInferenceBeforeRun(t *testing.T) {
+	r := new(Regression)
+	if !math.IsNaN(r.StdErr(0)) || !math.IsNaN(r.PValue(0)) || !math.IsNaN(r.FStat()) {
+		t.Error("Expected NaN statistics before Run")
+	}
+}
+
+func TestPredictSE(t *testing.T) {
+	r := carsRegression(t)
+
+	// sigma * sqrt(1/n + (x - mean(x))^2 / sum((x - mean(x))^2))
+	se, err := r.PredictSE([]float64{21})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "se at 21", se, 3.185116, 1e-5)
+
+	// The standard error is smallest at the mean of the variable.
+	mean, _ := r.PredictSE([]float64{15.4})
+	if mean >= se {
+		t.Errorf("Expected the standard error at the mean (%v) to be below %v", mean, se)
+	}
+
+	if _, err := new(Regression).PredictSE([]float64{1}); err != ErrNotEnoughData {
+		t.Errorf("Expected ErrNotEnoughData, got %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package regression
+
+import "math"
+
+// LeaveOneOutDeltas returns, for every training data point in the order they were trained, how each
+// coefficient would change if the model were refitted without that point: deltas[i][j] is the change of
+// coefficient j on removing data point i. It is computed in one pass from the leverages, the diagonal of
+// the hat matrix, rather than by refitting the model. Removing a data point with a leverage of one makes
+// the fit underdetermined, so its deltas are NaN. Only least squares fits with the default solver and
+// without instruments, fixed effects or per-segment models are supported.
+func (r *Regression) LeaveOneOutDeltas() ([][]float64, error) {
+	if !r.hasRun || len(r.coeff) == 0 {
+		return nil, ErrNotRun
+	}
+	if len(r.data) == 0 {
+		return nil, ErrNoStatistics
+	}
+	if r.solve != nil || r.split || r.instruments != nil || r.fixedEffects != nil || r.groupEffects != nil ||
+		r.censor != nil || r.prior != nil {
+		return nil, ErrUnsupported
+	}
+
+	// the training data already has the crosses applied
+	rows := make([][]float64, len(r.data))
+	a := newNormalEquations(len(r.coeff))
+	for i, d := range r.data {
+		rows[i] = append([]float64{1}, d.Variables...)
+		a.add(rows[i], d.Observed, d.Weight)
+	}
+	_, unscaled := a.solve()
+
+	deltas := make([][]float64, len(r.data))
+	u := make([]float64, len(r.coeff))
+	for i, d := range r.data {
+		// u = (X'WX)^-1 x_i and the leverage h_ii = w_i x_i'(X'WX)^-1 x_i
+		var h float64
+		for j := range u {
+			u[j] = 0
+			for k, x := range rows[i] {
+				u[j] += unscaled.At(j, k) * x
+			}
+			h += rows[i][j] * u[j]
+		}
+		h *= d.Weight
+
+		deltas[i] = make([]float64, len(u))
+		scale := -d.Weight * (d.Observed - r.predictRow(rows[i])) / (1 - h)
+		if 1-h <= aliasTolerance {
+			scale = math.NaN()
+		}
+		for j := range u {
+			deltas[i][j] = scale * u[j]
+		}
+	}
+	return deltas, nil
+}
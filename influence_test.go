@@ -0,0 +1,59 @@
+package regression
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLeaveOneOutDeltas(t *testing.T) {
+	for _, weighted := range []bool{false, true} {
+		r := new(Regression)
+		r.AddCross(PowCross(0, 2))
+		for i := range carsSpeed {
+			w := 1.0
+			if weighted {
+				w = float64(1 + i%3)
+			}
+			r.Train(WeightedDataPoint(carsDist[i], []float64{carsSpeed[i]}, w))
+		}
+		if err := r.Run(); err != nil {
+			t.Fatal(err)
+		}
+		deltas, err := r.LeaveOneOutDeltas()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(deltas) != len(carsSpeed) {
+			t.Fatalf("Expected %d rows of deltas, got %d", len(carsSpeed), len(deltas))
+		}
+
+		// the deltas match refitting without the data point
+		for _, i := range []int{0, 22, 48, 49} {
+			rest := append(append([]*dataPoint(nil), r.data[:i]...), r.data[i+1:]...)
+			c, err := r.refit(rest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for j := range c {
+				assertClose(t, "delta", deltas[i][j], c[j]-r.Coeff(j), 1e-8*math.Max(1, math.Abs(c[j])))
+			}
+		}
+	}
+}
+
+func TestLeaveOneOutDeltasErrors(t *testing.T) {
+	if _, err := new(Regression).LeaveOneOutDeltas(); err != ErrNotRun {
+		t.Errorf("Expected ErrNotRun, got %v", err)
+	}
+	r := new(Regression)
+	r.SetSolver(RidgeSolver{Lambda: 1})
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.LeaveOneOutDeltas(); err != ErrUnsupported {
+		t.Errorf("Expected ErrUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package regression
+
+import "sync"
+
+// ingestSamples is the number of rejected rows kept as samples by the ingest report.
+const ingestSamples = 20
+
+// Reasons for which rows are rejected on ingest.
+const (
+	// RejectZeroWeight marks data points with a weight of zero, which the fit ignores.
+	RejectZeroWeight = "zero_weight"
+	// RejectDecode marks records that ConsumeMessages couldn't decode and passed to OnError.
+	RejectDecode = "decode"
+)
+
+// RejectedRow is a row that was dropped or ignored on ingest.
+type RejectedRow struct {
+	Reason string
+	// Observed and Variables are set for data points, Record and Err for records that couldn't be decoded.
+	Observed  float64
+	Variables []float64
+	Record    []byte
+	Err       string
+}
+
+// IngestReport describes the rows that were dropped or ignored on their way into the model,
+// so data loss in pipelines is visible.
+type IngestReport struct {
+	// Received is the number of data points passed to Train and Update, and of records ConsumeMessages
+	// couldn't decode.
+	Received int
+	// Rejected is the number of rejected rows by reason.
+	Rejected map[string]int
+	// Samples are the first rejected rows, up to 20.
+	Samples []RejectedRow
+}
+
+// ingestStats accumulates the ingest report. It has its own lock as ConsumeMessages decodes records
+// while the model is updated.
+type ingestStats struct {
+	mu     sync.Mutex
+	report IngestReport
+}
+
+// IngestReport returns the report of the rows dropped or ignored since the model was created.
+func (r *Regression) IngestReport() IngestReport {
+	s := r.ingestStats()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	report := IngestReport{
+		Received: s.report.Received,
+		Rejected: make(map[string]int, len(s.report.Rejected)),
+		Samples:  append([]RejectedRow(nil), s.report.Samples...),
+	}
+	for reason, n := range s.report.Rejected {
+		report.Rejected[reason] = n
+	}
+	return report
+}
+
+func (r *Regression) ingestStats() *ingestStats {
+	if r.ingest == nil {
+		r.ingest = new(ingestStats)
+	}
+	return r.ingest
+}
+
+// ingestPoints records data points received by Train or Update.
+func (r *Regression) ingestPoints(d []*dataPoint) {
+	s := r.ingestStats()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.report.Received += len(d)
+	for _, p := range d {
+		if p.Weight == 0 {
+			s.reject(RejectedRow{
+				Reason:    RejectZeroWeight,
+				Observed:  p.Observed,
+				Variables: append([]float64(nil), p.Variables...),
+			})
+		}
+	}
+}
+
+// ingestRecord records a record that couldn't be decoded.
+func (r *Regression) ingestRecord(msg []byte, err error) {
+	s := r.ingestStats()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.report.Received++
+	s.reject(RejectedRow{Reason: RejectDecode, Record: append([]byte(nil), msg...), Err: err.Error()})
+}
+
+func (s *ingestStats) reject(row RejectedRow) {
+	if s.report.Rejected == nil {
+		s.report.Rejected = make(map[string]int)
+	}
+	s.report.Rejected[row.Reason]++
+	if len(s.report.Samples) < ingestSamples {
+		s.report.Samples = append(s.report.Samples, row)
+	}
+}
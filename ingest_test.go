@@ -0,0 +1,61 @@
+package regression
+
+import (
+	"context"
+	"io"
+	"testing"
+)
+
+func TestIngestReport(t *testing.T) {
+	r := new(Regression)
+	for i, speed := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{speed}))
+	}
+	r.Train(WeightedDataPoint(1000, []float64{1}, 0))
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Update(WeightedDataPoint(-5, []float64{2}, 0), DataPoint(10, []float64{4})); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs := []string{`{"observed": 3, "vars": [5]}`, `not json`}
+	i := 0
+	src := MessageSourceFunc(func(ctx context.Context) ([]byte, error) {
+		if i == len(msgs) {
+			return nil, io.EOF
+		}
+		i++
+		return []byte(msgs[i-1]), nil
+	})
+	err := r.ConsumeMessages(context.Background(), src, ConsumeOptions{OnError: func([]byte, error) {}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := r.IngestReport()
+	if report.Received != len(carsSpeed)+5 {
+		t.Errorf("Expected %d received rows, got %d", len(carsSpeed)+5, report.Received)
+	}
+	if report.Rejected[RejectZeroWeight] != 2 || report.Rejected[RejectDecode] != 1 {
+		t.Errorf("Unexpected rejections %v", report.Rejected)
+	}
+	if len(report.Samples) != 3 {
+		t.Fatalf("Expected 3 samples, got %+v", report.Samples)
+	}
+	if s := report.Samples[0]; s.Reason != RejectZeroWeight || s.Observed != 1000 || s.Variables[0] != 1 {
+		t.Errorf("Unexpected sample %+v", s)
+	}
+	if s := report.Samples[2]; s.Reason != RejectDecode || string(s.Record) != "not json" || s.Err == "" {
+		t.Errorf("Unexpected sample %+v", s)
+	}
+
+	// the samples are capped
+	r = new(Regression)
+	for i := 0; i < 2*ingestSamples; i++ {
+		r.Train(WeightedDataPoint(1, []float64{1}, 0))
+	}
+	if report := r.IngestReport(); len(report.Samples) != ingestSamples || report.Rejected[RejectZeroWeight] != 2*ingestSamples {
+		t.Errorf("Unexpected report %+v", report)
+	}
+}
@@ -0,0 +1,31 @@
+// Package crossname names the columns of the "pow" and "multiplier" feature crosses, so the regression
+// package and the infer package, which doesn't depend on it, label them alike.
+package crossname
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Pow returns the function name of a power cross, as in "(x)^2".
+func Pow(power float64) string {
+	return "^" + strconv.FormatFloat(power, 'f', -1, 64)
+}
+
+// Multiplier returns the function name of the interaction of vars, as in "(a)0*1".
+func Multiplier(vars []int) string {
+	parts := make([]string, len(vars))
+	for i, v := range vars {
+		parts[i] = strconv.Itoa(v)
+	}
+	return strings.Join(parts, "*")
+}
+
+// Extend names the column of a cross of vars with the function name fn at index cursor, after the first
+// variable if it has a name. It returns the number of columns the cross produces, which is 1.
+func Extend(names map[int]string, cursor int, vars []int, fn string) int {
+	if len(vars) > 0 && names[vars[0]] != "" {
+		names[cursor] = "(" + names[vars[0]] + ")" + fn
+	}
+	return 1
+}
@@ -0,0 +1,32 @@
+//go:build go1.23
+// +build go1.23
+
+package regression
+
+import "iter"
+
+// Points returns an iterator over the training data points.
+func (r *Regression) Points() iter.Seq[*dataPoint] {
+	return func(yield func(*dataPoint) bool) {
+		for _, d := range r.data {
+			if !yield(d) {
+				return
+			}
+		}
+	}
+}
+
+// TrainSeq trains the regression with the data points yielded by seq.
+func (r *Regression) TrainSeq(seq iter.Seq[*dataPoint]) {
+	for d := range seq {
+		r.Train(d)
+	}
+}
+
+// RunSeq fits the regression out-of-core with the data points yielded by seq, which are not retained.
+// See RunStream.
+func (r *Regression) RunSeq(seq iter.Seq[*dataPoint]) error {
+	next, stop := iter.Pull(seq)
+	defer stop()
+	return r.RunStream(next)
+}
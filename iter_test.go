@@ -0,0 +1,53 @@
+//go:build go1.23
+// +build go1.23
+
+package regression
+
+import (
+	"iter"
+	"testing"
+)
+
+func carsSeq() iter.Seq[*dataPoint] {
+	return func(yield func(*dataPoint) bool) {
+		for i := range carsSpeed {
+			if !yield(DataPoint(carsDist[i], []float64{carsSpeed[i]})) {
+				return
+			}
+		}
+	}
+}
+
+func TestTrainSeq(t *testing.T) {
+	r := new(Regression)
+	r.TrainSeq(carsSeq())
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "speed", r.Coeff(1), 3.9324, 1e-4)
+
+	n := 0
+	for d := range r.Points() {
+		if d.Observed != carsDist[n] {
+			t.Errorf("Expected %v, got %v", carsDist[n], d.Observed)
+		}
+		n++
+		if n == 10 {
+			break
+		}
+	}
+	if n != 10 {
+		t.Errorf("Expected to stop after 10 points, got %d", n)
+	}
+}
+
+func TestRunSeq(t *testing.T) {
+	r := new(Regression)
+	if err := r.RunSeq(carsSeq()); err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "speed", r.Coeff(1), 3.9324, 1e-4)
+	if len(r.data) != 0 {
+		t.Error("Expected the data points not to be retained")
+	}
+}
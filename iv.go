@@ -0,0 +1,152 @@
+package regression
+
+import (
+	"errors"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// ErrUnderidentified signals that there are fewer instruments than endogenous variables.
+var ErrUnderidentified = errors.New("fewer instruments than endogenous variables")
+
+// AddInstrument marks variable endogIdx as endogenous and instrumentVars as its instruments, which makes Run
+// fit the model by two-stage least squares. The instruments are variables of the data points that are
+// excluded from the model: their coefficient is zero and they are reported as aliased. In the first stage
+// every endogenous variable is regressed on the instruments and the other variables, and in the second
+// stage the observed value is regressed on the fitted endogenous variables. Standard errors use the
+// residuals of the endogenous variables themselves, not of the fitted values.
+// Feature crosses are treated as exogenous, so crosses of endogenous variables need instruments of their own.
+func (r *Regression) AddInstrument(endogIdx int, instrumentVars []int) {
+	if r.instruments == nil {
+		r.instruments = make(map[int][]int)
+	}
+	r.instruments[endogIdx] = append(r.instruments[endogIdx], instrumentVars...)
+}
+
+// ivSolver wraps a solver for the second stage of two-stage least squares. Columns are columns of the
+// design matrix, so variable i is column i+1.
+type ivSolver struct {
+	second      Solver
+	endogenous  []int
+	instruments []int
+}
+
+func (r *Regression) ivSolver(s Solver) Solver {
+	if len(r.instruments) == 0 {
+		return s
+	}
+	iv := ivSolver{second: s}
+	seen := make(map[int]bool)
+	for endog, instruments := range r.instruments {
+		iv.endogenous = append(iv.endogenous, endog+1)
+		for _, i := range instruments {
+			if !seen[i] {
+				seen[i] = true
+				iv.instruments = append(iv.instruments, i+1)
+			}
+		}
+	}
+	sort.Ints(iv.endogenous)
+	sort.Ints(iv.instruments)
+	return iv
+}
+
+// Solve satisfies the Solver interface.
+func (s ivSolver) Solve(x, y *mat.Dense) ([]float64, *Diagnostics, error) {
+	rows, cols := x.Dims()
+	if len(s.instruments) < len(s.endogenous) {
+		return nil, nil, ErrUnderidentified
+	}
+	endogenous := make(map[int]bool, len(s.endogenous))
+	instrument := make(map[int]bool, len(s.instruments))
+	for _, j := range s.endogenous {
+		endogenous[j] = true
+	}
+	for _, j := range s.instruments {
+		if j >= cols || endogenous[j] {
+			return nil, nil, ErrDimensions
+		}
+		instrument[j] = true
+	}
+
+	// first stage: the exogenous columns and the instruments
+	var zCols, xCols []int
+	for j := 0; j < cols; j++ {
+		if !endogenous[j] {
+			zCols = append(zCols, j)
+		}
+		if !instrument[j] {
+			xCols = append(xCols, j)
+		}
+	}
+	z := columns(x, zCols)
+	fitted := make(map[int][]float64, len(s.endogenous))
+	for _, e := range s.endogenous {
+		if e >= cols {
+			return nil, nil, ErrDimensions
+		}
+		gamma, _, err := QRSolver{}.Solve(z, columns(x, []int{e}))
+		if err != nil {
+			return nil, nil, err
+		}
+		fit := make([]float64, rows)
+		for i := range fit {
+			for k, j := range zCols {
+				fit[i] += gamma[k] * x.At(i, j)
+			}
+		}
+		fitted[e] = fit
+	}
+
+	// second stage: the endogenous columns replaced by their fitted values, without the instruments
+	xhat := columns(x, xCols)
+	for k, j := range xCols {
+		if fit, ok := fitted[j]; ok {
+			for i := 0; i < rows; i++ {
+				xhat.Set(i, k, fit[i])
+			}
+		}
+	}
+	c, diag, err := s.second.Solve(xhat, y)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(c) != len(xCols) {
+		return nil, nil, ErrSolverCoeffs
+	}
+
+	coeffs := make([]float64, cols)
+	for k, j := range xCols {
+		coeffs[j] = c[k]
+	}
+	aliased := append([]int(nil), s.instruments...)
+	var unscaled *mat.Dense
+	if diag != nil {
+		for _, k := range diag.Aliased {
+			aliased = append(aliased, xCols[k])
+		}
+		if diag.Unscaled != nil {
+			unscaled = mat.NewDense(cols, cols, nil)
+			for k, j := range xCols {
+				for l, m := range xCols {
+					unscaled.Set(j, m, diag.Unscaled.At(k, l))
+				}
+			}
+		}
+	}
+	sort.Ints(aliased)
+	return coeffs, &Diagnostics{Aliased: aliased, Unscaled: unscaled}, nil
+}
+
+// columns copies the given columns of x into a new matrix.
+func columns(x *mat.Dense, cols []int) *mat.Dense {
+	rows, _ := x.Dims()
+	m := mat.NewDense(rows, len(cols), nil)
+	for i := 0; i < rows; i++ {
+		for k, j := range cols {
+			m.Set(i, k, x.At(i, j))
+		}
+	}
+	return m
+}
@@ -0,0 +1,88 @@
+package regression
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestAddInstrument(t *testing.T) {
+	// x is correlated with the error u, z only affects y through x
+	rnd := rand.New(rand.NewSource(1))
+	n := 500
+	xs, ys, zs := make([]float64, n), make([]float64, n), make([]float64, n)
+	r := new(Regression)
+	r.SetVar(0, "x")
+	r.SetVar(1, "z")
+	for i := 0; i < n; i++ {
+		u := rnd.NormFloat64()
+		zs[i] = rnd.NormFloat64()
+		xs[i] = zs[i] + u + rnd.NormFloat64()
+		ys[i] = 1 + 2*xs[i] + 2*u
+		r.Train(DataPoint(ys[i], []float64{xs[i], zs[i]}))
+	}
+	r.AddInstrument(0, []int{1})
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the just-identified IV estimate is cov(z, y) / cov(z, x)
+	var mx, my, mz float64
+	for i := 0; i < n; i++ {
+		mx, my, mz = mx+xs[i]/float64(n), my+ys[i]/float64(n), mz+zs[i]/float64(n)
+	}
+	var szy, szx, szz float64
+	for i := 0; i < n; i++ {
+		szy += (zs[i] - mz) * (ys[i] - my)
+		szx += (zs[i] - mz) * (xs[i] - mx)
+		szz += (zs[i] - mz) * (zs[i] - mz)
+	}
+	beta := szy / szx
+	alpha := my - beta*mx
+	assertClose(t, "slope", r.Coeff(1), beta, 1e-9)
+	assertClose(t, "offset", r.Coeff(0), alpha, 1e-9)
+	if r.Coeff(2) != 0 {
+		t.Errorf("Expected the instrument to be excluded, got %v", r.Coeff(2))
+	}
+	if math.Abs(r.Coeff(1)-2) > 0.2 {
+		t.Errorf("Expected a consistent estimate near 2, got %v", r.Coeff(1))
+	}
+
+	// the residuals use x, not its first stage fit
+	var sse float64
+	for i := 0; i < n; i++ {
+		e := ys[i] - alpha - beta*xs[i]
+		sse += e * e
+	}
+	sigma2 := sse / float64(n-2)
+	assertClose(t, "slope stderr", r.StdErr(1), math.Sqrt(sigma2*szz/(szx*szx)), 1e-9)
+	if model, residual := r.DegreesOfFreedom(); model != 2 || residual != n-2 {
+		t.Errorf("Unexpected degrees of freedom %d and %d", model, residual)
+	}
+
+	ols := new(Regression)
+	for i := 0; i < n; i++ {
+		ols.Train(DataPoint(ys[i], []float64{xs[i]}))
+	}
+	ols.Run()
+	if math.Abs(ols.Coeff(1)-2) < math.Abs(r.Coeff(1)-2) {
+		t.Errorf("Expected OLS (%v) to be more biased than 2SLS (%v)", ols.Coeff(1), r.Coeff(1))
+	}
+}
+
+func TestAddInstrumentErrors(t *testing.T) {
+	r := new(Regression)
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i], float64(i)}))
+	}
+	r.AddInstrument(0, nil)
+	r.AddInstrument(1, []int{0})
+	if err := r.Run(); err != ErrUnderidentified {
+		t.Errorf("Expected ErrUnderidentified, got %v", err)
+	}
+	s := new(Regression)
+	s.AddInstrument(0, []int{1})
+	if err := s.RunStream(func() (*dataPoint, bool) { return nil, false }); err != ErrUnsupported {
+		t.Errorf("Expected ErrUnsupported, got %v", err)
+	}
+}
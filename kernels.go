@@ -0,0 +1,186 @@
+package regression
+
+import (
+	"math"
+	"runtime"
+	"sync"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// qrBlockRows is the number of rows factorized at a time by tallQR, so a block of a design matrix
+// with a few dozen columns stays in cache. It isn't a power of two, so the columns of a block don't
+// map to the same cache sets.
+const qrBlockRows = 2000
+
+// mulBlock is the tile size of mulBlocked.
+const mulBlock = 64
+
+// tallQR computes the R factor of the QR decomposition of the columns cols of x and Q^T y, without
+// forming Q. It suits the tall-skinny design matrices of regressions: the rows are factorized in blocks
+// that fit in cache, in parallel, and the stacked R factors of the blocks are factorized again (TSQR).
+// Only the first len(cols) entries of Q^T y are returned. The signs of the rows of R may differ from
+// those of mat.QR.
+func tallQR(x *mat.Dense, cols []int, y []float64) (*mat.Dense, []float64) {
+	rows, _ := x.Dims()
+	n := len(cols)
+	block := qrBlockRows
+	if block < 4*n {
+		block = 4 * n
+	}
+	if rows <= block {
+		a := make([]float64, rows*(n+1))
+		for i := 0; i < rows; i++ {
+			columnMajorRow(a[i:], rows, x.RawRowView(i), cols)
+		}
+		copy(a[n*rows:], y)
+		return householder(a, rows, n)
+	}
+
+	// factorize the blocks of rows in parallel and stack their R factors and projections
+	blocks := (rows + block - 1) / block
+	stacked := mat.NewDense(blocks*n, n, nil)
+	stackedY := make([]float64, blocks*n)
+	next := make(chan int, blocks)
+	for b := 0; b < blocks; b++ {
+		next <- b
+	}
+	close(next)
+	var wg sync.WaitGroup
+	for w := minInt(runtime.GOMAXPROCS(0), blocks); w > 0; w-- {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a := make([]float64, block*(n+1))
+			for b := range next {
+				lo, hi := b*block, minInt((b+1)*block, rows)
+				m := hi - lo
+				for i := 0; i < m; i++ {
+					columnMajorRow(a[i:], m, x.RawRowView(lo+i), cols)
+				}
+				copy(a[n*m:], y[lo:hi])
+				r, qty := householder(a[:m*(n+1)], m, n)
+				for i := 0; i < n; i++ {
+					copy(stacked.RawRowView(b*n+i), r.RawRowView(i))
+					stackedY[b*n+i] = qty[i]
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	all := make([]int, n)
+	for k := range all {
+		all[k] = k
+	}
+	return tallQR(stacked, all, stackedY)
+}
+
+// columnMajorRow copies the columns cols of row to every stride-th entry of dst, a row of a
+// column-major matrix.
+func columnMajorRow(dst []float64, stride int, row []float64, cols []int) {
+	for k, j := range cols {
+		dst[k*stride] = row[j]
+	}
+}
+
+// householder factorizes the column-major rows x n matrix a in place with Householder reflections, which
+// it also applies to y, the column following the n columns of a. It returns the n x n upper triangular R,
+// with zero rows beyond the rows of a, and the first n entries of Q^T y. The columns are scaled to unit
+// norm for the factorization and R is scaled back, so the precision of the fit doesn't suffer from
+// variables of very different magnitudes such as high powers.
+func householder(a []float64, rows, n int) (*mat.Dense, []float64) {
+	norms := make([]float64, n)
+	for j := range norms {
+		col := a[j*rows : (j+1)*rows]
+		norms[j] = euclidean(col)
+		if norms[j] == 0 || math.IsInf(norms[j], 0) {
+			norms[j] = 1
+			continue
+		}
+		floats.Scale(1/norms[j], col)
+	}
+
+	r := mat.NewDense(n, n, nil)
+	w := make([]float64, n)
+	steps := minInt(n, rows)
+	for k := 0; k < steps; k++ {
+		v := a[k*rows+k : (k+1)*rows]
+		var alpha float64
+		// a zero column needs no reflection, but the rest of row k of R is filled all the same
+		if norm := euclidean(v); norm != 0 {
+			alpha = -norm
+			if v[0] < 0 {
+				alpha = norm
+			}
+			v[0] -= alpha
+			if vv := 2 * norm * (norm + math.Abs(v[0]+alpha)); vv != 0 {
+				// reflect the following columns and y at once: c -= 2 v (v^T c) / v^T v
+				rest := blas64.General{Rows: n - k, Cols: rows - k, Stride: rows, Data: a[(k+1)*rows+k:]}
+				vt := blas64.Vector{N: rows - k, Inc: 1, Data: v}
+				vtc := blas64.Vector{N: n - k, Inc: 1, Data: w[:n-k]}
+				blas64.Gemv(blas.NoTrans, 1, rest, vt, 0, vtc)
+				blas64.Ger(-2/vv, vtc, vt, rest)
+			}
+		}
+		r.Set(k, k, alpha*norms[k])
+		for j := k + 1; j < n; j++ {
+			r.Set(k, j, a[j*rows+k]*norms[j])
+		}
+	}
+	qty := make([]float64, n)
+	copy(qty[:steps], a[n*rows:])
+	return r, qty
+}
+
+// euclidean returns the Euclidean norm of v, falling back to math.Hypot when the sum of squares
+// overflows or underflows.
+func euclidean(v []float64) float64 {
+	sum := floats.Dot(v, v)
+	if sum > 1e-290 && !math.IsInf(sum, 0) {
+		return math.Sqrt(sum)
+	}
+	norm := 0.0
+	for _, e := range v {
+		norm = math.Hypot(norm, e)
+	}
+	return norm
+}
+
+// mulBlocked computes the m x n product of the row-major m x k matrix a and k x n matrix b in tiles,
+// so the tiles of b are reused from cache.
+func mulBlocked(a, b []float64, m, k, n int) []float64 {
+	c := make([]float64, m*n)
+	for i0 := 0; i0 < m; i0 += mulBlock {
+		i1 := minInt(i0+mulBlock, m)
+		for l0 := 0; l0 < k; l0 += mulBlock {
+			l1 := minInt(l0+mulBlock, k)
+			for j0 := 0; j0 < n; j0 += mulBlock {
+				j1 := minInt(j0+mulBlock, n)
+				for i := i0; i < i1; i++ {
+					ci := c[i*n+j0 : i*n+j1]
+					for l := l0; l < l1; l++ {
+						ail := a[i*k+l]
+						if ail == 0 {
+							continue
+						}
+						bl := b[l*n+j0 : l*n+j1]
+						for j, e := range bl {
+							ci[j] += ail * e
+						}
+					}
+				}
+			}
+		}
+	}
+	return c
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,202 @@
+package regression
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func randomDesign(rows, cols int, seed int64) (*mat.Dense, []float64) {
+	rng := rand.New(rand.NewSource(seed))
+	x := mat.NewDense(rows, cols, nil)
+	y := make([]float64, rows)
+	for i := 0; i < rows; i++ {
+		x.Set(i, 0, 1)
+		y[i] = 1
+		for j := 1; j < cols; j++ {
+			v := rng.NormFloat64()
+			x.Set(i, j, v)
+			y[i] += float64(j) * v
+		}
+		y[i] += 0.1 * rng.NormFloat64()
+	}
+	return x, y
+}
+
+func TestTallQR(t *testing.T) {
+	for _, rows := range []int{7, qrBlockRows, 3*qrBlockRows + 5} {
+		x, y := randomDesign(rows, 6, int64(rows))
+		cols := []int{0, 1, 2, 3, 4, 5}
+		r, qty := tallQR(x, cols, y)
+
+		// R'R = X'X
+		rtr, xtx := new(mat.Dense), new(mat.Dense)
+		rtr.Mul(r.T(), r)
+		xtx.Mul(x.T(), x)
+		for i := 0; i < 6; i++ {
+			for j := 0; j < 6; j++ {
+				if math.Abs(rtr.At(i, j)-xtx.At(i, j)) > 1e-8*math.Max(1, math.Abs(xtx.At(i, j))) {
+					t.Errorf("%d rows: R'R differs from X'X at %d,%d: %v != %v", rows, i, j, rtr.At(i, j), xtx.At(i, j))
+				}
+			}
+		}
+		for i := 1; i < 6; i++ {
+			if r.At(i, i-1) != 0 {
+				t.Errorf("%d rows: expected R to be upper triangular", rows)
+			}
+		}
+
+		// the solution matches the normal equations
+		c := backSubstitute(r, qty)
+		xty := new(mat.Dense)
+		xty.Mul(x.T(), mat.NewDense(rows, 1, y))
+		want := new(mat.Dense)
+		if err := want.Solve(xtx, xty); err != nil {
+			t.Fatal(err)
+		}
+		for j := range c {
+			assertClose(t, "coefficient", c[j], want.At(j, 0), 1e-8)
+		}
+	}
+
+	// a subset of the columns
+	x, y := randomDesign(50, 4, 1)
+	r, _ := tallQR(x, []int{0, 2}, y)
+	if rows, cols := r.Dims(); rows != 2 || cols != 2 {
+		t.Errorf("Expected a 2x2 R, got %dx%d", rows, cols)
+	}
+}
+
+func TestTallQRZeroInBlock(t *testing.T) {
+	// a sorted dummy is zero throughout the first blocks
+	rows := 6000
+	rng := rand.New(rand.NewSource(5))
+	x := mat.NewDense(rows, 3, nil)
+	y := make([]float64, rows)
+	for i := 0; i < rows; i++ {
+		v := rng.NormFloat64()
+		dummy := float64(boolIndex(i >= rows/2))
+		x.Set(i, 0, 1)
+		x.Set(i, 1, dummy)
+		x.Set(i, 2, v)
+		y[i] = 1.3 + 0.5*dummy + 2*v + 0.1*rng.NormFloat64()
+	}
+	r, qty := tallQR(x, []int{0, 1, 2}, y)
+	c := backSubstitute(r, qty)
+
+	xtx, xty, want := new(mat.Dense), new(mat.Dense), new(mat.Dense)
+	xtx.Mul(x.T(), x)
+	xty.Mul(x.T(), mat.NewDense(rows, 1, y))
+	if err := want.Solve(xtx, xty); err != nil {
+		t.Fatal(err)
+	}
+	for j := range c {
+		assertClose(t, "coefficient", c[j], want.At(j, 0), 1e-9)
+	}
+}
+
+func TestMulBlocked(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	m, k, n := 70, 130, 65
+	a, b := make([]float64, m*k), make([]float64, k*n)
+	for i := range a {
+		a[i] = rng.NormFloat64()
+	}
+	for i := range b {
+		b[i] = rng.NormFloat64()
+	}
+	want := new(mat.Dense)
+	want.Mul(mat.NewDense(m, k, a), mat.NewDense(k, n, b))
+	got := mulBlocked(a, b, m, k, n)
+	for i := 0; i < m; i++ {
+		for j := 0; j < n; j++ {
+			assertClose(t, "product", got[i*n+j], want.At(i, j), 1e-12)
+		}
+	}
+}
+
+func BenchmarkTallQR(b *testing.B) {
+	x, y := randomDesign(100000, 20, 1)
+	cols := make([]int, 20)
+	for j := range cols {
+		cols[j] = j
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tallQR(x, cols, y)
+	}
+}
+
+// BenchmarkMatQR is the factorization QRSolver used before tallQR, which forms the full Q.
+func BenchmarkMatQR(b *testing.B) {
+	x, y := randomDesign(2000, 20, 1)
+	ym := mat.NewDense(2000, 1, y)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		qr := new(mat.QR)
+		qr.Factorize(x)
+		q, qty := new(mat.Dense), new(mat.Dense)
+		qr.QTo(q)
+		qty.Mul(q.T(), ym)
+	}
+}
+
+func BenchmarkTallQRSmall(b *testing.B) {
+	x, y := randomDesign(2000, 20, 1)
+	cols := make([]int, 20)
+	for j := range cols {
+		cols[j] = j
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tallQR(x, cols, y)
+	}
+}
+
+func BenchmarkMulBlocked(b *testing.B) {
+	a := make([]float64, 256*256)
+	for i := range a {
+		a[i] = float64(i % 7)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mulBlocked(a, a, 256, 256, 256)
+	}
+}
+
+func BenchmarkMatMul(b *testing.B) {
+	a := make([]float64, 256*256)
+	for i := range a {
+		a[i] = float64(i % 7)
+	}
+	m := mat.NewDense(256, 256, a)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		new(mat.Dense).Mul(m, m)
+	}
+}
+
+func BenchmarkRun500k(b *testing.B) {
+	x, y := randomDesign(500000, 20, 1)
+	points := make([]*dataPoint, 500000)
+	for i := range points {
+		points[i] = DataPoint(y[i], append([]float64(nil), x.RawRowView(i)[1:]...))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// only Run is timed
+		b.StopTimer()
+		r := new(Regression)
+		for _, p := range points {
+			q := *p
+			q.Variables = p.Variables[:19:19]
+			r.Train(&q)
+		}
+		b.StartTimer()
+		if err := r.Run(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
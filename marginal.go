@@ -0,0 +1,114 @@
+package regression
+
+import "math"
+
+// MarginalEffect is the change in the prediction per unit change of a variable, with its standard error.
+type MarginalEffect struct {
+	Name   string
+	Effect float64
+	StdErr float64
+}
+
+// MarginalEffectsAtMeans returns the marginal effect of every variable, evaluated at the (weighted) means
+// of the training data. The effects are derivatives of the prediction computed by numerical differentiation,
+// so they account for feature crosses; standard errors follow from the coefficient covariance by the delta method.
+func (r *Regression) MarginalEffectsAtMeans() ([]MarginalEffect, error) {
+	if err := r.checkMarginal(); err != nil {
+		return nil, err
+	}
+	means := make([]float64, r.names.base)
+	var weights float64
+	for _, d := range r.data {
+		weights += d.Weight
+		for j := range means {
+			means[j] += d.Weight * d.Variables[j]
+		}
+	}
+	for j := range means {
+		means[j] /= weights
+	}
+
+	effects := make([]MarginalEffect, r.names.base)
+	for k := range effects {
+		effects[k] = r.marginalEffect(k, r.rowDerivative(means, k))
+	}
+	return effects, nil
+}
+
+// AverageMarginalEffects returns the marginal effect of every variable averaged over the training data,
+// weighted by the data points' weights. See MarginalEffectsAtMeans.
+func (r *Regression) AverageMarginalEffects() ([]MarginalEffect, error) {
+	if err := r.checkMarginal(); err != nil {
+		return nil, err
+	}
+	var weights float64
+	for _, d := range r.data {
+		weights += d.Weight
+	}
+
+	effects := make([]MarginalEffect, r.names.base)
+	vars := make([]float64, r.names.base)
+	for k := range effects {
+		// the prediction is linear in the coefficients, so the average effect is the
+		// coefficients applied to the average derivative of the design row
+		avg := make([]float64, len(r.coeff))
+		for _, d := range r.data {
+			copy(vars, d.Variables[:r.names.base])
+			for j, v := range r.rowDerivative(vars, k) {
+				avg[j] += d.Weight * v
+			}
+		}
+		for j := range avg {
+			avg[j] /= weights
+		}
+		effects[k] = r.marginalEffect(k, avg)
+	}
+	return effects, nil
+}
+
+func (r *Regression) checkMarginal() error {
+	if !r.hasRun || len(r.coeff) == 0 {
+		return ErrNotRun
+	}
+	if r.split {
+		return ErrUnsupported
+	}
+	if len(r.data) == 0 {
+		return ErrNotEnoughData
+	}
+	return nil
+}
+
+// rowDerivative returns the derivative of the design row with respect to variable k at vars,
+// by central differences. vars is restored before returning.
+func (r *Regression) rowDerivative(vars []float64, k int) []float64 {
+	x := vars[k]
+	h := 1e-6 * math.Max(1, math.Abs(x))
+	vars[k] = x + h
+	up := r.designRow(vars)
+	vars[k] = x - h
+	down := r.designRow(vars)
+	vars[k] = x
+
+	d := make([]float64, len(up))
+	for j := range d {
+		d[j] = (up[j] - down[j]) / (2 * h)
+	}
+	return d
+}
+
+// marginalEffect applies the coefficients to the derivative d of the design row, with the
+// delta method standard error sqrt(d' Cov d).
+func (r *Regression) marginalEffect(k int, d []float64) MarginalEffect {
+	e := MarginalEffect{Name: r.GetVar(k), Effect: r.predictRow(d), StdErr: math.NaN()}
+	if r.covariance != nil {
+		var variance float64
+		for i := range d {
+			for j := range d {
+				variance += d[i] * r.covariance.At(i, j) * d[j]
+			}
+		}
+		e.StdErr = math.Sqrt(variance)
+	}
+	return e
+}
@@ -0,0 +1,63 @@
+package regression
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMarginalEffects(t *testing.T) {
+	r := new(Regression)
+	r.SetObserved("dist")
+	r.SetVar(0, "speed")
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	r.AddCross(PowCross(0, 2))
+	r.AddCross(PowCross(0, 3))
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	var mean, mean2 float64
+	for _, x := range carsSpeed {
+		mean += x
+		mean2 += x * x
+	}
+	n := float64(len(carsSpeed))
+	mean, mean2 = mean/n, mean2/n
+
+	// d/dx (b1 x + b2 x^2 + b3 x^3) = b1 + 2 b2 x + 3 b3 x^2, linear in the coefficients with gradient g
+	check := func(name string, e MarginalEffect, g []float64) {
+		var want, variance float64
+		for i := range g {
+			want += g[i] * r.Coeff(i+1)
+			for j := range g {
+				variance += g[i] * r.covariance.At(i+1, j+1) * g[j]
+			}
+		}
+		assertClose(t, name+" effect", e.Effect, want, 1e-5)
+		assertClose(t, name+" stderr", e.StdErr, math.Sqrt(variance), 1e-5)
+		if e.Name != "speed" {
+			t.Errorf("Expected the effect of speed, got %q", e.Name)
+		}
+	}
+
+	atMeans, err := r.MarginalEffectsAtMeans()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(atMeans) != 1 {
+		t.Fatalf("Expected one effect per variable, got %+v", atMeans)
+	}
+	check("at means", atMeans[0], []float64{1, 2 * mean, 3 * mean * mean})
+
+	average, err := r.AverageMarginalEffects()
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("average", average[0], []float64{1, 2 * mean, 3 * mean2})
+
+	if _, err := new(Regression).AverageMarginalEffects(); err != ErrNotRun {
+		t.Errorf("Expected ErrNotRun, got %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package regression
+
+import (
+	"math"
+	"sort"
+)
+
+// SetMissingIndicator treats NaN values of variable i as missing. Run imputes them with the weighted mean
+// of the values present and adds a companion indicator column, 1 where the value was missing and 0
+// otherwise, so missingness itself can carry signal. The indicator columns follow the feature crosses,
+// in the order of their variables, and are named "(name)missing". Predict imputes and indicates missing
+// values the same way. Feature crosses of the variable see the imputed value.
+// It returns ErrRegressionRun once the model has been run; call Reset first to refit.
+func (r *Regression) SetMissingIndicator(i int) error {
+	if r.hasRun {
+		return ErrRegressionRun
+	}
+	if i < 0 {
+		return ErrDimensions
+	}
+	if r.missing == nil {
+		r.missing = make(map[int]float64)
+	}
+	r.missing[i] = math.NaN()
+	return nil
+}
+
+// missingVars returns the variables with a missing indicator, in order.
+func (r *Regression) missingVars() []int {
+	vars := make([]int, 0, len(r.missing))
+	for i := range r.missing {
+		vars = append(vars, i)
+	}
+	sort.Ints(vars)
+	return vars
+}
+
+// fillMissing computes the imputed values from the training data. The values are imputed by crossPoints,
+// which gives data points with missing values a copy of their variables, so the caller's slices keep the NaNs.
+func (r *Regression) fillMissing() {
+	for _, v := range r.missingVars() {
+		var sum, total float64
+		for _, d := range r.data {
+			if x := d.Variables[v]; !math.IsNaN(x) {
+				sum += d.Weight * x
+				total += d.Weight
+			}
+		}
+		r.missing[v] = 0
+		if total > 0 {
+			r.missing[v] = sum / total
+		}
+	}
+}
+
+// impute returns vars with the missing values of the given variables replaced by their imputed values,
+// and the indicators of the variables. vars is copied if any value is missing.
+func (r *Regression) impute(vars []float64, missing []int) ([]float64, []float64) {
+	indicators := make([]float64, len(missing))
+	copied := false
+	for j, v := range missing {
+		if v >= len(vars) || !math.IsNaN(vars[v]) {
+			continue
+		}
+		if !copied {
+			vars = append([]float64(nil), vars...)
+			copied = true
+		}
+		vars[v] = r.missing[v]
+		indicators[j] = 1
+	}
+	return vars, indicators
+}
+
+// baseVariables returns the variables of a data point as trained: without the feature crosses and
+// with the missing values that were imputed. The data point is not modified.
+func (r *Regression) baseVariables(d *dataPoint) []float64 {
+	if d.crossed == 0 {
+		return d.Variables
+	}
+	if len(r.missing) == 0 {
+		return d.Variables[:len(d.Variables)-d.crossed]
+	}
+	restored := *d
+	restored.Variables = append([]float64(nil), d.Variables...)
+	r.restoreMissing(&restored)
+	return restored.Variables[:len(d.Variables)-d.crossed]
+}
+
+// restoreMissing sets the imputed values of a crossed data point back to NaN, using the indicator
+// columns at the end of its variables.
+func (r *Regression) restoreMissing(d *dataPoint) {
+	vars := r.missingVars()
+	first := len(d.Variables) - len(vars)
+	for j, v := range vars {
+		if d.Variables[first+j] == 1 {
+			d.Variables[v] = math.NaN()
+		}
+	}
+}
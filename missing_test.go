@@ -0,0 +1,151 @@
+package regression
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/sajari/regression/infer"
+)
+
+// missingPoints returns points whose second variable is missing for every third point, where the
+// observed value is shifted by 5.
+func missingPoints() []*dataPoint {
+	var points []*dataPoint
+	for i := 0; i < 30; i++ {
+		a, b := float64(i), float64(i%5)
+		obs := 1 + 2*a + 3*b
+		if i%3 == 0 {
+			b = math.NaN()
+			obs = 1 + 2*a + 3*2 + 5
+		}
+		points = append(points, DataPoint(obs, []float64{a, b}))
+	}
+	return points
+}
+
+func missingRegression(t *testing.T) *Regression {
+	r := new(Regression)
+	r.SetObserved("y")
+	r.SetVar(0, "a")
+	r.SetVar(1, "b")
+	r.AddCross(PowCross(0, 2))
+	if err := r.SetMissingIndicator(1); err != nil {
+		t.Fatal(err)
+	}
+	r.Train(missingPoints()...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestMissingIndicator(t *testing.T) {
+	points := missingPoints()
+	r := missingRegression(t)
+	names := r.coeffNames()
+	if len(names) != 5 || names[4] != "(b)missing" {
+		t.Fatalf("Expected the indicator after the cross, got %v", names)
+	}
+	fill := r.missing[1]
+	var present []float64
+	for _, d := range points {
+		if !math.IsNaN(d.Variables[1]) {
+			present = append(present, d.Variables[1])
+		}
+	}
+	var mean float64
+	for _, v := range present {
+		mean += v / float64(len(present))
+	}
+	assertClose(t, "fill", fill, mean, 1e-12)
+
+	// the indicator absorbs the shift of the points with a missing value
+	assertClose(t, "a", r.Coeff(1), 2, 1e-6)
+	assertClose(t, "b", r.Coeff(2), 3, 1e-6)
+	assertClose(t, "missing", r.Coeff(4), 3*(2-fill)+5, 1e-6)
+
+	p, err := r.Predict([]float64{4, math.NaN()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "prediction", p, 1+2*4+3*2+5, 1e-6)
+	p, _ = r.Predict([]float64{4, 1})
+	assertClose(t, "prediction", p, 1+2*4+3, 1e-6)
+
+	if err := r.SetMissingIndicator(0); err != ErrRegressionRun {
+		t.Errorf("Expected ErrRegressionRun, got %v", err)
+	}
+}
+
+func TestMissingIndicatorRerun(t *testing.T) {
+	points := missingPoints()
+	original := points[0].Variables
+	r := new(Regression)
+	r.SetMissingIndicator(1)
+	r.Train(points...)
+	if changed, err := r.RunIfChanged(); !changed || err != nil {
+		t.Fatal(err)
+	}
+	want := r.coeffs()
+	if changed, err := r.RunIfChanged(); changed || err != nil {
+		t.Errorf("Expected the imputed data unchanged, got %v, %v", changed, err)
+	}
+	r.Reset()
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	for i, c := range r.coeffs() {
+		assertClose(t, "coefficient", c, want[i], 1e-9)
+	}
+	if !math.IsNaN(original[1]) || len(points[0].Variables) != 3 || points[0].Variables[2] != 1 {
+		t.Errorf("Expected the caller's missing value kept, got %v and %v", original, points[0].Variables)
+	}
+}
+
+func TestMissingIndicatorPersist(t *testing.T) {
+	r := missingRegression(t)
+	var buf bytes.Buffer
+	if err := r.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := infer.Load(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Names[4] != "(b)missing" {
+		t.Errorf("Expected the indicator named, got %v", m.Names)
+	}
+	for _, vars := range [][]float64{{3, math.NaN()}, {7, 2}} {
+		want, _ := r.Predict(vars)
+		got, err := loaded.Predict(vars)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertClose(t, "loaded", got, want, 1e-12)
+		if got, err = m.Predict(vars); err != nil {
+			t.Fatal(err)
+		}
+		assertClose(t, "infer", got, want, 1e-12)
+	}
+	if _, err := r.Predictor(); err != ErrUnsupported {
+		t.Errorf("Expected ErrUnsupported, got %v", err)
+	}
+	if _, err := NewCompact[float64](r); err != ErrNotCompactable {
+		t.Errorf("Expected ErrNotCompactable, got %v", err)
+	}
+	if _, err := r.ExportSklearn(); err != ErrUnsupported {
+		t.Errorf("Expected ErrUnsupported from ExportSklearn, got %v", err)
+	}
+
+	bad := new(Regression)
+	bad.SetMissingIndicator(5)
+	bad.Train(missingPoints()...)
+	if err := bad.Run(); err != ErrDimensions {
+		t.Errorf("Expected ErrDimensions, got %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package regression
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"unsafe"
+)
+
+// ErrInvalidLayout signals that a binary file doesn't match the layout it is read with.
+var ErrInvalidLayout = errors.New("file does not match layout")
+
+// Layout describes a binary file of float64 rows.
+type Layout struct {
+	// Cols is the number of values in a row, including the observed value.
+	Cols int
+	// ObsIndex is the column holding the observed value.
+	ObsIndex int
+	// ByteOrder of the values, defaults to little endian.
+	ByteOrder binary.ByteOrder
+}
+
+var nativeLittleEndian = func() bool {
+	x := uint16(1)
+	return *(*byte)(unsafe.Pointer(&x)) == 1
+}()
+
+// TrainFromMmap trains the regression with the rows of a binary file of float64 values, which is
+// memory mapped where the platform supports it. When the file uses the native byte order and the
+// observed value is the first or last column, the variables of every data point reference the
+// mapping directly rather than being copied onto the heap. The mapping is private, so modifying
+// the data points never changes the file.
+// The returned Closer releases the mapping. It first copies the variables of the training data that
+// still reference the mapping onto the heap, so the model can be used after it is closed; data
+// points or variables taken from the model before must not be used after it.
+func (r *Regression) TrainFromMmap(path string, layout Layout) (io.Closer, error) {
+	if layout.Cols < 2 || layout.ObsIndex < 0 || layout.ObsIndex >= layout.Cols {
+		return nil, ErrInvalidLayout
+	}
+	order := layout.ByteOrder
+	if order == nil {
+		order = binary.LittleEndian
+	}
+
+	m, err := mmapFile(path)
+	if err != nil {
+		return nil, err
+	}
+	b := m.data
+	if len(b)%(8*layout.Cols) != 0 {
+		m.Close()
+		return nil, ErrInvalidLayout
+	}
+	rows := len(b) / (8 * layout.Cols)
+	if rows == 0 {
+		return m, nil
+	}
+	closer := &mmapCloser{r: r, m: m}
+
+	native := (order == binary.LittleEndian) == nativeLittleEndian
+	var values []float64
+	if native {
+		values = float64s(b)
+	} else {
+		values = make([]float64, len(b)/8)
+		for i := range values {
+			values[i] = math.Float64frombits(order.Uint64(b[8*i:]))
+		}
+	}
+
+	last := layout.Cols - 1
+	points := make([]*dataPoint, rows)
+	for i := range points {
+		row := values[i*layout.Cols : (i+1)*layout.Cols : (i+1)*layout.Cols]
+		switch layout.ObsIndex {
+		case 0:
+			points[i] = DataPoint(row[0], row[1:])
+		case last:
+			points[i] = DataPoint(row[last], row[:last:last])
+		default:
+			vars := make([]float64, 0, last)
+			vars = append(vars, row[:layout.ObsIndex]...)
+			vars = append(vars, row[layout.ObsIndex+1:]...)
+			points[i] = DataPoint(row[layout.ObsIndex], vars)
+		}
+	}
+	r.Train(points...)
+	return closer, nil
+}
+
+// mmapCloser releases a mapping once the training data no longer references it.
+type mmapCloser struct {
+	r *Regression
+	m *mapping
+}
+
+// Close copies the variables referencing the mapping onto the heap and releases it.
+func (c *mmapCloser) Close() error {
+	if b := c.m.data; len(b) > 0 {
+		start := uintptr(unsafe.Pointer(&b[0]))
+		end := start + uintptr(len(b))
+		for _, points := range [][]*dataPoint{c.r.data, c.r.unclipped} {
+			for _, p := range points {
+				if len(p.Variables) == 0 {
+					continue
+				}
+				if addr := uintptr(unsafe.Pointer(&p.Variables[0])); addr >= start && addr < end {
+					p.Variables = append([]float64(nil), p.Variables...)
+				}
+			}
+		}
+	}
+	return c.m.Close()
+}
@@ -0,0 +1,25 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package regression
+
+import "io/ioutil"
+
+// mapping holds the file contents on platforms without mmap support.
+type mapping struct {
+	data []byte
+}
+
+func mmapFile(path string) (*mapping, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &mapping{data: b}, nil
+}
+
+// Close releases the file contents.
+func (m *mapping) Close() error {
+	m.data = nil
+	return nil
+}
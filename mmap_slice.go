@@ -0,0 +1,11 @@
+//go:build go1.17
+// +build go1.17
+
+package regression
+
+import "unsafe"
+
+// float64s reinterprets b as a slice of float64 values without copying.
+func float64s(b []byte) []float64 {
+	return unsafe.Slice((*float64)(unsafe.Pointer(&b[0])), len(b)/8)
+}
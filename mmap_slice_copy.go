@@ -0,0 +1,23 @@
+//go:build !go1.17
+// +build !go1.17
+
+package regression
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// float64s decodes b as float64 values in the native byte order. Go versions without unsafe.Slice
+// copy the values rather than reinterpreting the mapping.
+func float64s(b []byte) []float64 {
+	var order binary.ByteOrder = binary.BigEndian
+	if nativeLittleEndian {
+		order = binary.LittleEndian
+	}
+	f := make([]float64, len(b)/8)
+	for i := range f {
+		f[i] = math.Float64frombits(order.Uint64(b[8*i:]))
+	}
+	return f
+}
@@ -0,0 +1,108 @@
+package regression
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"math"
+	"os"
+	"testing"
+)
+
+func writeRows(t *testing.T, order binary.ByteOrder, rows [][]float64) string {
+	f, err := ioutil.TempFile("", "regression")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	for _, row := range rows {
+		if err := binary.Write(f, order, row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return f.Name()
+}
+
+func TestTrainFromMmap(t *testing.T) {
+	var rows [][]float64
+	for i := range carsSpeed {
+		rows = append(rows, []float64{carsSpeed[i], carsDist[i], math.Sqrt(carsSpeed[i])})
+	}
+
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		path := writeRows(t, order, rows)
+		defer os.Remove(path)
+
+		for _, obsIndex := range []int{0, 1, 2} {
+			r := new(Regression)
+			m, err := r.TrainFromMmap(path, Layout{Cols: 3, ObsIndex: obsIndex, ByteOrder: order})
+			if err != nil {
+				t.Fatal(err)
+			}
+			r.AddCross(PowCross(0, 2))
+			if err := r.Run(); err != nil {
+				t.Fatal(err)
+			}
+			if len(r.data) != len(rows) {
+				t.Errorf("Expected %d data points, got %d", len(rows), len(r.data))
+			}
+			copied := make([][]float64, len(rows))
+			for i, row := range rows {
+				copied[i] = append([]float64(nil), row...)
+			}
+			want := new(Regression)
+			want.Train(MakeDataPoints(copied, obsIndex)...)
+			want.AddCross(PowCross(0, 2))
+			want.Run()
+			for i := 0; i < 4; i++ {
+				assertClose(t, "coefficient", r.Coeff(i), want.Coeff(i), 1e-9)
+			}
+			m.Close()
+		}
+
+		// the file is left untouched by crosses and Run
+		b, _ := ioutil.ReadFile(path)
+		if got := math.Float64frombits(order.Uint64(b[8:])); got != carsDist[0] {
+			t.Errorf("Expected the file to be unchanged, got %v", got)
+		}
+	}
+}
+
+func TestTrainFromMmapClose(t *testing.T) {
+	var rows [][]float64
+	for i := range carsSpeed {
+		rows = append(rows, []float64{carsDist[i], carsSpeed[i]})
+	}
+	path := writeRows(t, binary.LittleEndian, rows)
+	defer os.Remove(path)
+
+	r := new(Regression)
+	m, err := r.TrainFromMmap(path, Layout{Cols: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// the variables were copied off the mapping before it was released
+	if got := r.data[0].Variables[0]; got != carsSpeed[0] {
+		t.Errorf("Expected %v, got %v", carsSpeed[0], got)
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	want := carsRegression(t)
+	assertClose(t, "coefficient", r.Coeff(1), want.Coeff(1), 1e-9)
+}
+
+func TestTrainFromMmapInvalidLayout(t *testing.T) {
+	path := writeRows(t, binary.LittleEndian, [][]float64{{1, 2, 3}})
+	defer os.Remove(path)
+
+	r := new(Regression)
+	if _, err := r.TrainFromMmap(path, Layout{Cols: 2}); err != ErrInvalidLayout {
+		t.Errorf("Expected ErrInvalidLayout, got %v", err)
+	}
+	if _, err := r.TrainFromMmap(path, Layout{Cols: 3, ObsIndex: 3}); err != ErrInvalidLayout {
+		t.Errorf("Expected ErrInvalidLayout, got %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package regression
+
+import (
+	"os"
+	"syscall"
+)
+
+type mapping struct {
+	data []byte
+}
+
+func mmapFile(path string) (*mapping, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if fi.Size() == 0 {
+		return &mapping{}, nil
+	}
+	// A private writable mapping is copy-on-write: writes to the data points never reach the file.
+	b, err := syscall.Mmap(int(f.Fd()), 0, int(fi.Size()), syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_PRIVATE)
+	if err != nil {
+		return nil, err
+	}
+	return &mapping{data: b}, nil
+}
+
+// Close unmaps the file.
+func (m *mapping) Close() error {
+	if m.data == nil {
+		return nil
+	}
+	b := m.data
+	m.data = nil
+	return syscall.Munmap(b)
+}
@@ -0,0 +1,183 @@
+package regression
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"runtime/debug"
+	"time"
+)
+
+// modulePath is the path of this module in the build info.
+const modulePath = "github.com/sajari/regression"
+
+// moduleVersion returns the version of this module recorded in the build info of the binary, or
+// "(devel)" when it is unknown, e.g. when built from a working copy.
+func moduleVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(devel)"
+	}
+	version := ""
+	if info.Main.Path == modulePath {
+		version = info.Main.Version
+	}
+	for _, d := range info.Deps {
+		if d.Path == modulePath {
+			version = d.Version
+			if d.Replace != nil {
+				version = d.Replace.Version
+			}
+		}
+	}
+	if version == "" {
+		return "(devel)"
+	}
+	return version
+}
+
+// ModelCard documents a fitted model for governance and review: how and when it was trained,
+// its inputs, its fit and any notes set with SetMetadata.
+type ModelCard struct {
+	// Version is the version of this package that trained the model, taken from the build info.
+	Version      string    `json:"version"`
+	TrainedAt    time.Time `json:"trained_at"`
+	Observations int       `json:"observations"`
+	DataHash     string    `json:"data_hash,omitempty"`
+	Observed     string    `json:"observed"`
+	ObservedUnit string    `json:"observed_unit,omitempty"`
+	// Variables are the names of the variables and feature crosses, in coefficient order.
+	Variables []string `json:"variables"`
+	// Units are the units of the variables, in the same order, if any are set.
+	Units           []string           `json:"units,omitempty"`
+	Coefficients    []float64          `json:"coefficients"`
+	Metrics         map[string]float64 `json:"metrics"`
+	Hyperparameters Hyperparameters    `json:"hyperparameters"`
+	Notes           map[string]string  `json:"notes,omitempty"`
+}
+
+// Hyperparameters are the settings a model was fitted with.
+type Hyperparameters struct {
+	// Solver is "qr", "ridge", "pls" or the type of a custom Solver.
+	Solver        string      `json:"solver"`
+	Lambda        float64     `json:"lambda,omitempty"`
+	Components    int         `json:"components,omitempty"`
+	Normalization string      `json:"normalization"`
+	Crosses       []CrossSpec `json:"crosses,omitempty"`
+	SplitVar      *int        `json:"split_var,omitempty"`
+}
+
+// SetMetadata sets a free-form note, such as a deployment ID, git SHA or dataset snapshot name.
+// Metadata is saved with the model and included in the model card.
+func (r *Regression) SetMetadata(key, value string) {
+	if r.metadata == nil {
+		r.metadata = make(map[string]string)
+	}
+	r.metadata[key] = value
+}
+
+// GetMetadata returns the metadata value for key and whether it is set.
+func (r *Regression) GetMetadata(key string) (string, bool) {
+	v, ok := r.metadata[key]
+	return v, ok
+}
+
+// DeleteMetadata removes the metadata value for key.
+func (r *Regression) DeleteMetadata(key string) {
+	delete(r.metadata, key)
+}
+
+// Metadata returns a copy of all metadata of the model.
+func (r *Regression) Metadata() map[string]string {
+	m := make(map[string]string, len(r.metadata))
+	for k, v := range r.metadata {
+		m[k] = v
+	}
+	return m
+}
+
+// ModelCard generates the model card of a fitted model.
+func (r *Regression) ModelCard() (*ModelCard, error) {
+	if !r.hasRun || len(r.coeff) == 0 {
+		return nil, ErrNotRun
+	}
+	names := r.coeffNames()
+	card := &ModelCard{
+		Version:      moduleVersion(),
+		TrainedAt:    r.trainedAt,
+		Observations: r.observations,
+		DataHash:     r.DataHash(),
+		Observed:     r.names.obs,
+		Variables:    names[1:],
+		Coefficients: make([]float64, len(r.coeff)),
+		Metrics:      make(map[string]float64),
+		Notes:        make(map[string]string, len(r.metadata)),
+	}
+	for i := range card.Coefficients {
+		card.Coefficients[i] = r.coeff[i]
+	}
+	if len(r.names.units) > 0 {
+		card.Units = make([]string, len(card.Variables))
+		for i := range card.Units {
+			card.Units[i] = r.GetVarUnit(i)
+		}
+	}
+	card.ObservedUnit = r.names.obsUnit
+	model, residual := r.DegreesOfFreedom()
+	metrics := map[string]float64{
+		"r2":          r.R2,
+		"rmse":        r.rmse,
+		"sigma":       math.Sqrt(r.sigma2),
+		"f":           r.FStat(),
+		"f_p_value":   r.FPValue(),
+		"df_model":    float64(model),
+		"df_residual": float64(residual),
+	}
+	// undefined statistics are left out, JSON can't represent them
+	for k, v := range metrics {
+		if !math.IsNaN(v) && !math.IsInf(v, 0) {
+			card.Metrics[k] = v
+		}
+	}
+	for k, v := range r.metadata {
+		card.Notes[k] = v
+	}
+
+	h := &card.Hyperparameters
+	switch s := r.solver().(type) {
+	case QRSolver:
+		h.Solver = "qr"
+	case RidgeSolver:
+		h.Solver = "ridge"
+		h.Lambda = s.Lambda
+	case PLSSolver:
+		h.Solver = "pls"
+		h.Components = s.Components
+	default:
+		h.Solver = fmt.Sprintf("%T", s)
+	}
+	h.Normalization = r.normalization.String()
+	for _, cross := range r.crosses {
+		spec, err := specOf(cross)
+		if err != nil {
+			spec = CrossSpec{Type: fmt.Sprintf("%T", cross)}
+		}
+		h.Crosses = append(h.Crosses, spec)
+	}
+	if r.split {
+		v := r.splitVar
+		h.SplitVar = &v
+	}
+	return card, nil
+}
+
+// Save writes the model card to w as indented JSON, typically next to the saved model.
+func (c *ModelCard) Save(w io.Writer) error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
@@ -0,0 +1,117 @@
+package regression
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestModelCard(t *testing.T) {
+	start := time.Now()
+	r := new(Regression)
+	r.SetObserved("dist")
+	r.SetVar(0, "speed")
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	r.AddCross(PowCross(0, 2))
+	r.SetSolver(RidgeSolver{Lambda: 0.5})
+	r.SetNormalization(ZScore)
+	r.SetMetadata("owner", "pricing")
+	if _, err := r.ModelCard(); err != ErrNotRun {
+		t.Errorf("Expected ErrNotRun, got %v", err)
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	card, err := r.ModelCard()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card.Version != moduleVersion() || card.Observations != 50 || card.Observed != "dist" {
+		t.Errorf("Unexpected model card %+v", card)
+	}
+	if card.TrainedAt.Before(start) || card.TrainedAt.After(time.Now()) {
+		t.Errorf("Unexpected training time %v", card.TrainedAt)
+	}
+	if len(card.Variables) != 2 || card.Variables[0] != "speed" || card.Variables[1] != "(speed)^2" {
+		t.Errorf("Unexpected variables %v", card.Variables)
+	}
+	if len(card.Coefficients) != 3 || card.Coefficients[2] != r.Coeff(2) {
+		t.Errorf("Unexpected coefficients %v", card.Coefficients)
+	}
+	assertClose(t, "r2", card.Metrics["r2"], r.R2, 1e-12)
+	assertClose(t, "df_residual", card.Metrics["df_residual"], 47, 0)
+	h := card.Hyperparameters
+	if h.Solver != "ridge" || h.Lambda != 0.5 || h.Normalization != "zscore" || len(h.Crosses) != 1 || h.Crosses[0].Type != "pow" {
+		t.Errorf("Unexpected hyperparameters %+v", h)
+	}
+	if card.Notes["owner"] != "pricing" {
+		t.Errorf("Expected the owner note, got %v", card.Notes)
+	}
+
+	var buf bytes.Buffer
+	if err := card.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var decoded ModelCard
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.TrainedAt.Equal(card.TrainedAt) || decoded.Hyperparameters.Solver != "ridge" {
+		t.Errorf("Unexpected decoded model card %+v", decoded)
+	}
+}
+
+func TestModelCardAfterLoad(t *testing.T) {
+	r := carsRegression(t)
+	var buf bytes.Buffer
+	if err := r.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	card, err := loaded.ModelCard()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !card.TrainedAt.Equal(r.trainedAt) || card.Observations != 50 || card.Hyperparameters.Solver != "qr" {
+		t.Errorf("Unexpected model card %+v", card)
+	}
+	assertClose(t, "F", card.Metrics["f"], 89.57, 1e-2)
+}
+
+func TestMetadata(t *testing.T) {
+	r := carsRegression(t)
+	r.SetMetadata("deployment", "d-42")
+	r.SetMetadata("git_sha", "0a1b2c3")
+	r.SetMetadata("dataset", "cars-2024-01")
+	r.DeleteMetadata("dataset")
+	if v, ok := r.GetMetadata("git_sha"); !ok || v != "0a1b2c3" {
+		t.Errorf("Expected the git SHA, got %q", v)
+	}
+	if _, ok := r.GetMetadata("dataset"); ok {
+		t.Error("Expected the dataset to be deleted")
+	}
+
+	var buf bytes.Buffer
+	if err := r.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := loaded.Metadata()
+	if len(m) != 2 || m["deployment"] != "d-42" || m["git_sha"] != "0a1b2c3" {
+		t.Errorf("Unexpected metadata after Load %v", m)
+	}
+	m["deployment"] = "changed"
+	if v, _ := loaded.GetMetadata("deployment"); v != "d-42" {
+		t.Error("Expected Metadata to return a copy")
+	}
+}
@@ -0,0 +1,111 @@
+package regression
+
+import (
+	"math"
+	"sort"
+)
+
+// Direction is the required direction of the prediction in a variable.
+type Direction int
+
+const (
+	// Increasing requires the prediction not to decrease as the variable increases.
+	Increasing Direction = 1
+	// Decreasing requires the prediction not to increase as the variable increases.
+	Decreasing Direction = -1
+)
+
+// monotonicityGrid is the number of values each variable is swept over by MonotonicityReport.
+const monotonicityGrid = 25
+
+// Monotonicity describes whether the prediction is monotonic in a variable.
+type Monotonicity struct {
+	Name      string
+	Direction Direction
+	Monotonic bool
+	// Violations is the number of training data points from which sweeping the variable over
+	// its observed range moves the prediction against Direction.
+	Violations int
+	// WorstSlope is the slope of the prediction in the variable, times Direction, where it is most
+	// negative, and WorstAt are the variables at which it occurs. WorstAt is nil when Monotonic.
+	// WorstSlope is NaN if the variable takes a single value in the training data.
+	WorstSlope float64
+	WorstAt    []float64
+}
+
+// MonotonicityReport checks that the fitted model is monotonic in the given variables over their observed
+// range. For every training data point, each variable is swept over the range of its training values with
+// the other variables held at their observed values, and the prediction is checked to move in the required
+// direction. Feature crosses and per-segment models are applied as in Predict, so the check catches crosses
+// that break monotonicity even when the coefficients of the variables themselves have the right sign.
+// The results are ordered by variable. The training data is required, so models fitted with RunStream
+// or loaded with Load are not supported.
+func (r *Regression) MonotonicityReport(constraints map[int]Direction) ([]Monotonicity, error) {
+	if !r.hasRun || len(r.coeff) == 0 {
+		return nil, ErrNotRun
+	}
+	if len(r.data) == 0 {
+		return nil, ErrNotEnoughData
+	}
+	vars := make([]int, 0, len(constraints))
+	for k := range constraints {
+		if k < 0 || k >= r.names.base {
+			return nil, ErrDimensions
+		}
+		vars = append(vars, k)
+	}
+	sort.Ints(vars)
+
+	report := make([]Monotonicity, len(vars))
+	x := make([]float64, r.names.base)
+	for i, k := range vars {
+		m := Monotonicity{Name: r.GetVar(k), Direction: constraints[k], Monotonic: true, WorstSlope: math.Inf(1)}
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for _, d := range r.data {
+			lo, hi = math.Min(lo, d.Variables[k]), math.Max(hi, d.Variables[k])
+		}
+		step := (hi - lo) / (monotonicityGrid - 1)
+		if step == 0 {
+			m.WorstSlope = math.NaN()
+			report[i] = m
+			continue
+		}
+
+		for _, d := range r.data {
+			// the training data has the feature crosses appended to the base variables
+			copy(x, d.Variables[:r.names.base])
+			violated := false
+			var prev float64
+			for g := 0; g < monotonicityGrid; g++ {
+				x[k] = lo + float64(g)*step
+				p, err := r.Predict(x)
+				if err != nil {
+					return nil, err
+				}
+				if g > 0 {
+					slope := (p - prev) / step * float64(m.Direction)
+					if slope < m.WorstSlope {
+						m.WorstSlope = slope
+						if slope < 0 {
+							m.WorstAt = append([]float64(nil), x...)
+							m.WorstAt[k] -= step
+						}
+					}
+					if (p-prev)*float64(m.Direction) < -1e-9*math.Max(1, math.Abs(p)) {
+						violated = true
+					}
+				}
+				prev = p
+			}
+			if violated {
+				m.Violations++
+			}
+		}
+		m.Monotonic = m.Violations == 0
+		if m.Monotonic {
+			m.WorstAt = nil
+		}
+		report[i] = m
+	}
+	return report, nil
+}
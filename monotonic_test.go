@@ -0,0 +1,51 @@
+package regression
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMonotonicityReport(t *testing.T) {
+	// the price coefficient is negative, but the quadratic cross turns demand up at high prices
+	r := new(Regression)
+	r.SetObserved("demand")
+	r.SetVar(0, "price")
+	r.SetVar(1, "season")
+	for i := 0; i < 40; i++ {
+		price := float64(i%10) + 1
+		season := float64(i / 10)
+		r.Train(DataPoint(100-20*price+1.5*price*price+season, []float64{price, season}))
+	}
+	r.AddCross(PowCross(0, 2))
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := r.MonotonicityReport(map[int]Direction{1: Increasing, 0: Decreasing})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(report))
+	}
+	price, season := report[0], report[1]
+	if price.Name != "price" || price.Monotonic || price.Violations != 40 {
+		t.Errorf("Expected price to be non-monotonic for every data point, got %+v", price)
+	}
+	// the slope is 2*1.5*price-20, worst at the top of the range
+	assertClose(t, "worst slope", price.WorstSlope, -(1.5*(10+(10-9.0/24)) - 20), 1e-6)
+	if price.WorstAt == nil || math.Abs(price.WorstAt[0]-(10-9.0/24)) > 1e-9 {
+		t.Errorf("Unexpected location of the worst slope %v", price.WorstAt)
+	}
+	if season.Name != "season" || !season.Monotonic || season.WorstAt != nil {
+		t.Errorf("Expected season to be monotonic, got %+v", season)
+	}
+	assertClose(t, "season slope", season.WorstSlope, 1, 1e-6)
+
+	if _, err := r.MonotonicityReport(map[int]Direction{2: Increasing}); err != ErrDimensions {
+		t.Errorf("Expected ErrDimensions, got %v", err)
+	}
+	if _, err := new(Regression).MonotonicityReport(nil); err != ErrNotRun {
+		t.Errorf("Expected ErrNotRun, got %v", err)
+	}
+}
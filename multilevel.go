@@ -0,0 +1,227 @@
+package regression
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	multilevelIterations = 1000
+	multilevelTolerance  = 1e-10
+)
+
+// RunMultilevel fits a multilevel (mixed) model with a random intercept per group of the data points,
+// see GroupedDataPoint, and a random slope per group for each of the given base variables. Unlike
+// RunFixedEffects or per-segment models the group effects are shrunk toward zero, that is toward the
+// global estimate, the more so the fewer data points a group has, so small groups borrow strength from
+// the others. The coefficients, variance components and group effects are estimated by maximum likelihood
+// with the EM algorithm. The training data holds the data points with their group effects subtracted
+// afterwards, and the standard errors are those of a fit with known group effects. Custom solvers,
+// instruments, sign constraints, censoring, priors, AutoDropCollinear and per-segment models are not
+// supported.
+func (r *Regression) RunMultilevel(slopes ...int) error {
+	if !r.initialised {
+		return ErrNotEnoughData
+	}
+	if r.hasRun {
+		return ErrRegressionRun
+	}
+	if r.split || r.solve != nil || r.instruments != nil || r.signs != nil || r.censor != nil || r.prior != nil ||
+		r.vifThreshold > 0 {
+		return ErrUnsupported
+	}
+	r.DecompressData()
+	r.uncross()
+	numOfBaseVars := len(r.data[0].Variables)
+	for i, k := range slopes {
+		if k < 0 || k >= numOfBaseVars || containsInt(slopes[:i], k) {
+			return ErrDimensions
+		}
+	}
+
+	r.hashData()
+	if err := r.winsorize(); err != nil {
+		return err
+	}
+	if err := r.applyCrosses(); err != nil {
+		return err
+	}
+	r.hasRun = true
+
+	cols := len(r.data[0].Variables) + 1
+	q := len(slopes) + 1
+	groups := make(map[string][]*dataPoint)
+	var total float64
+	for _, d := range r.data {
+		groups[d.Group] = append(groups[d.Group], d)
+		total += d.Weight
+	}
+	z := func(d *dataPoint) []float64 {
+		row := make([]float64, q)
+		row[0] = 1
+		for i, k := range slopes {
+			row[i+1] = d.Variables[k]
+		}
+		return row
+	}
+	row := make([]float64, cols)
+	row[0] = 1
+	solveBeta := func(effects map[string][]float64) []float64 {
+		a := newNormalEquations(cols)
+		for _, d := range r.data {
+			copy(row[1:], d.Variables)
+			obs := d.Observed
+			if u, ok := effects[d.Group]; ok {
+				obs -= dot(z(d), u)
+			}
+			a.add(row, obs, d.Weight)
+		}
+		beta, _ := a.solve()
+		return beta
+	}
+
+	// start from the pooled fit, with group variances as large as the residual variance
+	effects := make(map[string][]float64, len(groups))
+	beta := solveBeta(effects)
+	var sse float64
+	scale := make([]float64, q)
+	for _, d := range r.data {
+		copy(row[1:], d.Variables)
+		e := d.Observed - dot(beta, row)
+		sse += d.Weight * e * e
+		for i, v := range z(d) {
+			scale[i] += d.Weight * v * v / total
+		}
+	}
+	sigma2 := sse / total
+	tau2 := make([]float64, q)
+	for i := range tau2 {
+		tau2[i] = sigma2 / math.Max(scale[i], 1e-12)
+	}
+
+	for iter := 0; iter < multilevelIterations; iter++ {
+		// E step: the posterior mean and covariance of the group effects
+		floor := 1e-10 * sigma2
+		nextTau2 := make([]float64, q)
+		var residual, trace float64
+		counted := 0
+		for group, points := range groups {
+			a := mat.NewDense(q, q, nil)
+			b := make([]float64, q)
+			var weight float64
+			for _, d := range points {
+				copy(row[1:], d.Variables)
+				e := d.Observed - dot(beta, row)
+				zd := z(d)
+				for i := range zd {
+					b[i] += d.Weight * zd[i] * e
+					for j := range zd {
+						a.Set(i, j, a.At(i, j)+d.Weight*zd[i]*zd[j])
+					}
+				}
+				weight += d.Weight
+			}
+			if weight == 0 {
+				delete(effects, group)
+				continue
+			}
+			counted++
+			for i := range tau2 {
+				a.Set(i, i, a.At(i, i)+sigma2/math.Max(tau2[i], floor))
+			}
+			c := new(mat.Dense)
+			if err := c.Inverse(a); err != nil {
+				return err
+			}
+			u := make([]float64, q)
+			for i := range u {
+				for j := range b {
+					u[i] += c.At(i, j) * b[j]
+				}
+				nextTau2[i] += u[i]*u[i] + sigma2*c.At(i, i)
+			}
+			effects[group] = u
+			for _, d := range points {
+				copy(row[1:], d.Variables)
+				zd := z(d)
+				e := d.Observed - dot(beta, row) - dot(zd, u)
+				residual += d.Weight * e * e
+				// tr(Z'WZ Var(u)) with Var(u) = sigma^2 C
+				for i := range zd {
+					for j := range zd {
+						trace += d.Weight * zd[i] * zd[j] * sigma2 * c.At(i, j)
+					}
+				}
+			}
+		}
+
+		// M step
+		beta = solveBeta(effects)
+		nextSigma2 := (residual + trace) / total
+		change := math.Abs(nextSigma2-sigma2) / math.Max(sigma2, floor)
+		for i := range nextTau2 {
+			nextTau2[i] /= float64(counted)
+			change = math.Max(change, math.Abs(nextTau2[i]-tau2[i])/math.Max(tau2[i], floor))
+		}
+		sigma2, tau2 = nextSigma2, nextTau2
+		if change < multilevelTolerance {
+			break
+		}
+	}
+
+	transformed := make([]*dataPoint, len(r.data))
+	for i, d := range r.data {
+		p := *d
+		if u, ok := effects[d.Group]; ok {
+			p.Observed -= dot(z(d), u)
+		}
+		transformed[i] = &p
+	}
+	r.data = transformed
+	if err := r.fit(numOfBaseVars); err != nil {
+		return err
+	}
+	r.groupEffects = effects
+	r.randomSlopes = append([]int(nil), slopes...)
+	r.groupVariances = tau2
+	return nil
+}
+
+// GroupEffect returns the random effects of a group fitted by RunMultilevel: the deviation of the group's
+// intercept from the offset, followed by the deviations of its slopes for the variables with random slopes.
+func (r *Regression) GroupEffect(group string) ([]float64, error) {
+	if r.groupEffects == nil {
+		return nil, ErrNotRun
+	}
+	u, ok := r.groupEffects[group]
+	if !ok {
+		return nil, ErrUnknownGroup
+	}
+	return append([]float64(nil), u...), nil
+}
+
+// GroupVariances returns the variances of the random intercepts followed by those of the random slopes
+// fitted by RunMultilevel.
+func (r *Regression) GroupVariances() ([]float64, error) {
+	if r.groupEffects == nil {
+		return nil, ErrNotRun
+	}
+	return append([]float64(nil), r.groupVariances...), nil
+}
+
+// predictMultilevel adds the random effects of a group to the prediction of the global model;
+// groups not seen in training get the global prediction.
+func (r *Regression) predictMultilevel(vars []float64, group string) (float64, error) {
+	if len(vars) != r.names.base {
+		return 0, ErrDimensions
+	}
+	p := r.predictRow(r.designRow(vars))
+	if u, ok := r.groupEffects[group]; ok {
+		p += u[0]
+		for i, k := range r.randomSlopes {
+			p += u[i+1] * vars[k]
+		}
+	}
+	return p, nil
+}
@@ -0,0 +1,116 @@
+package regression
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestRunMultilevel(t *testing.T) {
+	rng := rand.New(rand.NewSource(9))
+	r := new(Regression)
+	raw := make(map[string]float64)
+	counts := make(map[string]int)
+	for g := 0; g < 40; g++ {
+		group := fmt.Sprint("g", g)
+		u := 2 * rng.NormFloat64()
+		// group sizes from 2 to 41
+		for i := 0; i < g+2; i++ {
+			x := 10 * rng.Float64()
+			y := 1 + 3*x + u + rng.NormFloat64()
+			r.Train(GroupedDataPoint(y, []float64{x}, group))
+			raw[group] += y - 1 - 3*x
+			counts[group]++
+		}
+	}
+	if err := r.RunMultilevel(); err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "offset", r.Coeff(0), 1, 0.8)
+	assertClose(t, "slope", r.Coeff(1), 3, 0.05)
+	variances, err := r.GroupVariances()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "intercept variance", variances[0], 4, 2)
+	assertClose(t, "residual variance", r.sigma2, 1, 0.2)
+
+	// the effect of the smallest group is shrunk more than that of the largest
+	shrinkage := func(group string) float64 {
+		u, err := r.GroupEffect(group)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return (u[0] + r.Coeff(0) - 1) / (raw[group] / float64(counts[group]))
+	}
+	if small, large := shrinkage("g0"), shrinkage("g39"); !(small < large && large < 1.01) {
+		t.Errorf("Expected more shrinkage for small groups, got %v and %v", small, large)
+	}
+
+	u, _ := r.GroupEffect("g5")
+	got, err := r.PredictGroup([]float64{2}, "g5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "group prediction", got, r.Coeff(0)+u[0]+2*r.Coeff(1), 1e-9)
+	got, err = r.PredictGroup([]float64{2}, "new")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "new group prediction", got, r.Coeff(0)+2*r.Coeff(1), 1e-9)
+
+	var buf bytes.Buffer
+	if err := r.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := r.PredictGroup([]float64{2}, "g5")
+	if got, err := loaded.PredictGroup([]float64{2}, "g5"); err != nil || got != want {
+		t.Errorf("Expected %v from the loaded model, got %v, %v", want, got, err)
+	}
+	if _, err := r.GroupEffect("new"); err != ErrUnknownGroup {
+		t.Errorf("Expected ErrUnknownGroup, got %v", err)
+	}
+}
+
+func TestRunMultilevelSlopes(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	r := new(Regression)
+	slopes := make(map[string]float64)
+	for g := 0; g < 30; g++ {
+		group := fmt.Sprint("g", g)
+		slopes[group] = 2 + rng.NormFloat64()
+		for i := 0; i < 30; i++ {
+			x := 4*rng.Float64() - 2
+			r.Train(GroupedDataPoint(5+slopes[group]*x+0.5*rng.NormFloat64(), []float64{x}, group))
+		}
+	}
+	if err := r.RunMultilevel(0); err != nil {
+		t.Fatal(err)
+	}
+	variances, err := r.GroupVariances()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(variances) != 2 {
+		t.Fatalf("Expected 2 variance components, got %v", variances)
+	}
+	assertClose(t, "intercept variance", variances[0], 0, 0.05)
+	assertClose(t, "slope variance", variances[1], 1, 0.6)
+	for group, slope := range slopes {
+		u, _ := r.GroupEffect(group)
+		assertClose(t, "group slope "+group, r.Coeff(1)+u[1], slope, 0.3)
+	}
+
+	for _, slopes := range [][]int{{1}, {0, 0}} {
+		r := new(Regression)
+		r.Train(GroupedDataPoint(1, []float64{1}, "a"), GroupedDataPoint(2, []float64{2}, "b"), GroupedDataPoint(2, []float64{3}, "b"))
+		if err := r.RunMultilevel(slopes...); err != ErrDimensions {
+			t.Errorf("Expected ErrDimensions for %v, got %v", slopes, err)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package regression
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrUnknownModel signals that no model is registered under the requested key.
+var ErrUnknownModel = errors.New("unknown model")
+
+// MultiModelError collects the errors of a bulk operation, keyed by model.
+type MultiModelError map[string]error
+
+func (e MultiModelError) Error() string {
+	keys := make([]string, 0, len(e))
+	for k := range e {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	msgs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", k, e[k]))
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// MultiModel holds independent regressions keyed by name, e.g. one model per country.
+// It is safe for concurrent use: every model is locked while it is trained or run, so different models can
+// be used in parallel, and predictions and saving only share the lock of the model, so a model can serve
+// predictions from many goroutines at once. The models returned by Model and passed to Set
+// are not locked, and must not be used directly while other goroutines use the MultiModel.
+type MultiModel struct {
+	mu     sync.RWMutex
+	models map[string]*Regression
+	locks  map[*Regression]*sync.RWMutex
+	setup  func(key string, r *Regression)
+}
+
+// NewMultiModel creates an empty MultiModel. The optional setup function is called for every
+// model created by Train, so names and feature crosses can be configured in one place.
+func NewMultiModel(setup func(key string, r *Regression)) *MultiModel {
+	return &MultiModel{models: make(map[string]*Regression), setup: setup}
+}
+
+// Model returns the regression registered under key, or nil.
+func (m *MultiModel) Model(key string) *Regression {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.models[key]
+}
+
+// Set registers r under key, replacing any existing model.
+func (m *MultiModel) Set(key string, r *Regression) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.models == nil {
+		m.models = make(map[string]*Regression)
+	}
+	old := m.models[key]
+	m.models[key] = r
+	for _, other := range m.models {
+		if other == old {
+			return
+		}
+	}
+	delete(m.locks, old)
+}
+
+// Keys returns the sorted keys of all registered models.
+func (m *MultiModel) Keys() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	keys := make([]string, 0, len(m.models))
+	for k := range m.models {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// entry returns the model registered under key and its lock, creating the model if create is set, or
+// nil if there is none. The lock is not held: lock it for writing to change the model, or for reading
+// to predict with it.
+func (m *MultiModel) entry(key string, create bool) (*Regression, *sync.RWMutex) {
+	m.mu.Lock()
+	if m.models == nil {
+		m.models = make(map[string]*Regression)
+	}
+	r, ok := m.models[key]
+	if !ok {
+		if !create {
+			m.mu.Unlock()
+			return nil, nil
+		}
+		r = new(Regression)
+		if m.setup != nil {
+			m.setup(key, r)
+		}
+		m.models[key] = r
+	}
+	if m.locks == nil {
+		m.locks = make(map[*Regression]*sync.RWMutex)
+	}
+	l, ok := m.locks[r]
+	if !ok {
+		l = new(sync.RWMutex)
+		m.locks[r] = l
+	}
+	m.mu.Unlock()
+	return r, l
+}
+
+// Train adds data points to the model registered under key, creating the model if needed.
+func (m *MultiModel) Train(key string, d ...*dataPoint) {
+	r, l := m.entry(key, true)
+	l.Lock()
+	defer l.Unlock()
+	r.Train(d...)
+}
+
+// Run runs the regression registered under key.
+func (m *MultiModel) Run(key string) error {
+	r, l := m.entry(key, false)
+	if r == nil {
+		return ErrUnknownModel
+	}
+	l.Lock()
+	defer l.Unlock()
+	return r.Run()
+}
+
+// RunAll runs every model that has not been run yet. Failures are reported as a MultiModelError.
+func (m *MultiModel) RunAll() error {
+	errs := make(MultiModelError)
+	for _, key := range m.Keys() {
+		r, l := m.entry(key, false)
+		if r == nil {
+			continue
+		}
+		l.Lock()
+		if !r.hasRun {
+			if err := r.Run(); err != nil {
+				errs[key] = err
+			}
+		}
+		l.Unlock()
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// PredictFor predicts vars using the model registered under key.
+func (m *MultiModel) PredictFor(key string, vars []float64) (float64, error) {
+	r, l := m.entry(key, false)
+	if r == nil {
+		return 0, ErrUnknownModel
+	}
+	l.RLock()
+	defer l.RUnlock()
+	return r.Predict(vars)
+}
+
+// Save writes all models to w as a single JSON object keyed by model. Every model must have been run.
+func (m *MultiModel) Save(w io.Writer) error {
+	models := make(map[string]json.RawMessage)
+	for _, key := range m.Keys() {
+		r, l := m.entry(key, false)
+		if r == nil {
+			continue
+		}
+		l.RLock()
+		b, err := json.Marshal(r)
+		l.RUnlock()
+		if err != nil {
+			return err
+		}
+		models[key] = b
+	}
+	return json.NewEncoder(w).Encode(models)
+}
+
+// LoadMultiModel reads models previously written with MultiModel.Save.
+func LoadMultiModel(rd io.Reader) (*MultiModel, error) {
+	m := NewMultiModel(nil)
+	if err := json.NewDecoder(rd).Decode(&m.models); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
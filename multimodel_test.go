@@ -0,0 +1,116 @@
+package regression
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+func TestMultiModel(t *testing.T) {
+	m := NewMultiModel(func(key string, r *Regression) {
+		r.SetObserved("revenue " + key)
+		r.SetVar(0, "bids")
+	})
+	for i := 1; i <= 10; i++ {
+		x := float64(i)
+		m.Train("au", DataPoint(2*x+1+0.1*float64(i%2), []float64{x}))
+		m.Train("nz", DataPoint(-x+5+0.1*float64(i%3), []float64{x}))
+	}
+	m.Train("empty", DataPoint(1, []float64{1}))
+
+	err := m.RunAll()
+	errs, ok := err.(MultiModelError)
+	if !ok || len(errs) != 1 || errs["empty"] != ErrNotEnoughData {
+		t.Fatalf("Expected only the empty model to fail, got %v", err)
+	}
+
+	au, err := m.PredictFor("au", []float64{20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nz, _ := m.PredictFor("nz", []float64{20})
+	if au < 40 || nz > -10 {
+		t.Errorf("Expected predictions from separate models, got %v and %v", au, nz)
+	}
+	if m.Model("au").GetObserved() != "revenue au" {
+		t.Error("Expected the setup function to be applied")
+	}
+	if _, err := m.PredictFor("uk", []float64{1}); err != ErrUnknownModel {
+		t.Errorf("Expected ErrUnknownModel, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := m.Save(&buf); err == nil {
+		t.Error("Expected saving a model that has not been run to fail")
+	}
+	m.Set("empty", m.Model("au"))
+	buf.Reset()
+	if err := m.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadMultiModel(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keys := loaded.Keys(); len(keys) != 3 {
+		t.Errorf("Expected 3 models, got %v", keys)
+	}
+	got, _ := loaded.PredictFor("nz", []float64{20})
+	assertClose(t, "loaded prediction", got, nz, 1e-12)
+}
+
+func TestMultiModelConcurrent(t *testing.T) {
+	m := NewMultiModel(nil)
+	var wg sync.WaitGroup
+	for _, key := range []string{"au", "nz"} {
+		for g := 0; g < 4; g++ {
+			wg.Add(1)
+			go func(key string) {
+				defer wg.Done()
+				for i := 1; i <= 10; i++ {
+					x := float64(i)
+					m.Train(key, DataPoint(2*x+1+0.1*float64(i%2), []float64{x}))
+				}
+				m.Run(key)
+				m.PredictFor(key, []float64{3})
+				m.RunAll()
+			}(key)
+		}
+	}
+	wg.Wait()
+	for _, key := range m.Keys() {
+		if r := m.Model(key); !r.hasRun || len(r.data) != 40 {
+			t.Errorf("%s: expected a model run on 40 data points, got %d", key, len(r.data))
+		}
+	}
+}
+
+func TestMultiModelConcurrentPredictions(t *testing.T) {
+	m := NewMultiModel(nil)
+	for i := range carsSpeed {
+		m.Train("cars", DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	if err := m.Run("cars"); err != nil {
+		t.Fatal(err)
+	}
+	want, _ := m.PredictFor("cars", []float64{10})
+
+	// predictions share the lock of the model
+	_, l := m.entry("cars", false)
+	l.RLock()
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				if p, err := m.PredictFor("cars", []float64{10}); err != nil || p != want {
+					t.Errorf("Expected %v, got %v, %v", want, p, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	l.RUnlock()
+}
@@ -0,0 +1,93 @@
+package regression
+
+import "errors"
+
+var (
+	// ErrUnknownVariable signals a variable name outside the variables of the model.
+	ErrUnknownVariable = errors.New("unknown variable")
+	// ErrMissingVariable signals that a value keyed by name lacks one of the variables of the model.
+	ErrMissingVariable = errors.New("missing variable")
+	// ErrDuplicateVariable signals a variable name given twice, or an empty one.
+	ErrDuplicateVariable = errors.New("duplicate or empty variable name")
+)
+
+// DefineVars names the variables in order, like calling SetVar for each, and fixes them as the schema of
+// the model: TrainNamed, PredictNamed and Row take values keyed by these names and order them, so callers
+// needn't track positions. Fitted models must keep their number of variables.
+func (r *Regression) DefineVars(names ...string) error {
+	if r.hasRun && len(names) != r.names.base {
+		return ErrDimensions
+	}
+	index := make(map[string]int, len(names))
+	for i, name := range names {
+		if _, ok := index[name]; ok || name == "" {
+			return ErrDuplicateVariable
+		}
+		index[name] = i
+	}
+	for i, name := range names {
+		r.SetVar(i, name)
+	}
+	r.varIndex = index
+	return nil
+}
+
+// VarIndex returns the position of the variable name, as defined with DefineVars or, for fitted
+// models, set with SetVar.
+func (r *Regression) VarIndex(name string) (int, bool) {
+	i, ok := r.namedVars()[name]
+	return i, ok
+}
+
+// namedVars returns the positions of the variables by name.
+func (r *Regression) namedVars() map[string]int {
+	if r.varIndex != nil {
+		return r.varIndex
+	}
+	index := make(map[string]int, r.names.base)
+	for i := 0; i < r.names.base; i++ {
+		index[r.GetVar(i)] = i
+	}
+	return index
+}
+
+// Row orders values keyed by variable name into the variables of the model. Every variable must
+// have a value, and every key must name a variable.
+func (r *Regression) Row(values map[string]float64) ([]float64, error) {
+	index := r.namedVars()
+	if len(index) == 0 {
+		// no variable is named before DefineVars or a fit
+		return nil, ErrUnknownVariable
+	}
+	row := make([]float64, len(index))
+	for name, v := range values {
+		i, ok := index[name]
+		if !ok {
+			return nil, ErrUnknownVariable
+		}
+		row[i] = v
+	}
+	if len(values) != len(index) {
+		return nil, ErrMissingVariable
+	}
+	return row, nil
+}
+
+// TrainNamed trains the regression with a data point whose variables are keyed by name, see Row.
+func (r *Regression) TrainNamed(obs float64, values map[string]float64) error {
+	row, err := r.Row(values)
+	if err != nil {
+		return err
+	}
+	r.Train(DataPoint(obs, row))
+	return nil
+}
+
+// PredictNamed predicts like Predict for variables keyed by name, see Row.
+func (r *Regression) PredictNamed(values map[string]float64) (float64, error) {
+	row, err := r.Row(values)
+	if err != nil {
+		return 0, err
+	}
+	return r.Predict(row)
+}
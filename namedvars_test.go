@@ -0,0 +1,66 @@
+package regression
+
+import "testing"
+
+func TestDefineVars(t *testing.T) {
+	r := new(Regression)
+	r.SetObserved("y")
+	if err := r.DefineVars("a", "b", "c"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 30; i++ {
+		a, b, c := float64(i), float64(i%7), float64(i%4)
+		if err := r.TrainNamed(1+2*a-3*b+0.5*c, map[string]float64{"c": c, "a": a, "b": b}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "a", r.Coeff(1), 2, 1e-9)
+	assertClose(t, "b", r.Coeff(2), -3, 1e-9)
+	assertClose(t, "c", r.Coeff(3), 0.5, 1e-9)
+
+	p, err := r.PredictNamed(map[string]float64{"b": 1, "c": 2, "a": 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := r.Predict([]float64{3, 1, 2})
+	assertClose(t, "prediction", p, want, 1e-12)
+
+	if _, err := r.PredictNamed(map[string]float64{"a": 1, "b": 2}); err != ErrMissingVariable {
+		t.Errorf("Expected ErrMissingVariable, got %v", err)
+	}
+	if _, err := r.PredictNamed(map[string]float64{"a": 1, "b": 2, "d": 3}); err != ErrUnknownVariable {
+		t.Errorf("Expected ErrUnknownVariable, got %v", err)
+	}
+	if i, ok := r.VarIndex("c"); !ok || i != 2 {
+		t.Errorf("Expected c at 2, got %d, %v", i, ok)
+	}
+	r.SetVar(2, "z")
+	if _, ok := r.VarIndex("c"); ok {
+		t.Error("Expected c renamed")
+	}
+	if i, ok := r.VarIndex("z"); !ok || i != 2 {
+		t.Errorf("Expected z at 2, got %d, %v", i, ok)
+	}
+	if err := r.DefineVars("a", "b"); err != ErrDimensions {
+		t.Errorf("Expected ErrDimensions, got %v", err)
+	}
+	if err := new(Regression).DefineVars("a", "a"); err != ErrDuplicateVariable {
+		t.Errorf("Expected ErrDuplicateVariable, got %v", err)
+	}
+}
+
+func TestPredictNamedSetVar(t *testing.T) {
+	r := carsRegression(t)
+	p, err := r.PredictNamed(map[string]float64{"speed": 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := r.Predict([]float64{10})
+	assertClose(t, "prediction", p, want, 1e-12)
+	if err := new(Regression).TrainNamed(1, map[string]float64{"x": 1}); err != ErrUnknownVariable {
+		t.Errorf("Expected ErrUnknownVariable without a schema, got %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package regression
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Normalization is a scaling applied to the variables before the model is fitted. The fitted
+// coefficients are transformed back to the original scale, so predictions work on unscaled values.
+// Without regularization normalization only improves the conditioning of the problem; with it,
+// every variable is penalized on the same scale.
+type Normalization int
+
+const (
+	// NoNormalization fits the variables as they are.
+	NoNormalization Normalization = iota
+	// ZScore centers every variable on its mean and scales it by its standard deviation.
+	ZScore
+	// Robust centers every variable on its median and scales it by its interquartile range, so
+	// outliers don't dominate the scale. Variables with an interquartile range of zero, such as rare
+	// indicators, are scaled by their standard deviation instead.
+	Robust
+)
+
+func (n Normalization) String() string {
+	switch n {
+	case NoNormalization:
+		return "none"
+	case ZScore:
+		return "zscore"
+	case Robust:
+		return "robust"
+	}
+	return fmt.Sprintf("Normalization(%d)", int(n))
+}
+
+// parseNormalization is the inverse of Normalization.String.
+func parseNormalization(s string) (Normalization, error) {
+	switch strings.ToLower(s) {
+	case "", "none":
+		return NoNormalization, nil
+	case "zscore":
+		return ZScore, nil
+	case "robust":
+		return Robust, nil
+	}
+	return 0, fmt.Errorf("unknown normalization %q", s)
+}
+
+// SetNormalization sets the normalization applied to the variables and feature crosses by Run.
+func (r *Regression) SetNormalization(n Normalization) {
+	r.normalization = n
+}
+
+// SetVarNormalization sets the normalization of variable i, a base variable or a feature cross indexed
+// as in GetVar, overriding the one set with SetNormalization.
+func (r *Regression) SetVarNormalization(i int, n Normalization) {
+	if r.varNormalization == nil {
+		r.varNormalization = make(map[int]Normalization)
+	}
+	r.varNormalization[i] = n
+}
+
+// normalizes reports whether any variable is normalized.
+func (r *Regression) normalizes() bool {
+	if r.normalization != NoNormalization {
+		return true
+	}
+	for _, n := range r.varNormalization {
+		if n != NoNormalization {
+			return true
+		}
+	}
+	return false
+}
+
+// Scaling returns the centers and scales the variables were normalized with by the last fit, indexed
+// as in GetVar, and false when no variable was normalized. Unnormalized variables have a center of
+// zero and a scale of one.
+func (r *Regression) Scaling() (center, scale []float64, ok bool) {
+	if r.scaling == nil {
+		return nil, nil, false
+	}
+	return append([]float64(nil), r.scaling.center[1:]...), append([]float64(nil), r.scaling.scale[1:]...), true
+}
+
+// scaling records the centers and scales of the columns of a normalized design matrix.
+type scaling struct {
+	center []float64
+	scale  []float64
+}
+
+// normalize scales the variable columns of the design matrix x in place. It returns nil when
+// no normalization is configured.
+func (r *Regression) normalize(x *mat.Dense) *scaling {
+	r.scaling = nil
+	if !r.normalizes() {
+		return nil
+	}
+	rows, cols := x.Dims()
+	s := &scaling{center: make([]float64, cols), scale: make([]float64, cols)}
+	s.scale[0] = 1
+	column := make([]float64, rows)
+	for j := 1; j < cols; j++ {
+		s.scale[j] = 1
+		n, ok := r.varNormalization[j-1]
+		if !ok {
+			n = r.normalization
+		}
+		if n == NoNormalization {
+			continue
+		}
+		var sum, sq float64
+		for i := 0; i < rows; i++ {
+			sum += x.At(i, j)
+		}
+		mean := sum / float64(rows)
+		for i := 0; i < rows; i++ {
+			sq += math.Pow(x.At(i, j)-mean, 2)
+		}
+		sd := math.Sqrt(sq / float64(rows))
+		if sd == 0 {
+			// constant columns are left alone, the solver aliases them with the offset
+			continue
+		}
+		center, scale := mean, sd
+		if n == Robust {
+			for i := range column {
+				column[i] = x.At(i, j)
+			}
+			q1, q3 := quantiles(column, 0.25, 0.75)
+			center, _ = quantiles(column, 0.5, 0.5)
+			if q3 > q1 {
+				scale = q3 - q1
+			}
+		}
+		s.center[j], s.scale[j] = center, scale
+		for i := 0; i < rows; i++ {
+			x.Set(i, j, (x.At(i, j)-center)/scale)
+		}
+	}
+	r.scaling = s
+	return s
+}
+
+// restore transforms coefficients fitted on the normalized variables, and their unscaled covariance,
+// back to the original scale of the variables.
+func (s *scaling) restore(c []float64, diag *Diagnostics) ([]float64, *Diagnostics) {
+	n := len(c)
+	t := mat.NewDense(n, n, nil)
+	t.Set(0, 0, 1)
+	for j := 1; j < n; j++ {
+		t.Set(0, j, -s.center[j]/s.scale[j])
+		t.Set(j, j, 1/s.scale[j])
+	}
+
+	restored := make([]float64, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			restored[i] += t.At(i, j) * c[j]
+		}
+	}
+	if diag == nil || diag.Unscaled == nil {
+		return restored, diag
+	}
+	tu := new(mat.Dense)
+	tu.Mul(t, diag.Unscaled)
+	unscaled := new(mat.Dense)
+	unscaled.Mul(tu, t.T())
+	return restored, &Diagnostics{Aliased: diag.Aliased, Unscaled: unscaled, Loadings: diag.Loadings}
+}
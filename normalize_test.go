@@ -0,0 +1,108 @@
+package regression
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestZScore(t *testing.T) {
+	plain := new(Regression)
+	scaled := new(Regression)
+	scaled.SetNormalization(ZScore)
+	for _, r := range []*Regression{plain, scaled} {
+		for i := range carsSpeed {
+			r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i], 1000 * carsSpeed[i] * carsSpeed[i], 3}))
+		}
+		r.AddCross(PowCross(0, 3))
+		if err := r.Run(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// normalization does not change an unpenalized fit
+	for i := 0; i < 5; i++ {
+		assertClose(t, "coefficient", scaled.Coeff(i), plain.Coeff(i), 1e-9)
+		if i != 3 {
+			assertClose(t, "stderr", scaled.StdErr(i), plain.StdErr(i), 1e-9)
+		}
+	}
+	if scaled.Coeff(3) != 0 {
+		t.Errorf("Expected the constant variable to be aliased, got %v", scaled.Coeff(3))
+	}
+	want, _ := plain.Predict([]float64{21, 441000, 3})
+	got, _ := scaled.Predict([]float64{21, 441000, 3})
+	assertClose(t, "prediction", got, want, 1e-9)
+}
+
+func TestRidgeSolver(t *testing.T) {
+	var coeffs []float64
+	for _, lambda := range []float64{0, 10, 1000} {
+		r := new(Regression)
+		r.SetSolver(RidgeSolver{Lambda: lambda})
+		r.SetNormalization(ZScore)
+		for i := range carsSpeed {
+			r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+		}
+		if err := r.Run(); err != nil {
+			t.Fatal(err)
+		}
+		coeffs = append(coeffs, r.Coeff(1))
+	}
+	assertClose(t, "unpenalized", coeffs[0], 3.9324, 1e-4)
+	if !(coeffs[0] > coeffs[1] && coeffs[1] > coeffs[2] && coeffs[2] > 0) {
+		t.Errorf("Expected the coefficient to shrink towards zero, got %v", coeffs)
+	}
+}
+
+func TestRobustNormalization(t *testing.T) {
+	plain := new(Regression)
+	robust := new(Regression)
+	robust.SetNormalization(Robust)
+	robust.SetVarNormalization(1, ZScore)
+	robust.SetVarNormalization(2, NoNormalization)
+	for _, r := range []*Regression{plain, robust} {
+		for i := range carsSpeed {
+			r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i], carsSpeed[i] * carsSpeed[i], float64(i % 2)}))
+		}
+		if err := r.Run(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 4; i++ {
+		assertClose(t, "coefficient", robust.Coeff(i), plain.Coeff(i), 1e-9)
+	}
+
+	center, scale, ok := robust.Scaling()
+	if !ok {
+		t.Fatal("Expected scaling")
+	}
+	// R: median(cars$speed), IQR(cars$speed)
+	assertClose(t, "median", center[0], 15, 1e-12)
+	assertClose(t, "IQR", scale[0], 7, 1e-12)
+	if center[2] != 0 || scale[2] != 1 {
+		t.Errorf("Expected the third variable not to be normalized, got %v and %v", center[2], scale[2])
+	}
+	if _, _, ok := plain.Scaling(); ok {
+		t.Error("Expected no scaling without normalization")
+	}
+
+	var buf bytes.Buffer
+	if err := robust.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.normalization != Robust || loaded.varNormalization[1] != ZScore {
+		t.Errorf("Expected the normalization to be restored, got %v and %v", loaded.normalization, loaded.varNormalization)
+	}
+	if c, s, ok := loaded.Scaling(); !ok || c[0] != 15 || s[0] != 7 {
+		t.Errorf("Expected the scaling to be restored, got %v, %v", c, s)
+	}
+
+	r, err := LoadSpec([]byte(`{"observed": "y", "normalization": "robust"}`))
+	if err != nil || r.normalization != Robust {
+		t.Errorf("Expected a robust spec, got %v, %v", r, err)
+	}
+}
@@ -0,0 +1,221 @@
+package regression
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	// ErrNonFinite signals that an input or a result is NaN or infinite.
+	ErrNonFinite = errors.New("value is NaN or infinite")
+	// ErrDimensions signals that the dimensions of the inputs are inconsistent.
+	ErrDimensions = errors.New("inconsistent dimensions")
+	// ErrSingular signals that a matrix is singular.
+	ErrSingular = errors.New("matrix is singular")
+	// ErrInvalidWeight signals that a weight is negative.
+	ErrInvalidWeight = errors.New("weight is negative")
+)
+
+// FitMetrics are the goodness of fit measures of predictions against observations.
+type FitMetrics struct {
+	VarianceObserved  float64
+	VariancePredicted float64
+	// R2 is the ratio of the predicted to the observed variance, as reported by Run.
+	R2   float64
+	RMSE float64
+}
+
+// DesignMatrix builds the design matrix of rows as used by Run: an offset column followed by
+// the variables and the feature crosses applied to them.
+func DesignMatrix(rows [][]float64, crosses ...featureCross) (*mat.Dense, error) {
+	if len(rows) == 0 {
+		return nil, ErrNotEnoughData
+	}
+	var x *mat.Dense
+	for i, vars := range rows {
+		if len(vars) != len(rows[0]) {
+			return nil, ErrDimensions
+		}
+		row := designRow(vars, crosses)
+		if !allFinite(row) {
+			return nil, ErrNonFinite
+		}
+		if x == nil {
+			x = mat.NewDense(len(rows), len(row), nil)
+		}
+		if _, cols := x.Dims(); len(row) != cols {
+			return nil, ErrDimensions
+		}
+		for j, v := range row {
+			x.Set(i, j, v)
+		}
+	}
+	return x, nil
+}
+
+// BackSubstitute solves the upper triangular system R c = b, as the QR solver does after factorizing
+// the design matrix. Only the upper triangle of R is used.
+func BackSubstitute(r *mat.Dense, b []float64) ([]float64, error) {
+	rows, cols := r.Dims()
+	if rows != cols || len(b) != rows {
+		return nil, ErrDimensions
+	}
+	if !allFinite(b) {
+		return nil, ErrNonFinite
+	}
+	for i := 0; i < rows; i++ {
+		if r.At(i, i) == 0 {
+			return nil, ErrSingular
+		}
+		for j := i; j < cols; j++ {
+			if v := r.At(i, j); math.IsNaN(v) || math.IsInf(v, 0) {
+				return nil, ErrNonFinite
+			}
+		}
+	}
+	c := backSubstitute(r, b)
+	if !allFinite(c) {
+		return nil, ErrNonFinite
+	}
+	return c, nil
+}
+
+// Solve solves the least squares problem x c = y with the QR decomposition used by Run. x is used as
+// given: no offset column is added. Columns that are linear combinations of preceding columns are
+// aliased and get a coefficient of zero.
+func Solve(x [][]float64, y []float64) ([]float64, error) {
+	if len(x) == 0 || len(x[0]) == 0 {
+		return nil, ErrNotEnoughData
+	}
+	if len(y) != len(x) {
+		return nil, ErrDimensions
+	}
+	if len(x) < len(x[0]) {
+		return nil, ErrNotEnoughData
+	}
+	if !allFinite(y) {
+		return nil, ErrNonFinite
+	}
+	design := mat.NewDense(len(x), len(x[0]), nil)
+	for i, row := range x {
+		if len(row) != len(x[0]) {
+			return nil, ErrDimensions
+		}
+		if !allFinite(row) {
+			return nil, ErrNonFinite
+		}
+		for j, v := range row {
+			design.Set(i, j, v)
+		}
+	}
+	c, _, err := QRSolver{}.Solve(design, mat.NewDense(len(y), 1, append([]float64(nil), y...)))
+	if err != nil {
+		return nil, err
+	}
+	if !allFinite(c) {
+		return nil, ErrNonFinite
+	}
+	return c, nil
+}
+
+// Metrics computes the fit measures reported by Run for predicted against observed values.
+// weights may be nil, giving every value a weight of one.
+func Metrics(observed, predicted, weights []float64) (FitMetrics, error) {
+	if len(observed) == 0 {
+		return FitMetrics{}, ErrNotEnoughData
+	}
+	if len(predicted) != len(observed) || (weights != nil && len(weights) != len(observed)) {
+		return FitMetrics{}, ErrDimensions
+	}
+	if weights == nil {
+		weights = make([]float64, len(observed))
+		for i := range weights {
+			weights[i] = 1
+		}
+	}
+	if !allFinite(observed) || !allFinite(predicted) || !allFinite(weights) {
+		return FitMetrics{}, ErrNonFinite
+	}
+	var total float64
+	for _, w := range weights {
+		if w < 0 {
+			return FitMetrics{}, ErrInvalidWeight
+		}
+		total += w
+	}
+	if total == 0 {
+		return FitMetrics{}, ErrNotEnoughData
+	}
+	m := metrics(observed, predicted, weights)
+	if !allFinite([]float64{m.VarianceObserved, m.VariancePredicted, m.R2, m.RMSE}) {
+		return FitMetrics{}, ErrNonFinite
+	}
+	return m, nil
+}
+
+// designRow builds a row of the design matrix: the offset followed by vars
+// and any feature crosses applied to them.
+func designRow(vars []float64, crosses []featureCross) []float64 {
+	row := make([]float64, 1, len(vars)+1)
+	row[0] = 1
+	row = append(row, vars...)
+	for _, cross := range crosses {
+		row = append(row, cross.Calculate(row[1:])...)
+	}
+	return row
+}
+
+// backSubstitute solves R c = b for an upper triangular R without a zero on its diagonal.
+func backSubstitute(r *mat.Dense, b []float64) []float64 {
+	n := len(b)
+	c := make([]float64, n)
+	for i := n - 1; i >= 0; i-- {
+		c[i] = b[i]
+		for j := i + 1; j < n; j++ {
+			c[i] -= c[j] * r.At(i, j)
+		}
+		c[i] /= r.At(i, i)
+	}
+	return c
+}
+
+func metrics(observed, predicted, weights []float64) FitMetrics {
+	m := FitMetrics{
+		VarianceObserved:  weightedVariance(observed, weights),
+		VariancePredicted: weightedVariance(predicted, weights),
+	}
+	m.R2 = m.VariancePredicted / m.VarianceObserved
+	var sse, total float64
+	for i, w := range weights {
+		e := predicted[i] - observed[i]
+		sse += w * e * e
+		total += w
+	}
+	m.RMSE = math.Sqrt(sse / total)
+	return m
+}
+
+func weightedVariance(values, weights []float64) float64 {
+	var total, sum, variance float64
+	for i, w := range weights {
+		total += w
+		sum += w * values[i]
+	}
+	mean := sum / total
+	for i, w := range weights {
+		d := values[i] - mean
+		variance += w * d * d
+	}
+	return variance / total
+}
+
+func allFinite(values []float64) bool {
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+	}
+	return true
+}
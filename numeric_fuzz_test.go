@@ -0,0 +1,68 @@
+//go:build go1.18
+// +build go1.18
+
+package regression
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func FuzzDesignMatrix(f *testing.F) {
+	f.Add(1.0, 2.0, 3.0, 4.0, 2.0)
+	f.Add(0.0, -1.0, math.MaxFloat64, 1e-300, 0.5)
+	f.Add(math.NaN(), math.Inf(1), 0.0, 0.0, -3.0)
+	f.Fuzz(func(t *testing.T, a, b, c, d, power float64) {
+		x, err := DesignMatrix([][]float64{{a, b}, {c, d}}, PowCross(0, power), MultiplierCross(0, 1))
+		if err != nil {
+			return
+		}
+		rows, cols := x.Dims()
+		if rows != 2 || cols != 5 {
+			t.Fatalf("Unexpected dimensions %d x %d", rows, cols)
+		}
+		for i := 0; i < rows; i++ {
+			for j := 0; j < cols; j++ {
+				if v := x.At(i, j); math.IsNaN(v) || math.IsInf(v, 0) {
+					t.Fatalf("Non-finite value %v at %d,%d", v, i, j)
+				}
+			}
+		}
+	})
+}
+
+func FuzzBackSubstitute(f *testing.F) {
+	f.Add(2.0, 1.0, 3.0, 5.0, 6.0)
+	f.Add(1e-300, 1e300, 1e-300, 1e300, -1e300)
+	f.Add(0.0, 1.0, 1.0, 1.0, 1.0)
+	f.Fuzz(func(t *testing.T, r00, r01, r11, b0, b1 float64) {
+		r := mat.NewDense(2, 2, []float64{r00, r01, 0, r11})
+		c, err := BackSubstitute(r, []float64{b0, b1})
+		if err != nil {
+			return
+		}
+		if !allFinite(c) {
+			t.Fatalf("Non-finite solution %v", c)
+		}
+	})
+}
+
+func FuzzMetrics(f *testing.F) {
+	f.Add(1.0, 2.0, 3.0, 1.5, 2.5, 2.0, 1.0, 1.0)
+	f.Add(1e308, -1e308, 0.0, 1e308, 1e308, 0.0, 1.0, 0.0)
+	f.Add(1.0, 1.0, 1.0, 1.0, 1.0, 1.0, 0.0, 0.0)
+	f.Fuzz(func(t *testing.T, o0, o1, o2, p0, p1, p2, w0, w1 float64) {
+		m, err := Metrics([]float64{o0, o1, o2}, []float64{p0, p1, p2}, []float64{w0, w1, 1})
+		if err != nil {
+			return
+		}
+		if !allFinite([]float64{m.VarianceObserved, m.VariancePredicted, m.R2, m.RMSE}) {
+			t.Fatalf("Non-finite metrics %+v", m)
+		}
+		if m.RMSE < 0 || m.VarianceObserved < 0 || m.VariancePredicted < 0 {
+			t.Fatalf("Negative metrics %+v", m)
+		}
+	})
+}
@@ -0,0 +1,142 @@
+package regression
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestDesignMatrix(t *testing.T) {
+	x, err := DesignMatrix([][]float64{{2, 3}, {4, 5}}, PowCross(0, 2), MultiplierCross(0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]float64{{1, 2, 3, 4, 6}, {1, 4, 5, 16, 20}}
+	for i, row := range want {
+		for j, v := range row {
+			if x.At(i, j) != v {
+				t.Errorf("Expected %v at %d,%d, got %v", v, i, j, x.At(i, j))
+			}
+		}
+	}
+
+	for _, c := range []struct {
+		rows    [][]float64
+		crosses []featureCross
+		err     error
+	}{
+		{nil, nil, ErrNotEnoughData},
+		{[][]float64{{1, 2}, {3}}, nil, ErrDimensions},
+		{[][]float64{{1, math.NaN()}}, nil, ErrNonFinite},
+		{[][]float64{{-1}}, []featureCross{PowCross(0, 0.5)}, ErrNonFinite},
+		{[][]float64{{1e200}}, []featureCross{PowCross(0, 2)}, ErrNonFinite},
+	} {
+		if _, err := DesignMatrix(c.rows, c.crosses...); err != c.err {
+			t.Errorf("Expected %v for %v, got %v", c.err, c.rows, err)
+		}
+	}
+}
+
+func TestBackSubstitute(t *testing.T) {
+	r := mat.NewDense(3, 3, []float64{
+		2, 1, -1,
+		0, 3, 2,
+		0, 0, 4,
+	})
+	c, err := BackSubstitute(r, []float64{3, 13, 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []float64{1, 3, 2} {
+		assertClose(t, "c", c[i], want, 1e-12)
+	}
+
+	if _, err := BackSubstitute(r, []float64{1, 2}); err != ErrDimensions {
+		t.Errorf("Expected ErrDimensions, got %v", err)
+	}
+	if _, err := BackSubstitute(r, []float64{1, math.Inf(1), 2}); err != ErrNonFinite {
+		t.Errorf("Expected ErrNonFinite, got %v", err)
+	}
+	r.Set(1, 1, 0)
+	if _, err := BackSubstitute(r, []float64{3, 13, 8}); err != ErrSingular {
+		t.Errorf("Expected ErrSingular, got %v", err)
+	}
+	r.Set(1, 1, 1e-300)
+	if _, err := BackSubstitute(r, []float64{3, 1e10, 8}); err != ErrNonFinite {
+		t.Errorf("Expected ErrNonFinite on overflow, got %v", err)
+	}
+}
+
+func TestSolve(t *testing.T) {
+	r := carsRegression(t)
+	var x [][]float64
+	for _, speed := range carsSpeed {
+		x = append(x, []float64{1, speed})
+	}
+	c, err := Solve(x, carsDist)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "offset", c[0], r.Coeff(0), 1e-9)
+	assertClose(t, "slope", c[1], r.Coeff(1), 1e-9)
+
+	aliased := [][]float64{{1, 1, 2}, {2, 2, 4}, {3, 1, 2}}
+	c, err = Solve(aliased, []float64{1, 2, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c[2] != 0 {
+		t.Errorf("Expected an aliased column to be zero, got %v", c)
+	}
+
+	for _, tc := range []struct {
+		x   [][]float64
+		y   []float64
+		err error
+	}{
+		{nil, nil, ErrNotEnoughData},
+		{[][]float64{{1, 2}}, []float64{1}, ErrNotEnoughData},
+		{[][]float64{{1}, {2}}, []float64{1}, ErrDimensions},
+		{[][]float64{{1, 2}, {3}}, []float64{1, 2}, ErrDimensions},
+		{[][]float64{{1}, {math.NaN()}}, []float64{1, 2}, ErrNonFinite},
+		{[][]float64{{1}, {2}}, []float64{1, math.Inf(1)}, ErrNonFinite},
+	} {
+		if _, err := Solve(tc.x, tc.y); err != tc.err {
+			t.Errorf("Expected %v for %v, got %v", tc.err, tc.x, err)
+		}
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	r := carsRegression(t)
+	observed := make([]float64, len(r.data))
+	predicted := make([]float64, len(r.data))
+	for i, d := range r.data {
+		observed[i], predicted[i] = d.Observed, d.Predicted
+	}
+	m, err := Metrics(observed, predicted, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.R2 != r.R2 || m.VarianceObserved != r.Varianceobserved || m.RMSE != r.RMSE() {
+		t.Errorf("Expected the metrics of Run, got %+v", m)
+	}
+
+	for _, c := range []struct {
+		observed, predicted, weights []float64
+		err                          error
+	}{
+		{nil, nil, nil, ErrNotEnoughData},
+		{[]float64{1, 2}, []float64{1}, nil, ErrDimensions},
+		{[]float64{1, 2}, []float64{1, 2}, []float64{1}, ErrDimensions},
+		{[]float64{1, math.NaN()}, []float64{1, 2}, nil, ErrNonFinite},
+		{[]float64{1, 2}, []float64{1, 2}, []float64{1, -1}, ErrInvalidWeight},
+		{[]float64{1, 2}, []float64{1, 2}, []float64{0, 0}, ErrNotEnoughData},
+		{[]float64{-1e300, 1e300}, []float64{1e300, -1e300}, nil, ErrNonFinite},
+	} {
+		if _, err := Metrics(c.observed, c.predicted, c.weights); err != c.err {
+			t.Errorf("Expected %v for %v, got %v", c.err, c.observed, err)
+		}
+	}
+}
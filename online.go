@@ -0,0 +1,170 @@
+package regression
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	// ErrNoStatistics signals that a model cannot be updated because it holds neither training data
+	// nor the sufficient statistics of its fit.
+	ErrNoStatistics = errors.New("model holds no statistics to update")
+	// ErrUnsupported signals that an operation is not supported with the model's configuration.
+	ErrUnsupported = errors.New("operation not supported for this model")
+)
+
+// onlineState holds the sufficient statistics of an incrementally trained model and its holdout set.
+type onlineState struct {
+	stats     *normalEquations
+	baseline  []float64
+	holdout   []*dataPoint
+	next      int
+	size      int
+	fraction  float64
+	tolerance float64
+}
+
+// HoldoutReport describes the out-of-sample error on the rolling holdout set.
+type HoldoutReport struct {
+	// Points is the number of data points in the holdout set.
+	Points int
+	// RMSE is the root mean squared error of the current model on the holdout set.
+	RMSE float64
+	// BaselineRMSE is the error of the model as it was fitted by Run or RunStream, before any Update.
+	BaselineRMSE float64
+	// Degraded is set when RMSE exceeds BaselineRMSE by more than the configured tolerance,
+	// meaning the incremental updates hurt and a full refit is needed.
+	Degraded bool
+}
+
+// SetHoldout keeps a rolling holdout set during incremental training: each data point passed to Update
+// is held out of training with probability fraction, and the size most recent held out points are kept.
+// Points are sampled with the model's source of randomness, see SetSeed.
+// tolerance is the relative increase in holdout error over the baseline that is reported as degraded.
+func (r *Regression) SetHoldout(size int, fraction, tolerance float64) {
+	o := r.onlineState()
+	o.size = size
+	o.fraction = fraction
+	o.tolerance = tolerance
+	if len(o.holdout) > size {
+		o.holdout = o.holdout[len(o.holdout)-size:]
+		o.next = 0
+	}
+}
+
+func (r *Regression) onlineState() *onlineState {
+	if r.online == nil {
+		r.online = new(onlineState)
+	}
+	return r.online
+}
+
+// Update trains a fitted model incrementally with more data points, re-solving the normal equations
+// rather than refitting from scratch. Feature crosses and weights are applied as in Run. The data
+// points are not retained. Models with a custom solver, instruments, fixed or random group effects, sign constraints,
+// censoring, priors, AutoDropCollinear or per-segment models cannot be updated.
+func (r *Regression) Update(d ...*dataPoint) error {
+	if !r.hasRun || len(r.coeff) == 0 {
+		return ErrNotRun
+	}
+	if r.solve != nil || r.split || r.instruments != nil || r.fixedEffects != nil || r.groupEffects != nil ||
+		r.signs != nil || r.censor != nil || r.prior != nil || r.vifThreshold > 0 {
+		return ErrUnsupported
+	}
+	if err := checkWeights(d); err != nil {
+		return err
+	}
+	o, err := r.onlineStats()
+	if err != nil {
+		return err
+	}
+	r.ingestPoints(d)
+	r.newPoints += len(d)
+
+	for _, p := range d {
+		if o.size > 0 && r.random().Float64() < o.fraction {
+			o.hold(p)
+			continue
+		}
+		if r.hasher != nil {
+			r.hasher.add(p)
+		}
+		o.stats.add(r.designRow(p.Variables), r.clipObserved(p.Observed), p.Weight)
+	}
+
+	c, unscaled := o.stats.solve()
+	r.setCoeffs(c)
+	r.calcStreamMetrics(o.stats, c, unscaled)
+	return nil
+}
+
+// onlineStats returns the online state with the sufficient statistics of the fit, accumulating them
+// from the training data on first use.
+func (r *Regression) onlineStats() (*onlineState, error) {
+	o := r.onlineState()
+	if o.stats == nil {
+		r.DecompressData()
+		if len(r.data) == 0 {
+			return nil, ErrNoStatistics
+		}
+		// the training data already has the crosses applied
+		o.stats = newNormalEquations(len(r.coeff))
+		for _, p := range r.data {
+			o.stats.add(append([]float64{1}, p.Variables...), p.Observed, p.Weight)
+		}
+	}
+	if o.baseline == nil {
+		o.baseline = r.coeffs()
+	}
+	return o, nil
+}
+
+func (o *onlineState) hold(p *dataPoint) {
+	if len(o.holdout) < o.size {
+		o.holdout = append(o.holdout, p)
+		return
+	}
+	o.holdout[o.next] = p
+	o.next = (o.next + 1) % o.size
+}
+
+// Holdout reports the live out-of-sample error on the rolling holdout set.
+func (r *Regression) Holdout() HoldoutReport {
+	if r.online == nil || len(r.online.holdout) == 0 {
+		return HoldoutReport{RMSE: math.NaN(), BaselineRMSE: math.NaN()}
+	}
+	o := r.online
+	baseline := o.baseline
+	if baseline == nil {
+		baseline = r.coeffs()
+	}
+
+	var weights, sse, baselineSSE float64
+	for _, p := range o.holdout {
+		row := r.designRow(p.Variables)
+		e := r.predictRow(row) - p.Observed
+		var b float64
+		for j, c := range baseline {
+			b += c * row[j]
+		}
+		weights += p.Weight
+		sse += p.Weight * e * e
+		baselineSSE += p.Weight * (b - p.Observed) * (b - p.Observed)
+	}
+	report := HoldoutReport{
+		Points:       len(o.holdout),
+		RMSE:         math.Sqrt(sse / weights),
+		BaselineRMSE: math.Sqrt(baselineSSE / weights),
+	}
+	report.Degraded = report.RMSE > report.BaselineRMSE*(1+o.tolerance)
+	return report
+}
+
+// coeffs returns the coefficients as a slice.
+func (r *Regression) coeffs() []float64 {
+	c := make([]float64, len(r.coeff))
+	for i := range c {
+		c[i] = r.coeff[i]
+	}
+	return c
+}
@@ -0,0 +1,85 @@
+package regression
+
+import (
+	"math"
+	"testing"
+)
+
+func TestUpdate(t *testing.T) {
+	half := len(carsSpeed) / 2
+	r := new(Regression)
+	r.AddCross(PowCross(0, 2))
+	for i := 0; i < half; i++ {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	for i := half; i < len(carsSpeed); i++ {
+		if err := r.Update(DataPoint(carsDist[i], []float64{carsSpeed[i]})); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// incremental training gives the same fit as training on all the data
+	want := new(Regression)
+	want.AddCross(PowCross(0, 2))
+	for i := range carsSpeed {
+		want.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	want.Run()
+	for i := 0; i < 3; i++ {
+		assertClose(t, "coefficient", r.Coeff(i), want.Coeff(i), 1e-6)
+		assertClose(t, "stderr", r.StdErr(i), want.StdErr(i), 1e-6)
+	}
+	assertClose(t, "R2", r.R2, want.R2, 1e-9)
+	if r.Formula != want.Formula {
+		t.Errorf("Expected formula %q, got %q", want.Formula, r.Formula)
+	}
+}
+
+func TestUpdateErrors(t *testing.T) {
+	if err := new(Regression).Update(DataPoint(1, []float64{1})); err != ErrNotRun {
+		t.Errorf("Expected ErrNotRun, got %v", err)
+	}
+	r := carsRegression(t)
+	r.SetSolver(RidgeSolver{Lambda: 1})
+	if err := r.Update(DataPoint(1, []float64{1})); err != ErrUnsupported {
+		t.Errorf("Expected ErrUnsupported, got %v", err)
+	}
+}
+
+func TestHoldout(t *testing.T) {
+	r := carsRegression(t)
+	if report := r.Holdout(); report.Points != 0 || !math.IsNaN(report.RMSE) {
+		t.Errorf("Expected an empty report, got %+v", report)
+	}
+
+	r.SetHoldout(10, 0.5, 0.1)
+	// the relationship flips, so updates drag the model away from the baseline
+	for i := 0; i < 200; i++ {
+		x := float64(i%20 + 5)
+		r.Update(DataPoint(100-3*x, []float64{x}))
+	}
+	report := r.Holdout()
+	if report.Points != 10 {
+		t.Errorf("Expected a holdout of 10 points, got %d", report.Points)
+	}
+	if report.Degraded || report.RMSE >= report.BaselineRMSE {
+		t.Errorf("Expected the updated model to fit the new regime better, got %+v", report)
+	}
+
+	// back to the old regime: the updates now hurt compared to the baseline fit
+	for i := 0; i < 200; i++ {
+		x := float64(i%20 + 5)
+		r.Update(DataPoint(-17.5791+3.9324*x, []float64{x}))
+	}
+	r.online.holdout = r.online.holdout[:0]
+	for i := 0; i < 200 && len(r.online.holdout) < 10; i++ {
+		x := float64(i%20 + 5)
+		r.online.hold(DataPoint(-17.5791+3.9324*x, []float64{x}))
+	}
+	if report := r.Holdout(); !report.Degraded {
+		t.Errorf("Expected the holdout error to be degraded, got %+v", report)
+	}
+}
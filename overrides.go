@@ -0,0 +1,83 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownCoefficient signals that no coefficient has the given name.
+var ErrUnknownCoefficient = errors.New("unknown coefficient")
+
+// overridePrefix prefixes the metadata keys recording coefficient overrides.
+const overridePrefix = "override:"
+
+// ApplyOverrides sets coefficients by name, e.g. to tune a loaded model manually in an emergency. Names are
+// those of the variables and feature crosses, and "(offset)" for the offset. Every override is recorded in
+// the metadata under "override:" followed by the name, with the new and the fitted value. No coefficient is
+// changed unless all names are known and all values finite. Standard errors and metrics still describe
+// the fitted coefficients. Per-segment models are not supported, as predictions use the coefficients of
+// the segments.
+func (r *Regression) ApplyOverrides(overrides map[string]float64) error {
+	if !r.hasRun || len(r.coeff) == 0 {
+		return ErrNotRun
+	}
+	if r.split {
+		return ErrUnsupported
+	}
+	index := make(map[string]int, len(r.coeff))
+	for i, name := range r.coeffNames() {
+		index[name] = i
+	}
+	for name, v := range overrides {
+		if _, ok := index[name]; !ok {
+			return ErrUnknownCoefficient
+		}
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return ErrNonFinite
+		}
+	}
+
+	for name, v := range overrides {
+		i := index[name]
+		fitted := r.coeff[i]
+		if prev, ok := r.metadata[overridePrefix+name]; ok {
+			// keep the fitted value of an earlier override
+			if k := strings.Index(prev, " (was "); k >= 0 {
+				if f, err := strconv.ParseFloat(strings.TrimSuffix(prev[k+6:], ")"), 64); err == nil {
+					fitted = f
+				}
+			}
+		}
+		r.coeff[i] = v
+		r.SetMetadata(overridePrefix+name, formatOverride(v)+" (was "+formatOverride(fitted)+")")
+	}
+	r.Formula = r.formula()
+	return nil
+}
+
+// ParseOverrides parses coefficient overrides given as comma separated name=value pairs,
+// e.g. from a flag or an environment variable: "(offset)=1.5,speed=3".
+func ParseOverrides(s string) (map[string]float64, error) {
+	overrides := make(map[string]float64)
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		k := strings.LastIndex(pair, "=")
+		if k < 0 {
+			return nil, errors.New("override " + strconv.Quote(pair) + " is not name=value")
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(pair[k+1:]), 64)
+		if err != nil {
+			return nil, err
+		}
+		overrides[strings.TrimSpace(pair[:k])] = v
+	}
+	return overrides, nil
+}
+
+func formatOverride(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,118 @@
+package regression
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestApplyOverrides(t *testing.T) {
+	r := carsRegression(t)
+	slope := r.Coeff(1)
+	if err := r.ApplyOverrides(map[string]float64{"speed": 4, "(offset)": -10}); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := r.Predict([]float64{10}); p != 30 {
+		t.Errorf("Expected the overridden prediction 30, got %v", p)
+	}
+	if !strings.HasPrefix(r.Formula, "Predicted = -10.0000 + speed*4.0000") {
+		t.Errorf("Unexpected formula %q", r.Formula)
+	}
+	note, ok := r.GetMetadata("override:speed")
+	if !ok || note != "4 (was "+formatOverride(slope)+")" {
+		t.Errorf("Unexpected metadata %q", note)
+	}
+
+	// a second override keeps the fitted value
+	if err := r.ApplyOverrides(map[string]float64{"speed": 5}); err != nil {
+		t.Fatal(err)
+	}
+	if note, _ := r.GetMetadata("override:speed"); note != "5 (was "+formatOverride(slope)+")" {
+		t.Errorf("Unexpected metadata %q", note)
+	}
+
+	// overrides survive saving
+	var buf bytes.Buffer
+	if err := r.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Coeff(1) != 5 {
+		t.Errorf("Expected the loaded coefficient 5, got %v", loaded.Coeff(1))
+	}
+
+	if err := r.ApplyOverrides(map[string]float64{"speed": 1, "weight": 2}); err != ErrUnknownCoefficient {
+		t.Errorf("Expected ErrUnknownCoefficient, got %v", err)
+	}
+	if err := r.ApplyOverrides(map[string]float64{"speed": math.NaN()}); err != ErrNonFinite {
+		t.Errorf("Expected ErrNonFinite, got %v", err)
+	}
+	if r.Coeff(1) != 5 {
+		t.Errorf("Expected failed overrides to change nothing, got %v", r.Coeff(1))
+	}
+	if err := new(Regression).ApplyOverrides(nil); err != ErrNotRun {
+		t.Errorf("Expected ErrNotRun, got %v", err)
+	}
+
+	split := new(Regression)
+	for i := 0; i < 10; i++ {
+		x := float64(i)
+		split.Train(DataPoint(1+2*x, []float64{0, x}), DataPoint(10-3*x, []float64{1, x}))
+	}
+	split.SplitByVar(0)
+	if err := split.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if err := split.ApplyOverrides(map[string]float64{"(offset)": 1}); err != ErrUnsupported {
+		t.Errorf("Expected ErrUnsupported for a per-segment model, got %v", err)
+	}
+}
+
+func TestApplyOverridesAfterInteraction(t *testing.T) {
+	r := new(Regression)
+	r.SetVar(0, "a")
+	r.SetVar(1, "b")
+	r.AddCross(MultiplierCross(0, 1))
+	r.AddCross(PowCross(0, 2))
+	for i := 0; i < 20; i++ {
+		a, b := float64(i%7), float64(i%3)
+		r.Train(DataPoint(1+a-b+0.5*a*b+0.2*a*a+0.01*float64(i%4), []float64{a, b}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	interaction := r.Coeff(3)
+	if err := r.ApplyOverrides(map[string]float64{"(a)^2": 1}); err != nil {
+		t.Fatal(err)
+	}
+	// the power follows the single column of the interaction
+	if r.Coeff(4) != 1 || r.Coeff(3) != interaction {
+		t.Errorf("Expected only the power to be overridden, got %v and %v", r.Coeff(3), r.Coeff(4))
+	}
+	want := r.Coeff(0) + 2*r.Coeff(1) + 3*r.Coeff(2) + 6*interaction + 4
+	if p, _ := r.Predict([]float64{2, 3}); math.Abs(p-want) > 1e-9 {
+		t.Errorf("Expected the overridden prediction %v, got %v", want, p)
+	}
+	if _, ok := r.GetMetadata("override:(a)^2"); !ok {
+		t.Error("Expected the override to be recorded")
+	}
+}
+
+func TestParseOverrides(t *testing.T) {
+	o, err := ParseOverrides(" (offset)=1.5, speed = -3,,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(o) != 2 || o["(offset)"] != 1.5 || o["speed"] != -3 {
+		t.Errorf("Unexpected overrides %v", o)
+	}
+	for _, s := range []string{"speed", "speed=fast"} {
+		if _, err := ParseOverrides(s); err == nil {
+			t.Errorf("Expected an error for %q", s)
+		}
+	}
+}
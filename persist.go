@@ -0,0 +1,321 @@
+package regression
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// ErrCrossNotSerializable signals that a model uses a feature cross that was not created by this package
+// and therefore cannot be saved.
+var ErrCrossNotSerializable = errors.New("feature cross cannot be serialized")
+
+// model is the serialized form of a trained regression.
+type model struct {
+	Observed          string                 `json:"observed"`
+	Vars              map[int]string         `json:"vars,omitempty"`
+	BaseVars          int                    `json:"base_vars"`
+	Coefficients      []float64              `json:"coefficients"`
+	Crosses           []CrossSpec            `json:"crosses,omitempty"`
+	Formula           string                 `json:"formula"`
+	R2                float64                `json:"r2"`
+	VarianceObserved  float64                `json:"variance_observed"`
+	VariancePredicted float64                `json:"variance_predicted"`
+	RMSE              float64                `json:"rmse"`
+	Observations      int                    `json:"observations"`
+	TrainedAt         time.Time              `json:"trained_at"`
+	DFResidual        int                    `json:"df_residual"`
+	Sigma2            float64                `json:"sigma2,omitempty"`
+	Covariance        [][]float64            `json:"covariance,omitempty"`
+	Aliased           []int                  `json:"aliased,omitempty"`
+	SplitVar          *int                   `json:"split_var,omitempty"`
+	Segments          map[string]*Regression `json:"segments,omitempty"`
+	Metadata          map[string]string      `json:"metadata,omitempty"`
+	DataHash          string                 `json:"data_hash,omitempty"`
+	FixedEffects      map[string]float64     `json:"fixed_effects,omitempty"`
+	Units             map[int]string         `json:"units,omitempty"`
+	ObservedUnit      string                 `json:"observed_unit,omitempty"`
+	VarTypes          map[int]ColumnType     `json:"var_types,omitempty"`
+	Clips             map[int][2]float64     `json:"clips,omitempty"`
+	ObservedClip      *[2]float64            `json:"observed_clip,omitempty"`
+	Normalization     *normalizationModel    `json:"normalization,omitempty"`
+	Censoring         *censoringModel        `json:"censoring,omitempty"`
+	GroupEffects      map[string][]float64   `json:"group_effects,omitempty"`
+	RandomSlopes      []int                  `json:"random_slopes,omitempty"`
+	GroupVariances    []float64              `json:"group_variances,omitempty"`
+	Imputation        string                 `json:"imputation,omitempty"`
+	InputMoments      *momentsModel          `json:"input_moments,omitempty"`
+	MissingIndicators map[int]float64        `json:"missing_indicators,omitempty"`
+}
+
+// momentsModel is the serialized form of the moments of the variables used by PredictPartial.
+type momentsModel struct {
+	Mean       []float64   `json:"mean"`
+	Covariance [][]float64 `json:"covariance"`
+}
+
+// normalizationModel is the serialized form of the normalization settings and the centers and scales
+// of the last fit, which are informational: the coefficients are on the original scale.
+type normalizationModel struct {
+	Method string         `json:"method"`
+	Vars   map[int]string `json:"vars,omitempty"`
+	Center []float64      `json:"center,omitempty"`
+	Scale  []float64      `json:"scale,omitempty"`
+}
+
+// censoringModel is the serialized form of the censoring points; an uncensored side is omitted.
+type censoringModel struct {
+	Lower *float64 `json:"lower,omitempty"`
+	Upper *float64 `json:"upper,omitempty"`
+}
+
+// MarshalJSON satisfies the json.Marshaler interface. Only the fitted model is serialized, not the training data.
+func (r *Regression) MarshalJSON() ([]byte, error) {
+	if !r.hasRun || len(r.coeff) == 0 {
+		return nil, ErrNotRun
+	}
+	m := model{
+		Observed:          r.names.obs,
+		BaseVars:          r.names.base,
+		Coefficients:      make([]float64, len(r.coeff)),
+		Formula:           r.Formula,
+		R2:                r.R2,
+		VarianceObserved:  r.Varianceobserved,
+		VariancePredicted: r.VariancePredicted,
+		RMSE:              r.rmse,
+		Observations:      r.observations,
+		TrainedAt:         r.trainedAt,
+		DFResidual:        r.dfResidual,
+		Aliased:           r.aliased,
+		Metadata:          r.metadata,
+		DataHash:          r.DataHash(),
+		FixedEffects:      r.fixedEffects,
+		GroupEffects:      r.groupEffects,
+		RandomSlopes:      r.randomSlopes,
+		GroupVariances:    r.groupVariances,
+		Units:             r.names.units,
+		ObservedUnit:      r.names.obsUnit,
+		VarTypes:          r.names.types,
+		Clips:             r.clips,
+		ObservedClip:      r.obsClip,
+		MissingIndicators: r.missing,
+	}
+	for i := range m.Coefficients {
+		m.Coefficients[i] = r.coeff[i]
+	}
+	// the names of the feature crosses are generated from the base variable names on load
+	for i, name := range r.names.vars {
+		if i < r.names.base {
+			if m.Vars == nil {
+				m.Vars = make(map[int]string, len(r.names.vars))
+			}
+			m.Vars[i] = name
+		}
+	}
+	for _, cross := range r.crosses {
+		spec, err := specOf(cross)
+		if err != nil {
+			return nil, err
+		}
+		m.Crosses = append(m.Crosses, spec)
+	}
+	// The residual variance and covariance are undefined (NaN) without residual degrees of freedom.
+	if r.dfResidual > 0 {
+		m.Sigma2 = r.sigma2
+	}
+	if r.covariance != nil && r.dfResidual > 0 {
+		n, _ := r.covariance.Dims()
+		m.Covariance = make([][]float64, n)
+		for i := range m.Covariance {
+			m.Covariance[i] = make([]float64, n)
+			for j := range m.Covariance[i] {
+				m.Covariance[i][j] = r.covariance.At(i, j)
+			}
+		}
+	}
+	if r.normalizes() {
+		m.Normalization = &normalizationModel{Method: r.normalization.String()}
+		for i, n := range r.varNormalization {
+			if m.Normalization.Vars == nil {
+				m.Normalization.Vars = make(map[int]string, len(r.varNormalization))
+			}
+			m.Normalization.Vars[i] = n.String()
+		}
+		if r.scaling != nil {
+			m.Normalization.Center, m.Normalization.Scale = r.scaling.center, r.scaling.scale
+		}
+	}
+	if r.imputation != MeanImputation {
+		m.Imputation = r.imputation.String()
+	}
+	if mo, err := r.inputMoments(); err == nil {
+		m.InputMoments = &momentsModel{Mean: mo.mean, Covariance: mo.cov}
+	}
+	if c := r.censor; c != nil {
+		m.Censoring = &censoringModel{}
+		if !math.IsInf(c.lower, -1) {
+			m.Censoring.Lower = &c.lower
+		}
+		if !math.IsInf(c.upper, 1) {
+			m.Censoring.Upper = &c.upper
+		}
+	}
+	if r.split {
+		m.SplitVar = &r.splitVar
+		m.Segments = make(map[string]*Regression, len(r.segments))
+		for v, s := range r.segments {
+			m.Segments[strconv.FormatFloat(v, 'g', -1, 64)] = s
+		}
+	}
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON satisfies the json.Unmarshaler interface, restoring a model written by MarshalJSON.
+func (r *Regression) UnmarshalJSON(b []byte) error {
+	var m model
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+	if len(m.Coefficients) == 0 {
+		return ErrNotRun
+	}
+
+	crosses := make([]featureCross, 0, len(m.Crosses))
+	for _, spec := range m.Crosses {
+		cross, err := spec.build()
+		if err != nil {
+			return err
+		}
+		crosses = append(crosses, cross)
+	}
+
+	*r = Regression{
+		names:             describe{obs: m.Observed, units: m.Units, obsUnit: m.ObservedUnit, types: m.VarTypes},
+		coeff:             make(map[int]float64, len(m.Coefficients)),
+		crosses:           crosses,
+		Formula:           m.Formula,
+		R2:                m.R2,
+		Varianceobserved:  m.VarianceObserved,
+		VariancePredicted: m.VariancePredicted,
+		rmse:              m.RMSE,
+		observations:      m.Observations,
+		trainedAt:         m.TrainedAt,
+		dfResidual:        m.DFResidual,
+		aliased:           m.Aliased,
+		metadata:          m.Metadata,
+		dataHash:          m.DataHash,
+		fixedEffects:      m.FixedEffects,
+		groupEffects:      m.GroupEffects,
+		randomSlopes:      m.RandomSlopes,
+		groupVariances:    m.GroupVariances,
+		clips:             m.Clips,
+		obsClip:           m.ObservedClip,
+		missing:           m.MissingIndicators,
+		sigma2:            math.NaN(),
+		initialised:       true,
+		hasRun:            true,
+	}
+	for i, c := range m.Coefficients {
+		r.coeff[i] = c
+	}
+	base := m.BaseVars
+	if base == 0 {
+		// models saved before the base variables were recorded also hold the names of the feature crosses
+		base = len(m.Coefficients) - 1
+		for _, cross := range crosses {
+			base -= cross.ExtendNames(make(map[int]string), 0)
+		}
+	}
+	for i, name := range m.Vars {
+		if i < base {
+			r.SetVar(i, name)
+		}
+	}
+	r.extendNames(base)
+	// a coefficient for the offset, every variable, every column of the crosses and every missing indicator
+	if len(m.Coefficients) != 1+base+len(r.names.crosses) {
+		return ErrDimensions
+	}
+	if m.DFResidual > 0 {
+		r.sigma2 = m.Sigma2
+	}
+	if n := len(m.Covariance); n > 0 {
+		if n != len(m.Coefficients) {
+			return ErrDimensions
+		}
+		r.covariance = mat.NewDense(n, n, nil)
+		for i, row := range m.Covariance {
+			if len(row) != n {
+				return ErrDimensions
+			}
+			for j, v := range row {
+				r.covariance.Set(i, j, v)
+			}
+		}
+	}
+	if n := m.Normalization; n != nil {
+		var err error
+		if r.normalization, err = parseNormalization(n.Method); err != nil {
+			return err
+		}
+		for i, method := range n.Vars {
+			v, err := parseNormalization(method)
+			if err != nil {
+				return err
+			}
+			r.SetVarNormalization(i, v)
+		}
+		if len(n.Center) > 0 && len(n.Center) == len(n.Scale) {
+			r.scaling = &scaling{center: n.Center, scale: n.Scale}
+		}
+	}
+	imputation, err := parseImputation(m.Imputation)
+	if err != nil {
+		return err
+	}
+	r.imputation = imputation
+	if mo := m.InputMoments; mo != nil && len(mo.Mean) == r.names.base && len(mo.Covariance) == r.names.base {
+		r.moments = &moments{mean: mo.Mean, cov: mo.Covariance}
+	}
+	if c := m.Censoring; c != nil {
+		r.censor = &censoring{lower: math.Inf(-1), upper: math.Inf(1)}
+		if c.Lower != nil {
+			r.censor.lower = *c.Lower
+		}
+		if c.Upper != nil {
+			r.censor.upper = *c.Upper
+		}
+	}
+	if m.SplitVar != nil {
+		r.SplitByVar(*m.SplitVar)
+		r.segments = make(map[float64]*Regression, len(m.Segments))
+		for k, s := range m.Segments {
+			v, err := strconv.ParseFloat(k, 64)
+			if err != nil {
+				return err
+			}
+			r.segments[v] = s
+		}
+	}
+	return nil
+}
+
+// Save writes the fitted model to w as JSON.
+func (r *Regression) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(r)
+}
+
+// Load reads a model previously written with Save. The loaded model can be used for predictions
+// and inference but holds no training data.
+func Load(rd io.Reader) (*Regression, error) {
+	r := new(Regression)
+	if err := json.NewDecoder(rd).Decode(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
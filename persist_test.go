@@ -0,0 +1,131 @@
+package regression
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestSaveLoad(t *testing.T) {
+	r := new(Regression)
+	r.SetObserved("dist")
+	r.SetVar(0, "speed")
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	r.AddCross(PowCross(0, 2))
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := r.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, _ := r.Predict([]float64{21})
+	got, err := loaded.Predict([]float64{21})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "prediction", got, want, 1e-12)
+
+	wantSE, _ := r.PredictSE([]float64{21})
+	gotSE, _ := loaded.PredictSE([]float64{21})
+	assertClose(t, "prediction stderr", gotSE, wantSE, 1e-12)
+	assertClose(t, "p-value", loaded.PValue(2), r.PValue(2), 1e-12)
+	assertClose(t, "F", loaded.FStat(), r.FStat(), 1e-9)
+
+	if loaded.Formula != r.Formula || loaded.GetObserved() != "dist" || loaded.GetVar(1) != "(speed)^2" {
+		t.Error("Expected names and formula to be restored")
+	}
+}
+
+type customCross struct{}
+
+func (customCross) Calculate(vars []float64) []float64             { return []float64{vars[0] + 1} }
+func (customCross) ExtendNames(names map[int]string, size int) int { return 1 }
+
+func TestSaveErrors(t *testing.T) {
+	var buf bytes.Buffer
+	if err := new(Regression).Save(&buf); err == nil {
+		t.Error("Expected an error saving a model that has not been run")
+	}
+
+	r := carsRegression(t)
+	r.crosses = append(r.crosses, customCross{})
+	if err := r.Save(&buf); err == nil {
+		t.Error("Expected an error saving a custom cross")
+	}
+}
+
+func TestSaveLoadNames(t *testing.T) {
+	r := new(Regression)
+	r.SetObserved("dist")
+	r.SetVar(0, "speed")
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	r.AddCross(PowCross(0, 2))
+	r.AddCross(PowCross(0, 3))
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	var first, second bytes.Buffer
+	if err := r.Save(&first); err != nil {
+		t.Fatal(err)
+	}
+	saved := first.String()
+	loaded, err := Load(&first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// re-applying the crosses doesn't add names
+	loaded.extendNames(1)
+	if err := loaded.Save(&second); err != nil {
+		t.Fatal(err)
+	}
+	if second.String() != saved {
+		t.Errorf("Expected repeated Save/Load cycles to be idempotent:\n%s\n%s", saved, second.String())
+	}
+	if len(loaded.names.vars) != 1 || loaded.GetVar(1) != "(speed)^2" || loaded.GetVar(2) != "(speed)^3" {
+		t.Errorf("Unexpected names %v and %v", loaded.names.vars, loaded.names.crosses)
+	}
+}
+
+func TestLoadLegacyNames(t *testing.T) {
+	// models saved before base_vars was recorded list the cross names with the variables
+	legacy := `{"observed":"dist","vars":{"0":"speed","1":"(speed)^2"},"coefficients":[2.47,0.91,0.1],` +
+		`"crosses":[{"type":"pow","vars":[0],"power":2}],"formula":"","r2":0.67}`
+	r, err := Load(bytes.NewBufferString(legacy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.names.vars) != 1 || r.GetVar(0) != "speed" || r.GetVar(1) != "(speed)^2" {
+		t.Errorf("Unexpected names %v and %v", r.names.vars, r.names.crosses)
+	}
+	if p, _ := r.Predict([]float64{10}); math.Abs(p-(2.47+9.1+10)) > 1e-9 {
+		t.Errorf("Unexpected prediction %v", p)
+	}
+}
+
+func TestLoadDimensionErrors(t *testing.T) {
+	for name, saved := range map[string]string{
+		// the cross adds a column, so 2 coefficients are one short
+		"coefficients": `{"vars":{"0":"speed"},"base_vars":1,"coefficients":[2.47,0.91],` +
+			`"crosses":[{"type":"pow","vars":[0],"power":2}]}`,
+		"ragged covariance": `{"vars":{"0":"speed"},"base_vars":1,"coefficients":[2.47,0.91],` +
+			`"covariance":[[1,0],[0]]}`,
+		"covariance size": `{"vars":{"0":"speed"},"base_vars":1,"coefficients":[2.47,0.91],` +
+			`"covariance":[[1]]}`,
+	} {
+		if _, err := Load(bytes.NewBufferString(saved)); err != ErrDimensions {
+			t.Errorf("%s: expected ErrDimensions, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package regression
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// ErrInvalidComponents signals a PLSSolver without a positive number of components.
+var ErrInvalidComponents = errors.New("number of components must be positive")
+
+// WideSolver is implemented by solvers that can fit more variables than observations,
+// so Run doesn't return ErrTooManyVars when they are used.
+type WideSolver interface {
+	Solver
+	FitsWide() bool
+}
+
+func fitsWide(s Solver) bool {
+	w, ok := s.(WideSolver)
+	return ok && w.FitsWide()
+}
+
+// PLSSolver fits partial least squares regression (PLS1) with the given number of latent components,
+// which are the directions in the space of the variables with the largest covariance with the observed
+// value. It works with more variables than observations. With as many components as variables it
+// gives the least squares fit. PLS depends on the scale of the variables, so it is usually combined
+// with ZScore normalization. The X loadings of the components are available from Loadings.
+// Standard errors are not available. Solve fails with ErrInvalidComponents unless Components is positive.
+type PLSSolver struct {
+	Components int
+}
+
+// FitsWide satisfies the WideSolver interface.
+func (PLSSolver) FitsWide() bool {
+	return true
+}
+
+// Solve satisfies the Solver interface.
+func (s PLSSolver) Solve(x, y *mat.Dense) ([]float64, *Diagnostics, error) {
+	if s.Components <= 0 {
+		return nil, nil, ErrInvalidComponents
+	}
+	rows, cols := x.Dims()
+	p := cols - 1
+
+	// project the offset out of the variables and the observed value
+	offset := make([]float64, rows)
+	var oo float64
+	for i := range offset {
+		offset[i] = x.At(i, 0)
+		oo += offset[i] * offset[i]
+	}
+	if oo == 0 {
+		return nil, nil, ErrSingular
+	}
+	center := func(v []float64) {
+		var ov float64
+		for i := range v {
+			ov += offset[i] * v[i]
+		}
+		for i := range v {
+			v[i] -= offset[i] * ov / oo
+		}
+	}
+	e := make([][]float64, p)
+	for j := range e {
+		e[j] = make([]float64, rows)
+		for i := range e[j] {
+			e[j][i] = x.At(i, j+1)
+		}
+		center(e[j])
+	}
+	f := make([]float64, rows)
+	for i := range f {
+		f[i] = y.At(i, 0)
+	}
+	center(f)
+
+	// NIPALS: extract components until the requested number or the residuals are exhausted
+	var weights, loadings [][]float64
+	var q []float64
+	for a := 0; a < s.Components && a < p; a++ {
+		w := make([]float64, p)
+		var norm float64
+		for j := range w {
+			w[j] = dot(e[j], f)
+			norm = math.Hypot(norm, w[j])
+		}
+		if norm == 0 {
+			break
+		}
+		t := make([]float64, rows)
+		for j := range w {
+			w[j] /= norm
+			for i := range t {
+				t[i] += e[j][i] * w[j]
+			}
+		}
+		tt := dot(t, t)
+		if tt <= aliasTolerance*aliasTolerance*norm*norm {
+			break
+		}
+		load := make([]float64, p)
+		for j := range load {
+			load[j] = dot(e[j], t) / tt
+			for i := range t {
+				e[j][i] -= t[i] * load[j]
+			}
+		}
+		qa := dot(f, t) / tt
+		for i := range f {
+			f[i] -= t[i] * qa
+		}
+		weights = append(weights, w)
+		loadings = append(loadings, load)
+		q = append(q, qa)
+	}
+
+	coeffs := make([]float64, cols)
+	var loadingsOut *mat.Dense
+	if n := len(q); n > 0 {
+		// coefficients W (P'W)^-1 q
+		ptw := mat.NewDense(n, n, nil)
+		for a := 0; a < n; a++ {
+			for b := 0; b < n; b++ {
+				ptw.Set(a, b, dot(loadings[a], weights[b]))
+			}
+		}
+		z := new(mat.Dense)
+		if err := z.Solve(ptw, mat.NewDense(n, 1, q)); err != nil {
+			return nil, nil, err
+		}
+		loadingsOut = mat.NewDense(p, n, nil)
+		for j := 0; j < p; j++ {
+			for a := 0; a < n; a++ {
+				coeffs[j+1] += weights[a][j] * z.At(a, 0)
+				loadingsOut.Set(j, a, loadings[a][j])
+			}
+		}
+	}
+
+	// the offset fits what the variables leave
+	var residual float64
+	for i := 0; i < rows; i++ {
+		v := y.At(i, 0)
+		for j := 1; j < cols; j++ {
+			v -= coeffs[j] * x.At(i, j)
+		}
+		residual += offset[i] * v
+	}
+	coeffs[0] = residual / oo
+	return coeffs, &Diagnostics{Loadings: loadingsOut}, nil
+}
+
+func dot(a, b []float64) float64 {
+	var s float64
+	for i := range a {
+		s += a[i] * b[i]
+	}
+	return s
+}
+
+// Loadings returns the X loadings of the latent components of a fit with a solver that reports them,
+// such as PLSSolver: a row per variable and feature cross and a column per component.
+// With normalization the loadings are those of the normalized variables. It returns nil for other fits
+// and for loaded models.
+func (r *Regression) Loadings() *mat.Dense {
+	return r.loadings
+}
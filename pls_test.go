@@ -0,0 +1,93 @@
+package regression
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestPLSSolver(t *testing.T) {
+	// with a component per variable PLS is least squares
+	pls := new(Regression)
+	ols := new(Regression)
+	for i := range carsSpeed {
+		vars := []float64{carsSpeed[i], float64(i % 7)}
+		pls.Train(DataPoint(carsDist[i], vars))
+		ols.Train(DataPoint(carsDist[i], vars))
+	}
+	pls.SetSolver(PLSSolver{Components: 2})
+	if err := pls.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if err := ols.Run(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		assertClose(t, "coefficient", pls.Coeff(i), ols.Coeff(i), 1e-8)
+	}
+	if l := pls.Loadings(); l == nil {
+		t.Error("Expected loadings")
+	} else if rows, cols := l.Dims(); rows != 2 || cols != 2 {
+		t.Errorf("Expected 2x2 loadings, got %dx%d", rows, cols)
+	}
+	if ols.Loadings() != nil {
+		t.Error("Expected no loadings for least squares")
+	}
+}
+
+func TestPLSSolverComponents(t *testing.T) {
+	for _, n := range []int{0, -1} {
+		r := new(Regression)
+		for i := range carsSpeed {
+			r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+		}
+		r.SetSolver(PLSSolver{Components: n})
+		if err := r.Run(); err != ErrInvalidComponents {
+			t.Errorf("%d components: expected ErrInvalidComponents, got %v", n, err)
+		}
+	}
+}
+
+func TestPLSSolverWide(t *testing.T) {
+	// 30 variables driven by 2 latent factors, but only 12 observations
+	rnd := rand.New(rand.NewSource(5))
+	r := new(Regression)
+	r.SetSolver(PLSSolver{Components: 2})
+	r.SetNormalization(ZScore)
+	var points [][]float64
+	var observed []float64
+	for i := 0; i < 12; i++ {
+		f1, f2 := rnd.NormFloat64(), rnd.NormFloat64()
+		vars := make([]float64, 30)
+		for j := range vars {
+			vars[j] = f1*float64(j%3) + f2*float64(j%5) + 0.01*rnd.NormFloat64()
+		}
+		y := 3 + 2*f1 - f2
+		points = append(points, vars)
+		observed = append(observed, y)
+		r.Train(DataPoint(y, append([]float64(nil), vars...)))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatalf("Expected PLS to fit more variables than observations, got %v", err)
+	}
+	if rows, cols := r.Loadings().Dims(); rows != 30 || cols != 2 {
+		t.Errorf("Expected 30x2 loadings, got %dx%d", rows, cols)
+	}
+	for i, vars := range points {
+		p, _ := r.Predict(vars)
+		if math.Abs(p-observed[i]) > 0.1 {
+			t.Errorf("Expected a close fit of %v, got %v", observed[i], p)
+		}
+	}
+	if !math.IsNaN(r.StdErr(1)) {
+		t.Error("Expected no standard errors")
+	}
+
+	ols := new(Regression)
+	for i, vars := range points {
+		ols.Train(DataPoint(observed[i], vars))
+	}
+	if err := ols.Run(); err != ErrTooManyVars {
+		t.Errorf("Expected ErrTooManyVars without PLS, got %v", err)
+	}
+}
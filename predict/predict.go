@@ -0,0 +1,275 @@
+// Package predict evaluates models fitted with github.com/sajari/regression. It has no dependencies beyond
+// a few small standard library packages and uses neither reflection nor a matrix library, so it builds with
+// TinyGo and for WebAssembly, e.g. to evaluate models in browsers and edge workers.
+//
+// Models are exported with Regression.Predictor and moved around in the binary format of MarshalBinary.
+package predict
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+var (
+	// ErrDimensions signals that the number of variables doesn't match the model.
+	ErrDimensions = errors.New("predict: wrong number of variables")
+	// ErrInvalid signals that a model is malformed or its encoding is corrupt.
+	ErrInvalid = errors.New("predict: invalid model")
+)
+
+// CrossType is the kind of a feature cross.
+type CrossType uint8
+
+const (
+	// Pow raises its variable to Power.
+	Pow CrossType = iota + 1
+	// Multiplier multiplies its variables.
+	Multiplier
+)
+
+// Cross is a feature cross, appended to the variables in order. Vars index the variables and
+// the preceding crosses.
+type Cross struct {
+	Type  CrossType
+	Vars  []int
+	Power float64
+}
+
+// Segment is the model used when the split variable equals Value.
+type Segment struct {
+	Value float64
+	Model *Model
+}
+
+// Model is a fitted linear model.
+type Model struct {
+	// Coefficients are the offset followed by a coefficient per variable and feature cross.
+	Coefficients []float64
+	Crosses      []Cross
+	// SplitVar is the variable that selects a segment, or -1 without segments.
+	SplitVar int
+	// Segments are per-segment models, used instead of this one for a matching split variable.
+	Segments []Segment
+}
+
+// Vars returns the number of variables the model is evaluated on.
+func (m *Model) Vars() int {
+	return len(m.Coefficients) - 1 - len(m.Crosses)
+}
+
+// Predict returns the prediction for vars.
+func (m *Model) Predict(vars []float64) (float64, error) {
+	if len(vars) != m.Vars() {
+		return 0, ErrDimensions
+	}
+	if m.SplitVar >= 0 && m.SplitVar < len(vars) {
+		for _, s := range m.Segments {
+			if s.Value == vars[m.SplitVar] {
+				return s.Model.Predict(vars)
+			}
+		}
+	}
+
+	p := m.Coefficients[0]
+	row := make([]float64, len(vars), len(m.Coefficients)-1)
+	copy(row, vars)
+	for _, c := range m.Crosses {
+		v, err := c.value(row)
+		if err != nil {
+			return 0, err
+		}
+		row = append(row, v)
+	}
+	for j, v := range row {
+		p += m.Coefficients[j+1] * v
+	}
+	return p, nil
+}
+
+func (c Cross) value(row []float64) (float64, error) {
+	for _, i := range c.Vars {
+		if i < 0 || i >= len(row) {
+			return 0, ErrInvalid
+		}
+	}
+	switch c.Type {
+	case Pow:
+		if len(c.Vars) != 1 {
+			return 0, ErrInvalid
+		}
+		return math.Pow(row[c.Vars[0]], c.Power), nil
+	case Multiplier:
+		v := 1.0
+		for _, i := range c.Vars {
+			v *= row[i]
+		}
+		return v, nil
+	}
+	return 0, ErrInvalid
+}
+
+// magic starts the binary encoding of a model, followed by a version byte.
+const magic = "RGP"
+
+const version = 1
+
+// MarshalBinary encodes the model in a compact binary format.
+func (m *Model) MarshalBinary() ([]byte, error) {
+	b := append([]byte(magic), version)
+	return m.append(b), nil
+}
+
+func (m *Model) append(b []byte) []byte {
+	b = appendUvarint(b, uint64(len(m.Coefficients)))
+	for _, c := range m.Coefficients {
+		b = appendFloat(b, c)
+	}
+	b = appendUvarint(b, uint64(len(m.Crosses)))
+	for _, c := range m.Crosses {
+		b = append(b, byte(c.Type))
+		b = appendUvarint(b, uint64(len(c.Vars)))
+		for _, v := range c.Vars {
+			b = appendUvarint(b, uint64(v))
+		}
+		b = appendFloat(b, c.Power)
+	}
+	b = appendVarint(b, int64(m.SplitVar))
+	b = appendUvarint(b, uint64(len(m.Segments)))
+	for _, s := range m.Segments {
+		b = appendFloat(b, s.Value)
+		b = s.Model.append(b)
+	}
+	return b
+}
+
+// UnmarshalBinary decodes a model encoded with MarshalBinary.
+func (m *Model) UnmarshalBinary(b []byte) error {
+	if len(b) < len(magic)+1 || string(b[:len(magic)]) != magic || b[len(magic)] != version {
+		return ErrInvalid
+	}
+	d := decoder{b: b[len(magic)+1:]}
+	d.model(m)
+	if d.err != nil || len(d.b) != 0 {
+		return ErrInvalid
+	}
+	return nil
+}
+
+// Decode decodes a model encoded with MarshalBinary.
+func Decode(b []byte) (*Model, error) {
+	m := new(Model)
+	if err := m.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+type decoder struct {
+	b   []byte
+	err error
+}
+
+func (d *decoder) model(m *Model) {
+	n := d.count(8)
+	m.Coefficients = make([]float64, n)
+	for i := range m.Coefficients {
+		m.Coefficients[i] = d.float()
+	}
+	n = d.count(10)
+	m.Crosses = make([]Cross, n)
+	for i := range m.Crosses {
+		c := &m.Crosses[i]
+		c.Type = CrossType(d.byte())
+		c.Vars = make([]int, d.count(1))
+		for j := range c.Vars {
+			c.Vars[j] = int(d.uvarint())
+		}
+		c.Power = d.float()
+	}
+	m.SplitVar = int(d.varint())
+	n = d.count(9)
+	m.Segments = make([]Segment, n)
+	for i := range m.Segments {
+		m.Segments[i].Value = d.float()
+		m.Segments[i].Model = new(Model)
+		d.model(m.Segments[i].Model)
+	}
+	if d.err == nil && (len(m.Coefficients) == 0 || m.Vars() < 0) {
+		d.err = ErrInvalid
+	}
+}
+
+// count reads a length, checking that the remaining input can hold that many items of at least size bytes.
+func (d *decoder) count(size int) int {
+	n := d.uvarint()
+	if d.err == nil && n > uint64(len(d.b)/size) {
+		d.err = ErrInvalid
+	}
+	if d.err != nil {
+		return 0
+	}
+	return int(n)
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.b)
+	if n <= 0 {
+		d.err = ErrInvalid
+		return 0
+	}
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *decoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.b)
+	if n <= 0 {
+		d.err = ErrInvalid
+		return 0
+	}
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *decoder) byte() byte {
+	if d.err != nil || len(d.b) < 1 {
+		d.err = ErrInvalid
+		return 0
+	}
+	v := d.b[0]
+	d.b = d.b[1:]
+	return v
+}
+
+func (d *decoder) float() float64 {
+	if d.err != nil || len(d.b) < 8 {
+		d.err = ErrInvalid
+		return 0
+	}
+	v := math.Float64frombits(binary.LittleEndian.Uint64(d.b))
+	d.b = d.b[8:]
+	return v
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], v)]...)
+}
+
+func appendVarint(b []byte, v int64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutVarint(buf[:], v)]...)
+}
+
+func appendFloat(b []byte, v float64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], math.Float64bits(v))
+	return append(b, buf[:]...)
+}
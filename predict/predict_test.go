@@ -0,0 +1,82 @@
+package predict
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPredict(t *testing.T) {
+	m := &Model{
+		Coefficients: []float64{1, 2, 3, 0.5, -1},
+		Crosses: []Cross{
+			{Type: Pow, Vars: []int{0}, Power: 2},
+			{Type: Multiplier, Vars: []int{1, 2}},
+		},
+		SplitVar: -1,
+	}
+	if m.Vars() != 2 {
+		t.Fatalf("Expected 2 variables, got %d", m.Vars())
+	}
+	// 1 + 2*2 + 3*5 + 0.5*4 - 1*5*4, the second cross multiplies a variable with the first cross
+	p, err := m.Predict([]float64{2, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p != 2 {
+		t.Errorf("Expected 2, got %v", p)
+	}
+	if _, err := m.Predict([]float64{1}); err != ErrDimensions {
+		t.Errorf("Expected ErrDimensions, got %v", err)
+	}
+}
+
+func TestSegments(t *testing.T) {
+	m := &Model{
+		Coefficients: []float64{1, 1, 0},
+		SplitVar:     1,
+		Segments: []Segment{
+			{Value: 7, Model: &Model{Coefficients: []float64{10, 2, 0}, SplitVar: -1}},
+		},
+	}
+	if p, _ := m.Predict([]float64{3, 7}); p != 16 {
+		t.Errorf("Expected the segment model, got %v", p)
+	}
+	if p, _ := m.Predict([]float64{3, 8}); p != 4 {
+		t.Errorf("Expected the pooled model, got %v", p)
+	}
+}
+
+func TestBinary(t *testing.T) {
+	m := &Model{
+		Coefficients: []float64{1, -2.5, math.Pi},
+		Crosses:      []Cross{{Type: Pow, Vars: []int{0}, Power: 0.5}},
+		SplitVar:     0,
+		Segments: []Segment{
+			{Value: 2, Model: &Model{Coefficients: []float64{3, 4, 5}, Crosses: []Cross{{Type: Pow, Vars: []int{0}, Power: 2}}, SplitVar: -1}},
+		},
+	}
+	b, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := Decode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, x := range []float64{2, 4} {
+		want, _ := m.Predict([]float64{x})
+		got, err := decoded.Predict([]float64{x})
+		if err != nil || got != want {
+			t.Errorf("Expected %v, got %v (%v)", want, got, err)
+		}
+	}
+
+	for i := 0; i < len(b); i++ {
+		if _, err := Decode(b[:i]); err != ErrInvalid {
+			t.Fatalf("Expected ErrInvalid for a truncated model of %d bytes, got %v", i, err)
+		}
+	}
+	if _, err := Decode(append(b, 0)); err != ErrInvalid {
+		t.Errorf("Expected ErrInvalid for trailing bytes, got %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package regression
+
+import "github.com/sajari/regression/predict"
+
+// Predictor exports the fitted model to the dependency-free predict package, e.g. to evaluate it
+// under TinyGo or WebAssembly. Models with custom feature crosses, missing indicators or winsorized
+// variables cannot be exported.
+func (r *Regression) Predictor() (*predict.Model, error) {
+	if !r.hasRun || len(r.coeff) == 0 {
+		return nil, ErrNotRun
+	}
+	if len(r.missing) > 0 || r.clips != nil {
+		return nil, ErrUnsupported
+	}
+	m := &predict.Model{Coefficients: r.coeffs(), SplitVar: -1}
+	for _, cross := range r.crosses {
+		spec, err := specOf(cross)
+		if err != nil {
+			return nil, err
+		}
+		c := predict.Cross{Vars: append([]int(nil), spec.Vars...), Power: spec.Power}
+		switch spec.Type {
+		case "pow":
+			c.Type = predict.Pow
+		case "multiplier":
+			c.Type = predict.Multiplier
+		default:
+			return nil, ErrCrossNotSerializable
+		}
+		m.Crosses = append(m.Crosses, c)
+	}
+	if r.split {
+		m.SplitVar = r.splitVar
+		for _, v := range r.Segments() {
+			s, err := r.segments[v].Predictor()
+			if err != nil {
+				return nil, err
+			}
+			m.Segments = append(m.Segments, predict.Segment{Value: v, Model: s})
+		}
+	}
+	return m, nil
+}
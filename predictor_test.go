@@ -0,0 +1,49 @@
+package regression
+
+import (
+	"testing"
+
+	"github.com/sajari/regression/predict"
+)
+
+func TestPredictor(t *testing.T) {
+	r := new(Regression)
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i], float64(i % 2), float64(i % 3)}))
+	}
+	r.AddCross(PowCross(0, 2))
+	r.AddCross(MultiplierCross(0, 2))
+	r.SplitByVar(1)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := r.Predictor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := predict.Decode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Segments) != 2 {
+		t.Errorf("Expected 2 segments, got %d", len(decoded.Segments))
+	}
+	for _, vars := range [][]float64{{10, 0, 1}, {21, 1, 2}, {15, 3, 0}} {
+		want, _ := r.Predict(vars)
+		got, err := decoded.Predict(vars)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertClose(t, "prediction", got, want, 1e-12)
+	}
+
+	r.crosses = append(r.crosses, customCross{})
+	if _, err := r.Predictor(); err != ErrCrossNotSerializable {
+		t.Errorf("Expected ErrCrossNotSerializable, got %v", err)
+	}
+}
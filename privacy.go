@@ -0,0 +1,145 @@
+package regression
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/rand"
+	"strconv"
+)
+
+// ErrInvalidPrivacy signals that the privacy parameters don't give a formal guarantee, e.g. a
+// non-positive epsilon or a missing bound.
+var ErrInvalidPrivacy = errors.New("invalid differential privacy parameters")
+
+// Privacy configures PrivateRelease.
+type Privacy struct {
+	// Epsilon is the privacy budget of the release; smaller values add more noise.
+	Epsilon float64
+	// VarBound bounds the absolute value of every design column, the variables and the feature
+	// crosses, and ObsBound that of the observed value. Values beyond them are clipped, so they
+	// should be chosen from domain knowledge rather than from the data.
+	VarBound float64
+	ObsBound float64
+	// RowsPerUser is the maximum number of data points contributed by a user, 1 by default. The
+	// guarantee holds for all the data points of a user, which must not exceed it.
+	RowsPerUser int
+	// Lambda is added to the diagonal of X'X except for the intercept, stabilizing the solution of
+	// the noisy normal equations. It doesn't affect the guarantee.
+	Lambda float64
+	// Seed makes the noise reproducible, for tests only: the noise is drawn from crypto/rand
+	// unless it is set.
+	Seed int64
+}
+
+// PrivateRelease fits the model's training data again with epsilon-differential privacy, returning
+// a model that can be released outside the data boundary. The fit perturbs the objective: the
+// sufficient statistics X'X and X'y are computed with clipped values and unit weights and released
+// with Laplace noise calibrated to their sensitivity, then solved as the normal equations.
+// The released model holds only the coefficients, names, feature crosses and metadata, with the
+// metadata "dp_epsilon" set; fit statistics, the training data and its hash are left out, as they
+// aren't covered by the guarantee. Predict doesn't clip its inputs.
+// The model must have been run. Models with a custom solver, segments, instruments, sign constraints,
+// priors, AutoDropCollinear, fixed or random group effects, censoring, missing indicators,
+// winsorization or normalization are not supported, as the release is an ordinary least squares fit.
+func (r *Regression) PrivateRelease(p Privacy) (*Regression, error) {
+	if !r.hasRun || len(r.coeff) == 0 {
+		return nil, ErrNotRun
+	}
+	if r.solve != nil || r.split || r.instruments != nil || r.signs != nil || r.prior != nil ||
+		r.vifThreshold > 0 || r.fixedEffects != nil || r.groupEffects != nil || r.censor != nil ||
+		len(r.missing) > 0 || r.winsorVars != nil || r.winsorObs != nil || r.normalizes() {
+		return nil, ErrUnsupported
+	}
+	if p.RowsPerUser == 0 {
+		p.RowsPerUser = 1
+	}
+	if !(p.Epsilon > 0) || !(p.VarBound > 0) || !(p.ObsBound > 0) || p.RowsPerUser < 0 || p.Lambda < 0 ||
+		math.IsInf(p.VarBound, 0) || math.IsInf(p.ObsBound, 0) {
+		return nil, ErrInvalidPrivacy
+	}
+	r.DecompressData()
+
+	var a *normalEquations
+	for _, d := range r.data {
+		row := designRow(r.baseVariables(d), r.crosses)
+		for j := 1; j < len(row); j++ {
+			row[j] = math.Max(-p.VarBound, math.Min(p.VarBound, row[j]))
+		}
+		if a == nil {
+			a = newNormalEquations(len(row))
+		}
+		a.add(row, math.Max(-p.ObsBound, math.Min(p.ObsBound, d.Observed)), 1)
+	}
+	if a == nil {
+		return nil, ErrNotEnoughData
+	}
+
+	// the L1 sensitivity of the upper triangle of X'X and of X'y to the rows of a user
+	b, y := p.VarBound, p.ObsBound
+	d := float64(len(a.xty) - 1)
+	sensitivity := 1 + d*b + d*(d+1)/2*b*b + y*(1+d*b)
+	scale := float64(p.RowsPerUser) * sensitivity / p.Epsilon
+	rng := rand.New(privacySource(p.Seed))
+	for i := range a.xtx {
+		a.xty[i] += laplace(rng, scale)
+		for j := i; j < len(a.xtx); j++ {
+			a.xtx[i][j] += laplace(rng, scale)
+		}
+		if i > 0 {
+			a.xtx[i][i] += p.Lambda
+		}
+	}
+	c, _ := a.solve()
+
+	release := &Regression{crosses: append([]featureCross(nil), r.crosses...), initialised: true, hasRun: true, sigma2: math.NaN()}
+	release.SetObserved(r.names.obs)
+	for i := 0; i < r.names.base; i++ {
+		if name, ok := r.names.vars[i]; ok {
+			release.SetVar(i, name)
+		}
+	}
+	release.names.units, release.names.obsUnit, release.names.types = r.names.units, r.names.obsUnit, r.names.types
+	release.extendNames(r.names.base)
+	release.setCoeffs(c)
+	for k, v := range r.metadata {
+		release.SetMetadata(k, v)
+	}
+	release.SetMetadata("dp_epsilon", strconv.FormatFloat(p.Epsilon, 'g', -1, 64))
+	return release, nil
+}
+
+// laplace draws from the Laplace distribution centered at zero with the given scale.
+func laplace(rng *rand.Rand, scale float64) float64 {
+	u := rng.Float64() - 0.5
+	for u == -0.5 {
+		u = rng.Float64() - 0.5
+	}
+	if u < 0 {
+		return scale * math.Log(1+2*u)
+	}
+	return -scale * math.Log(1-2*u)
+}
+
+// privacySource returns a source seeded with seed, or reading crypto/rand if it is zero.
+func privacySource(seed int64) rand.Source {
+	if seed == 0 {
+		return cryptoSource{}
+	}
+	return rand.NewSource(seed)
+}
+
+// cryptoSource is a rand.Source reading crypto/rand, so the noise can't be predicted from a seed.
+type cryptoSource struct{}
+
+func (cryptoSource) Int63() int64 {
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		// crypto/rand only fails when the platform has no source of randomness
+		panic(err)
+	}
+	return int64(binary.LittleEndian.Uint64(b[:]) >> 1)
+}
+
+func (cryptoSource) Seed(int64) {}
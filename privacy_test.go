@@ -0,0 +1,97 @@
+package regression
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestPrivateRelease(t *testing.T) {
+	r := carsRegression(t)
+	r.SetMetadata("owner", "growth")
+
+	// with a large budget the noise is negligible and the coefficients are those of the fit
+	release, err := r.PrivateRelease(Privacy{Epsilon: 1e9, VarBound: 30, ObsBound: 130})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, c := range r.coeffs() {
+		assertClose(t, "coefficient", release.Coeff(i), c, 1e-3)
+	}
+	if release.GetVar(0) != "speed" || release.GetObserved() != "dist" {
+		t.Errorf("Expected the names kept, got %q and %q", release.GetVar(0), release.GetObserved())
+	}
+	if v, _ := release.GetMetadata("dp_epsilon"); v != "1e+09" {
+		t.Errorf("Expected the budget in the metadata, got %q", v)
+	}
+	if v, _ := release.GetMetadata("owner"); v != "growth" {
+		t.Errorf("Expected the metadata kept, got %q", v)
+	}
+	if len(release.data) != 0 || release.R2 != 0 || release.rmse != 0 {
+		t.Errorf("Expected no training data or fit statistics, got %d data points, R2 %v", len(release.data), release.R2)
+	}
+	if _, err := release.Predict([]float64{10}); err != nil {
+		t.Error(err)
+	}
+	var buf bytes.Buffer
+	if err := release.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// a small budget perturbs them, reproducibly with a seed
+	p := Privacy{Epsilon: 1, VarBound: 30, ObsBound: 130, Lambda: 1, Seed: 7}
+	a, err := r.PrivateRelease(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := r.PrivateRelease(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Coeff(1) != b.Coeff(1) {
+		t.Errorf("Expected the seeded noise reproduced, got %v and %v", a.Coeff(1), b.Coeff(1))
+	}
+	if math.Abs(a.Coeff(1)-r.Coeff(1)) < 1e-6 {
+		t.Errorf("Expected noise in the coefficients, got %v", a.Coeff(1))
+	}
+	p.Seed = 0
+	if c, err := r.PrivateRelease(p); err != nil || c.Coeff(1) == a.Coeff(1) {
+		t.Errorf("Expected fresh noise without a seed, got %v, %v", c.Coeff(1), err)
+	}
+}
+
+func TestPrivateReleaseErrors(t *testing.T) {
+	if _, err := new(Regression).PrivateRelease(Privacy{Epsilon: 1, VarBound: 1, ObsBound: 1}); err != ErrNotRun {
+		t.Errorf("Expected ErrNotRun, got %v", err)
+	}
+	r := carsRegression(t)
+	for _, p := range []Privacy{
+		{VarBound: 1, ObsBound: 1},
+		{Epsilon: 1, ObsBound: 1},
+		{Epsilon: 1, VarBound: 1},
+		{Epsilon: 1, VarBound: math.Inf(1), ObsBound: 1},
+		{Epsilon: 1, VarBound: 1, ObsBound: 1, RowsPerUser: -1},
+	} {
+		if _, err := r.PrivateRelease(p); err != ErrInvalidPrivacy {
+			t.Errorf("%+v: expected ErrInvalidPrivacy, got %v", p, err)
+		}
+	}
+
+	r = new(Regression)
+	r.WinsorizeObserved(0.05, 0.95)
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.PrivateRelease(Privacy{Epsilon: 1, VarBound: 30, ObsBound: 130}); err != ErrUnsupported {
+		t.Errorf("Expected ErrUnsupported for data dependent clipping, got %v", err)
+	}
+
+	r = carsRegression(t)
+	r.RequireSign(1, Positive)
+	if _, err := r.PrivateRelease(Privacy{Epsilon: 1, VarBound: 30, ObsBound: 130}); err != ErrUnsupported {
+		t.Errorf("Expected ErrUnsupported for sign constraints, got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"strconv"
+)
+
+// ErrInvalidPrecision signals a number of decimals or bits that coefficients cannot be rounded to.
+var ErrInvalidPrecision = errors.New("invalid precision")
+
+// QuantizeReport describes how rounding the coefficients changed the fit on the training data.
+type QuantizeReport struct {
+	Before FitMetrics
+	// After holds the metrics of the rounded model, which are NaN if the model holds no training data.
+	After FitMetrics
+	// MaxCoeffDelta is the largest absolute change of a coefficient.
+	MaxCoeffDelta float64
+	// MaxPredictionDelta is the largest absolute change of a prediction on the training data.
+	MaxPredictionDelta float64
+}
+
+// Quantize rounds the coefficients to decimals decimal places, e.g. so they read well in reviewed config
+// files, and reports the resulting change of the fit. The R2, variances and RMSE of the model are those of
+// the rounded coefficients afterwards, while standard errors and p-values still describe the original fit.
+// The coefficients of per-segment models are rounded too.
+func (r *Regression) Quantize(decimals int) (*QuantizeReport, error) {
+	if decimals < 0 {
+		return nil, ErrInvalidPrecision
+	}
+	return r.quantize(func(c float64) float64 {
+		// round as the coefficient is printed, so it reads exactly as stored
+		v, _ := strconv.ParseFloat(strconv.FormatFloat(c, 'f', decimals, 64), 64)
+		return v
+	})
+}
+
+// QuantizeBits rounds the coefficients to n significant bits, between 1 and 52, e.g. to compress them well
+// or store them as float32 for n up to 24, and reports the resulting change of the fit as Quantize does.
+func (r *Regression) QuantizeBits(n int) (*QuantizeReport, error) {
+	if n < 1 || n > 52 {
+		return nil, ErrInvalidPrecision
+	}
+	scale := math.Ldexp(1, n)
+	return r.quantize(func(c float64) float64 {
+		frac, exp := math.Frexp(c)
+		frac *= scale
+		if frac < 0 {
+			frac = -math.Floor(-frac + 0.5)
+		} else {
+			frac = math.Floor(frac + 0.5)
+		}
+		return math.Ldexp(frac/scale, exp)
+	})
+}
+
+func (r *Regression) quantize(round func(float64) float64) (*QuantizeReport, error) {
+	if !r.hasRun || len(r.coeff) == 0 {
+		return nil, ErrNotRun
+	}
+	report := &QuantizeReport{
+		Before: FitMetrics{
+			VarianceObserved:  r.Varianceobserved,
+			VariancePredicted: r.VariancePredicted,
+			R2:                r.R2,
+			RMSE:              r.rmse,
+		},
+		After:              FitMetrics{math.NaN(), math.NaN(), math.NaN(), math.NaN()},
+		MaxPredictionDelta: math.NaN(),
+	}
+
+	c := r.coeffs()
+	for i := range c {
+		q := round(c[i])
+		report.MaxCoeffDelta = math.Max(report.MaxCoeffDelta, math.Abs(q-c[i]))
+		c[i] = q
+	}
+	r.setCoeffs(c)
+	for _, s := range r.segments {
+		if _, err := s.quantize(round); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(r.data) > 0 {
+		before := make([]float64, len(r.data))
+		observed := make([]float64, len(r.data))
+		predicted := make([]float64, len(r.data))
+		weights := make([]float64, len(r.data))
+		for i, d := range r.data {
+			before[i] = d.Predicted
+		}
+		r.calcPredicted()
+		r.calcVariance()
+		r.calcR2()
+		report.MaxPredictionDelta = 0
+		for i, d := range r.data {
+			observed[i], predicted[i], weights[i] = d.Observed, d.Predicted, d.Weight
+			report.MaxPredictionDelta = math.Max(report.MaxPredictionDelta, math.Abs(d.Predicted-before[i]))
+		}
+		report.After = metrics(observed, predicted, weights)
+		r.rmse = report.After.RMSE
+	}
+	return report, nil
+}
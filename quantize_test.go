@@ -0,0 +1,64 @@
+package regression
+
+import (
+	"math"
+	"testing"
+)
+
+func TestQuantize(t *testing.T) {
+	r := carsRegression(t)
+	orig := r.coeffs()
+	report, err := r.Quantize(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// -17.5791 + 3.9324*speed
+	if r.Coeff(0) != -17.6 || r.Coeff(1) != 3.9 {
+		t.Errorf("Expected the coefficients to be rounded to one decimal, got %v and %v", r.Coeff(0), r.Coeff(1))
+	}
+	assertClose(t, "max coefficient delta", report.MaxCoeffDelta, math.Abs(3.9-orig[1]), 1e-12)
+	if report.After.RMSE < report.Before.RMSE || report.After.RMSE > report.Before.RMSE*1.01 {
+		t.Errorf("Expected a slight degradation, got RMSE %v before and %v after", report.Before.RMSE, report.After.RMSE)
+	}
+	if report.MaxPredictionDelta <= 0 || report.MaxPredictionDelta > 0.1*25+0.05 {
+		t.Errorf("Unexpected prediction delta %v", report.MaxPredictionDelta)
+	}
+	if r.RMSE() != report.After.RMSE || r.R2 != report.After.R2 {
+		t.Errorf("Expected the model to report the metrics of the rounded coefficients")
+	}
+
+	if _, err := r.Quantize(-1); err != ErrInvalidPrecision {
+		t.Errorf("Expected ErrInvalidPrecision, got %v", err)
+	}
+}
+
+func TestQuantizeBits(t *testing.T) {
+	r := carsRegression(t)
+	orig := r.coeffs()
+	report, err := r.QuantizeBits(24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, c := range orig {
+		if got := r.Coeff(i); float64(float32(c)) != got {
+			t.Errorf("Expected coefficient %d to be exactly representable as float32 %v, got %v", i, float32(c), got)
+		}
+	}
+	if report.MaxCoeffDelta == 0 || report.MaxCoeffDelta > 1e-6 {
+		t.Errorf("Unexpected coefficient delta %v", report.MaxCoeffDelta)
+	}
+
+	loaded := new(Regression)
+	*loaded = *r
+	loaded.data = nil
+	report, err = loaded.QuantizeBits(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !math.IsNaN(report.After.RMSE) || !math.IsNaN(report.MaxPredictionDelta) {
+		t.Errorf("Expected NaN metrics without training data, got %+v", report)
+	}
+	if _, err := r.QuantizeBits(0); err != ErrInvalidPrecision {
+		t.Errorf("Expected ErrInvalidPrecision, got %v", err)
+	}
+}
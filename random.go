@@ -0,0 +1,25 @@
+package regression
+
+import "math/rand"
+
+// DefaultSeed seeds the source of randomness of a model unless SetSeed or SetRandSource is used,
+// so identical inputs always give bit-identical results.
+const DefaultSeed = 1
+
+// SetRandSource sets the source of randomness used by stochastic features, such as the
+// holdout sampling of Update.
+func (r *Regression) SetRandSource(src rand.Source) {
+	r.rng = rand.New(src)
+}
+
+// SetSeed seeds the source of randomness used by stochastic features.
+func (r *Regression) SetSeed(seed int64) {
+	r.SetRandSource(rand.NewSource(seed))
+}
+
+func (r *Regression) random() *rand.Rand {
+	if r.rng == nil {
+		r.SetSeed(DefaultSeed)
+	}
+	return r.rng
+}
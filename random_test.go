@@ -0,0 +1,56 @@
+package regression
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// holdoutAfterUpdates returns the holdout points selected while updating a cars model.
+func holdoutAfterUpdates(t *testing.T, seed func(r *Regression)) []float64 {
+	r := carsRegression(t)
+	seed(r)
+	r.SetHoldout(20, 0.3, 0.1)
+	for i := 0; i < 100; i++ {
+		if err := r.Update(DataPoint(float64(i), []float64{float64(i)})); err != nil {
+			t.Fatal(err)
+		}
+	}
+	held := make([]float64, len(r.online.holdout))
+	for i, p := range r.online.holdout {
+		held[i] = p.Observed
+	}
+	return held
+}
+
+func equalFloats(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSeed(t *testing.T) {
+	unseeded := func(r *Regression) {}
+	if a, b := holdoutAfterUpdates(t, unseeded), holdoutAfterUpdates(t, unseeded); !equalFloats(a, b) {
+		t.Errorf("Expected the default seed to be deterministic, got %v and %v", a, b)
+	}
+
+	seed := func(s int64) func(r *Regression) { return func(r *Regression) { r.SetSeed(s) } }
+	a, b := holdoutAfterUpdates(t, seed(7)), holdoutAfterUpdates(t, seed(7))
+	if !equalFloats(a, b) {
+		t.Errorf("Expected identical holdouts for the same seed, got %v and %v", a, b)
+	}
+	if c := holdoutAfterUpdates(t, seed(8)); equalFloats(a, c) {
+		t.Errorf("Expected different holdouts for different seeds, got %v", c)
+	}
+
+	source := func(r *Regression) { r.SetRandSource(rand.NewSource(7)) }
+	if c := holdoutAfterUpdates(t, source); !equalFloats(a, c) {
+		t.Errorf("Expected SetRandSource to match SetSeed, got %v and %v", a, c)
+	}
+}
@@ -0,0 +1,83 @@
+package regression
+
+import (
+	"encoding/csv"
+	"io"
+	"math"
+	"strconv"
+)
+
+// CoefficientRow is a row of the coefficient table of R's summary(lm(...)).
+type CoefficientRow struct {
+	Name     string
+	Estimate float64
+	StdErr   float64
+	TValue   float64
+	PValue   float64
+	// Aliased is set for coefficients left out of the fit, which R reports as NA.
+	Aliased bool
+}
+
+// CoefficientTable returns the coefficients with their standard errors, t values and p-values,
+// like coef(summary(fit)) in R. The offset is named "(Intercept)".
+func (r *Regression) CoefficientTable() ([]CoefficientRow, error) {
+	if !r.hasRun || len(r.coeff) == 0 {
+		return nil, ErrNotRun
+	}
+	aliased := make(map[int]bool, len(r.aliased))
+	for _, i := range r.aliased {
+		aliased[i] = true
+	}
+	names := r.coeffNames()
+	names[0] = "(Intercept)"
+	rows := make([]CoefficientRow, len(names))
+	for i, name := range names {
+		rows[i] = CoefficientRow{Name: name, Aliased: aliased[i]}
+		if aliased[i] {
+			rows[i].Estimate, rows[i].StdErr, rows[i].TValue, rows[i].PValue = math.NaN(), math.NaN(), math.NaN(), math.NaN()
+			continue
+		}
+		rows[i].Estimate = r.Coeff(i)
+		rows[i].StdErr = r.StdErr(i)
+		rows[i].TValue = r.TStat(i)
+		rows[i].PValue = r.PValue(i)
+	}
+	return rows, nil
+}
+
+// WriteCoefficientsCSV writes the coefficient table as CSV in the layout of R's write.csv(coef(summary(fit))),
+// so it can be compared with R using read.csv(file, row.names = 1). Unavailable values are written as NA.
+func (r *Regression) WriteCoefficientsCSV(w io.Writer) error {
+	rows, err := r.CoefficientTable()
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"", "Estimate", "Std. Error", "t value", "Pr(>|t|)"}); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := []string{row.Name}
+		for _, v := range []float64{row.Estimate, row.StdErr, row.TValue, row.PValue} {
+			record = append(record, formatR(v))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatR formats a number as R reads it, with NA for unavailable values.
+func formatR(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NA"
+	case math.IsInf(v, 1):
+		return "Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
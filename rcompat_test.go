@@ -0,0 +1,138 @@
+package regression
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// rGolden is the summary of an R lm() fit, with the precision R prints.
+type rGolden struct {
+	name      string
+	observed  []float64
+	vars      [][]float64
+	crosses   []featureCross
+	estimates []float64
+	stdErrs   []float64
+	tValues   []float64
+	pValues   []float64
+	sigma     float64
+	r2        float64
+	f         float64
+	fp        float64
+}
+
+var (
+	womenHeight = []float64{58, 59, 60, 61, 62, 63, 64, 65, 66, 67, 68, 69, 70, 71, 72}
+	womenWeight = []float64{115, 117, 120, 123, 126, 129, 132, 135, 139, 142, 146, 150, 154, 159, 164}
+)
+
+func columns1(x []float64) [][]float64 {
+	vars := make([][]float64, len(x))
+	for i, v := range x {
+		vars[i] = []float64{v}
+	}
+	return vars
+}
+
+var rGoldens = []rGolden{
+	{
+		name:     "lm(dist ~ speed, data = cars)",
+		observed: carsDist, vars: columns1(carsSpeed),
+		estimates: []float64{-17.5791, 3.9324},
+		stdErrs:   []float64{6.7584, 0.4155},
+		tValues:   []float64{-2.601, 9.464},
+		pValues:   []float64{0.0123, 1.49e-12},
+		sigma:     15.38, r2: 0.6511, f: 89.57, fp: 1.49e-12,
+	},
+	{
+		name:     "lm(dist ~ speed + I(speed^2), data = cars)",
+		observed: carsDist, vars: columns1(carsSpeed),
+		crosses:   []featureCross{PowCross(0, 2)},
+		estimates: []float64{2.47014, 0.91329, 0.09996},
+		stdErrs:   []float64{14.81716, 2.03422, 0.06597},
+		tValues:   []float64{0.167, 0.449, 1.515},
+		pValues:   []float64{0.868, 0.656, 0.136},
+		sigma:     15.18, r2: 0.6673, f: 47.14, fp: 5.852e-12,
+	},
+	{
+		name:     "lm(weight ~ height, data = women)",
+		observed: womenWeight, vars: columns1(womenHeight),
+		estimates: []float64{-87.51667, 3.45000},
+		stdErrs:   []float64{5.93694, 0.09114},
+		tValues:   []float64{-14.74, 37.85},
+		pValues:   []float64{1.71e-09, 1.09e-14},
+		sigma:     1.525, r2: 0.991, f: 1433, fp: 1.091e-14,
+	},
+}
+
+// closeR compares got with a value as R printed it, allowing for a unit in the last printed digit
+// as R rounds some columns twice.
+func closeR(t *testing.T, name string, got, want float64) {
+	// the number of significant digits and the exponent of the printed value
+	e := strconv.FormatFloat(want, 'e', -1, 64)
+	mantissa, exponent := e[:strings.Index(e, "e")], e[strings.Index(e, "e")+1:]
+	digits := len(strings.Trim(strings.Replace(mantissa, ".", "", 1), "-"))
+	exp, _ := strconv.Atoi(exponent)
+	tol := math.Pow(10, float64(exp-digits+1)) * (1 + 1e-9)
+	if math.Abs(got-want) > tol {
+		t.Errorf("%s: expected %v, got %v", name, want, got)
+	}
+}
+
+func TestRGoldens(t *testing.T) {
+	for _, g := range rGoldens {
+		r := new(Regression)
+		for i, vars := range g.vars {
+			r.Train(DataPoint(g.observed[i], append([]float64(nil), vars...)))
+		}
+		for _, c := range g.crosses {
+			r.AddCross(c)
+		}
+		if err := r.Run(); err != nil {
+			t.Fatalf("%s: %v", g.name, err)
+		}
+		rows, err := r.CoefficientTable()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, row := range rows {
+			closeR(t, g.name+" estimate", row.Estimate, g.estimates[i])
+			closeR(t, g.name+" stderr", row.StdErr, g.stdErrs[i])
+			closeR(t, g.name+" t value", row.TValue, g.tValues[i])
+			closeR(t, g.name+" p-value", row.PValue, g.pValues[i])
+		}
+		_, residual := r.DegreesOfFreedom()
+		closeR(t, g.name+" sigma", r.RMSE()*math.Sqrt(float64(len(g.observed))/float64(residual)), g.sigma)
+		closeR(t, g.name+" R2", r.R2, g.r2)
+		closeR(t, g.name+" F", r.FStat(), g.f)
+		closeR(t, g.name+" F p-value", r.FPValue(), g.fp)
+	}
+}
+
+func TestWriteCoefficientsCSV(t *testing.T) {
+	r := new(Regression)
+	r.SetVar(0, "speed")
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i], 2 * carsSpeed[i]}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := r.WriteCoefficientsCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 || lines[0] != `,Estimate,Std. Error,t value,Pr(>|t|)` {
+		t.Fatalf("Unexpected CSV %s", buf.String())
+	}
+	if !strings.HasPrefix(lines[1], "(Intercept),-17.579") || !strings.HasPrefix(lines[2], "speed,3.932") {
+		t.Errorf("Unexpected coefficients %s", buf.String())
+	}
+	if lines[3] != "X1,NA,NA,NA,NA" {
+		t.Errorf("Expected the aliased coefficient as NA, got %s", lines[3])
+	}
+}
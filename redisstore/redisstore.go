@@ -0,0 +1,194 @@
+// Package redisstore stores models fitted with github.com/sajari/regression in Redis and notifies
+// subscribers over pub/sub when a new version is saved, so a fleet of scoring services reloads a model
+// as soon as a trainer publishes it.
+//
+// The package doesn't depend on a Redis client. Any client is adapted by implementing Client,
+// e.g. for github.com/redis/go-redis:
+//
+//	type goRedis struct{ *redis.Client }
+//
+//	func (c goRedis) Get(ctx context.Context, key string) ([]byte, error) {
+//		b, err := c.Client.Get(ctx, key).Bytes()
+//		if err == redis.Nil {
+//			return nil, nil
+//		}
+//		return b, err
+//	}
+//
+//	func (c goRedis) Set(ctx context.Context, key string, value []byte) error {
+//		return c.Client.Set(ctx, key, value, 0).Err()
+//	}
+//
+//	func (c goRedis) Publish(ctx context.Context, channel, message string) error {
+//		return c.Client.Publish(ctx, channel, message).Err()
+//	}
+//
+//	func (c goRedis) Subscribe(ctx context.Context, channel string) (<-chan string, error) {
+//		sub := c.Client.Subscribe(ctx, channel)
+//		if _, err := sub.Receive(ctx); err != nil {
+//			return nil, err
+//		}
+//		msgs := make(chan string)
+//		go func() {
+//			defer close(msgs)
+//			defer sub.Close()
+//			for {
+//				select {
+//				case m := <-sub.Channel():
+//					msgs <- m.Payload
+//				case <-ctx.Done():
+//					return
+//				}
+//			}
+//		}()
+//		return msgs, nil
+//	}
+package redisstore
+
+import (
+	"bytes"
+	"context"
+	"sync/atomic"
+
+	"github.com/sajari/regression"
+)
+
+// Client is the subset of a Redis client used by Store.
+type Client interface {
+	// Get returns the value of key, or a nil value and no error if key doesn't exist.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set sets the value of key.
+	Set(ctx context.Context, key string, value []byte) error
+	// Publish posts message to channel.
+	Publish(ctx context.Context, channel, message string) error
+	// Subscribe returns the messages posted to channel once the subscription is active.
+	// The channel is closed when ctx is done or the subscription fails.
+	Subscribe(ctx context.Context, channel string) (<-chan string, error)
+}
+
+// Store is a regression.Storage keeping models in Redis. Saving a model publishes its key
+// on the updates channel.
+type Store struct {
+	client Client
+	prefix string
+}
+
+var _ regression.Storage = (*Store)(nil)
+
+// New creates a Store. Models are stored under prefix followed by their key, and updates
+// are published on the channel prefix followed by "updates".
+func New(client Client, prefix string) *Store {
+	return &Store{client: client, prefix: prefix}
+}
+
+func (s *Store) channel() string {
+	return s.prefix + "updates"
+}
+
+// Save stores r under key and notifies the subscribers of the updates channel.
+func (s *Store) Save(key string, r *regression.Regression) error {
+	if key == "" {
+		return regression.ErrInvalidKey
+	}
+	var buf bytes.Buffer
+	if err := r.Save(&buf); err != nil {
+		return err
+	}
+	ctx := context.Background()
+	if err := s.client.Set(ctx, s.prefix+key, buf.Bytes()); err != nil {
+		return err
+	}
+	return s.client.Publish(ctx, s.channel(), key)
+}
+
+// Load returns the model stored under key, or regression.ErrModelNotFound.
+func (s *Store) Load(key string) (*regression.Regression, error) {
+	return s.load(context.Background(), key)
+}
+
+func (s *Store) load(ctx context.Context, key string) (*regression.Regression, error) {
+	if key == "" {
+		return nil, regression.ErrInvalidKey
+	}
+	b, err := s.client.Get(ctx, s.prefix+key)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, regression.ErrModelNotFound
+	}
+	return regression.Load(bytes.NewReader(b))
+}
+
+// Watcher serves the latest version of a stored model. It is safe for concurrent use.
+type Watcher struct {
+	model atomic.Value
+	done  chan struct{}
+}
+
+// Watch loads the model stored under key and reloads it whenever a new version is saved, until ctx is
+// done. If no model is stored yet, Model returns nil until one is saved. Errors reloading the model are
+// passed to the optional onError, and the previous version is kept.
+func (s *Store) Watch(ctx context.Context, key string, onError func(error)) (*Watcher, error) {
+	return s.WatchCanary(ctx, key, regression.Canary{}, onError)
+}
+
+// WatchCanary watches key like Watch, evaluating every version loaded with canary before serving it.
+// A rejected version is passed to onError as a *regression.CanaryError, and the previous version is kept.
+// If the first version is rejected, WatchCanary returns the error.
+func (s *Store) WatchCanary(ctx context.Context, key string, canary regression.Canary, onError func(error)) (*Watcher, error) {
+	// subscribe before the first load, so no version saved in between is missed
+	ctx, cancel := context.WithCancel(ctx)
+	updates, err := s.client.Subscribe(ctx, s.channel())
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	w := &Watcher{done: make(chan struct{})}
+	r, err := s.load(ctx, key)
+	if err != nil && err != regression.ErrModelNotFound {
+		cancel()
+		return nil, err
+	}
+	if r != nil {
+		if _, err := canary.Evaluate(nil, r); err != nil {
+			cancel()
+			return nil, err
+		}
+		w.model.Store(r)
+	}
+
+	go func() {
+		defer close(w.done)
+		defer cancel()
+		for msg := range updates {
+			if msg != key {
+				continue
+			}
+			r, err := s.load(ctx, key)
+			if err == nil {
+				_, err = canary.Evaluate(w.Model(), r)
+			}
+			if err != nil {
+				if onError != nil {
+					onError(err)
+				}
+				continue
+			}
+			w.model.Store(r)
+		}
+	}()
+	return w, nil
+}
+
+// Model returns the latest version of the model, or nil if none is stored.
+func (w *Watcher) Model() *regression.Regression {
+	r, _ := w.model.Load().(*regression.Regression)
+	return r
+}
+
+// Done is closed once the watcher stopped following updates, after its context is done
+// or the subscription failed.
+func (w *Watcher) Done() <-chan struct{} {
+	return w.done
+}
@@ -0,0 +1,180 @@
+package redisstore
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sajari/regression"
+)
+
+// fakeClient is an in-memory Client.
+type fakeClient struct {
+	mu     sync.Mutex
+	values map[string][]byte
+	subs   map[string][]chan string
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{values: make(map[string][]byte), subs: make(map[string][]chan string)}
+}
+
+func (c *fakeClient) Get(ctx context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.values[key], nil
+}
+
+func (c *fakeClient) Set(ctx context.Context, key string, value []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[key] = append([]byte(nil), value...)
+	return nil
+}
+
+func (c *fakeClient) Publish(ctx context.Context, channel, message string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, sub := range c.subs[channel] {
+		sub <- message
+	}
+	return nil
+}
+
+func (c *fakeClient) Subscribe(ctx context.Context, channel string) (<-chan string, error) {
+	in := make(chan string, 16)
+	out := make(chan string)
+	c.mu.Lock()
+	c.subs[channel] = append(c.subs[channel], in)
+	c.mu.Unlock()
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case m := <-in:
+				select {
+				case out <- m:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+func fit(t *testing.T, slope float64) *regression.Regression {
+	r := new(regression.Regression)
+	for x := 0.0; x < 10; x++ {
+		r.Train(regression.DataPoint(1+slope*x, []float64{x}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestStore(t *testing.T) {
+	s := New(newFakeClient(), "models:")
+	if _, err := s.Load("cars"); err != regression.ErrModelNotFound {
+		t.Errorf("Expected ErrModelNotFound, got %v", err)
+	}
+	if err := s.Save("cars", fit(t, 2)); err != nil {
+		t.Fatal(err)
+	}
+	r, err := s.Load("cars")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := r.Predict([]float64{3}); got < 6.999 || got > 7.001 {
+		t.Errorf("Expected a prediction of 7, got %v", got)
+	}
+	if err := s.Save("", r); err != regression.ErrInvalidKey {
+		t.Errorf("Expected ErrInvalidKey, got %v", err)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	s := New(newFakeClient(), "models:")
+	ctx, cancel := context.WithCancel(context.Background())
+	w, err := s.Watch(ctx, "cars", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Model() != nil {
+		t.Fatal("Expected no model before one is saved")
+	}
+
+	for _, slope := range []float64{2, 3} {
+		if err := s.Save("other", fit(t, 10)); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Save("cars", fit(t, slope)); err != nil {
+			t.Fatal(err)
+		}
+		want := 1 + slope*3
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			if r := w.Model(); r != nil {
+				if got, _ := r.Predict([]float64{3}); got > want-0.001 && got < want+0.001 {
+					break
+				}
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("Watcher didn't reload the model with slope %v", slope)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	cancel()
+	select {
+	case <-w.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watcher didn't stop")
+	}
+}
+
+func TestWatchCanary(t *testing.T) {
+	s := New(newFakeClient(), "models:")
+	if err := s.Save("line", fit(t, 2)); err != nil {
+		t.Fatal(err)
+	}
+	var validation regression.DataPoints
+	for x := 0.0; x < 10; x += 0.5 {
+		validation = append(validation, regression.DataPoint(1+2*x, []float64{x}))
+	}
+	errs := make(chan error, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w, err := s.WatchCanary(ctx, "line", regression.Canary{Validation: validation, MaxRMSEIncrease: 0.1},
+		func(err error) { errs <- err })
+	if err != nil {
+		t.Fatal(err)
+	}
+	current := w.Model()
+
+	// a worse model is rejected and the current one kept
+	if err := s.Save("line", fit(t, 5)); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-errs:
+		if _, ok := err.(*regression.CanaryError); !ok {
+			t.Errorf("Expected a CanaryError, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the new version rejected")
+	}
+	if w.Model() != current {
+		t.Error("Expected the current model kept")
+	}
+
+	// the first version is checked too
+	if _, err := s.WatchCanary(ctx, "line", regression.Canary{Validation: validation, MaxRMSE: 1}, nil); err == nil {
+		t.Error("Expected the stored version rejected")
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"math/rand"
 	"strconv"
 	"strings"
+	"time"
 
+	"gonum.org/v1/gonum/floats"
 	"gonum.org/v1/gonum/mat"
 )
 
@@ -17,6 +20,8 @@ var (
 	ErrTooManyVars = errors.New("not enough observations to to support this many variables")
 	// ErrRegressionRun signals that the Run method has already been called on the trained dataset.
 	ErrRegressionRun = errors.New("regression has already been run")
+	// ErrNotRun signals that the regression has to be run before the requested value is available.
+	ErrNotRun = errors.New("regression has not been run")
 )
 
 // Regression is the exposed data structure for interacting with the API.
@@ -30,7 +35,68 @@ type Regression struct {
 	initialised       bool
 	Formula           string
 	crosses           []featureCross
+	crossSet          []crossEntry
 	hasRun            bool
+	dist              DistributionProvider
+	dfResidual        int
+	sigma2            float64
+	covariance        *mat.Dense
+	split             bool
+	splitVar          int
+	segments          map[float64]*Regression
+	solve             Solver
+	normalization     Normalization
+	varNormalization  map[int]Normalization
+	scaling           *scaling
+	rmse              float64
+	online            *onlineState
+	observations      int
+	trainedAt         time.Time
+	metadata          map[string]string
+	rng               *rand.Rand
+	instruments       map[int][]int
+	fixedEffects      map[string]float64
+	absorbed          int
+	loadings          *mat.Dense
+	aliased           []int
+	hashOrder         HashOrder
+	hasher            *dataHasher
+	dataHash          string
+	signs             map[int]Sign
+	signDrops         []signDrop
+	stats             FitStats
+	runPolicy         RunPolicy
+	fitted            int
+	fittedHash        string
+	fittedCrosses     string
+	censor            *censoring
+	prior             *Prior
+	posterior         *posterior
+	groupEffects      map[string][]float64
+	randomSlopes      []int
+	groupVariances    []float64
+	winsorVars        map[int][2]float64
+	winsorObs         *[2]float64
+	clips             map[int][2]float64
+	obsClip           *[2]float64
+	dataGuard         DataGuard
+	sealed            string
+	numberFormat      *NumberFormat
+	ingest            *ingestStats
+	staleness         *StalenessPolicy
+	newPoints         int
+	imputation        Imputation
+	moments           *moments
+	freshness         map[int]time.Duration
+	onStaleFeature    func(StaleFeature)
+	audit             *auditor
+	varIndex          map[string]int
+	missing           map[int]float64
+	packed            *packedData
+	trainLog          *TrainingLog
+	vifThreshold      float64
+	collinearDrops    []collinearDrop
+	unclipped         []*dataPoint
 }
 
 type dataPoint struct {
@@ -38,11 +104,30 @@ type dataPoint struct {
 	Variables []float64
 	Predicted float64
 	Error     float64
+	Weight    float64
+	Group     string
+	Treated   bool
+	Post      bool
+	// Contributions and StdErr are set by PredictPoint.
+	Contributions []float64
+	StdErr        float64
+	// crossed is the number of feature cross values appended to Variables by the last run.
+	crossed int
 }
 
 type describe struct {
-	obs  string
+	obs string
+	// vars are the names of the base variables, set with SetVar
 	vars map[int]string
+	// crosses are the names generated for the feature crosses, which follow the base variables
+	crosses []string
+	// base is the number of base variables, known once the feature crosses are applied
+	base int
+	// units are the units of the base variables and obsUnit that of the observed value, e.g. "USD"
+	units   map[int]string
+	obsUnit string
+	// types are the types of the base variables in the source data, see SetVarType
+	types map[int]ColumnType
 }
 
 // DataPoints is a slice of *dataPoint
@@ -51,25 +136,93 @@ type DataPoints []*dataPoint
 
 // DataPoint creates a well formed *datapoint used for training.
 func DataPoint(obs float64, vars []float64) *dataPoint {
-	return &dataPoint{Observed: obs, Variables: vars}
+	return &dataPoint{Observed: obs, Variables: vars, Weight: 1}
+}
+
+// WeightedDataPoint creates a *datapoint with a weight, e.g. the number of times it was observed
+// or its inverse variance. Data points with a weight of zero don't affect the fit, and negative weights
+// fail the fit with ErrInvalidWeight.
+func WeightedDataPoint(obs float64, vars []float64, weight float64) *dataPoint {
+	return &dataPoint{Observed: obs, Variables: vars, Weight: weight}
+}
+
+// GroupedDataPoint creates a *datapoint belonging to a group, e.g. the entity of panel data,
+// for use with RunFixedEffects.
+func GroupedDataPoint(obs float64, vars []float64, group string) *dataPoint {
+	return &dataPoint{Observed: obs, Variables: vars, Weight: 1, Group: group}
 }
 
 // Predict updates the "Predicted" value for the inputed features.
+// Calls are recorded by the audit sink set with SetAudit, if any.
 func (r *Regression) Predict(vars []float64) (float64, error) {
+	p, err := r.predict(vars)
+	if r.audit != nil {
+		r.audit.record(r, vars, p, err)
+	}
+	return p, err
+}
+
+func (r *Regression) predict(vars []float64) (float64, error) {
 	if !r.initialised {
 		return 0, ErrNotEnoughData
 	}
+	if r.coeff != nil && len(r.coeff) == 0 {
+		return 0, ErrNotRun
+	}
+	if err := r.checkTypes(vars); err != nil {
+		return 0, err
+	}
+	if err := r.checkData(); err != nil {
+		return 0, err
+	}
+	if s := r.segmentFor(vars); s != nil {
+		return s.Predict(vars)
+	}
 
-	// apply any features crosses to vars
-	for _, cross := range r.crosses {
-		vars = append(vars, cross.Calculate(vars)...)
+	return r.predictRow(r.designRow(vars)), nil
+}
+
+// PredictPoint predicts the observed value for vars like Predict, returning a data point with the prediction,
+// the contribution of every variable and feature cross (see ContributionStats) and the standard error of
+// the prediction, which is NaN when it isn't available.
+func (r *Regression) PredictPoint(vars []float64) (*dataPoint, error) {
+	p, err := r.Predict(vars)
+	if err != nil {
+		return nil, err
+	}
+	if len(r.coeff) == 0 && r.segmentFor(vars) == nil {
+		return nil, ErrNotRun
 	}
+	d := &dataPoint{
+		Variables:     append([]float64(nil), vars...),
+		Predicted:     p,
+		Weight:        1,
+		Contributions: r.contributions(vars),
+		StdErr:        math.NaN(),
+	}
+	if se, err := r.PredictSE(vars); err == nil {
+		d.StdErr = se
+	}
+	return d, nil
+}
+
+// designRow builds a row of the design matrix for the model's feature crosses, after clipping
+// the variables to the cut points of winsorization.
+func (r *Regression) designRow(vars []float64) []float64 {
+	vars = r.clipVars(vars)
+	if len(r.missing) == 0 {
+		return designRow(vars, r.crosses)
+	}
+	vars, indicators := r.impute(vars, r.missingVars())
+	return append(designRow(vars, r.crosses), indicators...)
+}
 
-	p := r.Coeff(0)
-	for j := 1; j < len(r.data[0].Variables)+1; j++ {
-		p += r.Coeff(j) * vars[j-1]
+func (r *Regression) predictRow(row []float64) float64 {
+	var p float64
+	for j := 0; j < len(r.coeff); j++ {
+		p += r.coeff[j] * row[j]
 	}
-	return p, nil
+	return p
 }
 
 // SetObserved sets the name of the observed value.
@@ -87,12 +240,63 @@ func (r *Regression) SetVar(i int, name string) {
 	if len(r.names.vars) == 0 {
 		r.names.vars = make(map[int]string, 5)
 	}
+	if r.varIndex != nil {
+		// keep the schema set with DefineVars in step
+		delete(r.varIndex, r.names.vars[i])
+		r.varIndex[name] = i
+	}
 	r.names.vars[i] = name
 }
 
-// GetVar gets the name of variable i
+// SetVarUnit sets the unit of variable i, e.g. "USD". Units are shown in the formula, the summary
+// and ExplainModel.
+func (r *Regression) SetVarUnit(i int, unit string) {
+	if r.names.units == nil {
+		r.names.units = make(map[int]string)
+	}
+	r.names.units[i] = unit
+}
+
+// GetVarUnit gets the unit of variable i, which is empty if not set or if i is a feature cross.
+func (r *Regression) GetVarUnit(i int) string {
+	if i >= r.names.base && r.names.crosses != nil {
+		return ""
+	}
+	return r.names.units[i]
+}
+
+// SetObservedUnit sets the unit of the observed value.
+func (r *Regression) SetObservedUnit(unit string) {
+	r.names.obsUnit = unit
+}
+
+// GetObservedUnit gets the unit of the observed value.
+func (r *Regression) GetObservedUnit() string {
+	return r.names.obsUnit
+}
+
+// varLabel returns the name of variable i followed by its unit, if any.
+func (r *Regression) varLabel(i int) string {
+	return withUnit(r.GetVar(i), r.GetVarUnit(i))
+}
+
+func withUnit(name, unit string) string {
+	if unit == "" {
+		return name
+	}
+	return name + " [" + unit + "]"
+}
+
+// GetVar gets the name of variable i, which may be a feature cross once the regression has run.
 func (r *Regression) GetVar(i int) string {
-	x := r.names.vars[i]
+	var x string
+	if j := i - r.names.base; r.names.crosses != nil && j >= 0 {
+		if j < len(r.names.crosses) {
+			x = r.names.crosses[j]
+		}
+	} else {
+		x = r.names.vars[i]
+	}
 	if x == "" {
 		s := []string{"X", strconv.Itoa(i)}
 		return strings.Join(s, "")
@@ -103,32 +307,121 @@ func (r *Regression) GetVar(i int) string {
 // AddCross registers a feature cross to be applied to the data points.
 func (r *Regression) AddCross(cross featureCross) {
 	r.crosses = append(r.crosses, cross)
+	if r.crossSet != nil {
+		r.crossSet = append(r.crossSet, crossEntry{cross: cross, enabled: true})
+	}
 }
 
 // Train the regression with some data points.
 func (r *Regression) Train(d ...*dataPoint) {
-	r.data = append(r.data, d...)
+	if r.trainLog != nil {
+		r.trainLog.append(d)
+	}
+	r.ingestPoints(d)
+	if r.dataGuard == CopyOnTrain {
+		for _, p := range d {
+			r.data = append(r.data, copyPoint(p))
+		}
+	} else {
+		r.data = append(r.data, d...)
+	}
 	if len(r.data) > 2 {
 		r.initialised = true
 	}
 }
 
+// TrainMatrix trains the regression with dense row-major data: X holds rows observations of cols variables
+// one row after another, and y holds the observed values. The data points are created in bulk and refer to
+// the rows of X rather than to copies, so X must not be modified afterwards. Every data point has a weight of one.
+func (r *Regression) TrainMatrix(X []float64, rows, cols int, y []float64) error {
+	if rows < 0 || len(X) != rows*cols || len(y) != rows {
+		return ErrDimensions
+	}
+	points, err := NewDataPoints(y, X, cols)
+	if err != nil {
+		return err
+	}
+	r.Train(points...)
+	return nil
+}
+
 // Apply any feature crosses, generating new observations and updating the data points, as well as
 // populating variable names for the feature crosses.
-// this should only be run once, as part of Run().
-func (r *Regression) applyCrosses() {
-	unusedVariableIndexCursor := len(r.data[0].Variables)
-	for _, point := range r.data {
-		for _, cross := range r.crosses {
-			point.Variables = append(point.Variables, cross.Calculate(point.Variables)...)
+// The data points must not carry the crosses of an earlier run, see uncross.
+func (r *Regression) applyCrosses() error {
+	start := time.Now()
+	defer func() { r.stats.Crosses = time.Since(start) }()
+	numOfBaseVars := len(r.data[0].Variables)
+	if len(r.missing) > 0 {
+		if vars := r.missingVars(); vars[len(vars)-1] >= numOfBaseVars {
+			return ErrDimensions
 		}
+		r.fillMissing()
 	}
+	r.crossPoints(r.data)
+	r.extendNames(numOfBaseVars)
+	return nil
+}
 
-	if len(r.names.vars) == 0 {
-		r.names.vars = make(map[int]string, 5)
+// crossPoints appends the feature crosses and the missing indicators to the variables of points,
+// imputing missing values with the values computed by fillMissing.
+func (r *Regression) crossPoints(points []*dataPoint) {
+	var indicators [][]float64
+	if len(r.missing) > 0 {
+		vars := r.missingVars()
+		indicators = make([][]float64, len(points))
+		for k, d := range points {
+			d.Variables, indicators[k] = r.impute(d.Variables, vars)
+		}
+	}
+	compileCrosses(r.crosses).applyAll(points)
+	for k, ind := range indicators {
+		// never append into the capacity of the caller's slice
+		d := points[k]
+		d.Variables = append(d.Variables[:len(d.Variables):len(d.Variables)], ind...)
+		d.crossed += len(ind)
+	}
+}
+
+// uncross removes the feature cross values materialized by an earlier run from the data points,
+// so the crosses are applied exactly once however often the model is run, and restores the data points
+// clipped by winsorization.
+func (r *Regression) uncross() {
+	r.unwinsorize()
+	for _, d := range r.data {
+		if d.crossed > 0 {
+			if len(r.missing) > 0 {
+				r.restoreMissing(d)
+			}
+			d.Variables = d.Variables[:len(d.Variables)-d.crossed]
+			d.crossed = 0
+		}
 	}
+}
+
+// extendNames generates the variable names of the feature crosses, which follow the base variables.
+// The names are derived from the base variable names only, so this can be repeated.
+func (r *Regression) extendNames(numOfBaseVars int) {
+	names := make(map[int]string, len(r.names.vars))
+	for i, name := range r.names.vars {
+		if i < numOfBaseVars {
+			names[i] = name
+		}
+	}
+	unusedVariableIndexCursor := numOfBaseVars
 	for _, cross := range r.crosses {
-		unusedVariableIndexCursor += cross.ExtendNames(r.names.vars, unusedVariableIndexCursor)
+		unusedVariableIndexCursor += cross.ExtendNames(names, unusedVariableIndexCursor)
+	}
+	for _, v := range r.missingVars() {
+		if names[v] != "" {
+			names[unusedVariableIndexCursor] = "(" + names[v] + ")missing"
+		}
+		unusedVariableIndexCursor++
+	}
+	r.names.base = numOfBaseVars
+	r.names.crosses = make([]string, unusedVariableIndexCursor-numOfBaseVars)
+	for i := range r.names.crosses {
+		r.names.crosses[i] = names[numOfBaseVars+i]
 	}
 }
 
@@ -144,71 +437,156 @@ func (r *Regression) Run() error {
 		return ErrRegressionRun
 	}
 
+	r.DecompressData()
+	if err := checkWeights(r.data); err != nil {
+		return err
+	}
+	r.uncross()
+	numOfBaseVars := len(r.data[0].Variables)
+	if err := r.checkSplit(numOfBaseVars); err != nil {
+		return err
+	}
+	r.hashData()
+	if err := r.winsorize(); err != nil {
+		return err
+	}
+
 	//apply any features crosses
-	r.applyCrosses()
+	if err := r.applyCrosses(); err != nil {
+		return err
+	}
 	r.hasRun = true
+	return r.fit(numOfBaseVars)
+}
 
+// fit solves the regression on the training data, which already has the feature crosses applied.
+func (r *Regression) fit(numOfBaseVars int) error {
 	observations := len(r.data)
 	numOfvars := len(r.data[0].Variables)
 
-	if observations < (numOfvars + 1) {
+	if observations < (numOfvars+1) && !fitsWide(r.solver()) {
 		return ErrTooManyVars
 	}
+	if r.censor != nil && (r.solve != nil || r.instruments != nil || r.signs != nil || r.split ||
+		r.vifThreshold > 0) {
+		return ErrUnsupported
+	}
+	if r.prior != nil && (r.normalizes() || r.solve != nil || r.instruments != nil ||
+		r.signs != nil || r.censor != nil || r.split || r.vifThreshold > 0) {
+		return ErrUnsupported
+	}
+	t := r.startTimer()
+	defer t.stop()
 
 	// Create some blank variable space
 	observed := mat.NewDense(observations, 1, nil)
 	variables := mat.NewDense(observations, numOfvars+1, nil)
 
-	for i := 0; i < observations; i++ {
-		observed.Set(i, 0, r.data[i].Observed)
-		for j := 0; j < numOfvars+1; j++ {
-			if j == 0 {
-				variables.Set(i, 0, 1)
-			} else {
-				variables.Set(i, j, r.data[i].Variables[j-1])
-			}
-		}
+	for i, d := range r.data {
+		observed.Set(i, 0, d.Observed)
+		row := variables.RawRowView(i)
+		row[0] = 1
+		copy(row[1:], d.Variables)
 	}
 
+	r.stats.Design = t.lap()
+	r.stats.Rows, r.stats.Cols = observations, numOfvars+1
+	r.stats.MatrixBytes = 8 * observations * (numOfvars + 1)
+
 	// Now run the regression
-	_, n := variables.Dims() // cols
-	qr := new(mat.QR)
-	qr.Factorize(variables)
-	q := new(mat.Dense)
-	reg := new(mat.Dense)
-	qr.QTo(q)
-	qr.RTo(reg)
-
-	qtr := q.T()
-	qty := new(mat.Dense)
-	qty.Mul(qtr, observed)
-
-	c := make([]float64, n)
-	for i := n - 1; i >= 0; i-- {
-		c[i] = qty.At(i, 0)
-		for j := i + 1; j < n; j++ {
-			c[i] -= c[j] * reg.At(i, j)
-		}
-		c[i] /= reg.At(i, i)
+	scale := r.normalize(variables)
+	var c []float64
+	var diag *Diagnostics
+	var sigma float64
+	var post *posterior
+	var err error
+	switch {
+	case r.censor != nil:
+		c, diag, sigma, err = r.censor.fit(variables, observed, r.weights())
+	case r.prior != nil:
+		r.applyWeights(variables, observed)
+		c, diag, post, err = r.prior.solve(variables, observed, r.weightedObservations())
+	default:
+		r.applyWeights(variables, observed)
+		c, diag, err = r.ivSolver(r.collinearSolver(r.signSolver(r.solverFor(observations, numOfvars+1)))).Solve(variables, observed)
 	}
+	if err != nil {
+		return err
+	}
+	if len(c) != numOfvars+1 {
+		return ErrSolverCoeffs
+	}
+	if scale != nil {
+		c, diag = scale.restore(c, diag)
+	}
+
+	r.stats.Solve = t.lap()
 
 	// Output the regression results
-	r.coeff = make(map[int]float64, numOfvars)
-	for i, val := range c {
-		r.coeff[i] = val
-		if i == 0 {
-			r.Formula = fmt.Sprintf("Predicted = %.4f", val)
-		} else {
-			r.Formula += fmt.Sprintf(" + %v*%.4f", r.GetVar(i-1), val)
-		}
+	r.setCoeffs(c)
+	r.loadings, r.aliased = nil, nil
+	if diag != nil {
+		r.loadings, r.aliased = diag.Loadings, diag.Aliased
 	}
 
 	r.calcPredicted()
 	r.calcVariance()
 	r.calcR2()
+	r.calcInference(diag)
+	if r.censor != nil {
+		r.censoredInference(diag, sigma)
+	}
+	if post != nil {
+		r.bayesInference(post)
+	}
+	r.stats.Metrics = t.lap()
+
+	if r.split {
+		r.runSegments(numOfBaseVars)
+		r.stats.Segments = t.lap()
+	}
+	r.sealData()
 	return nil
 }
 
+// hashData records the hash of the training data, before the feature crosses are applied.
+func (r *Regression) hashData() {
+	r.moments = nil
+	r.hasher = newDataHasher(r.hashOrder)
+	for _, d := range r.data {
+		r.hasher.add(d)
+	}
+	// RunIfChanged compares the data by its ordered hash
+	fitted := r.hasher
+	if r.hashOrder != OrderedHash {
+		fitted = newDataHasher(OrderedHash)
+		for _, d := range r.data {
+			fitted.add(d)
+		}
+	}
+	r.fitted = len(r.data)
+	r.fittedHash = fitted.String()
+	r.fittedCrosses = r.crossesSignature()
+}
+
+// setCoeffs stores the fitted coefficients and renders the formula.
+func (r *Regression) setCoeffs(c []float64) {
+	r.coeff = make(map[int]float64, len(c))
+	for i, val := range c {
+		r.coeff[i] = val
+	}
+	r.Formula = r.formula()
+}
+
+// formula renders the fitted coefficients as a formula.
+func (r *Regression) formula() string {
+	formula := fmt.Sprintf("%v = %v", withUnit("Predicted", r.names.obsUnit), r.formatNumber(r.coeff[0], 4))
+	for i := 1; i < len(r.coeff); i++ {
+		formula += fmt.Sprintf(" + %v*%v", r.varLabel(i-1), r.formatNumber(r.coeff[i], 4))
+	}
+	return formula
+}
+
 // Coeff returns the calculated coefficient for variable i.
 func (r *Regression) Coeff(i int) float64 {
 	if len(r.coeff) == 0 {
@@ -217,47 +595,38 @@ func (r *Regression) Coeff(i int) float64 {
 	return r.coeff[i]
 }
 
-func (r *Regression) calcPredicted() string {
-	observations := len(r.data)
-	var predicted float64
-	var output string
-	for i := 0; i < observations; i++ {
-		r.data[i].Predicted, _ = r.Predict(r.data[i].Variables)
-		r.data[i].Error = r.data[i].Predicted - r.data[i].Observed
-
-		output += fmt.Sprintf("%v. observed = %v, Predicted = %v, Error = %v", i, r.data[i].Observed, predicted, r.data[i].Error)
+// calcPredicted sets the predicted value and error of every training data point.
+func (r *Regression) calcPredicted() {
+	c := r.coeffs()
+	for _, d := range r.data {
+		d.Predicted = c[0] + floats.Dot(c[1:], d.Variables[:len(c)-1])
+		d.Error = d.Predicted - d.Observed
 	}
-	return output
 }
 
 func (r *Regression) calcVariance() string {
 	observations := len(r.data)
-	var obtotal, prtotal, obvar, prvar float64
-	for i := 0; i < observations; i++ {
-		obtotal += r.data[i].Observed
-		prtotal += r.data[i].Predicted
-	}
-	obaverage := obtotal / float64(observations)
-	praverage := prtotal / float64(observations)
-
-	for i := 0; i < observations; i++ {
-		obvar += math.Pow(r.data[i].Observed-obaverage, 2)
-		prvar += math.Pow(r.data[i].Predicted-praverage, 2)
+	observed := make([]float64, observations)
+	predicted := make([]float64, observations)
+	weights := make([]float64, observations)
+	for i, d := range r.data {
+		observed[i], predicted[i], weights[i] = d.Observed, d.Predicted, d.Weight
 	}
-	r.Varianceobserved = obvar / float64(observations)
-	r.VariancePredicted = prvar / float64(observations)
+	r.Varianceobserved = weightedVariance(observed, weights)
+	r.VariancePredicted = weightedVariance(predicted, weights)
 	return fmt.Sprintf("N = %v\nVariance observed = %v\nVariance Predicted = %v\n", observations, r.Varianceobserved, r.VariancePredicted)
 }
 
 func (r *Regression) calcR2() string {
 	r.R2 = r.VariancePredicted / r.Varianceobserved
-	return fmt.Sprintf("R2 = %.2f", r.R2)
+	return "R2 = " + r.formatNumber(r.R2, 2)
 }
 
 func (r *Regression) calcResiduals() string {
 	str := fmt.Sprintf("Residuals:\nobserved|\tPredicted|\tResidual\n")
 	for _, d := range r.data {
-		str += fmt.Sprintf("%.2f|\t%.2f|\t%.2f\n", d.Observed, d.Predicted, d.Observed-d.Predicted)
+		str += fmt.Sprintf("%v|\t%v|\t%v\n", r.formatNumber(d.Observed, 2), r.formatNumber(d.Predicted, 2),
+			r.formatNumber(d.Observed-d.Predicted, 2))
 	}
 	str += "\n"
 	return str
@@ -277,24 +646,35 @@ func (r *Regression) String() string {
 	if !r.initialised {
 		return ErrNotEnoughData.Error()
 	}
-	str := fmt.Sprintf("%v", r.GetObserved())
-	for i := 0; i < len(r.names.vars); i++ {
-		str += fmt.Sprintf("|\t%v", r.GetVar(i))
+	str := fmt.Sprintf("%v", withUnit(r.GetObserved(), r.names.obsUnit))
+	vars := len(r.names.vars)
+	if r.names.crosses != nil {
+		vars = r.names.base + len(r.names.crosses)
+	}
+	for i := 0; i < vars; i++ {
+		str += fmt.Sprintf("|\t%v", r.varLabel(i))
 	}
 	str += "\n"
 	for _, d := range r.data {
-		str += fmt.Sprintf("%v\n", d)
+		str += r.formatNumber(d.Observed, 2)
+		for _, v := range d.Variables {
+			str += "|\t" + r.formatNumber(v, 2)
+		}
+		str += "\n"
 	}
 	fmt.Println(r.calcResiduals())
-	str += fmt.Sprintf("\nN = %v\nVariance observed = %v\nVariance Predicted = %v", len(r.data), r.Varianceobserved, r.VariancePredicted)
-	str += fmt.Sprintf("\nR2 = %v\n", r.R2)
+	str += fmt.Sprintf("\nN = %v\nVariance observed = %v\nVariance Predicted = %v", len(r.data),
+		r.formatNumber(r.Varianceobserved, -1), r.formatNumber(r.VariancePredicted, -1))
+	str += fmt.Sprintf("\nR2 = %v\n", r.formatNumber(r.R2, -1))
 	return str
 }
 
 // MakeDataPoints makes a `[]*dataPoint` from a `[][]float64`. The expected fomat for the input is a row-major [][]float64.
 // That is to say the first slice represents a row, and the second represents the cols.
 // Furthermore it is expected that all the col slices are of the same length.
-// The obsIndex parameter indicates which column should be used
+// The obsIndex parameter indicates which column should be used.
+// When it is the first or the last column the data points are views of the rows rather than copies,
+// so a must not be modified afterwards. Feature crosses are never written into a.
 func MakeDataPoints(a [][]float64, obsIndex int) []*dataPoint {
 	if obsIndex != 0 && obsIndex != len(a[0])-1 {
 		return perverseMakeDataPoints(a, obsIndex)
@@ -303,19 +683,50 @@ func MakeDataPoints(a [][]float64, obsIndex int) []*dataPoint {
 	retVal := make([]*dataPoint, 0, len(a))
 	if obsIndex == 0 {
 		for _, r := range a {
-			retVal = append(retVal, DataPoint(r[0], r[1:]))
+			retVal = append(retVal, DataPoint(r[0], r[1:len(r):len(r)]))
 		}
 		return retVal
 	}
 
-	// otherwise the observation is expected to be the last col
+	// otherwise the observation is expected to be the last col, which appending
+	// the feature crosses to the variables must not overwrite
 	last := len(a[0]) - 1
 	for _, r := range a {
-		retVal = append(retVal, DataPoint(r[last], r[:last]))
+		retVal = append(retVal, DataPoint(r[last], r[:last:last]))
 	}
 	return retVal
 }
 
+// MakeDataPointsColumnMajor makes a `[]*dataPoint` from column-major data, such as the columns of a
+// columnar store: cols holds a slice per column, all of the same length, and obsCol is the index of the
+// observed column. Data points hold their variables contiguously, so the variables are transposed once
+// into a single block, without allocating per data point.
+func MakeDataPointsColumnMajor(cols [][]float64, obsCol int) ([]*dataPoint, error) {
+	if obsCol < 0 || obsCol >= len(cols) {
+		return nil, ErrDimensions
+	}
+	rows := len(cols[obsCol])
+	for _, c := range cols {
+		if len(c) != rows {
+			return nil, ErrDimensions
+		}
+	}
+
+	numVars := len(cols) - 1
+	vars := make([]float64, rows*numVars)
+	j := 0
+	for k, c := range cols {
+		if k == obsCol {
+			continue
+		}
+		for i, v := range c {
+			vars[i*numVars+j] = v
+		}
+		j++
+	}
+	return NewDataPoints(cols[obsCol], vars, numVars)
+}
+
 func perverseMakeDataPoints(a [][]float64, obsIndex int) []*dataPoint {
 	retVal := make([]*dataPoint, 0, len(a))
 	for _, r := range a {
@@ -75,7 +75,7 @@ func TestCrossApply(t *testing.T) {
 
 	fmt.Printf("Regression formula:\n%v\n", r.Formula)
 	fmt.Printf("Regression:\n%s\n", r)
-	if r.names.vars[1] != "(Input)^2" {
+	if r.GetVar(1) != "(Input)^2" {
 		t.Error("Name incorrect")
 	}
 
@@ -156,3 +156,111 @@ func TestMakeDataPoints(t *testing.T) {
 	}
 
 }
+
+func TestPredictPoint(t *testing.T) {
+	r := new(Regression)
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	if _, err := r.PredictPoint([]float64{21}); err != ErrNotRun {
+		t.Errorf("Expected ErrNotRun, got %v", err)
+	}
+	r.AddCross(PowCross(0, 2))
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	vars := []float64{21}
+	d, err := r.PredictPoint(vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := r.Predict(vars)
+	se, _ := r.PredictSE(vars)
+	if d.Predicted != want || d.StdErr != se {
+		t.Errorf("Expected %v with stderr %v, got %v and %v", want, se, d.Predicted, d.StdErr)
+	}
+	if len(d.Contributions) != 2 {
+		t.Fatalf("Unexpected contributions %v", d.Contributions)
+	}
+	assertClose(t, "contribution", d.Contributions[1], r.Coeff(2)*21*21, 1e-9)
+	vars[0] = 0
+	if d.Variables[0] != 21 {
+		t.Error("Expected the variables to be copied")
+	}
+}
+
+func TestMakeDataPointsView(t *testing.T) {
+	a := make([][]float64, len(carsSpeed))
+	for i := range a {
+		a[i] = []float64{carsSpeed[i], carsDist[i]}
+	}
+	r := new(Regression)
+	r.Train(MakeDataPoints(a, 1)...)
+	r.AddCross(PowCross(0, 2))
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	for i, row := range a {
+		if row[1] != carsDist[i] {
+			t.Fatalf("Expected the observed column to be left intact, got %v in row %d", row, i)
+		}
+	}
+}
+
+func TestMakeDataPointsColumnMajor(t *testing.T) {
+	cols := [][]float64{carsDist, carsSpeed, carsSpeed}
+	dps, err := MakeDataPointsColumnMajor(cols, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dps) != len(carsSpeed) {
+		t.Fatalf("Expected %d data points, got %d", len(carsSpeed), len(dps))
+	}
+	for i, dp := range dps {
+		if dp.Observed != carsDist[i] || len(dp.Variables) != 2 || dp.Variables[0] != carsSpeed[i] || dp.Variables[1] != carsSpeed[i] {
+			t.Errorf("Unexpected data point %d: %+v", i, dp)
+		}
+	}
+
+	dps, _ = MakeDataPointsColumnMajor([][]float64{carsSpeed, carsDist}, 1)
+	r := new(Regression)
+	r.Train(dps...)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	want := carsRegression(t)
+	assertClose(t, "coefficient", r.Coeff(1), want.Coeff(1), 1e-12)
+
+	if _, err := MakeDataPointsColumnMajor([][]float64{carsSpeed, carsDist[:3]}, 0); err != ErrDimensions {
+		t.Errorf("Expected ErrDimensions, got %v", err)
+	}
+}
+
+func TestTrainMatrix(t *testing.T) {
+	X := make([]float64, 0, 2*len(carsSpeed))
+	for _, s := range carsSpeed {
+		X = append(X, s, s*s)
+	}
+	r := new(Regression)
+	if err := r.TrainMatrix(X, len(carsSpeed), 2, carsDist); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := new(Regression)
+	want.AddCross(PowCross(0, 2))
+	for i := range carsSpeed {
+		want.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	want.Run()
+	for i := 0; i < 3; i++ {
+		assertClose(t, "coefficient", r.Coeff(i), want.Coeff(i), 1e-9)
+	}
+
+	if err := new(Regression).TrainMatrix(X, len(carsSpeed), 3, carsDist); err != ErrDimensions {
+		t.Errorf("Expected ErrDimensions, got %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+// Package regressiontest pins the behaviour of models fitted with github.com/sajari/regression in tests:
+// AssertModelMatches compares a model's coefficients and metrics with a golden JSON file.
+//
+// Golden files are written by running the tests with the environment variable
+// REGRESSION_UPDATE_GOLDEN=1, or with WriteGolden, and are meant to be committed with the tests.
+package regressiontest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/sajari/regression"
+)
+
+// UpdateEnv is the environment variable that makes AssertModelMatches write the golden file
+// instead of comparing with it.
+const UpdateEnv = "REGRESSION_UPDATE_GOLDEN"
+
+// defaultTolerance is the relative tolerance used when a tolerance is zero.
+const defaultTolerance = 1e-9
+
+// ErrInvalidGolden signals that a golden file can't be decoded.
+var ErrInvalidGolden = errors.New("regressiontest: invalid golden file")
+
+// TestingT is the part of *testing.T used by AssertModelMatches.
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+}
+
+// Tolerances are the accepted differences of the coefficients and metrics from the golden values.
+// A value matches when it is within the tolerance of the golden value, relative to the magnitude of the
+// golden value when it is above one. A zero tolerance is 1e-9.
+type Tolerances struct {
+	Coefficients float64
+	Metrics      float64
+}
+
+// Golden is the content of a golden file: the names and values of the coefficients, the offset first,
+// and the metrics of the model card.
+type Golden struct {
+	Observed     string             `json:"observed"`
+	Variables    []string           `json:"variables"`
+	Coefficients []float64          `json:"coefficients"`
+	Metrics      map[string]float64 `json:"metrics"`
+}
+
+// GoldenOf returns the golden values of a fitted model.
+func GoldenOf(model *regression.Regression) (*Golden, error) {
+	card, err := model.ModelCard()
+	if err != nil {
+		return nil, err
+	}
+	return &Golden{
+		Observed:     card.Observed,
+		Variables:    card.Variables,
+		Coefficients: card.Coefficients,
+		Metrics:      card.Metrics,
+	}, nil
+}
+
+// WriteGolden writes the golden file of a fitted model.
+func WriteGolden(file string, model *regression.Regression) error {
+	g, err := GoldenOf(model)
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, append(b, '\n'), 0644)
+}
+
+// LoadGolden reads a golden file.
+func LoadGolden(file string) (*Golden, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	g := new(Golden)
+	if err := json.Unmarshal(b, g); err != nil {
+		return nil, ErrInvalidGolden
+	}
+	if len(g.Variables)+1 != len(g.Coefficients) {
+		return nil, ErrInvalidGolden
+	}
+	return g, nil
+}
+
+// Compare lists the differences of a fitted model from the golden values beyond the tolerances,
+// one line per difference.
+func Compare(model *regression.Regression, golden *Golden, tol Tolerances) ([]string, error) {
+	got, err := GoldenOf(model)
+	if err != nil {
+		return nil, err
+	}
+	var diffs []string
+	if got.Observed != golden.Observed {
+		diffs = append(diffs, fmt.Sprintf("observed: expected %q, got %q", golden.Observed, got.Observed))
+	}
+	if len(got.Coefficients) != len(golden.Coefficients) {
+		diffs = append(diffs, fmt.Sprintf("coefficients: expected %d, got %d", len(golden.Coefficients), len(got.Coefficients)))
+		return diffs, nil
+	}
+	for i, want := range golden.Coefficients {
+		name := "(offset)"
+		if i > 0 {
+			name = golden.Variables[i-1]
+			if got.Variables[i-1] != name {
+				diffs = append(diffs, fmt.Sprintf("coefficient %d: expected variable %q, got %q", i, name, got.Variables[i-1]))
+			}
+		}
+		if !within(got.Coefficients[i], want, tol.Coefficients) {
+			diffs = append(diffs, fmt.Sprintf("coefficient %s: expected %v, got %v", name, want, got.Coefficients[i]))
+		}
+	}
+
+	names := make([]string, 0, len(golden.Metrics))
+	for name := range golden.Metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		want := golden.Metrics[name]
+		v, ok := got.Metrics[name]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("metric %s: expected %v, got none", name, want))
+		} else if !within(v, want, tol.Metrics) {
+			diffs = append(diffs, fmt.Sprintf("metric %s: expected %v, got %v", name, want, v))
+		}
+	}
+	return diffs, nil
+}
+
+// AssertModelMatches fails the test when the coefficients or metrics of a fitted model differ from those
+// of the golden file beyond the tolerances. With REGRESSION_UPDATE_GOLDEN=1 it writes the golden file instead.
+func AssertModelMatches(t TestingT, model *regression.Regression, goldenFile string, tol Tolerances) {
+	if os.Getenv(UpdateEnv) == "1" {
+		if err := WriteGolden(goldenFile, model); err != nil {
+			t.Fatalf("regressiontest: writing %s: %v", goldenFile, err)
+		}
+		return
+	}
+	golden, err := LoadGolden(goldenFile)
+	if err != nil {
+		t.Fatalf("regressiontest: loading %s (run with %s=1 to create it): %v", goldenFile, UpdateEnv, err)
+		return
+	}
+	diffs, err := Compare(model, golden, tol)
+	if err != nil {
+		t.Fatalf("regressiontest: %v", err)
+		return
+	}
+	for _, d := range diffs {
+		t.Errorf("regressiontest: %s: %s", goldenFile, d)
+	}
+}
+
+func within(got, want, tol float64) bool {
+	if tol == 0 {
+		tol = defaultTolerance
+	}
+	return math.Abs(got-want) <= tol*math.Max(1, math.Abs(want))
+}
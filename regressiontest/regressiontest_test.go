@@ -0,0 +1,94 @@
+package regressiontest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sajari/regression"
+)
+
+// recorder is a TestingT that records the failures.
+type recorder struct {
+	errors []string
+	fatal  string
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.fatal = fmt.Sprintf(format, args...)
+}
+
+func fitLine(t *testing.T, slope float64) *regression.Regression {
+	r := new(regression.Regression)
+	r.SetObserved("y")
+	r.SetVar(0, "x")
+	for i := 0; i < 20; i++ {
+		x := float64(i)
+		r.Train(regression.DataPoint(1+slope*x+0.1*float64(i%3), []float64{x}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestAssertModelMatches(t *testing.T) {
+	dir, err := ioutil.TempDir("", "regressiontest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "line.json")
+
+	rec := new(recorder)
+	AssertModelMatches(rec, fitLine(t, 2), file, Tolerances{})
+	if !strings.Contains(rec.fatal, UpdateEnv) {
+		t.Errorf("Expected a hint to create the golden file, got %q", rec.fatal)
+	}
+
+	os.Setenv(UpdateEnv, "1")
+	rec = new(recorder)
+	AssertModelMatches(rec, fitLine(t, 2), file, Tolerances{})
+	os.Unsetenv(UpdateEnv)
+	if rec.fatal != "" || len(rec.errors) != 0 {
+		t.Fatalf("Unexpected failures writing the golden file: %q %v", rec.fatal, rec.errors)
+	}
+	golden, err := LoadGolden(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if golden.Observed != "y" || len(golden.Variables) != 1 || golden.Variables[0] != "x" || golden.Metrics["r2"] == 0 {
+		t.Errorf("Unexpected golden file %+v", golden)
+	}
+
+	rec = new(recorder)
+	AssertModelMatches(rec, fitLine(t, 2), file, Tolerances{})
+	if rec.fatal != "" || len(rec.errors) != 0 {
+		t.Errorf("Expected the same model to match, got %q %v", rec.fatal, rec.errors)
+	}
+
+	rec = new(recorder)
+	AssertModelMatches(rec, fitLine(t, 2.001), file, Tolerances{})
+	if len(rec.errors) == 0 || !strings.Contains(rec.errors[0], "coefficient x: expected 2") {
+		t.Errorf("Expected the changed slope to fail, got %v", rec.errors)
+	}
+	rec = new(recorder)
+	AssertModelMatches(rec, fitLine(t, 2.001), file, Tolerances{Coefficients: 0.01, Metrics: 0.01})
+	if len(rec.errors) != 0 {
+		t.Errorf("Expected the changed slope to be within tolerance, got %v", rec.errors)
+	}
+
+	if err := ioutil.WriteFile(file, []byte(`{"variables": ["x"], "coefficients": [1]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadGolden(file); err != ErrInvalidGolden {
+		t.Errorf("Expected ErrInvalidGolden, got %v", err)
+	}
+}
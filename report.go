@@ -0,0 +1,28 @@
+package regression
+
+// FitReport describes how the model was fitted.
+type FitReport struct {
+	// Observations is the number of data points with a nonzero weight.
+	Observations int
+	// Parameters is the number of coefficients, including the offset.
+	Parameters int
+	// Aliased lists the coefficients that were left out of the fit, see Diagnostics.
+	Aliased []int
+	// Underdetermined is set when there are more parameters than observations. Such a fit is only
+	// well-posed with a WideSolver, such as RidgeSolver with a positive Lambda or PLSSolver, and there are
+	// no residual degrees of freedom: standard errors, p-values and F tests are not available.
+	Underdetermined bool
+}
+
+// FitReport returns the report of the fit.
+func (r *Regression) FitReport() (*FitReport, error) {
+	if !r.hasRun || len(r.coeff) == 0 {
+		return nil, ErrNotRun
+	}
+	return &FitReport{
+		Observations:    r.observations,
+		Parameters:      len(r.coeff),
+		Aliased:         append([]int(nil), r.aliased...),
+		Underdetermined: r.observations < len(r.coeff),
+	}, nil
+}
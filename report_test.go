@@ -0,0 +1,76 @@
+package regression
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFitReport(t *testing.T) {
+	r := new(Regression)
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i], 2 * carsSpeed[i]}))
+	}
+	if _, err := r.FitReport(); err != ErrNotRun {
+		t.Errorf("Expected ErrNotRun, got %v", err)
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	report, err := r.FitReport()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Observations != 50 || report.Parameters != 3 || report.Underdetermined {
+		t.Errorf("Unexpected report %+v", report)
+	}
+	if len(report.Aliased) != 1 || report.Aliased[0] != 2 {
+		t.Errorf("Expected the doubled speed to be aliased, got %v", report.Aliased)
+	}
+}
+
+func TestRidgeWide(t *testing.T) {
+	// 8 variables and 5 observations
+	rows := [][]float64{
+		{1, 2, 0, 1, 3, 1, 0, 2},
+		{2, 1, 1, 0, 1, 2, 1, 0},
+		{0, 1, 2, 2, 0, 1, 3, 1},
+		{3, 0, 1, 1, 2, 0, 1, 3},
+		{1, 3, 3, 0, 1, 2, 0, 1},
+	}
+	observed := []float64{10, 8, 9, 12, 11}
+	build := func() *Regression {
+		r := new(Regression)
+		for i, vars := range rows {
+			r.Train(DataPoint(observed[i], append([]float64(nil), vars...)))
+		}
+		return r
+	}
+
+	if err := build().Run(); err != ErrTooManyVars {
+		t.Errorf("Expected ErrTooManyVars with least squares, got %v", err)
+	}
+	unpenalized := build()
+	unpenalized.SetSolver(RidgeSolver{})
+	if err := unpenalized.Run(); err != ErrTooManyVars {
+		t.Errorf("Expected ErrTooManyVars without a penalty, got %v", err)
+	}
+
+	r := build()
+	r.SetSolver(RidgeSolver{Lambda: 0.1})
+	if err := r.Run(); err != nil {
+		t.Fatalf("Expected ridge to fit more variables than observations, got %v", err)
+	}
+	for i, vars := range rows {
+		p, _ := r.Predict(vars)
+		if math.Abs(p-observed[i]) > 0.5 {
+			t.Errorf("Expected a close fit of %v, got %v", observed[i], p)
+		}
+	}
+	report, _ := r.FitReport()
+	if !report.Underdetermined || report.Parameters != 9 || report.Observations != 5 {
+		t.Errorf("Unexpected report %+v", report)
+	}
+	if !math.IsNaN(r.PValue(1)) {
+		t.Error("Expected no p-values without residual degrees of freedom")
+	}
+}
@@ -0,0 +1,88 @@
+package regression
+
+import "fmt"
+
+// RunPolicy selects what RunIfChanged does when the training data or the feature crosses changed
+// since the last fit.
+type RunPolicy int
+
+const (
+	// RefitOnChange refits the model on the changed training data.
+	RefitOnChange RunPolicy = iota
+	// ErrorOnChange returns ErrRegressionRun, as Run does.
+	ErrorOnChange
+)
+
+// SetRunPolicy sets what RunIfChanged does when the training data or the feature crosses changed.
+func (r *Regression) SetRunPolicy(p RunPolicy) {
+	r.runPolicy = p
+}
+
+// RunIfChanged runs the regression unless it has already been fitted with the same training data and
+// feature crosses, which makes pipeline steps idempotent. The training data is compared by its hash, see
+// DataHash. If the data or the crosses changed, the model is refitted or ErrRegressionRun is returned,
+// depending on the policy set with SetRunPolicy. It reports whether the data or the crosses changed.
+// A model fitted by RunFixedEffects is refitted with RunFixedEffects. Data points passed to Update are not
+// part of the training data, so they are dropped by a refit.
+func (r *Regression) RunIfChanged() (bool, error) {
+	r.DecompressData()
+	if !r.hasRun {
+		return true, r.Run()
+	}
+	if !r.trainingChanged() {
+		return false, nil
+	}
+	if r.runPolicy == ErrorOnChange {
+		return true, ErrRegressionRun
+	}
+
+	fixed := r.fixedEffects != nil
+	r.Reset()
+	if fixed {
+		return true, r.RunFixedEffects()
+	}
+	return true, r.Run()
+}
+
+// Reset discards the fit so Run can be called again, for example after Train added data points to a
+// loaded model. The feature crosses materialized by the last run are removed from the data points, so
+// they are not applied twice. The coefficients remain until the next run replaces them.
+func (r *Regression) Reset() {
+	r.DecompressData()
+	r.uncross()
+	if r.online != nil {
+		// the statistics of Update belong to the previous fit
+		r.online.stats, r.online.baseline = nil, nil
+	}
+	r.initialised = len(r.data) > 2
+	r.hasRun = false
+}
+
+// trainingChanged reports whether the training data or the feature crosses differ from the last fit.
+func (r *Regression) trainingChanged() bool {
+	if len(r.data) != r.fitted || r.crossesSignature() != r.fittedCrosses {
+		return true
+	}
+	h := newDataHasher(OrderedHash)
+	for i := range r.data {
+		d := r.trainedPoint(i)
+		view := *d
+		// compare the missing values as trained, not as imputed
+		view.Variables = r.baseVariables(d)
+		h.add(&view)
+	}
+	return h.String() != r.fittedHash
+}
+
+// crossesSignature identifies the feature crosses of the model.
+func (r *Regression) crossesSignature() string {
+	sig := make([]interface{}, len(r.crosses))
+	for i, cross := range r.crosses {
+		if spec, err := specOf(cross); err == nil {
+			sig[i] = spec
+		} else {
+			sig[i] = fmt.Sprintf("%p", cross)
+		}
+	}
+	return fmt.Sprint(sig)
+}
@@ -0,0 +1,174 @@
+package regression
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRunIfChanged(t *testing.T) {
+	r := new(Regression)
+	r.AddCross(PowCross(0, 2))
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	if changed, err := r.RunIfChanged(); !changed || err != nil {
+		t.Fatalf("Expected the first call to run, got %v, %v", changed, err)
+	}
+	coeff := r.Coeff(2)
+	if changed, err := r.RunIfChanged(); changed || err != nil {
+		t.Errorf("Expected a no-op, got %v, %v", changed, err)
+	}
+
+	// changing a data point refits the model
+	r.data[0].Observed += 50
+	if changed, err := r.RunIfChanged(); !changed || err != nil {
+		t.Fatalf("Expected a refit, got %v, %v", changed, err)
+	}
+	if r.Coeff(2) == coeff || len(r.data[0].Variables) != 2 {
+		t.Errorf("Expected a refit with the crosses applied once, got %v and %v", r.Coeff(2), r.data[0].Variables)
+	}
+
+	// as does adding a data point or a feature cross
+	r.Train(DataPoint(100, []float64{30}))
+	if changed, err := r.RunIfChanged(); !changed || err != nil || r.Observations() != len(carsSpeed)+1 {
+		t.Fatalf("Expected a refit on the new data point, got %v, %v", changed, err)
+	}
+	r.AddCross(PowCross(0, 3))
+	if changed, err := r.RunIfChanged(); !changed || err != nil || len(r.coeff) != 4 {
+		t.Fatalf("Expected a refit with the new cross, got %v, %v", changed, err)
+	}
+
+	r.SetRunPolicy(ErrorOnChange)
+	if changed, err := r.RunIfChanged(); changed || err != nil {
+		t.Errorf("Expected a no-op, got %v, %v", changed, err)
+	}
+	r.Train(DataPoint(100, []float64{30}))
+	if changed, err := r.RunIfChanged(); !changed || err != ErrRegressionRun {
+		t.Errorf("Expected ErrRegressionRun, got %v, %v", changed, err)
+	}
+}
+
+func TestRunIfChangedFixedEffects(t *testing.T) {
+	r := new(Regression)
+	for i := range carsSpeed {
+		r.Train(GroupedDataPoint(carsDist[i], []float64{carsSpeed[i]}, []string{"a", "b", "c"}[i%3]))
+	}
+	if err := r.RunFixedEffects(); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := r.RunIfChanged(); changed || err != nil {
+		t.Errorf("Expected a no-op after RunFixedEffects, got %v, %v", changed, err)
+	}
+
+	// a refit estimates the fixed effects again
+	fresh := new(Regression)
+	for i, d := range r.data {
+		observed := d.Observed
+		if i == 0 {
+			observed += 50
+		}
+		fresh.Train(GroupedDataPoint(observed, []float64{carsSpeed[i]}, d.Group))
+	}
+	if err := fresh.RunFixedEffects(); err != nil {
+		t.Fatal(err)
+	}
+	r.data[0].Observed += 50
+	if changed, err := r.RunIfChanged(); !changed || err != nil {
+		t.Fatalf("Expected a refit, got %v, %v", changed, err)
+	}
+	assertClose(t, "slope", r.Coeff(1), fresh.Coeff(1), 1e-9)
+	got, _ := r.FixedEffect("a")
+	want, _ := fresh.FixedEffect("a")
+	assertClose(t, "fixed effect", got, want, 1e-9)
+}
+
+func TestResetAppliesCrossesOnce(t *testing.T) {
+	r := new(Regression)
+	r.AddCross(PowCross(0, 2))
+	r.AddCross(MultiplierCross(0, 1))
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	want := r.coeffs()
+	for i := 0; i < 3; i++ {
+		r.Reset()
+		if err := r.Run(); err != nil {
+			t.Fatalf("run %d: %v", i, err)
+		}
+	}
+	if len(r.data[0].Variables) != 3 {
+		t.Errorf("Expected the crosses applied once, got %v", r.data[0].Variables)
+	}
+	for i, c := range r.coeffs() {
+		assertClose(t, r.coeffNames()[i], c, want[i], 1e-9)
+	}
+}
+
+func TestLoadTrainRun(t *testing.T) {
+	r := new(Regression)
+	r.AddCross(PowCross(0, 2))
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := r.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	for cycle := 0; cycle < 2; cycle++ {
+		loaded, err := Load(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := loaded.Run(); err != ErrRegressionRun {
+			t.Fatalf("Expected ErrRegressionRun on a loaded model, got %v", err)
+		}
+		loaded.Reset()
+		if err := loaded.Run(); err != ErrNotEnoughData {
+			t.Fatalf("Expected ErrNotEnoughData without training data, got %v", err)
+		}
+		for i := range carsSpeed {
+			loaded.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+		}
+		if err := loaded.Run(); err != nil {
+			t.Fatal(err)
+		}
+		if len(loaded.data[0].Variables) != 2 || len(loaded.coeff) != 3 {
+			t.Fatalf("Expected one cross column, got %v", loaded.data[0].Variables)
+		}
+		for i, c := range loaded.coeffs() {
+			assertClose(t, loaded.coeffNames()[i], c, r.Coeff(i), 1e-9)
+		}
+		buf.Reset()
+		if err := loaded.Save(&buf); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSharedPointsCrossedOnce(t *testing.T) {
+	var points []*dataPoint
+	for i := range carsSpeed {
+		points = append(points, DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	first, second := new(Regression), new(Regression)
+	for _, r := range []*Regression{first, second} {
+		r.AddCross(PowCross(0, 2))
+		r.Train(points...)
+		if err := r.Run(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(points[0].Variables) != 2 {
+		t.Errorf("Expected the crosses applied once, got %v", points[0].Variables)
+	}
+	for i := range first.coeff {
+		assertClose(t, "coefficient", second.Coeff(i), first.Coeff(i), 1e-9)
+	}
+}
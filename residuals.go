@@ -0,0 +1,61 @@
+package regression
+
+import "sort"
+
+// ResidualBucket summarizes the residuals of the data points in one quantile bucket of a variable.
+// A positive MeanResidual means the model under-predicts in the bucket, a negative one that it over-predicts.
+type ResidualBucket struct {
+	Count        int
+	Min          float64
+	Max          float64
+	MeanResidual float64
+	MeanAbsolute float64
+}
+
+// ResidualByBucket buckets the training data into quantile buckets of variable varIndex and reports the
+// weighted mean residual (observed minus predicted) per bucket, so regions where the model is
+// systematically off can be found programmatically or charted, e.g. as one row of a heatmap per variable.
+// Fewer buckets are returned when there are fewer data points.
+func (r *Regression) ResidualByBucket(varIndex, buckets int) ([]ResidualBucket, error) {
+	if !r.hasRun || len(r.coeff) == 0 {
+		return nil, ErrNotRun
+	}
+	if buckets < 1 {
+		return nil, ErrInvalidBins
+	}
+	if len(r.data) == 0 {
+		return nil, ErrNotEnoughData
+	}
+	if varIndex < 0 || varIndex >= r.names.base {
+		return nil, ErrDimensions
+	}
+	if buckets > len(r.data) {
+		buckets = len(r.data)
+	}
+	points := make([]*dataPoint, len(r.data))
+	copy(points, r.data)
+	sort.SliceStable(points, func(i, j int) bool { return points[i].Variables[varIndex] < points[j].Variables[varIndex] })
+
+	result := make([]ResidualBucket, buckets)
+	for b := range result {
+		group := points[b*len(points)/buckets : (b+1)*len(points)/buckets]
+		var weights, residual, absolute float64
+		for _, p := range group {
+			e := p.Observed - p.Predicted
+			weights += p.Weight
+			residual += p.Weight * e
+			if e < 0 {
+				e = -e
+			}
+			absolute += p.Weight * e
+		}
+		result[b] = ResidualBucket{
+			Count:        len(group),
+			Min:          group[0].Variables[varIndex],
+			Max:          group[len(group)-1].Variables[varIndex],
+			MeanResidual: residual / weights,
+			MeanAbsolute: absolute / weights,
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,51 @@
+package regression
+
+import "testing"
+
+func TestResidualByBucket(t *testing.T) {
+	// a straight line fitted to a parabola over-predicts in the middle and under-predicts at the ends
+	r := new(Regression)
+	for i := -10; i <= 10; i++ {
+		x := float64(i)
+		r.Train(DataPoint(x*x, []float64{x}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	buckets, err := r.ResidualByBucket(0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(buckets) != 3 {
+		t.Fatalf("Expected 3 buckets, got %d", len(buckets))
+	}
+	if buckets[0].Count != 7 || buckets[0].Min != -10 || buckets[0].Max != -4 {
+		t.Errorf("Unexpected first bucket %+v", buckets[0])
+	}
+	if buckets[0].MeanResidual <= 0 || buckets[1].MeanResidual >= 0 || buckets[2].MeanResidual <= 0 {
+		t.Errorf("Expected under, over and under-prediction, got %+v", buckets)
+	}
+	var total float64
+	for _, b := range buckets {
+		total += float64(b.Count) * b.MeanResidual
+		if b.MeanAbsolute < b.MeanResidual || b.MeanAbsolute < -b.MeanResidual {
+			t.Errorf("Mean absolute residual below mean residual in %+v", b)
+		}
+	}
+	assertClose(t, "total residual", total, 0, 1e-9)
+
+	buckets, err = r.ResidualByBucket(0, 100)
+	if err != nil || len(buckets) != 21 {
+		t.Errorf("Expected one bucket per data point, got %d, %v", len(buckets), err)
+	}
+	if _, err := r.ResidualByBucket(1, 3); err != ErrDimensions {
+		t.Errorf("Expected ErrDimensions, got %v", err)
+	}
+	if _, err := r.ResidualByBucket(0, 0); err != ErrInvalidBins {
+		t.Errorf("Expected ErrInvalidBins, got %v", err)
+	}
+	if _, err := new(Regression).ResidualByBucket(0, 3); err != ErrNotRun {
+		t.Errorf("Expected ErrNotRun, got %v", err)
+	}
+}
@@ -0,0 +1,216 @@
+package regression
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrCircuitOpen signals that loading was skipped because recent loads failed, see LoadPolicy.
+	ErrCircuitOpen = errors.New("storage circuit breaker is open")
+	// ErrLoadTimeout signals that loading a model took longer than LoadPolicy.Timeout.
+	ErrLoadTimeout = errors.New("loading the model timed out")
+	// ErrRateLimited signals that loading was skipped because the previous load was too recent.
+	ErrRateLimited = errors.New("model loads are rate limited")
+)
+
+// LoadPolicy configures how a ResilientStorage loads models. Zero values select the defaults.
+type LoadPolicy struct {
+	// Attempts is the number of tries of a load, 3 by default.
+	Attempts int
+	// Backoff is the delay before the first retry, doubled for each further retry up to MaxBackoff.
+	// The defaults are 100ms and 5s.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// Timeout bounds every try, without a bound by default. A try that times out keeps running in
+	// the background until the storage returns, as Storage.Load can't be cancelled.
+	Timeout time.Duration
+	// FailureThreshold is the number of consecutive failed loads after which the circuit breaker
+	// opens, 5 by default. While it is open loads fail with ErrCircuitOpen without calling the storage.
+	FailureThreshold int
+	// Cooldown is how long the breaker stays open, 30s by default. The first load after it is let
+	// through and closes the breaker again if it succeeds.
+	Cooldown time.Duration
+	// MinInterval is the minimum time between loads, which fail with ErrRateLimited in between.
+	// Loads aren't rate limited by default.
+	MinInterval time.Duration
+}
+
+func (p LoadPolicy) withDefaults() LoadPolicy {
+	if p.Attempts <= 0 {
+		p.Attempts = 3
+	}
+	if p.Backoff <= 0 {
+		p.Backoff = 100 * time.Millisecond
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = 5 * time.Second
+	}
+	if p.FailureThreshold <= 0 {
+		p.FailureThreshold = 5
+	}
+	if p.Cooldown <= 0 {
+		p.Cooldown = 30 * time.Second
+	}
+	return p
+}
+
+// ResilientStorage wraps a Storage, typically a remote blob store, retrying failed loads with
+// exponential backoff, bounding them with a timeout and failing fast with a circuit breaker while the
+// store is down. ErrModelNotFound and ErrInvalidKey are returned as is, without retries. Saves are
+// passed through. It is safe for concurrent use.
+type ResilientStorage struct {
+	storage Storage
+	policy  LoadPolicy
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	lastLoad  time.Time
+}
+
+var _ Storage = (*ResilientStorage)(nil)
+
+// NewResilientStorage wraps s with the given policy.
+func NewResilientStorage(s Storage, p LoadPolicy) *ResilientStorage {
+	return &ResilientStorage{storage: s, policy: p.withDefaults()}
+}
+
+// Save stores r under key in the wrapped storage.
+func (s *ResilientStorage) Save(key string, r *Regression) error {
+	return s.storage.Save(key, r)
+}
+
+// Load returns the model stored under key, retrying failures as set by the policy.
+func (s *ResilientStorage) Load(key string) (*Regression, error) {
+	if err := s.admit(); err != nil {
+		return nil, err
+	}
+	backoff := s.policy.Backoff
+	var err error
+	for attempt := 0; attempt < s.policy.Attempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			if backoff *= 2; backoff > s.policy.MaxBackoff {
+				backoff = s.policy.MaxBackoff
+			}
+		}
+		var r *Regression
+		r, err = s.try(key)
+		if err == nil || err == ErrModelNotFound || err == ErrInvalidKey {
+			s.record(true)
+			return r, err
+		}
+	}
+	s.record(false)
+	return nil, err
+}
+
+// CircuitOpen reports whether the circuit breaker is open.
+func (s *ResilientStorage) CircuitOpen() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return time.Now().Before(s.openUntil)
+}
+
+// admit checks the circuit breaker and the rate limit before a load.
+func (s *ResilientStorage) admit() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if now.Before(s.openUntil) {
+		return ErrCircuitOpen
+	}
+	if s.policy.MinInterval > 0 && now.Sub(s.lastLoad) < s.policy.MinInterval {
+		return ErrRateLimited
+	}
+	s.lastLoad = now
+	return nil
+}
+
+// record counts the outcome of a load, opening the breaker after too many consecutive failures.
+func (s *ResilientStorage) record(ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ok {
+		s.failures = 0
+		return
+	}
+	if s.failures++; s.failures >= s.policy.FailureThreshold {
+		s.openUntil = time.Now().Add(s.policy.Cooldown)
+		// after the cooldown a single failure opens the breaker again
+		s.failures = s.policy.FailureThreshold - 1
+	}
+}
+
+func (s *ResilientStorage) try(key string) (*Regression, error) {
+	if s.policy.Timeout <= 0 {
+		return s.storage.Load(key)
+	}
+	type result struct {
+		r   *Regression
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		r, err := s.storage.Load(key)
+		done <- result{r, err}
+	}()
+	timer := time.NewTimer(s.policy.Timeout)
+	defer timer.Stop()
+	select {
+	case res := <-done:
+		return res.r, res.err
+	case <-timer.C:
+		return nil, ErrLoadTimeout
+	}
+}
+
+// StoredModel serves a model loaded from a Storage and refreshes it on demand. A failed refresh keeps
+// the current model, so scoring degrades to the last good version while the store is unavailable.
+// It is safe for concurrent use.
+type StoredModel struct {
+	storage Storage
+	key     string
+	model   atomic.Value
+	canary  *Canary
+}
+
+// NewStoredModel loads the model stored under key. Wrap s with NewResilientStorage to retry loads.
+func NewStoredModel(s Storage, key string) (*StoredModel, error) {
+	m := &StoredModel{storage: s, key: key}
+	if err := m.Refresh(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// SetCanary evaluates every model loaded by Refresh with c before swapping it in. Set it before
+// refreshing concurrently.
+func (m *StoredModel) SetCanary(c Canary) {
+	m.canary = &c
+}
+
+// Refresh loads the latest version of the model. On error the current model is kept and the error
+// returned, e.g. to be logged. A model rejected by the canary is reported with a *CanaryError.
+func (m *StoredModel) Refresh() error {
+	r, err := m.storage.Load(m.key)
+	if err != nil {
+		return err
+	}
+	if m.canary != nil {
+		if _, err := m.canary.Evaluate(m.Model(), r); err != nil {
+			return err
+		}
+	}
+	m.model.Store(r)
+	return nil
+}
+
+// Model returns the last model loaded.
+func (m *StoredModel) Model() *Regression {
+	r, _ := m.model.Load().(*Regression)
+	return r
+}
@@ -0,0 +1,144 @@
+package regression
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+var errUnavailable = errors.New("store unavailable")
+
+// flakyStorage fails the next failures loads, and sleeps delay in every load.
+type flakyStorage struct {
+	mu       sync.Mutex
+	model    *Regression
+	failures int
+	delay    time.Duration
+	calls    int
+}
+
+func (s *flakyStorage) Save(key string, r *Regression) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.model = r
+	return nil
+}
+
+func (s *flakyStorage) Load(key string) (*Regression, error) {
+	s.mu.Lock()
+	s.calls++
+	fail := s.failures > 0
+	if fail {
+		s.failures--
+	}
+	delay, model := s.delay, s.model
+	s.mu.Unlock()
+	time.Sleep(delay)
+	if fail {
+		return nil, errUnavailable
+	}
+	if model == nil {
+		return nil, ErrModelNotFound
+	}
+	return model, nil
+}
+
+func (s *flakyStorage) set(failures int, delay time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures, s.delay, s.calls = failures, delay, 0
+}
+
+func TestResilientStorageRetries(t *testing.T) {
+	flaky := &flakyStorage{model: carsRegression(t)}
+	s := NewResilientStorage(flaky, LoadPolicy{Attempts: 3, Backoff: time.Millisecond})
+
+	flaky.set(2, 0)
+	if r, err := s.Load("cars"); err != nil || r != flaky.model || flaky.calls != 3 {
+		t.Errorf("Expected a load after two retries, got %v after %d calls", err, flaky.calls)
+	}
+	flaky.set(3, 0)
+	if _, err := s.Load("cars"); err != errUnavailable || flaky.calls != 3 {
+		t.Errorf("Expected the storage error after 3 calls, got %v after %d calls", err, flaky.calls)
+	}
+
+	flaky.model = nil
+	flaky.set(0, 0)
+	if _, err := s.Load("cars"); err != ErrModelNotFound || flaky.calls != 1 {
+		t.Errorf("Expected ErrModelNotFound without retries, got %v after %d calls", err, flaky.calls)
+	}
+}
+
+func TestResilientStorageTimeout(t *testing.T) {
+	flaky := &flakyStorage{model: carsRegression(t)}
+	s := NewResilientStorage(flaky, LoadPolicy{Attempts: 1, Timeout: 5 * time.Millisecond})
+	flaky.set(0, 200*time.Millisecond)
+	start := time.Now()
+	if _, err := s.Load("cars"); err != ErrLoadTimeout {
+		t.Errorf("Expected ErrLoadTimeout, got %v", err)
+	}
+	if d := time.Since(start); d > 100*time.Millisecond {
+		t.Errorf("Expected the load to time out early, took %v", d)
+	}
+}
+
+func TestResilientStorageCircuitBreaker(t *testing.T) {
+	flaky := &flakyStorage{model: carsRegression(t)}
+	s := NewResilientStorage(flaky, LoadPolicy{Attempts: 1, FailureThreshold: 2, Cooldown: 20 * time.Millisecond})
+
+	flaky.set(100, 0)
+	s.Load("cars")
+	s.Load("cars")
+	if !s.CircuitOpen() {
+		t.Fatal("Expected the breaker open after 2 failures")
+	}
+	if _, err := s.Load("cars"); err != ErrCircuitOpen || flaky.calls != 2 {
+		t.Errorf("Expected ErrCircuitOpen without calling the storage, got %v after %d calls", err, flaky.calls)
+	}
+
+	// after the cooldown a single failure opens it again, a success closes it
+	time.Sleep(30 * time.Millisecond)
+	if _, err := s.Load("cars"); err != errUnavailable || !s.CircuitOpen() {
+		t.Errorf("Expected the breaker open again, got %v", err)
+	}
+	time.Sleep(30 * time.Millisecond)
+	flaky.set(0, 0)
+	if _, err := s.Load("cars"); err != nil || s.CircuitOpen() {
+		t.Errorf("Expected the breaker closed, got %v", err)
+	}
+}
+
+func TestResilientStorageRateLimit(t *testing.T) {
+	flaky := &flakyStorage{model: carsRegression(t)}
+	s := NewResilientStorage(flaky, LoadPolicy{MinInterval: time.Hour})
+	if _, err := s.Load("cars"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Load("cars"); err != ErrRateLimited {
+		t.Errorf("Expected ErrRateLimited, got %v", err)
+	}
+}
+
+func TestStoredModelKeepsModel(t *testing.T) {
+	first := carsRegression(t)
+	flaky := &flakyStorage{model: first}
+	m, err := NewStoredModel(NewResilientStorage(flaky, LoadPolicy{Attempts: 2, Backoff: time.Millisecond}), "cars")
+	if err != nil {
+		t.Fatal(err)
+	}
+	flaky.set(10, 0)
+	if err := m.Refresh(); err != errUnavailable || m.Model() != first {
+		t.Errorf("Expected the current model kept, got %v", err)
+	}
+	second := carsRegression(t)
+	flaky.Save("cars", second)
+	flaky.set(1, 0)
+	if err := m.Refresh(); err != nil || m.Model() != second {
+		t.Errorf("Expected the new model, got %v", err)
+	}
+
+	if _, err := NewStoredModel(&flakyStorage{}, "cars"); err != ErrModelNotFound {
+		t.Errorf("Expected ErrModelNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package regression
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+var (
+	// ErrSanityCheck signals that predictions of a sanity check are out of their expected bounds.
+	ErrSanityCheck = errors.New("sanity check failed")
+	// ErrInvalidBounds signals that the minimum of a sanity check case is above its maximum.
+	ErrInvalidBounds = errors.New("minimum is above maximum")
+)
+
+// SanityCase is a known input with the bounds its prediction is expected to stay within.
+type SanityCase struct {
+	In       []float64
+	Min, Max float64
+}
+
+// SanityFailure describes a case of a sanity check whose prediction failed or was out of bounds.
+type SanityFailure struct {
+	// Case is the index of the case.
+	Case      int
+	In        []float64
+	Predicted float64
+	Min, Max  float64
+	// Err is set when the prediction itself failed, or the input or bounds are invalid.
+	Err error
+}
+
+// SanityReport is the result of SanityCheck.
+type SanityReport struct {
+	// Checked is the number of cases checked.
+	Checked  int
+	Failures []SanityFailure
+}
+
+// String lists the failures of the report, one per line.
+func (s *SanityReport) String() string {
+	lines := []string{fmt.Sprintf("%d of %d cases failed", len(s.Failures), s.Checked)}
+	for _, f := range s.Failures {
+		if f.Err != nil {
+			lines = append(lines, fmt.Sprintf("case %d %v: %v", f.Case, f.In, f.Err))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("case %d %v: predicted %v, expected within [%v, %v]",
+			f.Case, f.In, f.Predicted, f.Min, f.Max))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SanityCheck verifies that the predictions for known inputs stay within their expected bounds, e.g. as a
+// deployment gate after training or loading a model. It returns ErrSanityCheck when any case fails, with
+// the report describing every failure.
+func (r *Regression) SanityCheck(cases []SanityCase) (*SanityReport, error) {
+	report := &SanityReport{Checked: len(cases)}
+	for i, c := range cases {
+		f := SanityFailure{Case: i, In: c.In, Predicted: math.NaN(), Min: c.Min, Max: c.Max}
+		if c.Min > c.Max {
+			f.Err = ErrInvalidBounds
+			report.Failures = append(report.Failures, f)
+			continue
+		}
+		if r.names.base > 0 && len(c.In) != r.names.base {
+			f.Err = ErrDimensions
+			report.Failures = append(report.Failures, f)
+			continue
+		}
+		f.Predicted, f.Err = r.Predict(c.In)
+		if f.Err != nil || !(f.Predicted >= c.Min && f.Predicted <= c.Max) {
+			report.Failures = append(report.Failures, f)
+		}
+	}
+	if len(report.Failures) > 0 {
+		return report, ErrSanityCheck
+	}
+	return report, nil
+}
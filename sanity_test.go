@@ -0,0 +1,46 @@
+package regression
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanityCheck(t *testing.T) {
+	r := carsRegression(t)
+	report, err := r.SanityCheck([]SanityCase{
+		{In: []float64{10}, Min: 15, Max: 30},
+		{In: []float64{20}, Min: 55, Max: 65},
+	})
+	if err != nil {
+		t.Fatalf("Expected the checks to pass, got %v\n%v", err, report)
+	}
+	if report.Checked != 2 || len(report.Failures) != 0 {
+		t.Errorf("Unexpected report %+v", report)
+	}
+
+	report, err = r.SanityCheck([]SanityCase{
+		{In: []float64{10}, Min: 15, Max: 30},
+		{In: []float64{20}, Min: 0, Max: 10},
+		{In: []float64{20}, Min: 10, Max: 0},
+		{In: []float64{20, 1}, Min: 0, Max: 100},
+	})
+	if err != ErrSanityCheck {
+		t.Fatalf("Expected ErrSanityCheck, got %v", err)
+	}
+	if len(report.Failures) != 3 {
+		t.Fatalf("Expected 3 failures, got %+v", report.Failures)
+	}
+	if f := report.Failures[0]; f.Case != 1 || f.Err != nil {
+		t.Errorf("Unexpected failure %+v", f)
+	}
+	assertClose(t, "predicted", report.Failures[0].Predicted, r.Coeff(0)+20*r.Coeff(1), 1e-9)
+	if f := report.Failures[1]; f.Case != 2 || f.Err != ErrInvalidBounds {
+		t.Errorf("Unexpected failure %+v", f)
+	}
+	if f := report.Failures[2]; f.Case != 3 || f.Err != ErrDimensions {
+		t.Errorf("Expected ErrDimensions, got %+v", f)
+	}
+	if s := report.String(); !strings.HasPrefix(s, "3 of 4 cases failed\ncase 1 [20]: predicted ") {
+		t.Errorf("Unexpected report %q", s)
+	}
+}
@@ -0,0 +1,166 @@
+package regression
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrNoSource signals that a Scheduler has no data source to retrain from.
+var ErrNoSource = errors.New("scheduler has no data source")
+
+// RetrainResult reports the outcome of one retraining by a Scheduler.
+type RetrainResult struct {
+	// Model is the newly fitted model, or nil if retraining failed.
+	Model *Regression
+	// Metrics are the goodness of fit measures of the new model.
+	Metrics      FitMetrics
+	Observations int
+	// Duration is how long fetching the data and fitting took.
+	Duration time.Duration
+	// Err is set if the data source or the fit failed, in which case the previous model is kept.
+	Err error
+}
+
+// Scheduler periodically retrains a model in a background goroutine and swaps the served model
+// atomically, so Model can be called concurrently with retraining. It is safe for concurrent use.
+type Scheduler struct {
+	source    func() ([]*dataPoint, error)
+	setup     func(r *Regression)
+	interval  time.Duration
+	onRetrain func(RetrainResult)
+
+	model   atomic.Value
+	mu      sync.Mutex
+	trigger chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+	start   sync.Once
+	halt    sync.Once
+}
+
+// NewScheduler creates a Scheduler which retrains a new model every interval on the data points returned
+// by source. The optional setup function configures each new model before it is run, e.g. its names,
+// feature crosses and solver. An interval of zero disables periodic retraining, leaving Trigger and Retrain.
+func NewScheduler(source func() ([]*dataPoint, error), setup func(r *Regression), interval time.Duration) *Scheduler {
+	return &Scheduler{
+		source:   source,
+		setup:    setup,
+		interval: interval,
+		trigger:  make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// OnRetrain sets a callback which receives the result of every retraining, including failed ones.
+// It is called from the retraining goroutine, so it should not block. Set it before calling Start.
+func (s *Scheduler) OnRetrain(f func(RetrainResult)) {
+	s.onRetrain = f
+}
+
+// Model returns the most recently fitted model, or nil if no retraining has succeeded yet.
+// The returned model is replaced rather than modified by later retrainings.
+func (s *Scheduler) Model() *Regression {
+	r, _ := s.model.Load().(*Regression)
+	return r
+}
+
+// Set replaces the served model, e.g. with one loaded from disk before the first retraining.
+func (s *Scheduler) Set(r *Regression) {
+	s.model.Store(r)
+}
+
+// Start runs the background goroutine. It returns immediately; calling it again has no effect.
+func (s *Scheduler) Start() {
+	s.start.Do(func() {
+		go s.loop()
+	})
+}
+
+// Trigger requests a retraining outside the schedule without waiting for it, e.g. when the Holdout
+// report of the served model is Degraded. Requests made while one is pending are coalesced.
+func (s *Scheduler) Trigger() {
+	select {
+	case s.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Stop stops the background goroutine, waiting for a retraining in progress to finish.
+// The served model remains available.
+func (s *Scheduler) Stop() {
+	s.halt.Do(func() {
+		close(s.stop)
+	})
+	s.start.Do(func() {
+		close(s.done)
+	})
+	<-s.done
+}
+
+func (s *Scheduler) loop() {
+	defer close(s.done)
+	var tick <-chan time.Time
+	if s.interval > 0 {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-tick:
+		case <-s.trigger:
+		}
+		s.Retrain()
+	}
+}
+
+// Retrain fetches the data, fits a new model and swaps it in if the fit succeeded. It blocks until
+// done and reports the result to the OnRetrain callback as the background retraining does.
+func (s *Scheduler) Retrain() RetrainResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	start := time.Now()
+	res := s.fit()
+	res.Duration = time.Since(start)
+	if res.Err == nil {
+		s.model.Store(res.Model)
+	}
+	if s.onRetrain != nil {
+		s.onRetrain(res)
+	}
+	return res
+}
+
+func (s *Scheduler) fit() RetrainResult {
+	if s.source == nil {
+		return RetrainResult{Err: ErrNoSource}
+	}
+	data, err := s.source()
+	if err != nil {
+		return RetrainResult{Err: err}
+	}
+	r := new(Regression)
+	if s.setup != nil {
+		s.setup(r)
+	}
+	r.Train(data...)
+	if err := r.Run(); err != nil {
+		return RetrainResult{Err: err}
+	}
+	return RetrainResult{
+		Model: r,
+		Metrics: FitMetrics{
+			VarianceObserved:  r.Varianceobserved,
+			VariancePredicted: r.VariancePredicted,
+			R2:                r.R2,
+			RMSE:              r.rmse,
+		},
+		Observations: r.observations,
+	}
+}
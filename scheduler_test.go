@@ -0,0 +1,92 @@
+package regression
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScheduler(t *testing.T) {
+	var calls int32
+	source := func() ([]*dataPoint, error) {
+		atomic.AddInt32(&calls, 1)
+		return carsPoints(false), nil
+	}
+	setup := func(r *Regression) {
+		r.SetObserved("dist")
+		r.SetVar(0, "speed")
+	}
+	s := NewScheduler(source, setup, 0)
+	if s.Model() != nil {
+		t.Fatal("expected no model before the first retraining")
+	}
+	results := make(chan RetrainResult, 10)
+	s.OnRetrain(func(res RetrainResult) { results <- res })
+
+	res := s.Retrain()
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	}
+	if s.Model() != res.Model || res.Observations != len(carsSpeed) {
+		t.Errorf("Retrain() = %+v, want the served model fitted on %d points", res, len(carsSpeed))
+	}
+	assertClose(t, "R2", res.Metrics.R2, 0.6511, 1e-4)
+	<-results
+
+	s.Start()
+	first := s.Model()
+	s.Trigger()
+	select {
+	case res := <-results:
+		if res.Err != nil {
+			t.Fatal(res.Err)
+		}
+		if s.Model() == first || s.Model() != res.Model {
+			t.Error("the triggered retraining didn't swap the model")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("triggered retraining didn't run")
+	}
+	s.Stop()
+	s.Stop()
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("source called %d times, want 2", got)
+	}
+}
+
+func TestSchedulerInterval(t *testing.T) {
+	fail := errors.New("source unavailable")
+	var calls int32
+	s := NewScheduler(func() ([]*dataPoint, error) {
+		if atomic.AddInt32(&calls, 1) > 1 {
+			return nil, fail
+		}
+		return carsPoints(false), nil
+	}, nil, time.Millisecond)
+	results := make(chan RetrainResult, 100)
+	s.OnRetrain(func(res RetrainResult) { results <- res })
+	s.Start()
+	defer s.Stop()
+
+	first := <-results
+	if first.Err != nil {
+		t.Fatal(first.Err)
+	}
+	second := <-results
+	if second.Err != fail {
+		t.Errorf("Err = %v, want %v", second.Err, fail)
+	}
+	if s.Model() != first.Model {
+		t.Error("a failed retraining should keep the previous model")
+	}
+}
+
+func TestSchedulerStopBeforeStart(t *testing.T) {
+	s := NewScheduler(nil, nil, time.Millisecond)
+	s.Stop()
+	s.Start()
+	if res := s.Retrain(); res.Err != ErrNoSource {
+		t.Errorf("Err = %v, want %v", res.Err, ErrNoSource)
+	}
+}
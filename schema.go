@@ -0,0 +1,230 @@
+package regression
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrColumnType signals a value that doesn't match the type of its column, e.g. 0.5 for a boolean.
+	ErrColumnType = errors.New("value does not match the column type")
+	// ErrIntOverflow signals an integer too large to be represented exactly as a float64.
+	ErrIntOverflow = errors.New("integer cannot be represented exactly")
+)
+
+// maxExactInt is the largest magnitude up to which every integer is exactly representable as a float64.
+const maxExactInt = 1 << 53
+
+// ColumnType is the type of a variable in the source data.
+type ColumnType int
+
+const (
+	// FloatColumn is the default type, taking any finite number.
+	FloatColumn ColumnType = iota
+	// IntColumn takes integers up to 2^53 in magnitude, which convert to float64 exactly.
+	IntColumn
+	// BoolColumn takes booleans, which convert to 0 and 1.
+	BoolColumn
+)
+
+func (t ColumnType) String() string {
+	switch t {
+	case FloatColumn:
+		return "float"
+	case IntColumn:
+		return "int"
+	case BoolColumn:
+		return "bool"
+	}
+	return "ColumnType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// Schema describes the observed value and the base variables of a model.
+type Schema struct {
+	Observed string
+	Vars     []string
+	Types    []ColumnType
+}
+
+// SetVarType sets the type of variable i in the source data. ConvertRow and ParseRow convert values
+// according to it, and Predict rejects values that the type can't take, e.g. 0.5 for a BoolColumn.
+func (r *Regression) SetVarType(i int, t ColumnType) {
+	if r.names.types == nil {
+		r.names.types = make(map[int]ColumnType)
+	}
+	r.names.types[i] = t
+}
+
+// GetVarType returns the type of variable i, FloatColumn unless set with SetVarType.
+func (r *Regression) GetVarType(i int) ColumnType {
+	return r.names.types[i]
+}
+
+// Schema returns the names and types of the observed value and the base variables. The number of
+// base variables is known once the model has been run; before that the variables with a name or type are listed.
+func (r *Regression) Schema() Schema {
+	n := r.names.base
+	if !r.hasRun {
+		for i := range r.names.vars {
+			n = maxInt(n, i+1)
+		}
+		for i := range r.names.types {
+			n = maxInt(n, i+1)
+		}
+	}
+	s := Schema{Observed: r.names.obs, Vars: make([]string, n), Types: make([]ColumnType, n)}
+	for i := 0; i < n; i++ {
+		s.Vars[i] = r.GetVar(i)
+		s.Types[i] = r.GetVarType(i)
+	}
+	return s
+}
+
+// ConvertRow converts the values of a row, e.g. as scanned from database/sql into []interface{},
+// to variables according to their types. Numbers of any Go type, booleans, strings, []byte and
+// json.Number are accepted.
+func (r *Regression) ConvertRow(values []interface{}) ([]float64, error) {
+	vars := make([]float64, len(values))
+	for i, v := range values {
+		var err error
+		if vars[i], err = ConvertValue(v, r.GetVarType(i)); err != nil {
+			return nil, fmt.Errorf("variable %q: %v", r.GetVar(i), err)
+		}
+	}
+	return vars, nil
+}
+
+// ParseRow parses the fields of a row, e.g. a CSV record, to variables according to their types.
+func (r *Regression) ParseRow(fields []string) ([]float64, error) {
+	values := make([]interface{}, len(fields))
+	for i, f := range fields {
+		values[i] = f
+	}
+	return r.ConvertRow(values)
+}
+
+// ConvertValue converts a value to a variable of type t: booleans to 0 and 1, integers to float64
+// with a check that they are represented exactly, and strings by parsing them.
+func ConvertValue(v interface{}, t ColumnType) (float64, error) {
+	switch x := v.(type) {
+	case bool:
+		if t != BoolColumn {
+			return 0, ErrColumnType
+		}
+		if x {
+			return 1, nil
+		}
+		return 0, nil
+	case int:
+		return convertInt(int64(x), t)
+	case int8:
+		return convertInt(int64(x), t)
+	case int16:
+		return convertInt(int64(x), t)
+	case int32:
+		return convertInt(int64(x), t)
+	case int64:
+		return convertInt(x, t)
+	case uint:
+		return convertUint(uint64(x), t)
+	case uint8:
+		return convertUint(uint64(x), t)
+	case uint16:
+		return convertUint(uint64(x), t)
+	case uint32:
+		return convertUint(uint64(x), t)
+	case uint64:
+		return convertUint(x, t)
+	case float32:
+		return checkValue(float64(x), t)
+	case float64:
+		return checkValue(x, t)
+	case json.Number:
+		return parseValue(string(x), t)
+	case string:
+		return parseValue(x, t)
+	case []byte:
+		return parseValue(string(x), t)
+	}
+	return 0, ErrColumnType
+}
+
+func convertInt(x int64, t ColumnType) (float64, error) {
+	if x > maxExactInt || x < -maxExactInt {
+		return 0, ErrIntOverflow
+	}
+	return checkValue(float64(x), t)
+}
+
+func convertUint(x uint64, t ColumnType) (float64, error) {
+	if x > maxExactInt {
+		return 0, ErrIntOverflow
+	}
+	return checkValue(float64(x), t)
+}
+
+func parseValue(s string, t ColumnType) (float64, error) {
+	s = strings.TrimSpace(s)
+	switch t {
+	case BoolColumn:
+		if b, err := strconv.ParseBool(s); err == nil {
+			return ConvertValue(b, t)
+		}
+	case IntColumn:
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return convertInt(i, t)
+		} else if e, ok := err.(*strconv.NumError); ok && e.Err == strconv.ErrRange {
+			return 0, ErrIntOverflow
+		}
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	return checkValue(f, t)
+}
+
+// checkValue checks that a variable is a value that type t can take.
+func checkValue(v float64, t ColumnType) (float64, error) {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, ErrNonFinite
+	}
+	switch t {
+	case IntColumn:
+		if v != math.Trunc(v) {
+			return 0, ErrColumnType
+		}
+		if math.Abs(v) > maxExactInt {
+			return 0, ErrIntOverflow
+		}
+	case BoolColumn:
+		if v != 0 && v != 1 {
+			return 0, ErrColumnType
+		}
+	}
+	return v, nil
+}
+
+// checkTypes checks the variables passed to Predict against their types.
+func (r *Regression) checkTypes(vars []float64) error {
+	for i, t := range r.names.types {
+		if t == FloatColumn || i >= len(vars) {
+			continue
+		}
+		if _, err := checkValue(vars[i], t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
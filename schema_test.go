@@ -0,0 +1,117 @@
+package regression
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestConvertValue(t *testing.T) {
+	cases := []struct {
+		v    interface{}
+		t    ColumnType
+		want float64
+		err  error
+	}{
+		{true, BoolColumn, 1, nil},
+		{false, BoolColumn, 0, nil},
+		{"true", BoolColumn, 1, nil},
+		{"0", BoolColumn, 0, nil},
+		{int64(1), BoolColumn, 1, nil},
+		{2, BoolColumn, 0, ErrColumnType},
+		{true, FloatColumn, 0, ErrColumnType},
+		{int32(-7), IntColumn, -7, nil},
+		{uint8(200), FloatColumn, 200, nil},
+		{int64(1) << 53, IntColumn, 1 << 53, nil},
+		{int64(1)<<53 + 1, IntColumn, 0, ErrIntOverflow},
+		{uint64(math.MaxUint64), FloatColumn, 0, ErrIntOverflow},
+		{"9007199254740993", IntColumn, 0, ErrIntOverflow},
+		{"99999999999999999999", IntColumn, 0, ErrIntOverflow},
+		{[]byte(" 42 "), IntColumn, 42, nil},
+		{json.Number("12"), IntColumn, 12, nil},
+		{2.5, IntColumn, 0, ErrColumnType},
+		{3.0, IntColumn, 3, nil},
+		{"2.5", FloatColumn, 2.5, nil},
+		{math.NaN(), FloatColumn, 0, ErrNonFinite},
+		{nil, FloatColumn, 0, ErrColumnType},
+	}
+	for _, c := range cases {
+		got, err := ConvertValue(c.v, c.t)
+		if err != c.err || got != c.want {
+			t.Errorf("ConvertValue(%v, %v): expected %v, %v, got %v, %v", c.v, c.t, c.want, c.err, got, err)
+		}
+	}
+	if _, err := ConvertValue("abc", FloatColumn); err == nil {
+		t.Error("Expected an error for an unparsable string")
+	}
+}
+
+func TestSchema(t *testing.T) {
+	r := new(Regression)
+	r.SetObserved("price")
+	r.SetVar(0, "rooms")
+	r.SetVarType(0, IntColumn)
+	r.SetVar(1, "garden")
+	r.SetVarType(1, BoolColumn)
+	r.SetVar(2, "area")
+
+	want := Schema{Observed: "price", Vars: []string{"rooms", "garden", "area"}, Types: []ColumnType{IntColumn, BoolColumn, FloatColumn}}
+	if got := r.Schema(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+
+	rows := [][]string{{"3", "true", "80.5"}, {"5", "false", "120"}, {"2", "1", "55"}, {"4", "0", "101"}, {"6", "true", "150"}}
+	prices := []float64{300, 380, 220, 330, 520}
+	for i, row := range rows {
+		vars, err := r.ParseRow(row)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.Train(DataPoint(prices[i], vars))
+	}
+	if _, err := r.ParseRow([]string{"3.5", "true", "1"}); err == nil {
+		t.Error("Expected an error for a fractional integer")
+	}
+	vars, err := r.ConvertRow([]interface{}{int64(3), true, 90.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(vars, []float64{3, 1, 90}) {
+		t.Errorf("Unexpected row %v", vars)
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.Predict([]float64{3, 0.5, 90}); err != ErrColumnType {
+		t.Errorf("Expected ErrColumnType, got %v", err)
+	}
+	if _, err := r.Predict([]float64{3, 1, 90}); err != nil {
+		t.Error(err)
+	}
+
+	vars, _, err = r.parseRecord([]byte(`{"rooms": 3, "garden": true, "area": 90}`))
+	if err != nil || !reflect.DeepEqual(vars, []float64{3, 1, 90}) {
+		t.Errorf("Unexpected record %v, %v", vars, err)
+	}
+	if _, _, err := r.parseRecord([]byte(`{"rooms": 9007199254740993, "garden": true, "area": 90}`)); err == nil {
+		t.Error("Expected an error for an integer overflow")
+	}
+
+	var buf bytes.Buffer
+	if err := r.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.Schema(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected the loaded schema %+v, got %+v", want, got)
+	}
+	if _, err := loaded.Predict([]float64{3.5, 1, 90}); err != ErrColumnType {
+		t.Errorf("Expected ErrColumnType from the loaded model, got %v", err)
+	}
+}
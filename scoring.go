@@ -0,0 +1,184 @@
+package regression
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"runtime"
+	"sync"
+)
+
+// ScoreOptions configures ScoreJSONL.
+type ScoreOptions struct {
+	// Workers is the number of records scored concurrently, runtime.NumCPU() when zero.
+	Workers int
+	// Explain adds the contribution of every variable and feature cross and the standard error
+	// of the prediction to every output record.
+	Explain bool
+}
+
+// scoreInput is a record read by ScoreJSONL.
+type scoreInput struct {
+	ID   json.RawMessage `json:"id,omitempty"`
+	Vars []float64       `json:"vars"`
+}
+
+// scoreOutput is a record written by ScoreJSONL.
+type scoreOutput struct {
+	ID            json.RawMessage    `json:"id,omitempty"`
+	Line          int                `json:"line,omitempty"`
+	Predicted     *float64           `json:"predicted,omitempty"`
+	StdErr        *float64           `json:"stderr,omitempty"`
+	Contributions map[string]float64 `json:"contributions,omitempty"`
+	Error         string             `json:"error,omitempty"`
+}
+
+type scoreJob struct {
+	line int
+	in   []byte
+	out  []byte
+	done chan struct{}
+}
+
+// ScoreJSONL reads JSON lines of feature records from rd, scores them and writes a JSON line per record
+// to w, in the order of the input. A record either holds its variables in a "vars" array or has a field per
+// variable, named as set with SetVar. An "id" field is copied to the output record, which has the prediction
+// in "predicted". Records that can't be scored are written with the line number and an "error" field.
+// It returns the first error reading from rd or writing to w.
+func (r *Regression) ScoreJSONL(rd io.Reader, w io.Writer, opts ScoreOptions) error {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	jobs := make(chan *scoreJob, workers)
+	ordered := make(chan *scoreJob, 4*workers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				j.out = r.scoreRecord(j.line, j.in, opts.Explain)
+				close(j.done)
+			}
+		}()
+	}
+
+	// the writer drains ordered even after an error, so the reader never blocks
+	written := make(chan error, 1)
+	go func() {
+		bw := bufio.NewWriter(w)
+		var err error
+		for j := range ordered {
+			<-j.done
+			if err == nil {
+				_, err = bw.Write(append(j.out, '\n'))
+			}
+		}
+		if err == nil {
+			err = bw.Flush()
+		}
+		written <- err
+	}()
+
+	scanner := bufio.NewScanner(rd)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		j := &scoreJob{line: line, in: append([]byte(nil), scanner.Bytes()...), done: make(chan struct{})}
+		ordered <- j
+		jobs <- j
+	}
+	close(jobs)
+	close(ordered)
+	wg.Wait()
+	if err := <-written; err != nil {
+		return err
+	}
+	return scanner.Err()
+}
+
+// scoreRecord scores a single input record and returns the encoded output record.
+func (r *Regression) scoreRecord(line int, in []byte, explain bool) []byte {
+	var out scoreOutput
+	fail := func(err error) []byte {
+		out.Line = line
+		out.Predicted, out.StdErr, out.Contributions = nil, nil, nil
+		out.Error = err.Error()
+		b, _ := json.Marshal(out)
+		return b
+	}
+
+	vars, id, err := r.parseRecord(in)
+	out.ID = id
+	if err != nil {
+		return fail(err)
+	}
+	d, err := r.PredictPoint(vars)
+	if err != nil {
+		return fail(err)
+	}
+	out.Predicted = &d.Predicted
+	if explain {
+		if !math.IsNaN(d.StdErr) {
+			out.StdErr = &d.StdErr
+		}
+		out.Contributions = make(map[string]float64, len(d.Contributions))
+		for j, c := range d.Contributions {
+			out.Contributions[r.GetVar(j)] = c
+		}
+	}
+	b, err := json.Marshal(out)
+	if err != nil {
+		return fail(err)
+	}
+	return b
+}
+
+// parseRecord decodes the variables and the id of an input record.
+func (r *Regression) parseRecord(in []byte) ([]float64, json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(in, &fields); err != nil {
+		return nil, nil, err
+	}
+	id := fields["id"]
+	if _, ok := fields["vars"]; ok {
+		var rec scoreInput
+		if err := json.Unmarshal(in, &rec); err != nil {
+			return nil, id, err
+		}
+		if len(rec.Vars) != r.names.base {
+			return nil, id, fmt.Errorf("expected %d variables, got %d", r.names.base, len(rec.Vars))
+		}
+		return rec.Vars, id, nil
+	}
+
+	vars := make([]float64, r.names.base)
+	for i := range vars {
+		name := r.GetVar(i)
+		v, ok := fields[name]
+		if !ok {
+			return nil, id, fmt.Errorf("missing variable %q", name)
+		}
+		// decode numbers exactly, so large integers can be checked against the variable type
+		var x interface{}
+		dec := json.NewDecoder(bytes.NewReader(v))
+		dec.UseNumber()
+		if err := dec.Decode(&x); err != nil {
+			return nil, id, fmt.Errorf("variable %q: %v", name, err)
+		}
+		var err error
+		if vars[i], err = ConvertValue(x, r.GetVarType(i)); err != nil {
+			return nil, id, fmt.Errorf("variable %q: %v", name, err)
+		}
+	}
+	return vars, id, nil
+}
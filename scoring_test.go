@@ -0,0 +1,85 @@
+package regression
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestScoreJSONL(t *testing.T) {
+	r := new(Regression)
+	r.SetObserved("dist")
+	r.SetVar(0, "speed")
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	r.AddCross(PowCross(0, 2))
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	var in bytes.Buffer
+	for i := 0; i < 200; i++ {
+		if i%2 == 0 {
+			fmt.Fprintf(&in, "{\"id\": %d, \"vars\": [%d]}\n", i, i%25)
+		} else {
+			fmt.Fprintf(&in, "{\"id\": \"r%d\", \"speed\": %d}\n", i, i%25)
+		}
+	}
+	in.WriteString("\n{\"id\": 200, \"vars\": [1, 2]}\n{\"id\": 201}\nnot json\n")
+
+	var out bytes.Buffer
+	if err := r.ScoreJSONL(&in, &out, ScoreOptions{Workers: 4, Explain: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	scanner := bufio.NewScanner(&out)
+	i := 0
+	for ; scanner.Scan(); i++ {
+		var rec scoreOutput
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatal(err)
+		}
+		if i >= 200 {
+			if rec.Error == "" || rec.Line == 0 {
+				t.Errorf("Expected an error record, got %s", scanner.Text())
+			}
+			continue
+		}
+		wantID := fmt.Sprint(i)
+		if i%2 == 1 {
+			wantID = fmt.Sprintf("%q", "r"+wantID)
+		}
+		if string(rec.ID) != wantID {
+			t.Fatalf("Expected records in input order, got id %s for record %d", rec.ID, i)
+		}
+		want, _ := r.Predict([]float64{float64(i % 25)})
+		if rec.Predicted == nil || *rec.Predicted != want {
+			t.Errorf("Expected %v for record %d, got %s", want, i, scanner.Text())
+		}
+		if rec.StdErr == nil || len(rec.Contributions) != 2 {
+			t.Errorf("Expected an explanation, got %s", scanner.Text())
+		}
+		if _, ok := rec.Contributions["(speed)^2"]; !ok {
+			t.Errorf("Expected named contributions, got %v", rec.Contributions)
+		}
+	}
+	if i != 203 {
+		t.Errorf("Expected 203 output records, got %d", i)
+	}
+}
+
+func TestScoreJSONLWithoutExplanation(t *testing.T) {
+	r := carsRegression(t)
+	var out bytes.Buffer
+	if err := r.ScoreJSONL(strings.NewReader(`{"vars": [10]}`), &out, ScoreOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	want, _ := r.Predict([]float64{10})
+	if got := strings.TrimSpace(out.String()); got != fmt.Sprintf(`{"predicted":%v}`, want) {
+		t.Errorf("Unexpected output %s", got)
+	}
+}
@@ -0,0 +1,79 @@
+package regression
+
+import "sort"
+
+// SplitByVar partitions the training data by the distinct values of the categorical variable i
+// and fits a separate model per segment when Run is called. Predict routes to the model of the
+// matching segment, falling back to the pooled model for unseen values or for segments that
+// didn't have enough data to be fitted. Run fails with ErrDimensions if i isn't a base variable.
+func (r *Regression) SplitByVar(i int) {
+	r.split = true
+	r.splitVar = i
+}
+
+// Segment returns the model fitted for the segment where the split variable equals value, or nil.
+func (r *Regression) Segment(value float64) *Regression {
+	return r.segments[value]
+}
+
+// Segments returns the values of the split variable for which a segment model was fitted.
+func (r *Regression) Segments() []float64 {
+	values := make([]float64, 0, len(r.segments))
+	for v := range r.segments {
+		values = append(values, v)
+	}
+	sort.Float64s(values)
+	return values
+}
+
+func (r *Regression) segmentFor(vars []float64) *Regression {
+	if len(r.segments) == 0 || r.splitVar >= len(vars) {
+		return nil
+	}
+	return r.segments[vars[r.splitVar]]
+}
+
+// checkSplit returns ErrDimensions if the split variable isn't one of the base variables.
+func (r *Regression) checkSplit(numOfBaseVars int) error {
+	if r.split && (r.splitVar < 0 || r.splitVar >= numOfBaseVars) {
+		return ErrDimensions
+	}
+	return nil
+}
+
+// runSegments fits a model per distinct value of the split variable. Within a segment the split
+// variable is constant, so it is aliased and gets a zero coefficient.
+func (r *Regression) runSegments(numOfBaseVars int) {
+	partitions := make(map[float64][]*dataPoint)
+	for _, d := range r.data {
+		v := d.Variables[r.splitVar]
+		base := make([]float64, numOfBaseVars)
+		copy(base, d.Variables)
+		partitions[v] = append(partitions[v], WeightedDataPoint(d.Observed, base, d.Weight))
+	}
+
+	r.segments = make(map[float64]*Regression, len(partitions))
+	for v, points := range partitions {
+		s := &Regression{
+			names:            describe{obs: r.names.obs, vars: make(map[int]string, numOfBaseVars)},
+			crosses:          r.crosses,
+			dist:             r.dist,
+			solve:            r.solve,
+			instruments:      r.instruments,
+			signs:            r.signs,
+			vifThreshold:     r.vifThreshold,
+			normalization:    r.normalization,
+			varNormalization: r.varNormalization,
+		}
+		for i := 0; i < numOfBaseVars; i++ {
+			if name, ok := r.names.vars[i]; ok {
+				s.names.vars[i] = name
+			}
+		}
+		s.Train(points...)
+		if err := s.Run(); err != nil {
+			continue
+		}
+		r.segments[v] = s
+	}
+}
@@ -0,0 +1,109 @@
+package regression
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSplitByVar(t *testing.T) {
+	r := new(Regression)
+	r.SetObserved("revenue")
+	r.SetVar(0, "region")
+	r.SetVar(1, "spend")
+	for i := 0; i < 10; i++ {
+		x := float64(i)
+		r.Train(
+			DataPoint(1+2*x, []float64{0, x}),
+			DataPoint(10-3*x, []float64{1, x}),
+		)
+	}
+	// too few points to fit its own model
+	r.Train(DataPoint(5, []float64{2, 1}), DataPoint(6, []float64{2, 2}))
+	r.SplitByVar(0)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := r.Segments(); len(got) != 2 || got[0] != 0 || got[1] != 1 {
+		t.Fatalf("Expected segments [0 1], got %v", got)
+	}
+	if c := r.Segment(0).Coeff(1); c != 0 {
+		t.Errorf("Expected the split variable to be aliased in the segment, got coefficient %v", c)
+	}
+
+	p0, err := r.Predict([]float64{0, 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "segment 0", p0, 41, 1e-9)
+	p1, _ := r.Predict([]float64{1, 20})
+	assertClose(t, "segment 1", p1, -50, 1e-9)
+
+	pooled := r.predictRow(r.designRow([]float64{2, 20}))
+	p2, _ := r.Predict([]float64{2, 20})
+	assertClose(t, "pooled fallback", p2, pooled, 1e-9)
+
+	se, err := r.PredictSE([]float64{0, 20})
+	if err != nil || se > 1e-6 {
+		t.Errorf("Expected an exact fit within the segment, got %v (%v)", se, err)
+	}
+}
+
+func TestSplitByVarOutOfRange(t *testing.T) {
+	for _, i := range []int{-1, 2} {
+		r := new(Regression)
+		for j := 0; j < 10; j++ {
+			r.Train(DataPoint(float64(j), []float64{float64(j % 2), float64(j)}))
+		}
+		r.SplitByVar(i)
+		if err := r.Run(); err != ErrDimensions {
+			t.Errorf("%d: expected ErrDimensions, got %v", i, err)
+		}
+	}
+}
+
+func TestAliasedVariable(t *testing.T) {
+	r := new(Regression)
+	for i := 0; i < 10; i++ {
+		x := float64(i)
+		r.Train(DataPoint(3+2*x+float64(i%3), []float64{x, 2 * x}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if r.Coeff(2) != 0 {
+		t.Errorf("Expected the duplicate variable to be aliased, got %v", r.Coeff(2))
+	}
+	if _, residual := r.DegreesOfFreedom(); residual != 8 {
+		t.Errorf("Expected 8 residual degrees of freedom, got %d", residual)
+	}
+}
+
+func TestSplitByVarSaveLoad(t *testing.T) {
+	r := new(Regression)
+	for i := 0; i < 10; i++ {
+		x := float64(i)
+		r.Train(
+			DataPoint(1+2*x+float64(i%2), []float64{0, x}),
+			DataPoint(10-3*x+float64(i%3), []float64{1, x}),
+		)
+	}
+	r.SplitByVar(0)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := r.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, vars := range [][]float64{{0, 3}, {1, 3}, {5, 3}} {
+		want, _ := r.Predict(vars)
+		got, _ := loaded.Predict(vars)
+		assertClose(t, "loaded prediction", got, want, 1e-12)
+	}
+}
@@ -0,0 +1,135 @@
+package regression
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Sign is the required sign of a coefficient.
+type Sign int
+
+const (
+	// Positive requires a coefficient to be zero or positive.
+	Positive Sign = 1
+	// Negative requires a coefficient to be zero or negative.
+	Negative Sign = -1
+)
+
+// SignAdjustment reports a variable that was dropped from the fit because its coefficient had the wrong sign.
+type SignAdjustment struct {
+	Name     string
+	Required Sign
+	// Coeff is the coefficient of the variable in the fit that violated the constraint.
+	Coeff float64
+}
+
+// RequireSign requires the coefficient of variable i, which can be a feature cross, to have the given sign,
+// e.g. to keep demand monotonic in the price. When a fit violates constraints, Run drops the variable with
+// the largest violating coefficient and refits, until all constraints hold. Dropped variables get a zero
+// coefficient, are reported as aliased and are listed by SignAdjustments.
+func (r *Regression) RequireSign(i int, s Sign) {
+	if r.signs == nil {
+		r.signs = make(map[int]Sign)
+	}
+	r.signs[i] = s
+}
+
+// SignAdjustments returns the variables dropped by the last fit to satisfy the constraints of RequireSign,
+// in the order they were dropped.
+func (r *Regression) SignAdjustments() []SignAdjustment {
+	adjustments := make([]SignAdjustment, len(r.signDrops))
+	for k, d := range r.signDrops {
+		adjustments[k] = SignAdjustment{Name: r.GetVar(d.col - 1), Required: r.signs[d.col-1], Coeff: d.coeff}
+	}
+	return adjustments
+}
+
+// signDrop is a column dropped by signSolver and its violating coefficient.
+type signDrop struct {
+	col   int
+	coeff float64
+}
+
+// signSolver wraps a solver to enforce sign constraints by dropping columns. Columns are columns of the
+// design matrix, so variable i is column i+1.
+type signSolver struct {
+	inner   Solver
+	signs   map[int]Sign
+	dropped *[]signDrop
+}
+
+func (r *Regression) signSolver(s Solver) Solver {
+	r.signDrops = nil
+	if len(r.signs) == 0 {
+		return s
+	}
+	cols := make(map[int]Sign, len(r.signs))
+	for i, sign := range r.signs {
+		cols[i+1] = sign
+	}
+	return signSolver{inner: s, signs: cols, dropped: &r.signDrops}
+}
+
+// Solve satisfies the Solver interface.
+func (s signSolver) Solve(x, y *mat.Dense) ([]float64, *Diagnostics, error) {
+	rows, cols := x.Dims()
+	dropped := mat.NewDense(rows, cols, nil)
+	dropped.Copy(x)
+	x = dropped
+	for {
+		c, diag, err := s.inner.Solve(x, y)
+		if err != nil {
+			return nil, nil, err
+		}
+		worst, violation := -1, 0.0
+		for col, sign := range s.signs {
+			if col >= len(c) {
+				continue
+			}
+			v := -c[col] * float64(sign)
+			if v > violation || (v == violation && v > 0 && col < worst) {
+				worst, violation = col, v
+			}
+		}
+		if worst < 0 {
+			cols := make([]int, len(*s.dropped))
+			for k, d := range *s.dropped {
+				cols[k] = d.col
+			}
+			return c, markAliased(diag, cols), nil
+		}
+
+		*s.dropped = append(*s.dropped, signDrop{col: worst, coeff: c[worst]})
+		for i := 0; i < rows; i++ {
+			x.Set(i, worst, 0)
+		}
+		delete(s.signs, worst)
+	}
+}
+
+// markAliased adds the dropped columns to the aliased columns of diag, which is allocated if needed.
+func markAliased(diag *Diagnostics, dropped []int) *Diagnostics {
+	if len(dropped) == 0 {
+		return diag
+	}
+	if diag == nil {
+		diag = new(Diagnostics)
+	}
+	for _, col := range dropped {
+		if !containsInt(diag.Aliased, col) {
+			diag.Aliased = append(diag.Aliased, col)
+		}
+	}
+	sort.Ints(diag.Aliased)
+	return diag
+}
+
+func containsInt(list []int, v int) bool {
+	for _, w := range list {
+		if w == v {
+			return true
+		}
+	}
+	return false
+}
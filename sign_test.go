@@ -0,0 +1,64 @@
+package regression
+
+import "testing"
+
+func TestRequireSign(t *testing.T) {
+	// the observed value falls with the second variable, violating its constraint
+	r := new(Regression)
+	r.SetObserved("dist")
+	r.SetVar(0, "speed")
+	r.SetVar(1, "noise")
+	for i := range carsSpeed {
+		noise := float64(i%5) - 2
+		r.Train(DataPoint(carsDist[i]-3*noise, []float64{carsSpeed[i], noise}))
+	}
+	r.RequireSign(0, Positive)
+	r.RequireSign(1, Positive)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	adjustments := r.SignAdjustments()
+	if len(adjustments) != 1 || adjustments[0].Name != "noise" || adjustments[0].Required != Positive {
+		t.Fatalf("Expected noise to be dropped, got %+v", adjustments)
+	}
+	assertClose(t, "violating coefficient", adjustments[0].Coeff, -3, 0.5)
+	if r.Coeff(2) != 0 {
+		t.Errorf("Expected the noise coefficient to be zero, got %v", r.Coeff(2))
+	}
+	if len(r.aliased) != 1 || r.aliased[0] != 2 {
+		t.Errorf("Expected noise to be aliased, got %v", r.aliased)
+	}
+
+	// the remaining fit is that of the model without the variable
+	want := speedOnly(r)
+	assertClose(t, "speed", r.Coeff(1), want.Coeff(1), 1e-9)
+	assertClose(t, "stderr", r.StdErr(1), want.StdErr(1), 1e-9)
+}
+
+// speedOnly fits the observed values of r on speed alone.
+func speedOnly(r *Regression) *Regression {
+	want := new(Regression)
+	for i, d := range r.data {
+		want.Train(DataPoint(d.Observed, []float64{carsSpeed[i]}))
+	}
+	want.Run()
+	return want
+}
+
+func TestRequireSignSatisfied(t *testing.T) {
+	r := new(Regression)
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	r.RequireSign(0, Positive)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.SignAdjustments()) != 0 || len(r.aliased) != 0 {
+		t.Errorf("Expected no adjustments, got %+v", r.SignAdjustments())
+	}
+	if err := r.Update(DataPoint(1, []float64{1})); err != ErrUnsupported {
+		t.Errorf("Expected ErrUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package regression
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// sklearnModel is the JSON form of a scikit-learn linear model: its fitted attributes, written from Python with
+//
+//	json.dump({"model": type(m).__name__, "coef_": m.coef_.tolist(), "intercept_": float(m.intercept_),
+//	           "feature_names_in_": list(m.feature_names_in_)}, f)
+type sklearnModel struct {
+	Model        string          `json:"model,omitempty"`
+	Coef         json.RawMessage `json:"coef_"`
+	Intercept    json.RawMessage `json:"intercept_"`
+	FeatureNames []string        `json:"feature_names_in_,omitempty"`
+	NFeatures    int             `json:"n_features_in_,omitempty"`
+	Alpha        *float64        `json:"alpha,omitempty"`
+	Target       string          `json:"target,omitempty"`
+	Crosses      []CrossSpec     `json:"crosses,omitempty"`
+}
+
+// ImportSklearn creates a model from the fitted attributes of a scikit-learn LinearRegression or Ridge model
+// in JSON: "coef_", "intercept_" and optionally "feature_names_in_", "alpha" and "target", the name of the
+// observed value. Feature crosses written by ExportSklearn are restored. The coefficients of a single target may be nested in a list as scikit-learn stores them
+// for 2d targets. The model predicts like the scikit-learn one; no training data or inference statistics
+// are imported.
+func ImportSklearn(data []byte) (*Regression, error) {
+	var m sklearnModel
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	coef, err := sklearnVector(m.Coef)
+	if err != nil {
+		return nil, fmt.Errorf("coef_: %v", err)
+	}
+	intercept := []float64{0}
+	if len(m.Intercept) > 0 {
+		if intercept, err = sklearnVector(m.Intercept); err != nil || len(intercept) != 1 {
+			return nil, fmt.Errorf("intercept_: expected a single intercept")
+		}
+	}
+	if len(m.FeatureNames) > 0 && len(m.FeatureNames) != len(coef) {
+		return nil, fmt.Errorf("expected %d feature names, got %d", len(coef), len(m.FeatureNames))
+	}
+	if m.NFeatures > 0 && m.NFeatures != len(coef) {
+		return nil, fmt.Errorf("expected %d features, got %d", m.NFeatures, len(coef))
+	}
+
+	r := new(Regression)
+	r.SetObserved(m.Target)
+	// models exported by ExportSklearn list the feature crosses after the variables
+	base := len(coef)
+	for _, spec := range m.Crosses {
+		cross, err := spec.build()
+		if err != nil {
+			return nil, err
+		}
+		r.AddCross(cross)
+		base -= cross.ExtendNames(make(map[int]string), 0)
+	}
+	if base < 0 {
+		return nil, fmt.Errorf("expected at least %d coefficients, got %d", len(coef)-base, len(coef))
+	}
+	for i, name := range m.FeatureNames {
+		if i < base {
+			r.SetVar(i, name)
+		}
+	}
+	if m.Alpha != nil {
+		r.SetSolver(RidgeSolver{Lambda: *m.Alpha})
+	}
+	r.initialised = true
+	r.hasRun = true
+	r.sigma2 = math.NaN()
+	r.extendNames(base)
+	r.setCoeffs(append(intercept, coef...))
+	return r, nil
+}
+
+// sklearnVector decodes a number, a list of numbers, or a list holding a single list of numbers.
+func sklearnVector(raw json.RawMessage) ([]float64, error) {
+	var f float64
+	if err := json.Unmarshal(raw, &f); err == nil {
+		return []float64{f}, nil
+	}
+	var v []float64
+	if err := json.Unmarshal(raw, &v); err == nil {
+		return v, nil
+	}
+	var nested [][]float64
+	if err := json.Unmarshal(raw, &nested); err != nil {
+		return nil, err
+	}
+	if len(nested) != 1 {
+		return nil, errors.New("only a single target is supported")
+	}
+	return nested[0], nil
+}
+
+// ExportSklearn writes the model in the JSON form read by ImportSklearn, so it can be loaded into a scikit-learn
+// model in Python by setting its attributes:
+//
+//	m = LinearRegression()
+//	m.coef_, m.intercept_ = np.array(d["coef_"]), d["intercept_"]
+//	m.feature_names_in_, m.n_features_in_ = np.array(d["feature_names_in_"]), d["n_features_in_"]
+//
+// Feature crosses are exported as features named after the cross, such as "(x)^2", with their definitions in
+// "crosses", so the Python side has to compute them, e.g. with PolynomialFeatures. Per-segment models
+// and models with winsorized variables or missing indicators cannot be exported.
+func (r *Regression) ExportSklearn() ([]byte, error) {
+	if !r.hasRun || len(r.coeff) == 0 {
+		return nil, ErrNotRun
+	}
+	if r.split || r.clips != nil || len(r.missing) > 0 {
+		return nil, ErrUnsupported
+	}
+	coeffs := r.coeffs()
+	names := r.coeffNames()[1:]
+	m := sklearnModel{
+		Model:        "LinearRegression",
+		FeatureNames: names,
+		NFeatures:    len(names),
+		Target:       r.names.obs,
+	}
+	if s, ok := r.solve.(RidgeSolver); ok {
+		m.Model = "Ridge"
+		m.Alpha = &s.Lambda
+	}
+	for _, cross := range r.crosses {
+		spec, err := specOf(cross)
+		if err != nil {
+			return nil, err
+		}
+		m.Crosses = append(m.Crosses, spec)
+	}
+	var err error
+	if m.Coef, err = json.Marshal(coeffs[1:]); err != nil {
+		return nil, err
+	}
+	if m.Intercept, err = json.Marshal(coeffs[0]); err != nil {
+		return nil, err
+	}
+	return json.Marshal(m)
+}
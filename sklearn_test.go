@@ -0,0 +1,82 @@
+package regression
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestImportSklearn(t *testing.T) {
+	// json.dump of a LinearRegression fitted on a DataFrame with a 2d target
+	data := `{"model": "LinearRegression", "coef_": [[3.9324, 0.5]], "intercept_": [-17.5791],
+		"feature_names_in_": ["speed", "load"], "n_features_in_": 2}`
+	r, err := ImportSklearn([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := r.Predict([]float64{10, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "prediction", p, -17.5791+39.324+1, 1e-9)
+	if r.GetVar(1) != "load" {
+		t.Errorf("Expected the feature names, got %q", r.GetVar(1))
+	}
+
+	ridge, err := ImportSklearn([]byte(`{"coef_": [1, 2], "intercept_": 0.5, "alpha": 2}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, ok := ridge.solve.(RidgeSolver); !ok || s.Lambda != 2 {
+		t.Errorf("Expected a ridge solver, got %v", ridge.solve)
+	}
+
+	for _, bad := range []string{
+		`{"coef_": [[1], [2]], "intercept_": [0, 1]}`,
+		`{"coef_": [1, 2], "feature_names_in_": ["a"]}`,
+		`{"coef_": "x"}`,
+	} {
+		if _, err := ImportSklearn([]byte(bad)); err == nil {
+			t.Errorf("Expected an error for %s", bad)
+		}
+	}
+}
+
+func TestExportSklearn(t *testing.T) {
+	r := new(Regression)
+	r.SetObserved("dist")
+	r.SetVar(0, "speed")
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	r.AddCross(PowCross(0, 2))
+	r.SetSolver(RidgeSolver{Lambda: 1})
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := r.ExportSklearn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m["model"] != "Ridge" || m["alpha"] != 1.0 || m["n_features_in_"] != 2.0 {
+		t.Errorf("Unexpected export %s", data)
+	}
+	if names := m["feature_names_in_"].([]interface{}); names[1] != "(speed)^2" {
+		t.Errorf("Expected the cross to be exported as a feature, got %v", names)
+	}
+
+	imported, err := ImportSklearn(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := r.Predict([]float64{21})
+	got, _ := imported.Predict([]float64{21})
+	assertClose(t, "round trip", got, want, 1e-9)
+	if imported.GetObserved() != "dist" || imported.GetVar(1) != "(speed)^2" {
+		t.Errorf("Expected the names to be restored, got %q and %q", imported.GetObserved(), imported.GetVar(1))
+	}
+}
@@ -0,0 +1,139 @@
+package regression
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// snapshot is the serialized form of a model in online training.
+type snapshot struct {
+	Model  *Regression     `json:"model"`
+	Online *onlineSnapshot `json:"online"`
+	Hash   *hashSnapshot   `json:"hash,omitempty"`
+}
+
+// onlineSnapshot holds the sufficient statistics and the holdout set of onlineState.
+type onlineSnapshot struct {
+	N         int            `json:"n"`
+	SumW      float64        `json:"sum_w"`
+	XTX       [][]float64    `json:"xtx"`
+	XTY       []float64      `json:"xty"`
+	YTY       float64        `json:"yty"`
+	SumY      float64        `json:"sum_y"`
+	Baseline  []float64      `json:"baseline"`
+	Holdout   []holdoutPoint `json:"holdout,omitempty"`
+	Next      int            `json:"next,omitempty"`
+	Size      int            `json:"size,omitempty"`
+	Fraction  float64        `json:"fraction,omitempty"`
+	Tolerance float64        `json:"tolerance,omitempty"`
+}
+
+type holdoutPoint struct {
+	Observed  float64   `json:"observed"`
+	Variables []float64 `json:"vars"`
+	Weight    float64   `json:"weight"`
+}
+
+// hashSnapshot holds the state of the data hash, so it keeps covering the data points added by Update.
+type hashSnapshot struct {
+	Order HashOrder `json:"order"`
+	Lanes []uint64  `json:"lanes"`
+	Words uint64    `json:"words,omitempty"`
+	N     uint64    `json:"n"`
+}
+
+// SaveSnapshot writes the fitted model to w as JSON together with the state of its online training: the
+// sufficient statistics of the fit and the holdout set. Unlike a model written with Save, a model restored
+// with LoadSnapshot can be trained further with Update exactly as if the process had not restarted, except
+// that the source of randomness sampling the holdout set is not saved.
+func (r *Regression) SaveSnapshot(w io.Writer) error {
+	if !r.hasRun || len(r.coeff) == 0 {
+		return ErrNotRun
+	}
+	o, err := r.onlineStats()
+	if err != nil {
+		return err
+	}
+	s := snapshot{
+		Model: r,
+		Online: &onlineSnapshot{
+			N:         o.stats.n,
+			SumW:      o.stats.sumW,
+			XTX:       o.stats.xtx,
+			XTY:       o.stats.xty,
+			YTY:       o.stats.yty,
+			SumY:      o.stats.sumY,
+			Baseline:  o.baseline,
+			Next:      o.next,
+			Size:      o.size,
+			Fraction:  o.fraction,
+			Tolerance: o.tolerance,
+		},
+	}
+	for _, p := range o.holdout {
+		s.Online.Holdout = append(s.Online.Holdout, holdoutPoint{Observed: p.Observed, Variables: p.Variables, Weight: p.Weight})
+	}
+	if h := r.hasher; h != nil {
+		s.Hash = &hashSnapshot{Order: h.order, Lanes: h.sum[:], N: h.n}
+		if h.order == OrderedHash {
+			s.Hash.Lanes, s.Hash.Words = h.stream.lanes[:], h.stream.words
+		}
+	}
+	return json.NewEncoder(w).Encode(s)
+}
+
+// LoadSnapshot reads a model previously written with SaveSnapshot.
+func LoadSnapshot(rd io.Reader) (*Regression, error) {
+	var s snapshot
+	if err := json.NewDecoder(rd).Decode(&s); err != nil {
+		return nil, err
+	}
+	if s.Model == nil || s.Online == nil {
+		return nil, ErrNoStatistics
+	}
+	r := s.Model
+	cols := len(r.coeff)
+	if len(s.Online.XTX) != cols || len(s.Online.XTY) != cols || len(s.Online.Baseline) != cols {
+		return nil, ErrDimensions
+	}
+	for _, row := range s.Online.XTX {
+		if len(row) != cols {
+			return nil, ErrDimensions
+		}
+	}
+
+	if s.Hash != nil {
+		if len(s.Hash.Lanes) != 4 {
+			return nil, ErrDimensions
+		}
+		h := newDataHasher(s.Hash.Order)
+		h.n = s.Hash.N
+		if s.Hash.Order == OrderedHash {
+			copy(h.stream.lanes[:], s.Hash.Lanes)
+			h.stream.words = s.Hash.Words
+		} else {
+			copy(h.sum[:], s.Hash.Lanes)
+		}
+		r.hasher = h
+		r.hashOrder = s.Hash.Order
+	}
+
+	o := r.onlineState()
+	o.stats = &normalEquations{
+		n:    s.Online.N,
+		sumW: s.Online.SumW,
+		xtx:  s.Online.XTX,
+		xty:  s.Online.XTY,
+		yty:  s.Online.YTY,
+		sumY: s.Online.SumY,
+	}
+	o.baseline = s.Online.Baseline
+	o.next = s.Online.Next
+	o.size = s.Online.Size
+	o.fraction = s.Online.Fraction
+	o.tolerance = s.Online.Tolerance
+	for _, p := range s.Online.Holdout {
+		o.holdout = append(o.holdout, WeightedDataPoint(p.Observed, p.Variables, p.Weight))
+	}
+	return r, nil
+}
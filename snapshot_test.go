@@ -0,0 +1,104 @@
+package regression
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	for _, order := range []HashOrder{OrderedHash, UnorderedHash} {
+		points := carsPoints(false)
+		r := new(Regression)
+		r.SetObserved("dist")
+		r.SetVar(0, "speed")
+		r.SetHashOrder(order)
+		r.AddCross(PowCross(0, 2))
+		r.Train(points[:20]...)
+		if err := r.Run(); err != nil {
+			t.Fatal(err)
+		}
+		r.SetHoldout(5, 0.3, 0.1)
+		if err := r.Update(points[20:35]...); err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		if err := r.SaveSnapshot(&buf); err != nil {
+			t.Fatal(err)
+		}
+		restored, err := LoadSnapshot(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// stop sampling the holdout set, as the source of randomness isn't saved
+		for _, m := range []*Regression{r, restored} {
+			m.SetHoldout(5, 0, 0.1)
+			if err := m.Update(points[35:]...); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for i := 0; i < 3; i++ {
+			assertClose(t, "coefficient", restored.Coeff(i), r.Coeff(i), 1e-12)
+			assertClose(t, "stderr", restored.StdErr(i), r.StdErr(i), 1e-12)
+		}
+		if got, want := restored.Holdout(), r.Holdout(); got != want {
+			t.Errorf("Expected holdout report %+v, got %+v", want, got)
+		}
+		if got, want := restored.DataHash(), r.DataHash(); got != want {
+			t.Errorf("Expected data hash %q, got %q", want, got)
+		}
+		if restored.Formula != r.Formula {
+			t.Errorf("Expected formula %q, got %q", r.Formula, restored.Formula)
+		}
+	}
+}
+
+func TestSnapshotRunStream(t *testing.T) {
+	points := carsPoints(false)
+	i := 0
+	r := new(Regression)
+	err := r.RunStream(func() (*dataPoint, bool) {
+		if i == 30 {
+			return nil, false
+		}
+		i++
+		return points[i-1], true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := r.SaveSnapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := LoadSnapshot(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := restored.Update(points[30:]...); err != nil {
+		t.Fatal(err)
+	}
+	want := carsRegression(t)
+	for i := 0; i < 2; i++ {
+		assertClose(t, "coefficient", restored.Coeff(i), want.Coeff(i), 1e-6)
+	}
+}
+
+func TestSnapshotErrors(t *testing.T) {
+	var buf bytes.Buffer
+	if err := new(Regression).SaveSnapshot(&buf); err != ErrNotRun {
+		t.Errorf("Expected ErrNotRun, got %v", err)
+	}
+	r := carsRegression(t)
+	if err := r.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := loaded.SaveSnapshot(&buf); err != ErrNoStatistics {
+		t.Errorf("Expected ErrNoStatistics, got %v", err)
+	}
+}
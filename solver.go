@@ -0,0 +1,176 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// ErrSolverCoeffs signals that a Solver didn't return a coefficient for every column of the design matrix.
+var ErrSolverCoeffs = errors.New("solver returned the wrong number of coefficients")
+
+// Solver fits the coefficients of a least squares problem. The first column of the design matrix x
+// is the offset, followed by the variables and feature crosses; y holds the observed values in a
+// single column. A coefficient has to be returned for every column of x.
+type Solver interface {
+	Solve(x, y *mat.Dense) ([]float64, *Diagnostics, error)
+}
+
+// Diagnostics describes the solution found by a Solver.
+type Diagnostics struct {
+	// Aliased lists the columns that were left out of the fit, typically because they are linear
+	// combinations of preceding columns.
+	Aliased []int
+	// Unscaled is the unscaled covariance matrix (X'X)^-1 of the coefficients, with zero rows and
+	// columns for aliased variables. Solvers that leave it nil disable standard errors and p-values.
+	Unscaled *mat.Dense
+	// Loadings are the X loadings of the latent components of solvers such as PLSSolver, with a row
+	// per column of the design matrix except the offset. Other solvers leave it nil.
+	Loadings *mat.Dense
+}
+
+// SetSolver sets the solver used by Run. QRSolver is used by default.
+func (r *Regression) SetSolver(s Solver) {
+	r.solve = s
+}
+
+func (r *Regression) solver() Solver {
+	if r.solve == nil {
+		return QRSolver{}
+	}
+	return r.solve
+}
+
+// aliasTolerance is the relative size of a diagonal entry of R below which the column is considered
+// to be a linear combination of the preceding columns.
+const aliasTolerance = 1e-7
+
+// QRSolver is the default Solver. It solves the least squares problem using QR decomposition.
+// Columns that are linear combinations of preceding columns are aliased: they are left out of the fit and
+// their coefficient is zero.
+type QRSolver struct{}
+
+// Solve satisfies the Solver interface.
+func (QRSolver) Solve(x, y *mat.Dense) ([]float64, *Diagnostics, error) {
+	rows, cols := x.Dims()
+	active := make([]int, cols)
+	for j := range active {
+		active[j] = j
+	}
+	ys := make([]float64, rows)
+	for i := range ys {
+		ys[i] = y.At(i, 0)
+	}
+
+	var norms []float64
+	var aliased []int
+	for {
+		n := len(active)
+		reg, b := tallQR(x, active, ys)
+		if norms == nil {
+			// the columns of R have the norms of the columns of x
+			norms = make([]float64, cols)
+			column := make([]float64, cols)
+			for j := range norms {
+				for i := 0; i <= j; i++ {
+					column[i] = reg.At(i, j)
+				}
+				norms[j] = euclidean(column[:j+1])
+			}
+		}
+
+		dependent := -1
+		for k, j := range active {
+			if math.Abs(reg.At(k, k)) <= aliasTolerance*norms[j] {
+				dependent = k
+				break
+			}
+		}
+		if dependent >= 0 {
+			aliased = append(aliased, active[dependent])
+			active = append(active[:dependent], active[dependent+1:]...)
+			continue
+		}
+
+		c := backSubstitute(reg, b)
+
+		// (X'X)^-1 = R^-1 * R^-T
+		rinv := make([]float64, n*n)
+		rinvT := make([]float64, n*n)
+		for j := 0; j < n; j++ {
+			rinv[j*n+j] = 1 / reg.At(j, j)
+			for i := j - 1; i >= 0; i-- {
+				var sum float64
+				for k := i + 1; k <= j; k++ {
+					sum += reg.At(i, k) * rinv[k*n+j]
+				}
+				rinv[i*n+j] = -sum / reg.At(i, i)
+			}
+		}
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				rinvT[j*n+i] = rinv[i*n+j]
+			}
+		}
+		inv := mat.NewDense(n, n, mulBlocked(rinv, rinvT, n, n, n))
+
+		coeffs := make([]float64, cols)
+		unscaled := mat.NewDense(cols, cols, nil)
+		for k, j := range active {
+			coeffs[j] = c[k]
+			for l, m := range active {
+				unscaled.Set(j, m, inv.At(k, l))
+			}
+		}
+		sort.Ints(aliased)
+		return coeffs, &Diagnostics{Aliased: aliased, Unscaled: unscaled}, nil
+	}
+}
+
+// RidgeSolver solves the least squares problem with an L2 penalty of Lambda on the coefficients of
+// the variables; the offset is not penalized. The penalty depends on the scale of the variables,
+// so it is usually combined with ZScore normalization.
+type RidgeSolver struct {
+	Lambda float64
+}
+
+// FitsWide satisfies the WideSolver interface: with a positive penalty the problem is well-posed
+// even with more variables than observations.
+func (s RidgeSolver) FitsWide() bool {
+	return s.Lambda > 0
+}
+
+// Solve satisfies the Solver interface.
+func (s RidgeSolver) Solve(x, y *mat.Dense) ([]float64, *Diagnostics, error) {
+	_, cols := x.Dims()
+	xtx := new(mat.Dense)
+	xtx.Mul(x.T(), x)
+	xty := new(mat.Dense)
+	xty.Mul(x.T(), y)
+
+	a := mat.NewDense(cols, cols, nil)
+	a.Copy(xtx)
+	for j := 1; j < cols; j++ {
+		a.Set(j, j, a.At(j, j)+s.Lambda)
+	}
+	ainv := new(mat.Dense)
+	if err := ainv.Inverse(a); err != nil {
+		return nil, nil, err
+	}
+
+	c := new(mat.Dense)
+	c.Mul(ainv, xty)
+	coeffs := make([]float64, cols)
+	for i := range coeffs {
+		coeffs[i] = c.At(i, 0)
+	}
+
+	// Cov(c) = sigma^2 * A^-1 X'X A^-1
+	tmp := new(mat.Dense)
+	tmp.Mul(ainv, xtx)
+	unscaled := new(mat.Dense)
+	unscaled.Mul(tmp, ainv)
+	return coeffs, &Diagnostics{Unscaled: unscaled}, nil
+}
@@ -0,0 +1,88 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+type normalSolver struct{}
+
+// Solve solves the normal equations directly, without reporting diagnostics.
+func (normalSolver) Solve(x, y *mat.Dense) ([]float64, *Diagnostics, error) {
+	xtx, xty, c := new(mat.Dense), new(mat.Dense), new(mat.Dense)
+	xtx.Mul(x.T(), x)
+	xty.Mul(x.T(), y)
+	if err := c.Solve(xtx, xty); err != nil {
+		return nil, nil, err
+	}
+	n, _ := c.Dims()
+	coeffs := make([]float64, n)
+	for i := range coeffs {
+		coeffs[i] = c.At(i, 0)
+	}
+	return coeffs, nil, nil
+}
+
+type failingSolver struct{ coeffs []float64 }
+
+func (s failingSolver) Solve(x, y *mat.Dense) ([]float64, *Diagnostics, error) {
+	if s.coeffs != nil {
+		return s.coeffs, nil, nil
+	}
+	return nil, nil, errors.New("solver failed")
+}
+
+func TestSetSolver(t *testing.T) {
+	r := new(Regression)
+	r.SetSolver(normalSolver{})
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "intercept", r.Coeff(0), -17.5791, 1e-4)
+	assertClose(t, "speed", r.Coeff(1), 3.9324, 1e-4)
+	assertClose(t, "R2", r.R2, 0.6511, 1e-4)
+	if !math.IsNaN(r.StdErr(1)) {
+		t.Error("Expected no standard errors without diagnostics")
+	}
+}
+
+func TestSolverErrors(t *testing.T) {
+	for _, s := range []Solver{failingSolver{}, failingSolver{coeffs: []float64{1}}} {
+		r := new(Regression)
+		r.SetSolver(s)
+		for i := range carsSpeed {
+			r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+		}
+		if err := r.Run(); err == nil {
+			t.Error("Expected the solver error to be returned")
+		}
+	}
+}
+
+func TestQRSolverAliased(t *testing.T) {
+	x := mat.NewDense(4, 3, []float64{
+		1, 1, 2,
+		1, 2, 4,
+		1, 3, 6,
+		1, 4, 8,
+	})
+	y := mat.NewDense(4, 1, []float64{3, 5, 7, 9})
+	c, diag, err := QRSolver{}.Solve(x, y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diag.Aliased) != 1 || diag.Aliased[0] != 2 {
+		t.Errorf("Expected column 2 to be aliased, got %v", diag.Aliased)
+	}
+	assertClose(t, "offset", c[0], 1, 1e-9)
+	assertClose(t, "slope", c[1], 2, 1e-9)
+	if c[2] != 0 || diag.Unscaled.At(2, 2) != 0 {
+		t.Error("Expected the aliased column to have a zero coefficient and variance")
+	}
+}
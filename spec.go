@@ -0,0 +1,106 @@
+package regression
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Spec is a declarative description of a model, typically stored in a JSON or YAML config file.
+// The struct tags cover both JSON and YAML decoders.
+type Spec struct {
+	// Observed is the name of the observed value.
+	Observed string `json:"observed" yaml:"observed"`
+	// Vars are the names of the variables, by index.
+	Vars []string `json:"vars,omitempty" yaml:"vars,omitempty"`
+	// Formula is an R style formula, see SetFormula. It is applied after Observed and Vars.
+	Formula string `json:"formula,omitempty" yaml:"formula,omitempty"`
+	// Crosses are added after any crosses from the formula.
+	Crosses []CrossSpec `json:"crosses,omitempty" yaml:"crosses,omitempty"`
+	// Solver is "qr" (the default) or "ridge".
+	Solver string `json:"solver,omitempty" yaml:"solver,omitempty"`
+	// Regularization configures the penalty of a regularized solver. Setting it selects
+	// the ridge solver unless another solver is given.
+	Regularization *RegularizationSpec `json:"regularization,omitempty" yaml:"regularization,omitempty"`
+	// Normalization is "none" (the default), "zscore" or "robust".
+	Normalization string `json:"normalization,omitempty" yaml:"normalization,omitempty"`
+}
+
+// RegularizationSpec configures the penalty of a regularized solver.
+type RegularizationSpec struct {
+	Lambda float64 `json:"lambda" yaml:"lambda"`
+}
+
+// LoadSpec builds a Regression from a JSON or YAML model specification. Specifications starting
+// with a brace are decoded as JSON, others as YAML. The YAML decoder covers block and single line
+// flow collections, quoted and plain scalars and comments; use LoadSpecWith with a full YAML
+// decoder for anchors, tags or multi-line strings.
+func LoadSpec(data []byte) (*Regression, error) {
+	if trimmed := bytes.TrimLeft(data, " \t\r\n\ufeff"); len(trimmed) > 0 && trimmed[0] == '{' {
+		return LoadSpecWith(data, json.Unmarshal)
+	}
+	return LoadSpecWith(data, unmarshalYAML)
+}
+
+// LoadSpecWith builds a Regression from a model specification decoded with unmarshal,
+// e.g. yaml.Unmarshal for YAML config files.
+func LoadSpecWith(data []byte, unmarshal func([]byte, interface{}) error) (*Regression, error) {
+	var spec Spec
+	if err := unmarshal(data, &spec); err != nil {
+		return nil, err
+	}
+	return spec.Build()
+}
+
+// Build creates a new Regression configured according to the spec.
+func (s Spec) Build() (*Regression, error) {
+	r := new(Regression)
+	r.SetObserved(s.Observed)
+	for i, name := range s.Vars {
+		if name != "" {
+			r.SetVar(i, name)
+		}
+	}
+	if s.Formula != "" {
+		if err := r.SetFormula(s.Formula); err != nil {
+			return nil, err
+		}
+	}
+	for _, c := range s.Crosses {
+		cross, err := c.build()
+		if err != nil {
+			return nil, err
+		}
+		r.AddCross(cross)
+	}
+
+	solver := strings.ToLower(s.Solver)
+	if solver == "" && s.Regularization != nil {
+		solver = "ridge"
+	}
+	switch solver {
+	case "", "qr":
+		if s.Regularization != nil {
+			return nil, fmt.Errorf("solver %q does not support regularization", s.Solver)
+		}
+	case "ridge":
+		var lambda float64
+		if s.Regularization != nil {
+			lambda = s.Regularization.Lambda
+		}
+		if lambda < 0 {
+			return nil, fmt.Errorf("negative regularization lambda %v", lambda)
+		}
+		r.SetSolver(RidgeSolver{Lambda: lambda})
+	default:
+		return nil, fmt.Errorf("unknown solver %q", s.Solver)
+	}
+
+	n, err := parseNormalization(s.Normalization)
+	if err != nil {
+		return nil, err
+	}
+	r.SetNormalization(n)
+	return r, nil
+}
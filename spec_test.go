@@ -0,0 +1,151 @@
+package regression
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestLoadSpec(t *testing.T) {
+	r, err := LoadSpec([]byte(`{
+		"observed": "dist",
+		"vars": ["speed"],
+		"crosses": [{"type": "pow", "vars": [0], "power": 2}],
+		"regularization": {"lambda": 0},
+		"normalization": "zscore"
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.solve.(RidgeSolver); !ok || r.normalization != ZScore {
+		t.Error("Expected a normalized ridge regression")
+	}
+	// with no penalty this is lm(dist ~ speed + I(speed^2), data = cars)
+	assertClose(t, "intercept", r.Coeff(0), 2.47014, 1e-4)
+	assertClose(t, "speed", r.Coeff(1), 0.91329, 1e-4)
+	assertClose(t, "speed^2", r.Coeff(2), 0.09996, 1e-4)
+	assertClose(t, "speed^2 stderr", r.StdErr(2), 0.06597, 1e-5)
+	if r.GetVar(1) != "(speed)^2" {
+		t.Errorf("Expected the cross to be named, got %q", r.GetVar(1))
+	}
+}
+
+func TestLoadSpecFormula(t *testing.T) {
+	r, err := LoadSpec([]byte(`{"formula": "y ~ a + b + a:b", "solver": "QR"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.GetObserved() != "y" || r.GetVar(1) != "b" || len(r.crosses) != 1 {
+		t.Error("Expected the formula to be applied")
+	}
+}
+
+func TestLoadSpecYAML(t *testing.T) {
+	r, err := LoadSpec([]byte(`---
+# cars stopping distance
+observed: dist
+vars: [speed]
+crosses:
+  - type: pow
+    vars:
+    - 0
+    power: 2 # quadratic
+regularization:
+  lambda: 0
+normalization: "zscore"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.solve.(RidgeSolver); !ok || r.normalization != ZScore {
+		t.Error("Expected a normalized ridge regression")
+	}
+	assertClose(t, "speed^2", r.Coeff(2), 0.09996, 1e-4)
+
+	if r, err = LoadSpec([]byte("formula: y ~ a + b + a:b\nsolver: 'QR'\n")); err != nil {
+		t.Fatal(err)
+	}
+	if r.GetObserved() != "y" || r.GetVar(1) != "b" || len(r.crosses) != 1 {
+		t.Error("Expected the formula to be applied")
+	}
+}
+
+func TestUnmarshalYAML(t *testing.T) {
+	var got map[string]interface{}
+	err := unmarshalYAML([]byte(`
+a: {x: 1, "y": [true, null, 'it''s']}
+b:
+  - - 1
+    - 2
+  - c: "#not a comment"
+    d: ~
+e: plain, with commas # comment
+`), &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"a":{"x":1,"y":[true,null,"it's"]},"b":[[1,2],{"c":"#not a comment","d":null}],"e":"plain, with commas"}`
+	if b, _ := json.Marshal(got); string(b) != want {
+		t.Errorf("Expected %s, got %s", want, b)
+	}
+
+	for _, doc := range []string{
+		"a: 1\n\tb: 2",
+		"a: |\n  text",
+		"a: &anchor 1",
+		"a: 1\n  b: 2",
+		"a: [1, 2",
+		"a: 1\na: 2",
+		"a: 1\n---\nb: 2",
+	} {
+		if err := unmarshalYAML([]byte(doc), &got); err == nil {
+			t.Errorf("Expected an error for %q", doc)
+		}
+	}
+}
+
+func TestLoadSpecWith(t *testing.T) {
+	called := false
+	unmarshal := func(data []byte, v interface{}) error {
+		called = true
+		v.(*Spec).Observed = string(data)
+		return nil
+	}
+	r, err := LoadSpecWith([]byte("y"), unmarshal)
+	if err != nil || !called || r.GetObserved() != "y" {
+		t.Errorf("Expected the custom decoder to be used, got %v", err)
+	}
+
+	failing := func([]byte, interface{}) error { return errors.New("bad yaml") }
+	if _, err := LoadSpecWith(nil, failing); err == nil {
+		t.Error("Expected the decoder error")
+	}
+}
+
+func TestLoadSpecErrors(t *testing.T) {
+	for _, spec := range []string{
+		`{`,
+		`{"solver": "gpu"}`,
+		`{"solver": "qr", "regularization": {"lambda": 1}}`,
+		`{"regularization": {"lambda": -1}}`,
+		`{"normalization": "minmax"}`,
+		`{"formula": "y ~"}`,
+		`{"crosses": [{"type": "log", "vars": [0]}]}`,
+	} {
+		if _, err := LoadSpec([]byte(spec)); err == nil {
+			t.Errorf("Expected an error for %s", spec)
+		}
+	}
+}
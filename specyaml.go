@@ -0,0 +1,358 @@
+package regression
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// yamlLine is a line of a YAML document without its indentation and comment.
+type yamlLine struct {
+	num    int
+	indent int
+	text   string
+}
+
+// yamlDecoder decodes the subset of YAML used by specification files: block mappings and
+// sequences, flow sequences and mappings on a single line, quoted and plain scalars and comments.
+// Anchors, tags, multi-line strings and multiple documents are not supported.
+type yamlDecoder struct {
+	lines []yamlLine
+	pos   int
+}
+
+// unmarshalYAML decodes a YAML document into v by way of its JSON equivalent, so the JSON struct
+// tags apply.
+func unmarshalYAML(data []byte, v interface{}) error {
+	d := new(yamlDecoder)
+	if err := d.split(string(data)); err != nil {
+		return err
+	}
+	var doc interface{}
+	if len(d.lines) > 0 {
+		var err error
+		if doc, err = d.node(d.lines[0].indent); err != nil {
+			return err
+		}
+		if d.pos < len(d.lines) {
+			return d.errorf(d.lines[d.pos], "unexpected indentation")
+		}
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func (d *yamlDecoder) errorf(l yamlLine, format string, args ...interface{}) error {
+	return fmt.Errorf("yaml line %d: %s", l.num, fmt.Sprintf(format, args...))
+}
+
+// split breaks the document into lines, dropping blank lines, comments and the document marker.
+func (d *yamlDecoder) split(doc string) error {
+	for i, text := range strings.Split(strings.TrimPrefix(doc, "\ufeff"), "\n") {
+		text = strings.TrimRight(stripYAMLComment(text), " \t\r")
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" || (i == 0 || len(d.lines) == 0) && trimmed == "---" {
+			continue
+		}
+		l := yamlLine{num: i + 1, indent: len(text) - len(trimmed), text: trimmed}
+		if strings.HasPrefix(trimmed, "\t") {
+			return d.errorf(l, "tabs are not allowed in indentation")
+		}
+		if trimmed == "---" || trimmed == "..." {
+			return d.errorf(l, "multiple documents are not supported")
+		}
+		d.lines = append(d.lines, l)
+	}
+	return nil
+}
+
+// stripYAMLComment removes a comment, which starts with a # at the start of the line or after
+// whitespace, outside quotes.
+func stripYAMLComment(s string) string {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else if c == '\\' && quote == '"' {
+				i++
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || s[i-1] == ' ' || s[i-1] == '\t'):
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// node decodes the block node starting at the current line, which has the given indentation.
+func (d *yamlDecoder) node(indent int) (interface{}, error) {
+	l := d.lines[d.pos]
+	if l.indent != indent {
+		return nil, d.errorf(l, "unexpected indentation")
+	}
+	if isYAMLItem(l.text) {
+		return d.sequence(indent)
+	}
+	if _, _, ok := splitYAMLKey(l.text); ok {
+		return d.mapping(indent)
+	}
+	d.pos++
+	return d.value(l, l.text)
+}
+
+func isYAMLItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// sequence decodes the items of a block sequence at the given indentation.
+func (d *yamlDecoder) sequence(indent int) ([]interface{}, error) {
+	items := []interface{}{}
+	for d.pos < len(d.lines) && d.lines[d.pos].indent == indent && isYAMLItem(d.lines[d.pos].text) {
+		l := d.lines[d.pos]
+		rest := strings.TrimLeft(strings.TrimPrefix(l.text, "-"), " ")
+		var item interface{}
+		var err error
+		if rest == "" {
+			d.pos++
+			item, err = d.child(indent)
+		} else {
+			// the item continues as a node indented to its first character, e.g. "- key: value"
+			d.lines[d.pos] = yamlLine{num: l.num, indent: indent + len(l.text) - len(rest), text: rest}
+			item, err = d.node(d.lines[d.pos].indent)
+		}
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// mapping decodes the entries of a block mapping at the given indentation.
+func (d *yamlDecoder) mapping(indent int) (map[string]interface{}, error) {
+	m := make(map[string]interface{})
+	for d.pos < len(d.lines) && d.lines[d.pos].indent == indent && !isYAMLItem(d.lines[d.pos].text) {
+		l := d.lines[d.pos]
+		key, rest, ok := splitYAMLKey(l.text)
+		if !ok {
+			return nil, d.errorf(l, "expected a key")
+		}
+		if key, ok = unquoteYAML(key); !ok {
+			return nil, d.errorf(l, "invalid key")
+		}
+		if _, dup := m[key]; dup {
+			return nil, d.errorf(l, "duplicate key %q", key)
+		}
+		d.pos++
+		var value interface{}
+		var err error
+		switch {
+		case rest != "":
+			value, err = d.value(l, rest)
+		case d.pos < len(d.lines) && d.lines[d.pos].indent == indent && isYAMLItem(d.lines[d.pos].text):
+			// a sequence may be indented as much as its key
+			value, err = d.sequence(indent)
+		default:
+			value, err = d.child(indent)
+		}
+		if err != nil {
+			return nil, err
+		}
+		m[key] = value
+	}
+	return m, nil
+}
+
+// child decodes the node nested below a line with the given indentation, or nil if there is none.
+func (d *yamlDecoder) child(indent int) (interface{}, error) {
+	if d.pos < len(d.lines) && d.lines[d.pos].indent > indent {
+		return d.node(d.lines[d.pos].indent)
+	}
+	return nil, nil
+}
+
+// splitYAMLKey splits "key: value" at the first colon followed by a space or the end of the line,
+// outside quotes and flow collections.
+func splitYAMLKey(text string) (string, string, bool) {
+	if strings.HasPrefix(text, "[") || strings.HasPrefix(text, "{") {
+		return "", "", false
+	}
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else if c == '\\' && quote == '"' {
+				i++
+			}
+		case (c == '"' || c == '\'') && i == 0:
+			quote = c
+		case c == ':' && (i+1 == len(text) || text[i+1] == ' '):
+			return strings.TrimRight(text[:i], " "), strings.TrimLeft(text[i+1:], " "), true
+		}
+	}
+	return "", "", false
+}
+
+// value decodes a scalar or a flow collection given on a single line.
+func (d *yamlDecoder) value(l yamlLine, text string) (interface{}, error) {
+	if strings.HasPrefix(text, "|") || strings.HasPrefix(text, ">") {
+		return nil, d.errorf(l, "multi-line strings are not supported")
+	}
+	if strings.HasPrefix(text, "&") || strings.HasPrefix(text, "*") || strings.HasPrefix(text, "!") {
+		return nil, d.errorf(l, "anchors, aliases and tags are not supported")
+	}
+	if c := text[0]; c != '[' && c != '{' && c != '"' && c != '\'' {
+		// plain scalars in block context may contain flow indicators such as commas
+		return plainYAML(text), nil
+	}
+	f := &yamlFlow{s: text}
+	v, err := f.value()
+	if err == nil && f.skipSpace() < len(f.s) {
+		err = fmt.Errorf("unexpected %q", f.s[f.i:])
+	}
+	if err != nil {
+		return nil, d.errorf(l, "%v", err)
+	}
+	return v, nil
+}
+
+// yamlFlow scans a flow node.
+type yamlFlow struct {
+	s string
+	i int
+}
+
+func (f *yamlFlow) skipSpace() int {
+	for f.i < len(f.s) && f.s[f.i] == ' ' {
+		f.i++
+	}
+	return f.i
+}
+
+func (f *yamlFlow) value() (interface{}, error) {
+	if f.skipSpace() == len(f.s) {
+		return nil, nil
+	}
+	switch f.s[f.i] {
+	case '[':
+		items := []interface{}{}
+		err := f.collection(']', func() error {
+			v, err := f.value()
+			items = append(items, v)
+			return err
+		})
+		return items, err
+	case '{':
+		m := make(map[string]interface{})
+		err := f.collection('}', func() error {
+			k, err := f.scalar(":")
+			if err != nil {
+				return err
+			}
+			key, ok := k.(string)
+			if !ok {
+				key = fmt.Sprint(k)
+			}
+			if f.skipSpace() == len(f.s) || f.s[f.i] != ':' {
+				return fmt.Errorf("expected a colon after %q", key)
+			}
+			f.i++
+			v, err := f.value()
+			m[key] = v
+			return err
+		})
+		return m, err
+	}
+	return f.scalar(",]}")
+}
+
+// collection scans the comma separated items of a flow collection up to its closing bracket.
+func (f *yamlFlow) collection(end byte, item func() error) error {
+	f.i++
+	for {
+		if f.skipSpace() == len(f.s) {
+			return fmt.Errorf("missing %q", end)
+		}
+		if f.s[f.i] == end {
+			f.i++
+			return nil
+		}
+		if err := item(); err != nil {
+			return err
+		}
+		if f.skipSpace() < len(f.s) && f.s[f.i] == ',' {
+			f.i++
+		} else if f.i == len(f.s) || f.s[f.i] != end {
+			return fmt.Errorf("missing %q", end)
+		}
+	}
+}
+
+// scalar scans a quoted scalar, or a plain one up to any of the stop characters.
+func (f *yamlFlow) scalar(stop string) (interface{}, error) {
+	start := f.skipSpace()
+	if start < len(f.s) && (f.s[start] == '"' || f.s[start] == '\'') {
+		quote := f.s[start]
+		for f.i = start + 1; f.i < len(f.s); f.i++ {
+			if f.s[f.i] == '\\' && quote == '"' {
+				f.i++
+			} else if f.s[f.i] == quote {
+				if quote == '\'' && f.i+1 < len(f.s) && f.s[f.i+1] == '\'' {
+					f.i++
+					continue
+				}
+				f.i++
+				s, ok := unquoteYAML(f.s[start:f.i])
+				if !ok {
+					return nil, fmt.Errorf("invalid string %s", f.s[start:f.i])
+				}
+				return s, nil
+			}
+		}
+		return nil, fmt.Errorf("unterminated string %s", f.s[start:])
+	}
+	for f.i < len(f.s) && !strings.ContainsRune(stop, rune(f.s[f.i])) {
+		f.i++
+	}
+	return plainYAML(strings.TrimRight(f.s[start:f.i], " ")), nil
+}
+
+// unquoteYAML returns the contents of a quoted string, or s itself if it isn't quoted.
+func unquoteYAML(s string) (string, bool) {
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return strings.Replace(s[1:len(s)-1], "''", "'", -1), true
+	}
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		var u string
+		err := json.Unmarshal([]byte(s), &u)
+		return u, err == nil
+	}
+	return s, true
+}
+
+// plainYAML resolves a plain scalar to a null, boolean, number or string as the YAML core schema does.
+func plainYAML(s string) interface{} {
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) &&
+		!strings.ContainsAny(s, "xXpP_") {
+		return f
+	}
+	return s
+}
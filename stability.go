@@ -0,0 +1,176 @@
+package regression
+
+import "math"
+
+// StabilityOptions configures Stability.
+type StabilityOptions struct {
+	// Resamples is the number of bootstrap resamples, 100 when zero. It is ignored when Window is set.
+	Resamples int
+	// Window, when set, refits the model on rolling windows of this many consecutive data points, in the
+	// order they were trained, instead of on bootstrap resamples.
+	Window int
+	// Step is the number of data points the window moves between fits, Window when zero.
+	Step int
+	// MaxCV is the coefficient of variation above which a coefficient is flagged as unstable, 0.5 when zero.
+	MaxCV float64
+	// MinSignAgreement is the share of fits whose coefficient must have the sign of the full fit,
+	// below which it is flagged as unstable, 0.9 when zero.
+	MinSignAgreement float64
+}
+
+// CoefficientStability describes how much a coefficient varies across refits.
+type CoefficientStability struct {
+	Name string
+	// Coeff is the coefficient fitted on all the training data.
+	Coeff  float64
+	Mean   float64
+	StdDev float64
+	// CV is the coefficient of variation, StdDev relative to the magnitude of Mean.
+	CV float64
+	// SignAgreement is the share of refits whose coefficient has the sign of Coeff.
+	SignAgreement float64
+	Unstable      bool
+}
+
+// StabilityReport describes the variation of the coefficients across refits of the model.
+type StabilityReport struct {
+	// Fits is the number of refits that succeeded.
+	Fits         int
+	Coefficients []CoefficientStability
+}
+
+// Unstable returns the names of the coefficients flagged as unstable.
+func (s *StabilityReport) Unstable() []string {
+	var names []string
+	for _, c := range s.Coefficients {
+		if c.Unstable {
+			names = append(names, c.Name)
+		}
+	}
+	return names
+}
+
+// Stability refits the model on bootstrap resamples or rolling windows of its training data and reports
+// how much each coefficient varies, flagging the unstable ones. Resamples are drawn with the model's
+// source of randomness, see SetSeed. Refits that fail, e.g. because a window is too small, are skipped.
+// Models with per-segment models or fixed effects are not supported.
+func (r *Regression) Stability(opts StabilityOptions) (*StabilityReport, error) {
+	if !r.hasRun || len(r.coeff) == 0 {
+		return nil, ErrNotRun
+	}
+	if len(r.data) == 0 {
+		return nil, ErrNoStatistics
+	}
+	if r.split || r.fixedEffects != nil || r.groupEffects != nil {
+		return nil, ErrUnsupported
+	}
+	maxCV := opts.MaxCV
+	if maxCV == 0 {
+		maxCV = 0.5
+	}
+	minAgreement := opts.MinSignAgreement
+	if minAgreement == 0 {
+		minAgreement = 0.9
+	}
+
+	var samples [][]*dataPoint
+	if opts.Window > 0 {
+		step := opts.Step
+		if step <= 0 {
+			step = opts.Window
+		}
+		for start := 0; start+opts.Window <= len(r.data); start += step {
+			samples = append(samples, r.data[start:start+opts.Window])
+		}
+	} else {
+		n := opts.Resamples
+		if n <= 0 {
+			n = 100
+		}
+		for i := 0; i < n; i++ {
+			sample := make([]*dataPoint, len(r.data))
+			for j := range sample {
+				sample[j] = r.data[r.random().Intn(len(r.data))]
+			}
+			samples = append(samples, sample)
+		}
+	}
+
+	var fits [][]float64
+	for _, sample := range samples {
+		c, err := r.refit(sample)
+		if err == nil {
+			fits = append(fits, c)
+		}
+	}
+	if len(fits) < 2 {
+		return nil, ErrNotEnoughData
+	}
+
+	report := &StabilityReport{Fits: len(fits), Coefficients: make([]CoefficientStability, len(r.coeff))}
+	n := float64(len(fits))
+	for i := range report.Coefficients {
+		s := CoefficientStability{Name: offsetName, Coeff: r.coeff[i]}
+		if i > 0 {
+			s.Name = r.GetVar(i - 1)
+		}
+		var sum, agree float64
+		for _, c := range fits {
+			sum += c[i]
+			if math.Signbit(c[i]) == math.Signbit(s.Coeff) && c[i] != 0 {
+				agree++
+			}
+		}
+		s.Mean = sum / n
+		var ss float64
+		for _, c := range fits {
+			ss += (c[i] - s.Mean) * (c[i] - s.Mean)
+		}
+		s.StdDev = math.Sqrt(ss / (n - 1))
+		s.CV = s.StdDev / math.Abs(s.Mean)
+		s.SignAgreement = agree / n
+		s.Unstable = !(s.CV <= maxCV) || s.SignAgreement < minAgreement
+		report.Coefficients[i] = s
+	}
+	return report, nil
+}
+
+// refit fits a copy of the model, with the same configuration, on a subset of its training data
+// and returns the coefficients.
+func (r *Regression) refit(points []*dataPoint) ([]float64, error) {
+	s, err := r.refitWith(points)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.coeff) != len(r.coeff) {
+		return nil, ErrSolverCoeffs
+	}
+	return s.coeffs(), nil
+}
+
+// refitWith fits a copy of the model, with the same configuration and additional feature crosses,
+// on a subset of its training data.
+func (r *Regression) refitWith(points []*dataPoint, crosses ...featureCross) (*Regression, error) {
+	s := &Regression{
+		names:            describe{obs: r.names.obs},
+		crosses:          append(append([]featureCross(nil), r.crosses...), crosses...),
+		solve:            r.solve,
+		instruments:      r.instruments,
+		signs:            r.signs,
+		vifThreshold:     r.vifThreshold,
+		censor:           r.censor,
+		prior:            r.prior,
+		normalization:    r.normalization,
+		varNormalization: r.varNormalization,
+	}
+	for _, d := range points {
+		// the training data already has the crosses applied
+		base := make([]float64, r.names.base)
+		copy(base, d.Variables)
+		s.Train(WeightedDataPoint(d.Observed, base, d.Weight))
+	}
+	if err := s.Run(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
@@ -0,0 +1,67 @@
+package regression
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestStability(t *testing.T) {
+	noise := rand.New(rand.NewSource(3))
+	r := new(Regression)
+	r.SetObserved("dist")
+	r.SetVar(0, "speed")
+	r.SetVar(1, "noise")
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i], noise.NormFloat64()}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := r.Stability(StabilityOptions{Resamples: 200})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Fits != 200 || len(report.Coefficients) != 3 {
+		t.Fatalf("Expected 200 fits of 3 coefficients, got %+v", report)
+	}
+	speed := report.Coefficients[1]
+	if speed.Name != "speed" || speed.Coeff != r.Coeff(1) {
+		t.Errorf("Unexpected speed coefficient %+v", speed)
+	}
+	// the bootstrap standard deviation is close to the standard error
+	assertClose(t, "speed stddev", speed.StdDev, r.StdErr(1), 0.15)
+	if speed.SignAgreement != 1 || speed.Unstable {
+		t.Errorf("Expected speed to be stable, got %+v", speed)
+	}
+	if got := report.Unstable(); len(got) != 1 || got[0] != "noise" {
+		t.Errorf("Expected only noise to be unstable, got %v", got)
+	}
+
+	// the same seed draws the same resamples
+	r.SetSeed(7)
+	a, _ := r.Stability(StabilityOptions{Resamples: 20})
+	r.SetSeed(7)
+	b, _ := r.Stability(StabilityOptions{Resamples: 20})
+	if a.Coefficients[1] != b.Coefficients[1] {
+		t.Errorf("Expected equal reports, got %+v and %+v", a.Coefficients[1], b.Coefficients[1])
+	}
+}
+
+func TestStabilityWindows(t *testing.T) {
+	r := carsRegression(t)
+	report, err := r.Stability(StabilityOptions{Window: 20, Step: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Fits != 4 {
+		t.Errorf("Expected 4 windows, got %d", report.Fits)
+	}
+
+	if _, err := r.Stability(StabilityOptions{Window: 60}); err != ErrNotEnoughData {
+		t.Errorf("Expected ErrNotEnoughData, got %v", err)
+	}
+	if _, err := new(Regression).Stability(StabilityOptions{}); err != ErrNotRun {
+		t.Errorf("Expected ErrNotRun, got %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package regression
+
+import (
+	"math"
+	"time"
+)
+
+// Reasons for which a model is stale.
+const (
+	// StaleAge marks a model trained longer ago than MaxAge.
+	StaleAge = "age"
+	// StaleNewPoints marks a model updated with more than MaxNewPoints since it was fitted.
+	StaleNewPoints = "new_points"
+	// StaleDrift marks a model whose drift score exceeds MaxDrift.
+	StaleDrift = "drift"
+)
+
+// StalenessPolicy decides when a model is stale and should be refitted. Each threshold is disabled
+// when zero; the model is stale when any enabled threshold is exceeded.
+type StalenessPolicy struct {
+	// MaxAge is the time since the model was last trained, by a fit or by Update.
+	MaxAge time.Duration
+	// MaxNewPoints is the number of data points passed to Update since the model was fitted by Run or RunStream.
+	MaxNewPoints int
+	// MaxDrift is the drift score, the relative increase of the error on the holdout set over its
+	// baseline, see Holdout. It requires a holdout set.
+	MaxDrift float64
+	// OnStale, when set, is called by IsStale with the report of a stale model.
+	OnStale func(StaleReport)
+}
+
+// StaleReport describes the staleness of a model.
+type StaleReport struct {
+	Stale bool
+	// Reasons lists the exceeded thresholds, see StaleAge, StaleNewPoints and StaleDrift.
+	Reasons   []string
+	Age       time.Duration
+	NewPoints int
+	// Drift is the drift score, NaN without a holdout set.
+	Drift float64
+}
+
+// SetStalenessPolicy sets the policy used by IsStale and Staleness.
+func (r *Regression) SetStalenessPolicy(p StalenessPolicy) {
+	r.staleness = &p
+}
+
+// Staleness reports the staleness of the model according to its policy. A model that hasn't been
+// run is stale.
+func (r *Regression) Staleness() StaleReport {
+	report := StaleReport{NewPoints: r.newPoints, Drift: math.NaN()}
+	if !r.hasRun || len(r.coeff) == 0 {
+		report.Stale = true
+		return report
+	}
+	report.Age = time.Since(r.trainedAt)
+	if h := r.Holdout(); h.Points > 0 && h.BaselineRMSE > 0 {
+		report.Drift = h.RMSE/h.BaselineRMSE - 1
+	}
+	p := r.staleness
+	if p == nil {
+		return report
+	}
+	if p.MaxAge > 0 && report.Age > p.MaxAge {
+		report.Reasons = append(report.Reasons, StaleAge)
+	}
+	if p.MaxNewPoints > 0 && report.NewPoints > p.MaxNewPoints {
+		report.Reasons = append(report.Reasons, StaleNewPoints)
+	}
+	if p.MaxDrift > 0 && report.Drift > p.MaxDrift {
+		report.Reasons = append(report.Reasons, StaleDrift)
+	}
+	report.Stale = len(report.Reasons) > 0
+	return report
+}
+
+// IsStale reports whether the model is stale according to its policy, calling the policy's OnStale
+// callback if it is.
+func (r *Regression) IsStale() bool {
+	report := r.Staleness()
+	if report.Stale && r.staleness != nil && r.staleness.OnStale != nil {
+		r.staleness.OnStale(report)
+	}
+	return report.Stale
+}
@@ -0,0 +1,78 @@
+package regression
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestStaleness(t *testing.T) {
+	if report := new(Regression).Staleness(); !report.Stale {
+		t.Errorf("Expected a model that hasn't run to be stale, got %+v", report)
+	}
+
+	r := carsRegression(t)
+	if r.IsStale() {
+		t.Error("Expected no staleness without a policy")
+	}
+	var reports []StaleReport
+	r.SetStalenessPolicy(StalenessPolicy{
+		MaxAge:       time.Hour,
+		MaxNewPoints: 5,
+		MaxDrift:     0.5,
+		OnStale:      func(s StaleReport) { reports = append(reports, s) },
+	})
+	if report := r.Staleness(); report.Stale || !math.IsNaN(report.Drift) || report.Age > time.Minute {
+		t.Errorf("Expected a fresh model, got %+v", report)
+	}
+
+	for i := 0; i < 6; i++ {
+		if err := r.Update(DataPoint(carsDist[i], []float64{carsSpeed[i]})); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !r.IsStale() || len(reports) != 1 {
+		t.Fatalf("Expected the new points to make the model stale, got %+v", reports)
+	}
+	if report := reports[0]; len(report.Reasons) != 1 || report.Reasons[0] != StaleNewPoints || report.NewPoints != 6 {
+		t.Errorf("Unexpected report %+v", report)
+	}
+
+	r.trainedAt = time.Now().Add(-2 * time.Hour)
+	if report := r.Staleness(); len(report.Reasons) != 2 || report.Reasons[0] != StaleAge {
+		t.Errorf("Expected the age to make the model stale, got %+v", report)
+	}
+
+	// updates with a flipped relationship drift from the fitted model on a holdout set of the old one
+	r = carsRegression(t)
+	r.SetStalenessPolicy(StalenessPolicy{MaxDrift: 0.5})
+	r.SetHoldout(10, 0, 0)
+	for i := 0; i < 200; i++ {
+		x := float64(i%20 + 5)
+		if err := r.Update(DataPoint(100-3*x, []float64{x})); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 10; i++ {
+		x := float64(i + 5)
+		r.online.hold(DataPoint(-17.5791+3.9324*x, []float64{x}))
+	}
+	report := r.Staleness()
+	if !report.Stale || len(report.Reasons) != 1 || report.Reasons[0] != StaleDrift {
+		t.Errorf("Expected the drift to make the model stale, got %+v", report)
+	}
+	h := r.Holdout()
+	assertClose(t, "drift", report.Drift, h.RMSE/h.BaselineRMSE-1, 1e-12)
+
+	// a refit resets the new points
+	r = carsRegression(t)
+	r.Update(DataPoint(1, []float64{1}))
+	if r.newPoints != 1 {
+		t.Errorf("Expected 1 new point, got %d", r.newPoints)
+	}
+	r.hasRun = false
+	r.Run()
+	if r.newPoints != 0 {
+		t.Errorf("Expected the refit to reset the new points, got %d", r.newPoints)
+	}
+}
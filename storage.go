@@ -0,0 +1,74 @@
+package regression
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	// ErrModelNotFound signals that no model is stored under the requested key.
+	ErrModelNotFound = errors.New("model not found")
+	// ErrInvalidKey signals a storage key that cannot be used, e.g. an empty one.
+	ErrInvalidKey = errors.New("invalid storage key")
+)
+
+// Storage stores trained models by key, such as a model name, so trainers and scoring services
+// can share them.
+type Storage interface {
+	// Save stores r under key, replacing any model stored under it.
+	Save(key string, r *Regression) error
+	// Load returns the model stored under key, or ErrModelNotFound.
+	Load(key string) (*Regression, error)
+}
+
+// DirStorage stores models as JSON files in a directory, one file per key.
+type DirStorage string
+
+// Save writes r to the file of key. The file is replaced atomically, so readers never see a partial model.
+func (dir DirStorage) Save(key string, r *Regression) error {
+	path, err := dir.path(key)
+	if err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(string(dir), "."+key)
+	if err != nil {
+		return err
+	}
+	if err := r.Save(f); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// Load reads the model stored under key.
+func (dir DirStorage) Load(key string) (*Regression, error) {
+	path, err := dir.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, ErrModelNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Load(f)
+}
+
+func (dir DirStorage) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, ".") || strings.ContainsAny(key, `/\`) {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(string(dir), key+".json"), nil
+}
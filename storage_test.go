@@ -0,0 +1,37 @@
+package regression
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestDirStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "regression")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var s Storage = DirStorage(dir)
+	if _, err := s.Load("cars"); err != ErrModelNotFound {
+		t.Errorf("Expected ErrModelNotFound, got %v", err)
+	}
+	r := carsRegression(t)
+	if err := s.Save("cars", r); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := s.Load("cars")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Formula != r.Formula {
+		t.Errorf("Expected formula %q, got %q", r.Formula, loaded.Formula)
+	}
+
+	for _, key := range []string{"", "../cars", ".hidden"} {
+		if err := s.Save(key, r); err != ErrInvalidKey {
+			t.Errorf("Save(%q): expected ErrInvalidKey, got %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package regression
+
+import (
+	"math"
+	"time"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// normalEquations accumulates the sufficient statistics of a least squares problem,
+// so it can be solved without holding the design matrix in memory.
+type normalEquations struct {
+	n    int
+	sumW float64
+	xtx  [][]float64
+	xty  []float64
+	yty  float64
+	sumY float64
+}
+
+func newNormalEquations(cols int) *normalEquations {
+	a := &normalEquations{xtx: make([][]float64, cols), xty: make([]float64, cols)}
+	for i := range a.xtx {
+		a.xtx[i] = make([]float64, cols)
+	}
+	return a
+}
+
+// add accumulates a row of the design matrix and its observed value with weight w.
+func (a *normalEquations) add(row []float64, y, w float64) {
+	for i, xi := range row {
+		a.xty[i] += w * xi * y
+		for j := i; j < len(row); j++ {
+			a.xtx[i][j] += w * xi * row[j]
+		}
+	}
+	a.yty += w * y * y
+	a.sumY += w * y
+	a.sumW += w
+	if w != 0 {
+		a.n++
+	}
+}
+
+func (a *normalEquations) at(i, j int) float64 {
+	if i > j {
+		i, j = j, i
+	}
+	return a.xtx[i][j]
+}
+
+// solve solves the normal equations with a Cholesky decomposition of X'X. As with fitQR, columns that
+// are linear combinations of preceding columns are aliased and get a zero coefficient. It returns the
+// coefficients and the unscaled covariance matrix (X'X)^-1.
+func (a *normalEquations) solve() ([]float64, *mat.Dense) {
+	n := len(a.xty)
+	l := make([][]float64, n)
+	for j := range l {
+		l[j] = make([]float64, n)
+	}
+	active := make([]bool, n)
+	for j := 0; j < n; j++ {
+		d := a.at(j, j)
+		for k := 0; k < j; k++ {
+			d -= l[j][k] * l[j][k]
+		}
+		// the diagonal of the Cholesky factor equals that of R in the QR decomposition
+		if d <= aliasTolerance*aliasTolerance*a.at(j, j) {
+			continue
+		}
+		active[j] = true
+		l[j][j] = math.Sqrt(d)
+		for i := j + 1; i < n; i++ {
+			v := a.at(i, j)
+			for k := 0; k < j; k++ {
+				v -= l[i][k] * l[j][k]
+			}
+			l[i][j] = v / l[j][j]
+		}
+	}
+
+	// forward and back substitution, skipping aliased columns
+	solveL := func(b []float64) []float64 {
+		z := make([]float64, n)
+		for i := 0; i < n; i++ {
+			if !active[i] {
+				continue
+			}
+			v := b[i]
+			for k := 0; k < i; k++ {
+				v -= l[i][k] * z[k]
+			}
+			z[i] = v / l[i][i]
+		}
+		x := make([]float64, n)
+		for i := n - 1; i >= 0; i-- {
+			if !active[i] {
+				continue
+			}
+			v := z[i]
+			for k := i + 1; k < n; k++ {
+				v -= l[k][i] * x[k]
+			}
+			x[i] = v / l[i][i]
+		}
+		return x
+	}
+
+	c := solveL(a.xty)
+	unscaled := mat.NewDense(n, n, nil)
+	for j := 0; j < n; j++ {
+		if !active[j] {
+			continue
+		}
+		e := make([]float64, n)
+		e[j] = 1
+		for i, v := range solveL(e) {
+			unscaled.Set(i, j, v)
+		}
+	}
+	return c, unscaled
+}
+
+// RunStream fits the regression out-of-core: data points are pulled from next until it returns false,
+// and only the sufficient statistics X'X and X'y are kept in memory. Feature crosses are applied to every
+// data point, and weights are taken into account as in Run. The data points are not retained, so
+// per-point values such as residuals are not available. The statistics are kept, so the model can be
+// trained further with Update. Custom solvers, per-segment models, instrumental variables, sign
+// constraints, censoring, priors, winsorization and AutoDropCollinear are not supported.
+func (r *Regression) RunStream(next func() (*dataPoint, bool)) error {
+	if r.hasRun {
+		return ErrRegressionRun
+	}
+	if r.solve != nil || r.split || r.instruments != nil || r.signs != nil || r.censor != nil ||
+		r.prior != nil || r.winsorVars != nil || r.winsorObs != nil || r.vifThreshold > 0 {
+		return ErrUnsupported
+	}
+
+	var a *normalEquations
+	numOfBaseVars := 0
+	hasher := newDataHasher(r.hashOrder)
+	for {
+		d, ok := next()
+		if !ok {
+			break
+		}
+		if d.Weight < 0 {
+			return ErrInvalidWeight
+		}
+		hasher.add(d)
+		row := r.designRow(d.Variables)
+		if a == nil {
+			a = newNormalEquations(len(row))
+			numOfBaseVars = len(d.Variables)
+		}
+		a.add(row, d.Observed, d.Weight)
+	}
+	if a == nil || a.n < 3 {
+		return ErrNotEnoughData
+	}
+	if a.n < len(a.xty) {
+		return ErrTooManyVars
+	}
+
+	r.initialised = true
+	r.hasRun = true
+	r.hasher = hasher
+	r.extendNames(numOfBaseVars)
+	c, unscaled := a.solve()
+	r.setCoeffs(c)
+	r.calcStreamMetrics(a, c, unscaled)
+	r.onlineState().stats = a
+	r.newPoints = 0
+	return nil
+}
+
+// calcStreamMetrics derives the variances, R2 and coefficient covariance from the sufficient statistics.
+func (r *Regression) calcStreamMetrics(a *normalEquations, c []float64, unscaled *mat.Dense) {
+	n := a.sumW
+	var cty, ctxtxc float64
+	for i := range c {
+		cty += c[i] * a.xty[i]
+		for j := range c {
+			ctxtxc += c[i] * a.at(i, j) * c[j]
+		}
+	}
+	mean := a.sumY / n
+	sst := a.yty - n*mean*mean
+	sse := a.yty - 2*cty + ctxtxc
+	if sse < 0 {
+		sse = 0
+	}
+
+	r.Varianceobserved = sst / n
+	r.VariancePredicted = (ctxtxc - n*mean*mean) / n
+	r.calcR2()
+	r.rmse = math.Sqrt(sse / n)
+
+	rank := 0
+	r.aliased = nil
+	for i := range c {
+		if unscaled.At(i, i) != 0 {
+			rank++
+		} else {
+			r.aliased = append(r.aliased, i)
+		}
+	}
+	r.observations = a.n
+	r.trainedAt = time.Now()
+	r.dfResidual = a.n - rank
+	r.sigma2 = math.NaN()
+	if r.dfResidual > 0 {
+		r.sigma2 = sse / float64(r.dfResidual)
+	}
+	r.covariance = new(mat.Dense)
+	r.covariance.Scale(r.sigma2, unscaled)
+}
@@ -0,0 +1,66 @@
+package regression
+
+import "testing"
+
+func TestRunStream(t *testing.T) {
+	want := new(Regression)
+	for i := range carsSpeed {
+		want.Train(DataPoint(carsDist[i], []float64{carsSpeed[i], 2 * carsSpeed[i]}))
+	}
+	want.AddCross(PowCross(0, 2))
+	if err := want.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := new(Regression)
+	r.AddCross(PowCross(0, 2))
+	i := 0
+	err := r.RunStream(func() (*dataPoint, bool) {
+		if i == len(carsSpeed) {
+			return nil, false
+		}
+		i++
+		return DataPoint(carsDist[i-1], []float64{carsSpeed[i-1], 2 * carsSpeed[i-1]}), true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for j := 0; j < 4; j++ {
+		assertClose(t, "coefficient", r.Coeff(j), want.Coeff(j), 1e-6)
+		if j != 2 {
+			assertClose(t, "stderr", r.StdErr(j), want.StdErr(j), 1e-6)
+		}
+	}
+	if r.Coeff(2) != 0 {
+		t.Errorf("Expected the duplicate variable to be aliased, got %v", r.Coeff(2))
+	}
+	assertClose(t, "R2", r.R2, want.R2, 1e-9)
+	assertClose(t, "variance predicted", r.VariancePredicted, want.VariancePredicted, 1e-6)
+	if r.Formula != want.Formula {
+		t.Errorf("Expected formula %q, got %q", want.Formula, r.Formula)
+	}
+	if err := r.RunStream(func() (*dataPoint, bool) { return nil, false }); err != ErrRegressionRun {
+		t.Errorf("Expected ErrRegressionRun, got %v", err)
+	}
+}
+
+func TestRunStreamNotEnoughData(t *testing.T) {
+	r := new(Regression)
+	if err := r.RunStream(func() (*dataPoint, bool) { return nil, false }); err != ErrNotEnoughData {
+		t.Errorf("Expected ErrNotEnoughData, got %v", err)
+	}
+}
+
+func TestRunStreamUnsupported(t *testing.T) {
+	r := new(Regression)
+	r.SetSolver(QRSolver{})
+	if err := r.RunStream(func() (*dataPoint, bool) { return nil, false }); err != ErrUnsupported {
+		t.Errorf("Expected ErrUnsupported with a custom solver, got %v", err)
+	}
+	r = new(Regression)
+	r.SplitByVar(0)
+	if err := r.RunStream(func() (*dataPoint, bool) { return nil, false }); err != ErrUnsupported {
+		t.Errorf("Expected ErrUnsupported for a per-segment model, got %v", err)
+	}
+}
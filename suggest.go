@@ -0,0 +1,110 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// ErrInvalidDegree signals a polynomial degree below two, which generates no feature crosses.
+var ErrInvalidDegree = errors.New("degree must be at least 2")
+
+// suggestAlpha is the family-wise significance level of SuggestCrosses.
+const suggestAlpha = 0.05
+
+// CrossSuggestion is a candidate feature cross evaluated by SuggestCrosses.
+type CrossSuggestion struct {
+	Name string
+	// Cross is the feature cross, ready to be added with AddCross.
+	Cross featureCross
+	// R2Gain is the increase in R2 when the cross is added to the model.
+	R2Gain float64
+	// F and PValue are the partial F test of the cross, and AdjustedPValue is PValue adjusted for testing
+	// all candidates with the Holm-Bonferroni method.
+	F              float64
+	PValue         float64
+	AdjustedPValue float64
+	// Significant is set when AdjustedPValue is below 0.05.
+	Significant bool
+}
+
+// SuggestCrosses tests candidate feature crosses for a significant improvement of the fit: the powers of
+// every variable from 2 up to maxDegree, and the products of up to maxDegree distinct variables. Each
+// candidate is added to the model on its own and tested with a partial F test, and the p-values are
+// adjusted for the number of candidates. The suggestions are ranked by their gain in R2. Candidates that
+// add no information, e.g. because the model already has the cross, are left out. The training data is
+// required, and models with per-segment models or fixed effects are not supported.
+func (r *Regression) SuggestCrosses(maxDegree int) ([]CrossSuggestion, error) {
+	if maxDegree < 2 {
+		return nil, ErrInvalidDegree
+	}
+	if !r.hasRun || len(r.coeff) == 0 {
+		return nil, ErrNotRun
+	}
+	if len(r.data) == 0 {
+		return nil, ErrNotEnoughData
+	}
+	if r.split || r.fixedEffects != nil || r.groupEffects != nil {
+		return nil, ErrUnsupported
+	}
+
+	var candidates []featureCross
+	for i := 0; i < r.names.base; i++ {
+		for d := 2; d <= maxDegree; d++ {
+			candidates = append(candidates, PowCross(i, float64(d)))
+		}
+	}
+	var combine func(start int, vars []int)
+	combine = func(start int, vars []int) {
+		if len(vars) >= 2 {
+			candidates = append(candidates, MultiplierCross(append([]int(nil), vars...)...))
+		}
+		if len(vars) == maxDegree {
+			return
+		}
+		for i := start; i < r.names.base; i++ {
+			combine(i+1, append(vars, i))
+		}
+	}
+	combine(0, nil)
+
+	names := make(map[int]string, r.names.base+1)
+	for i := 0; i < r.names.base; i++ {
+		names[i] = r.GetVar(i)
+	}
+	var suggestions []CrossSuggestion
+	for _, cross := range candidates {
+		s, err := r.refitWith(r.data, cross)
+		if err != nil || len(s.coeff) != len(r.coeff)+1 || containsInt(s.aliased, len(r.coeff)) || s.dfResidual <= 0 {
+			continue
+		}
+		cross.ExtendNames(names, r.names.base)
+		gain := s.R2 - r.R2
+		f := gain / ((1 - s.R2) / float64(s.dfResidual))
+		d := r.distributions().F(1, float64(s.dfResidual))
+		p := 1 - d.CDF(f)
+		if sf, ok := d.(survival); ok {
+			p = sf.Survival(f)
+		}
+		suggestions = append(suggestions, CrossSuggestion{
+			Name:   names[r.names.base],
+			Cross:  cross,
+			R2Gain: gain,
+			F:      f,
+			PValue: p,
+		})
+	}
+
+	// Holm-Bonferroni: the k-th smallest p-value is multiplied by the number of hypotheses left,
+	// keeping the adjusted p-values monotonic
+	sort.SliceStable(suggestions, func(i, j int) bool { return suggestions[i].PValue < suggestions[j].PValue })
+	var max float64
+	for k := range suggestions {
+		adjusted := math.Min(1, float64(len(suggestions)-k)*suggestions[k].PValue)
+		max = math.Max(max, adjusted)
+		suggestions[k].AdjustedPValue = max
+		suggestions[k].Significant = max < suggestAlpha
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool { return suggestions[i].R2Gain > suggestions[j].R2Gain })
+	return suggestions, nil
+}
@@ -0,0 +1,61 @@
+package regression
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSuggestCrosses(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	r := new(Regression)
+	r.SetVar(0, "a")
+	r.SetVar(1, "b")
+	r.SetVar(2, "c")
+	for i := 0; i < 200; i++ {
+		a, b, c := rng.NormFloat64(), rng.NormFloat64(), rng.NormFloat64()
+		r.Train(DataPoint(1+2*a+3*b+4*a*b+rng.NormFloat64(), []float64{a, b, c}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	suggestions, err := r.SuggestCrosses(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the squares of the three variables and their three pairwise products
+	if len(suggestions) != 6 {
+		t.Fatalf("Expected 6 suggestions, got %d", len(suggestions))
+	}
+	best := suggestions[0]
+	if best.Name != "(a)0*1" || !best.Significant || best.AdjustedPValue < best.PValue {
+		t.Errorf("Expected a significant a*b interaction first, got %+v", best)
+	}
+	for _, s := range suggestions[1:] {
+		if s.R2Gain > best.R2Gain || s.AdjustedPValue < s.PValue {
+			t.Errorf("Unexpected suggestion %+v", s)
+		}
+	}
+
+	// once the interaction is in the model, nothing else helps
+	r.AddCross(best.Cross)
+	if _, err := r.RunIfChanged(); err != nil {
+		t.Fatal(err)
+	}
+	suggestions, err = r.SuggestCrosses(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 6 powers and 4 products, less the interaction already in the model
+	if len(suggestions) != 9 {
+		t.Errorf("Expected 9 suggestions, got %d", len(suggestions))
+	}
+	for _, s := range suggestions {
+		if s.Significant {
+			t.Errorf("Expected no significant suggestion, got %+v", s)
+		}
+	}
+	if _, err := r.SuggestCrosses(1); err != ErrInvalidDegree {
+		t.Errorf("Expected ErrInvalidDegree, got %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+package regression
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+// TimeFeature is a feature extracted from a Unix timestamp by TimeFeaturesCross.
+type TimeFeature string
+
+const (
+	// HourOfDay adds indicators for the hours 1 to 23; midnight is the reference level.
+	HourOfDay TimeFeature = "hour"
+	// DayOfWeek adds indicators for Monday to Saturday; Sunday is the reference level.
+	DayOfWeek TimeFeature = "weekday"
+	// Month adds indicators for February to December; January is the reference level.
+	Month TimeFeature = "month"
+	// HourCyclical adds the sine and cosine of the time of day, so 23:00 and 01:00 are close.
+	HourCyclical TimeFeature = "hour_cyclical"
+	// DayOfWeekCyclical adds the sine and cosine of the time of week.
+	DayOfWeekCyclical TimeFeature = "weekday_cyclical"
+	// MonthCyclical adds the sine and cosine of the time of year, by month.
+	MonthCyclical TimeFeature = "month_cyclical"
+)
+
+// timeCross expands a Unix timestamp into calendar features.
+type timeCross struct {
+	varIndex int
+	features []TimeFeature
+}
+
+// TimeFeaturesCross expands variable i, a Unix timestamp in seconds, into calendar features in UTC:
+// indicator variables per hour, weekday or month, or cyclical sine and cosine encodings of them.
+// The indicators leave out a reference level, so they aren't collinear with the offset. Without
+// features the three cyclical encodings are used. Unknown features add no variables.
+func TimeFeaturesCross(i int, features ...TimeFeature) featureCross {
+	if len(features) == 0 {
+		features = []TimeFeature{HourCyclical, DayOfWeekCyclical, MonthCyclical}
+	}
+	return &timeCross{varIndex: i, features: append([]TimeFeature(nil), features...)}
+}
+
+// width returns the number of variables added for a feature.
+func (f TimeFeature) width() int {
+	switch f {
+	case HourOfDay:
+		return 23
+	case DayOfWeek:
+		return 6
+	case Month:
+		return 11
+	case HourCyclical, DayOfWeekCyclical, MonthCyclical:
+		return 2
+	}
+	return 0
+}
+
+func (f TimeFeature) known() bool {
+	return f.width() > 0
+}
+
+func (c *timeCross) Calculate(input []float64) []float64 {
+	sec, frac := math.Modf(input[c.varIndex])
+	t := time.Unix(int64(sec), int64(frac*1e9)).UTC()
+	var out []float64
+	for _, f := range c.features {
+		switch f {
+		case HourOfDay:
+			out = appendIndicators(out, 23, t.Hour()-1)
+		case DayOfWeek:
+			out = appendIndicators(out, 6, int(t.Weekday())-1)
+		case Month:
+			out = appendIndicators(out, 11, int(t.Month())-2)
+		case HourCyclical:
+			hours := float64(t.Hour()) + float64(t.Minute())/60 + float64(t.Second())/3600
+			out = appendCyclical(out, hours/24)
+		case DayOfWeekCyclical:
+			days := float64(t.Weekday()) + float64(t.Hour())/24 + float64(t.Minute())/1440
+			out = appendCyclical(out, days/7)
+		case MonthCyclical:
+			out = appendCyclical(out, float64(t.Month()-1)/12)
+		}
+	}
+	return out
+}
+
+// appendIndicators appends n indicators of which the one at index k, if any, is set.
+func appendIndicators(out []float64, n, k int) []float64 {
+	for i := 0; i < n; i++ {
+		if i == k {
+			out = append(out, 1)
+		} else {
+			out = append(out, 0)
+		}
+	}
+	return out
+}
+
+// appendCyclical appends the sine and cosine of a fraction of a cycle.
+func appendCyclical(out []float64, fraction float64) []float64 {
+	return append(out, math.Sin(2*math.Pi*fraction), math.Cos(2*math.Pi*fraction))
+}
+
+func (c *timeCross) ExtendNames(input map[int]string, initialSize int) int {
+	name := input[c.varIndex]
+	n := 0
+	add := func(suffix string) {
+		if name != "" {
+			input[initialSize+n] = "(" + name + ")" + suffix
+		}
+		n++
+	}
+	for _, f := range c.features {
+		switch f {
+		case HourOfDay:
+			for h := 1; h < 24; h++ {
+				add("hour=" + strconv.Itoa(h))
+			}
+		case DayOfWeek:
+			for d := time.Monday; d <= time.Saturday; d++ {
+				add("weekday=" + d.String()[:3])
+			}
+		case Month:
+			for m := time.February; m <= time.December; m++ {
+				add("month=" + m.String()[:3])
+			}
+		case HourCyclical, DayOfWeekCyclical, MonthCyclical:
+			base := string(f[:len(f)-len("_cyclical")])
+			add(base + "_sin")
+			add(base + "_cos")
+		}
+	}
+	return n
+}
+
+func (c *timeCross) spec() CrossSpec {
+	s := CrossSpec{Type: "time", Vars: []int{c.varIndex}}
+	for _, f := range c.features {
+		s.Features = append(s.Features, string(f))
+	}
+	return s
+}
+
+// fourierCross adds the sine and cosine of a variable at harmonics of a period.
+type fourierCross struct {
+	varIndex  int
+	period    float64
+	harmonics int
+}
+
+// FourierCross adds sine and cosine pairs of variable i at the given period and its first harmonics,
+// sin(2*pi*k*x/period) and cos(2*pi*k*x/period) for k = 1 to harmonics, so a seasonal pattern of that
+// period can be fitted linearly. More harmonics fit sharper patterns. With a Unix timestamp, a period
+// of 86400 models the daily and 604800 the weekly cycle.
+func FourierCross(i int, period float64, harmonics int) featureCross {
+	return &fourierCross{varIndex: i, period: period, harmonics: harmonics}
+}
+
+func (c *fourierCross) Calculate(input []float64) []float64 {
+	out := make([]float64, 0, 2*c.harmonics)
+	for k := 1; k <= c.harmonics; k++ {
+		// reduce the phase first, so large timestamps don't lose precision
+		out = appendCyclical(out, math.Mod(float64(k)*input[c.varIndex]/c.period, 1))
+	}
+	return out
+}
+
+func (c *fourierCross) ExtendNames(input map[int]string, initialSize int) int {
+	n := 0
+	for k := 1; k <= c.harmonics; k++ {
+		if name := input[c.varIndex]; name != "" {
+			suffix := strconv.Itoa(k) + "/" + strconv.FormatFloat(c.period, 'g', -1, 64)
+			input[initialSize+n] = "(" + name + ")sin" + suffix
+			input[initialSize+n+1] = "(" + name + ")cos" + suffix
+		}
+		n += 2
+	}
+	return n
+}
+
+func (c *fourierCross) spec() CrossSpec {
+	return CrossSpec{Type: "fourier", Vars: []int{c.varIndex}, Period: c.period, Harmonics: c.harmonics}
+}
@@ -0,0 +1,135 @@
+package regression
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTimeFeaturesCross(t *testing.T) {
+	// Tuesday 2024-03-05 06:00 UTC
+	ts := float64(time.Date(2024, time.March, 5, 6, 0, 0, 0, time.UTC).Unix())
+	cross := TimeFeaturesCross(1, HourOfDay, DayOfWeek, Month, HourCyclical)
+	got := cross.Calculate([]float64{7, ts})
+	if len(got) != 23+6+11+2 {
+		t.Fatalf("Expected 42 features, got %d", len(got))
+	}
+	for i, v := range got[:40] {
+		want := 0.0
+		// hour 6, Tuesday and March
+		if i == 5 || i == 23+1 || i == 29+1 {
+			want = 1
+		}
+		if v != want {
+			t.Errorf("Feature %d: expected %v, got %v", i, want, v)
+		}
+	}
+	assertClose(t, "hour sine", got[40], 1, 1e-12)
+	assertClose(t, "hour cosine", got[41], 0, 1e-12)
+
+	// midnight on a Sunday in January is the reference level of every indicator
+	ref := float64(time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC).Unix())
+	for i, v := range cross.Calculate([]float64{0, ref})[:40] {
+		if v != 0 {
+			t.Errorf("Expected no indicator set at the reference levels, got %d", i)
+		}
+	}
+
+	names := map[int]string{1: "ts"}
+	if n := cross.ExtendNames(names, 2); n != 42 {
+		t.Fatalf("Expected 42 names, got %d", n)
+	}
+	for i, want := range map[int]string{2: "(ts)hour=1", 25: "(ts)weekday=Mon", 31: "(ts)month=Feb", 42: "(ts)hour_sin", 43: "(ts)hour_cos"} {
+		if names[i] != want {
+			t.Errorf("Expected name %q at %d, got %q", want, i, names[i])
+		}
+	}
+	if n := TimeFeaturesCross(0).ExtendNames(map[int]string{}, 1); n != 6 {
+		t.Errorf("Expected 6 default features, got %d", n)
+	}
+}
+
+func TestTimeFeaturesCrossFit(t *testing.T) {
+	// a daily cycle is fitted exactly by the cyclical hour encoding
+	r := new(Regression)
+	r.SetVar(0, "ts")
+	r.AddCross(TimeFeaturesCross(0, HourCyclical))
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for h := 0; h < 72; h++ {
+		at := start.Add(time.Duration(h) * time.Hour)
+		y := 10 + 3*math.Sin(2*math.Pi*float64(at.Hour())/24)
+		r.Train(DataPoint(y, []float64{float64(at.Unix())}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "sine coefficient", r.Coeff(2), 3, 1e-6)
+	assertClose(t, "R2", r.R2, 1, 1e-9)
+	if r.GetVar(1) != "(ts)hour_sin" {
+		t.Errorf("Unexpected name %q", r.GetVar(1))
+	}
+
+	var buf bytes.Buffer
+	if err := r.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := float64(start.Add(90 * time.Hour).Unix())
+	want, _ := r.Predict([]float64{at})
+	if got, err := loaded.Predict([]float64{at}); err != nil || got != want {
+		t.Errorf("Expected %v from the loaded model, got %v, %v", want, got, err)
+	}
+	if _, err := (CrossSpec{Type: "time", Vars: []int{0}, Features: []string{"minute"}}).build(); err == nil {
+		t.Error("Expected an error for an unknown time feature")
+	}
+}
+
+func TestFourierCross(t *testing.T) {
+	cross := FourierCross(0, 7, 2)
+	got := cross.Calculate([]float64{7*1e8 + 1.75})
+	want := []float64{1, 0, 0, -1}
+	for i := range want {
+		assertClose(t, "fourier term", got[i], want[i], 1e-6)
+	}
+	names := map[int]string{0: "day"}
+	if n := cross.ExtendNames(names, 1); n != 4 || names[1] != "(day)sin1/7" || names[4] != "(day)cos2/7" {
+		t.Errorf("Unexpected names %v", names)
+	}
+
+	// a weekly pattern with a sharp weekend effect needs several harmonics
+	fit := func(harmonics int) float64 {
+		r := new(Regression)
+		r.SetVar(0, "day")
+		r.AddCross(FourierCross(0, 7, harmonics))
+		for d := 0; d < 70; d++ {
+			y := 100.0
+			if d%7 >= 5 {
+				y = 60
+			}
+			r.Train(DataPoint(y, []float64{float64(d)}))
+		}
+		if err := r.Run(); err != nil {
+			t.Fatal(err)
+		}
+		return r.R2
+	}
+	if one, three := fit(1), fit(3); !(one < three && three > 0.999) {
+		t.Errorf("Expected more harmonics to fit better, got R2 %v and %v", one, three)
+	}
+
+	spec := CrossSpec{Type: "fourier", Vars: []int{0}, Period: 7, Harmonics: 2}
+	built, err := spec.build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, err := specOf(built); err != nil || s.Period != 7 || s.Harmonics != 2 {
+		t.Errorf("Unexpected spec %+v, %v", s, err)
+	}
+	if _, err := (CrossSpec{Type: "fourier", Vars: []int{0}, Harmonics: 2}).build(); err == nil {
+		t.Error("Expected an error without a period")
+	}
+}
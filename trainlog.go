@@ -0,0 +1,272 @@
+package regression
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"math"
+	"os"
+	"sync"
+)
+
+// ErrCorruptLog signals that a training log record failed its checksum or can't be decoded.
+var ErrCorruptLog = errors.New("training log is corrupt")
+
+// logVersion is the version of the encoding of the data points in the training log.
+const logVersion = 1
+
+// maxLogRecord bounds the payload of a record, so a corrupt length can't exhaust memory on replay.
+const maxLogRecord = 1 << 28
+
+// TrainingLog is an append-only log of the data points passed to Train, see SetTrainingLog. Every
+// record holds the data points of one Train call, framed by its length and a CRC-32 checksum, so the
+// log can be replayed exactly with ReplayLog, including NaN and infinite values. It is safe for
+// concurrent use.
+type TrainingLog struct {
+	mu  sync.Mutex
+	w   io.Writer
+	err error
+}
+
+// NewTrainingLog returns a log appending records to w, e.g. an object store upload. Every record is
+// written with a single call to Write.
+func NewTrainingLog(w io.Writer) *TrainingLog {
+	return &TrainingLog{w: w}
+}
+
+// OpenTrainingLog opens the log file at path for appending, creating it if needed. A record cut short
+// at the end of the file, as left by a crash during a write, is truncated first, so the records
+// appended after it can be replayed; a corrupt record fails with ErrCorruptLog. Close the log when
+// done.
+func OpenTrainingLog(path string) (*TrainingLog, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	valid, err := readLogRecords(f, func([]byte) error { return nil })
+	if err == nil {
+		var info os.FileInfo
+		if info, err = f.Stat(); err == nil && info.Size() > valid {
+			err = f.Truncate(valid)
+		}
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return NewTrainingLog(f), nil
+}
+
+// Sync commits the records written to stable storage, if the underlying writer supports it as
+// *os.File does.
+func (l *TrainingLog) Sync() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if s, ok := l.w.(interface {
+		Sync() error
+	}); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+// Close closes the underlying writer if it is an io.Closer.
+func (l *TrainingLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if c, ok := l.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Err returns the first error appending a record. The records after it are dropped, so the log
+// never has gaps: a replay reconstructs the data up to the failed Train call.
+func (l *TrainingLog) Err() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
+
+// errLogRecordSize signals a Train call with too many data points for a single record.
+var errLogRecordSize = errors.New("training log record too large")
+
+// append writes a record with the data points d.
+func (l *TrainingLog) append(d []*dataPoint) {
+	payload := encodeLogPoints(d)
+	if len(payload) > maxLogRecord {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if l.err == nil {
+			l.err = errLogRecordSize
+		}
+		return
+	}
+	rec := make([]byte, 8, 8+len(payload))
+	binary.LittleEndian.PutUint32(rec, uint32(len(payload)))
+	binary.LittleEndian.PutUint32(rec[4:], crc32.ChecksumIEEE(payload))
+	rec = append(rec, payload...)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err != nil {
+		return
+	}
+	l.err = writeAll(l.w, rec)
+}
+
+// SetTrainingLog appends the data points of every Train call to l before they are trained, so a
+// crashed trainer can recover its training data with ReplayLog and audits can replay how the model
+// was built. Calls of TrainMatrix, TrainNamed and the other methods training through Train are
+// logged too; Update is not, as its data points aren't retained. Check l.Err for write errors. A nil
+// log stops logging.
+func (r *Regression) SetTrainingLog(l *TrainingLog) {
+	r.trainLog = l
+}
+
+// ReplayLog trains the regression with the data points of the log file at path, in the order they
+// were logged. The data points aren't logged again. A record cut short at the end of the file, as
+// left by a crash during a write, is ignored.
+func (r *Regression) ReplayLog(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return r.ReplayLogFrom(f)
+}
+
+// ReplayLogFrom is ReplayLog reading the log from rd.
+func (r *Regression) ReplayLogFrom(rd io.Reader) error {
+	var points []*dataPoint
+	_, err := readLogRecords(rd, func(payload []byte) error {
+		d, err := decodeLogPoints(payload)
+		points = append(points, d...)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	l := r.trainLog
+	r.trainLog = nil
+	r.Train(points...)
+	r.trainLog = l
+	return nil
+}
+
+// readLogRecords calls fn with the payload of every record read from rd, stopping at a record cut
+// short at the end. It returns the length of the complete records.
+func readLogRecords(rd io.Reader, fn func(payload []byte) error) (int64, error) {
+	var valid int64
+	br := bufio.NewReader(rd)
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(br, header); err == io.EOF || err == io.ErrUnexpectedEOF {
+			return valid, nil
+		} else if err != nil {
+			return valid, err
+		}
+		size := binary.LittleEndian.Uint32(header)
+		if size > maxLogRecord {
+			return valid, ErrCorruptLog
+		}
+		payload := make([]byte, size)
+		if _, err := io.ReadFull(br, payload); err == io.EOF || err == io.ErrUnexpectedEOF {
+			return valid, nil
+		} else if err != nil {
+			return valid, err
+		}
+		if crc32.ChecksumIEEE(payload) != binary.LittleEndian.Uint32(header[4:]) {
+			return valid, ErrCorruptLog
+		}
+		if err := fn(payload); err != nil {
+			return valid, err
+		}
+		valid += int64(len(header) + len(payload))
+	}
+}
+
+// encodeLogPoints encodes data points as a version byte and their count followed by, for every
+// point, its flags, observed value, weight, variables and group.
+func encodeLogPoints(d []*dataPoint) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	b := []byte{logVersion}
+	b = append(b, buf[:binary.PutUvarint(buf[:], uint64(len(d)))]...)
+	for _, p := range d {
+		var flags byte
+		if p.Treated {
+			flags |= 1
+		}
+		if p.Post {
+			flags |= 2
+		}
+		b = append(b, flags)
+		b = appendFloat64(b, p.Observed)
+		b = appendFloat64(b, p.Weight)
+		b = append(b, buf[:binary.PutUvarint(buf[:], uint64(len(p.Variables)))]...)
+		for _, v := range p.Variables {
+			b = appendFloat64(b, v)
+		}
+		b = append(b, buf[:binary.PutUvarint(buf[:], uint64(len(p.Group)))]...)
+		b = append(b, p.Group...)
+	}
+	return b
+}
+
+// decodeLogPoints decodes data points encoded by encodeLogPoints.
+func decodeLogPoints(b []byte) ([]*dataPoint, error) {
+	if len(b) == 0 || b[0] != logVersion {
+		return nil, ErrCorruptLog
+	}
+	b = b[1:]
+	uvarint := func() (int, bool) {
+		v, n := binary.Uvarint(b)
+		if n <= 0 || v > uint64(len(b)) {
+			return 0, false
+		}
+		b = b[n:]
+		return int(v), true
+	}
+	float := func() float64 {
+		v := math.Float64frombits(binary.LittleEndian.Uint64(b))
+		b = b[8:]
+		return v
+	}
+
+	n, ok := uvarint()
+	if !ok {
+		return nil, ErrCorruptLog
+	}
+	points := make([]*dataPoint, 0, n)
+	for i := 0; i < n; i++ {
+		if len(b) < 17 {
+			return nil, ErrCorruptLog
+		}
+		flags := b[0]
+		b = b[1:]
+		p := &dataPoint{Treated: flags&1 != 0, Post: flags&2 != 0}
+		p.Observed, p.Weight = float(), float()
+		vars, ok := uvarint()
+		if !ok || len(b) < 8*vars {
+			return nil, ErrCorruptLog
+		}
+		p.Variables = make([]float64, vars)
+		for j := range p.Variables {
+			p.Variables[j] = float()
+		}
+		group, ok := uvarint()
+		if !ok || len(b) < group {
+			return nil, ErrCorruptLog
+		}
+		p.Group = string(b[:group])
+		b = b[group:]
+		points = append(points, p)
+	}
+	if len(b) != 0 {
+		return nil, ErrCorruptLog
+	}
+	return points, nil
+}
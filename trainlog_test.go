@@ -0,0 +1,164 @@
+package regression
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTrainingLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "regression")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "train.log")
+
+	l, err := OpenTrainingLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := new(Regression)
+	r.SetTrainingLog(l)
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	p := GroupedDataPoint(3, []float64{math.NaN()}, "b")
+	p.Weight, p.Treated, p.Post = 2, true, true
+	r.Train(p, DataPoint(math.Inf(1), []float64{-0.5}))
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// a crashed trainer recovers the exact data, in order
+	replayed := new(Regression)
+	if err := replayed.ReplayLog(path); err != nil {
+		t.Fatal(err)
+	}
+	if len(replayed.data) != len(r.data) {
+		t.Fatalf("Expected %d data points, got %d", len(r.data), len(replayed.data))
+	}
+	for i, d := range replayed.data {
+		w := r.data[i]
+		if math.Float64bits(d.Observed) != math.Float64bits(w.Observed) || d.Weight != w.Weight ||
+			d.Group != w.Group || d.Treated != w.Treated || d.Post != w.Post || len(d.Variables) != len(w.Variables) ||
+			math.Float64bits(d.Variables[0]) != math.Float64bits(w.Variables[0]) {
+			t.Fatalf("Point %d: got %+v, want %+v", i, *d, *w)
+		}
+	}
+
+	// the log is appended to after a restart, and replays aren't logged again
+	l, err = OpenTrainingLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed = new(Regression)
+	replayed.SetTrainingLog(l)
+	if err := replayed.ReplayLog(path); err != nil {
+		t.Fatal(err)
+	}
+	replayed.Train(DataPoint(1, []float64{2}))
+	l.Close()
+	again := new(Regression)
+	if err := again.ReplayLog(path); err != nil {
+		t.Fatal(err)
+	}
+	if len(again.data) != len(r.data)+1 || again.data[len(r.data)].Observed != 1 {
+		t.Fatalf("Expected %d data points ending with the new one, got %d", len(r.data)+1, len(again.data))
+	}
+}
+
+func TestReplayLogTorn(t *testing.T) {
+	var buf bytes.Buffer
+	r := new(Regression)
+	r.SetTrainingLog(NewTrainingLog(&buf))
+	r.Train(DataPoint(1, []float64{1}), DataPoint(2, []float64{2}))
+	r.Train(DataPoint(3, []float64{3}))
+	log := buf.Bytes()
+
+	// a record cut short by a crash is ignored
+	replayed := new(Regression)
+	if err := replayed.ReplayLogFrom(bytes.NewReader(log[:len(log)-3])); err != nil {
+		t.Fatal(err)
+	}
+	if len(replayed.data) != 2 {
+		t.Errorf("Expected the complete record only, got %d data points", len(replayed.data))
+	}
+
+	// a corrupt record isn't
+	corrupt := append([]byte(nil), log...)
+	corrupt[12]++
+	if err := new(Regression).ReplayLogFrom(bytes.NewReader(corrupt)); err != ErrCorruptLog {
+		t.Errorf("Expected ErrCorruptLog, got %v", err)
+	}
+}
+
+func TestOpenTrainingLogTorn(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "train.log")
+	l, err := OpenTrainingLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := new(Regression)
+	r.SetTrainingLog(l)
+	r.Train(DataPoint(1, []float64{1}), DataPoint(2, []float64{2}))
+	r.Train(DataPoint(3, []float64{3}))
+	l.Close()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Truncate(path, info.Size()-3); err != nil {
+		t.Fatal(err)
+	}
+
+	// records appended after a torn one are replayed
+	if l, err = OpenTrainingLog(path); err != nil {
+		t.Fatal(err)
+	}
+	r.SetTrainingLog(l)
+	r.Train(DataPoint(4, []float64{4}))
+	l.Close()
+	replayed := new(Regression)
+	if err := replayed.ReplayLog(path); err != nil {
+		t.Fatal(err)
+	}
+	if len(replayed.data) != 3 || replayed.data[2].Observed != 4 {
+		t.Errorf("Expected the first and the appended record, got %d data points", len(replayed.data))
+	}
+
+	// a length beyond the bound is corrupt rather than allocated
+	header := []byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0}
+	if err := new(Regression).ReplayLogFrom(bytes.NewReader(header)); err != ErrCorruptLog {
+		t.Errorf("Expected ErrCorruptLog, got %v", err)
+	}
+}
+
+type failingWriter struct{ writes int }
+
+func (w *failingWriter) Write(b []byte) (int, error) {
+	w.writes++
+	return 0, errors.New("disk full")
+}
+
+func TestTrainingLogError(t *testing.T) {
+	w := new(failingWriter)
+	l := NewTrainingLog(w)
+	r := new(Regression)
+	r.SetTrainingLog(l)
+	r.Train(DataPoint(1, []float64{1}))
+	r.Train(DataPoint(2, []float64{2}))
+	if l.Err() == nil {
+		t.Error("Expected the write error")
+	}
+	if w.writes != 1 {
+		t.Errorf("Expected no writes after the error, got %d", w.writes)
+	}
+	if len(r.data) != 2 {
+		t.Errorf("Expected training to go on, got %d data points", len(r.data))
+	}
+}
@@ -0,0 +1,54 @@
+package regression
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// applyWeights turns the least squares problem into a weighted one by scaling the rows of the
+// design matrix and the observations by the square root of the weights.
+func (r *Regression) applyWeights(x, y *mat.Dense) {
+	_, cols := x.Dims()
+	for i, d := range r.data {
+		if d.Weight == 1 {
+			continue
+		}
+		w := math.Sqrt(d.Weight)
+		y.Set(i, 0, w*y.At(i, 0))
+		for j := 0; j < cols; j++ {
+			x.Set(i, j, w*x.At(i, j))
+		}
+	}
+}
+
+// checkWeights returns ErrInvalidWeight if a data point has a negative weight, which has no square
+// root to scale its row by.
+func checkWeights(d []*dataPoint) error {
+	for _, p := range d {
+		if p.Weight < 0 {
+			return ErrInvalidWeight
+		}
+	}
+	return nil
+}
+
+// weights returns the weights of the training data points.
+func (r *Regression) weights() []float64 {
+	w := make([]float64, len(r.data))
+	for i, d := range r.data {
+		w[i] = d.Weight
+	}
+	return w
+}
+
+// weightedObservations returns the number of training data points with a non-zero weight.
+func (r *Regression) weightedObservations() int {
+	n := 0
+	for _, d := range r.data {
+		if d.Weight != 0 {
+			n++
+		}
+	}
+	return n
+}
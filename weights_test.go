@@ -0,0 +1,116 @@
+package regression
+
+import (
+	"math"
+	"testing"
+)
+
+// weightedCars fits dist ~ speed with weights 1 / speed.
+func weightedCars(t *testing.T) *Regression {
+	r := new(Regression)
+	for i := range carsSpeed {
+		r.Train(WeightedDataPoint(carsDist[i], []float64{carsSpeed[i]}, 1/carsSpeed[i]))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestWeightedRegression(t *testing.T) {
+	r := weightedCars(t)
+
+	// closed form weighted least squares for a single variable
+	var sw, swx, swy float64
+	for i, x := range carsSpeed {
+		w := 1 / x
+		sw += w
+		swx += w * x
+		swy += w * carsDist[i]
+	}
+	mx, my := swx/sw, swy/sw
+	var sxx, sxy, syy float64
+	for i, x := range carsSpeed {
+		w := 1 / x
+		sxx += w * (x - mx) * (x - mx)
+		sxy += w * (x - mx) * (carsDist[i] - my)
+		syy += w * (carsDist[i] - my) * (carsDist[i] - my)
+	}
+	slope := sxy / sxx
+	offset := my - slope*mx
+	var sse float64
+	for i, x := range carsSpeed {
+		e := carsDist[i] - offset - slope*x
+		sse += e * e / x
+	}
+	sigma2 := sse / float64(len(carsSpeed)-2)
+
+	assertClose(t, "offset", r.Coeff(0), offset, 1e-9)
+	assertClose(t, "slope", r.Coeff(1), slope, 1e-9)
+	assertClose(t, "R2", r.R2, 1-sse/syy, 1e-9)
+	assertClose(t, "slope stderr", r.StdErr(1), math.Sqrt(sigma2/sxx), 1e-9)
+	assertClose(t, "offset stderr", r.StdErr(0), math.Sqrt(sigma2*(1/sw+mx*mx/sxx)), 1e-9)
+	assertClose(t, "RMSE", r.RMSE(), math.Sqrt(sse/sw), 1e-9)
+	assertClose(t, "variance observed", r.Varianceobserved, syy/sw, 1e-9)
+}
+
+func TestFrequencyWeights(t *testing.T) {
+	weighted := new(Regression)
+	replicated := new(Regression)
+	for i := range carsSpeed {
+		n := i%3 + 1
+		weighted.Train(WeightedDataPoint(carsDist[i], []float64{carsSpeed[i]}, float64(n)))
+		for j := 0; j < n; j++ {
+			replicated.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+		}
+	}
+	// zero weights are ignored
+	weighted.Train(WeightedDataPoint(1000, []float64{1}, 0))
+	weighted.Run()
+	replicated.Run()
+
+	assertClose(t, "offset", weighted.Coeff(0), replicated.Coeff(0), 1e-9)
+	assertClose(t, "slope", weighted.Coeff(1), replicated.Coeff(1), 1e-9)
+	assertClose(t, "R2", weighted.R2, replicated.R2, 1e-9)
+	assertClose(t, "RMSE", weighted.RMSE(), replicated.RMSE(), 1e-9)
+	if _, residual := weighted.DegreesOfFreedom(); residual != len(carsSpeed)-2 {
+		t.Errorf("Expected %d residual degrees of freedom, got %d", len(carsSpeed)-2, residual)
+	}
+}
+
+func TestWeightedRunStream(t *testing.T) {
+	want := weightedCars(t)
+
+	r := new(Regression)
+	i := 0
+	err := r.RunStream(func() (*dataPoint, bool) {
+		if i == len(carsSpeed) {
+			return nil, false
+		}
+		i++
+		return WeightedDataPoint(carsDist[i-1], []float64{carsSpeed[i-1]}, 1/carsSpeed[i-1]), true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "slope", r.Coeff(1), want.Coeff(1), 1e-9)
+	assertClose(t, "R2", r.R2, want.R2, 1e-9)
+	assertClose(t, "RMSE", r.RMSE(), want.RMSE(), 1e-9)
+	assertClose(t, "stderr", r.StdErr(1), want.StdErr(1), 1e-9)
+}
+
+func TestNegativeWeight(t *testing.T) {
+	r := new(Regression)
+	for i := range carsSpeed {
+		r.Train(WeightedDataPoint(carsDist[i], []float64{carsSpeed[i]}, 1))
+	}
+	r.Train(WeightedDataPoint(10, []float64{10}, -1))
+	if err := r.Run(); err != ErrInvalidWeight {
+		t.Errorf("Expected ErrInvalidWeight, got %v", err)
+	}
+
+	r = carsRegression(t)
+	if err := r.Update(WeightedDataPoint(10, []float64{10}, -1)); err != ErrInvalidWeight {
+		t.Errorf("Expected ErrInvalidWeight updating, got %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package regression
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// ErrInvalidPercentile signals percentiles outside [0, 1] or a lower percentile above the upper one.
+var ErrInvalidPercentile = errors.New("percentiles must satisfy 0 <= lower <= upper <= 1")
+
+// WinsorizeVar makes Run clip variable i to its lower and upper percentiles in the training data,
+// given as fractions, e.g. 0.01 and 0.99, to tame heavy tails. The cut points are stored with the model
+// and applied to the variables passed to Predict. Percentiles are computed without weights, from the
+// data points with a non-zero weight, interpolating linearly between data points.
+func (r *Regression) WinsorizeVar(i int, lower, upper float64) error {
+	if !validPercentiles(lower, upper) {
+		return ErrInvalidPercentile
+	}
+	if r.winsorVars == nil {
+		r.winsorVars = make(map[int][2]float64)
+	}
+	r.winsorVars[i] = [2]float64{lower, upper}
+	return nil
+}
+
+// WinsorizeObserved makes Run clip the observed value to its lower and upper percentiles in the training
+// data, see WinsorizeVar. Predictions aren't clipped.
+func (r *Regression) WinsorizeObserved(lower, upper float64) error {
+	if !validPercentiles(lower, upper) {
+		return ErrInvalidPercentile
+	}
+	r.winsorObs = &[2]float64{lower, upper}
+	return nil
+}
+
+func validPercentiles(lower, upper float64) bool {
+	return 0 <= lower && lower <= upper && upper <= 1
+}
+
+// CutPoints returns the values variable i was clipped to by winsorization, and false if it wasn't.
+func (r *Regression) CutPoints(i int) (lo, hi float64, ok bool) {
+	c, ok := r.clips[i]
+	return c[0], c[1], ok
+}
+
+// ObservedCutPoints returns the values the observed value was clipped to by winsorization, and false
+// if it wasn't.
+func (r *Regression) ObservedCutPoints() (lo, hi float64, ok bool) {
+	if r.obsClip == nil {
+		return 0, 0, false
+	}
+	return r.obsClip[0], r.obsClip[1], true
+}
+
+// winsorize learns the cut points from the training data and fits clipped copies of the data points,
+// so the caller's data points are left alone. The copies replace the training data until unwinsorize
+// restores the original data points, so refits learn the cut points from the unclipped data again.
+func (r *Regression) winsorize() error {
+	r.clips, r.obsClip = nil, nil
+	if r.winsorVars == nil && r.winsorObs == nil {
+		return nil
+	}
+	numOfBaseVars := len(r.data[0].Variables)
+	values := make([]float64, 0, len(r.data))
+	column := func(f func(d *dataPoint) float64) []float64 {
+		values = values[:0]
+		for _, d := range r.data {
+			if d.Weight != 0 {
+				values = append(values, f(d))
+			}
+		}
+		return values
+	}
+
+	for i, p := range r.winsorVars {
+		if i < 0 || i >= numOfBaseVars {
+			return ErrDimensions
+		}
+		lo, hi := quantiles(column(func(d *dataPoint) float64 { return d.Variables[i] }), p[0], p[1])
+		if r.clips == nil {
+			r.clips = make(map[int][2]float64, len(r.winsorVars))
+		}
+		r.clips[i] = [2]float64{lo, hi}
+	}
+	if p := r.winsorObs; p != nil {
+		lo, hi := quantiles(column(func(d *dataPoint) float64 { return d.Observed }), p[0], p[1])
+		r.obsClip = &[2]float64{lo, hi}
+	}
+
+	r.unclipped = r.data[:len(r.data):len(r.data)]
+	clipped := make([]*dataPoint, len(r.data))
+	for i, d := range r.data {
+		c := *d
+		c.Variables = r.clipVars(d.Variables)
+		c.Observed = r.clipObserved(d.Observed)
+		clipped[i] = &c
+	}
+	r.data = clipped
+	return nil
+}
+
+// unwinsorize puts the original data points back in place of the clipped copies fitted by the last run,
+// keeping the data points trained since.
+func (r *Regression) unwinsorize() {
+	if r.unclipped == nil {
+		return
+	}
+	r.data = append(r.unclipped, r.data[len(r.unclipped):]...)
+	r.unclipped = nil
+}
+
+// trainedPoint returns data point i of the training data as trained, before winsorization.
+func (r *Regression) trainedPoint(i int) *dataPoint {
+	if i < len(r.unclipped) {
+		return r.unclipped[i]
+	}
+	return r.data[i]
+}
+
+// clipVars returns a copy of vars clipped to the cut points, or vars itself without cut points.
+func (r *Regression) clipVars(vars []float64) []float64 {
+	if r.clips == nil {
+		return vars
+	}
+	clipped := append([]float64(nil), vars...)
+	for i, c := range r.clips {
+		if i < len(clipped) {
+			clipped[i] = math.Max(c[0], math.Min(c[1], clipped[i]))
+		}
+	}
+	return clipped
+}
+
+func (r *Regression) clipObserved(v float64) float64 {
+	if r.obsClip == nil {
+		return v
+	}
+	return math.Max(r.obsClip[0], math.Min(r.obsClip[1], v))
+}
+
+// quantiles returns the lower and upper quantiles of values, interpolating linearly between the
+// order statistics as R's default quantile type 7. The values are sorted in place.
+func quantiles(values []float64, lower, upper float64) (float64, float64) {
+	if len(values) == 0 {
+		return math.Inf(-1), math.Inf(1)
+	}
+	sort.Float64s(values)
+	q := func(p float64) float64 {
+		h := p * float64(len(values)-1)
+		k := int(h)
+		if k >= len(values)-1 {
+			return values[len(values)-1]
+		}
+		return values[k] + (h-float64(k))*(values[k+1]-values[k])
+	}
+	return q(lower), q(upper)
+}
@@ -0,0 +1,158 @@
+package regression
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWinsorize(t *testing.T) {
+	r := new(Regression)
+	if err := r.WinsorizeVar(0, 0.1, 0.9); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.WinsorizeObserved(0, 0.8); err != nil {
+		t.Fatal(err)
+	}
+	// x = 0..10 with an outlier in both the variable and the observed value
+	vars := make([][]float64, 11)
+	for i := range vars {
+		x := float64(i)
+		if i == 10 {
+			x = 1000
+		}
+		vars[i] = []float64{x}
+		y := 2 * x
+		if i == 5 {
+			y = 500
+		}
+		r.Train(DataPoint(y, vars[i]))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	lo, hi, ok := r.CutPoints(0)
+	if !ok {
+		t.Fatal("Expected cut points for variable 0")
+	}
+	assertClose(t, "lower cut point", lo, 1, 1e-12)
+	assertClose(t, "upper cut point", hi, 9, 1e-12)
+	if _, _, ok := r.CutPoints(1); ok {
+		t.Error("Expected no cut points for variable 1")
+	}
+	olo, ohi, ok := r.ObservedCutPoints()
+	if !ok || olo != 0 || ohi != 18 {
+		t.Errorf("Unexpected observed cut points %v, %v, %v", olo, ohi, ok)
+	}
+	if vars[10][0] != 1000 {
+		t.Errorf("Expected the caller's variables unchanged, got %v", vars[10])
+	}
+
+	// predictions clip the variables to the cut points
+	at9, _ := r.Predict([]float64{9})
+	far, err := r.Predict([]float64{1e6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if far != at9 {
+		t.Errorf("Expected a prediction clipped to the upper cut point %v, got %v", at9, far)
+	}
+
+	var buf bytes.Buffer
+	if err := r.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := loaded.Predict([]float64{1e6}); err != nil || got != at9 {
+		t.Errorf("Expected %v from the loaded model, got %v, %v", at9, got, err)
+	}
+
+	for _, p := range [][2]float64{{-0.1, 0.5}, {0.6, 0.5}, {0, 1.5}} {
+		if err := r.WinsorizeVar(0, p[0], p[1]); err != ErrInvalidPercentile {
+			t.Errorf("Expected ErrInvalidPercentile for %v, got %v", p, err)
+		}
+	}
+	bad := new(Regression)
+	if err := bad.WinsorizeVar(3, 0.1, 0.9); err != nil {
+		t.Fatal(err)
+	}
+	bad.Train(DataPoint(1, []float64{1}), DataPoint(2, []float64{2}), DataPoint(3, []float64{4}))
+	if err := bad.Run(); err != ErrDimensions {
+		t.Errorf("Expected ErrDimensions, got %v", err)
+	}
+}
+
+func TestQuantiles(t *testing.T) {
+	// R: quantile(c(4, 1, 3, 2), c(0.25, 0.9))
+	lo, hi := quantiles([]float64{4, 1, 3, 2}, 0.25, 0.9)
+	assertClose(t, "lower quantile", lo, 1.75, 1e-12)
+	assertClose(t, "upper quantile", hi, 3.7, 1e-12)
+}
+
+func TestWinsorizeRerun(t *testing.T) {
+	r := carsRegression(t)
+	r.Reset()
+	if err := r.WinsorizeVar(0, 0.1, 0.9); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.WinsorizeObserved(0.05, 0.95); err != nil {
+		t.Fatal(err)
+	}
+	points := make([]*dataPoint, len(r.data))
+	copy(points, r.data)
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	lo, hi, _ := r.ObservedCutPoints()
+	coeffs := r.coeffs()
+	for i, p := range points {
+		if p.Observed != carsDist[i] || p.Variables[0] != carsSpeed[i] {
+			t.Fatalf("Expected the data points unchanged, got %+v", *p)
+		}
+	}
+
+	// refits learn the same cut points from the unclipped data
+	if changed, err := r.RunIfChanged(); changed || err != nil {
+		t.Errorf("Expected no change, got %v, %v", changed, err)
+	}
+	for i := 0; i < 2; i++ {
+		r.Reset()
+		if err := r.Run(); err != nil {
+			t.Fatal(err)
+		}
+		if l, h, _ := r.ObservedCutPoints(); l != lo || h != hi {
+			t.Errorf("Expected the cut points %v and %v, got %v and %v", lo, hi, l, h)
+		}
+		for j, c := range r.coeffs() {
+			if c != coeffs[j] {
+				t.Errorf("Expected coefficient %v, got %v", coeffs[j], c)
+			}
+		}
+	}
+}
+
+func TestWinsorizeExports(t *testing.T) {
+	r := new(Regression)
+	if err := r.WinsorizeVar(0, 0.1, 0.9); err != nil {
+		t.Fatal(err)
+	}
+	for i := range carsSpeed {
+		r.Train(DataPoint(carsDist[i], []float64{carsSpeed[i]}))
+	}
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	// the exports can't clip the variables to the cut points
+	if _, err := r.Predictor(); err != ErrUnsupported {
+		t.Errorf("Expected ErrUnsupported from Predictor, got %v", err)
+	}
+	if _, err := NewCompact[float64](r); err != ErrNotCompactable {
+		t.Errorf("Expected ErrNotCompactable, got %v", err)
+	}
+	if _, err := r.ExportSklearn(); err != ErrUnsupported {
+		t.Errorf("Expected ErrUnsupported from ExportSklearn, got %v", err)
+	}
+}